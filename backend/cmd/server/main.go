@@ -41,32 +41,64 @@ func main() {
 	shareCodeRepo := repository.NewShareCodeRepository(database.DB, q)
 	userInviteRepo := repository.NewUserInviteRepository(database.DB, q)
 	variationRepo := repository.NewVariationRepository(database.DB, q)
+	ingredientRepo := repository.NewRecipeIngredientRepository(database.DB, q)
+	revisionRepo := repository.NewRecipeRevisionRepository(database.DB, q)
+	backupRepo := repository.NewBackupRepository(database.DB, q)
+	cookLogRepo := repository.NewCookLogRepository(database.DB, q)
+	collectionRepo := repository.NewCollectionRepository(database.DB, q)
+	commentRepo := repository.NewCommentRepository(database.DB, q)
 
 	authService := services.NewAuthService(cfg, userRepo)
-	recipeService := services.NewRecipeService(recipeRepo)
-	categoryService := services.NewCategoryService(categoryRepo)
-	tagService := services.NewTagService(tagRepo)
+	recipeService := services.NewRecipeService(recipeRepo, ingredientRepo, revisionRepo, tagRepo)
+	revisionService := services.NewRevisionService(revisionRepo, recipeService)
+	categoryService := services.NewCategoryService(categoryRepo, recipeRepo)
+	tagService := services.NewTagService(tagRepo, recipeRepo)
 	recipeGroupService := services.NewRecipeGroupService(recipeGroupRepo)
 	shareCodeService := services.NewShareCodeService(shareCodeRepo, recipeRepo)
 	userInviteService := services.NewUserInviteService(userInviteRepo, userRepo)
 	variationService := services.NewVariationService(variationRepo, recipeRepo)
 	storageService := services.NewStorageService(cfg.Storage.LocalPath, cfg.Storage.MaxFileSize)
 	aiService := services.NewAIService(cfg)
+	importService := services.NewImportService(recipeService, categoryService, storageService)
+	printService := services.NewPrintService(recipeService, categoryService, recipeGroupService, storageService)
+	backupService := services.NewBackupService(backupRepo, recipeService, storageService)
+	cookLogService := services.NewCookLogService(cookLogRepo, recipeRepo, variationRepo, storageService)
+	collectionService := services.NewCollectionService(collectionRepo, recipeRepo)
+	commentService := services.NewCommentService(commentRepo, recipeRepo, variationRepo)
+	trashService := services.NewTrashService(recipeRepo, storageService)
 
 	storageService.EnsureDirectory()
 
+	if count, err := recipeService.ClassifyUnclassified(); err != nil {
+		log.Printf("Failed to classify recipes: %v", err)
+	} else if count > 0 {
+		log.Printf("Classified allergens and diets of %d recipes", count)
+	}
+
+	stopPurge := make(chan struct{})
+	go purgeTrash(trashService, cfg.Trash, stopPurge)
+
 	authHandler := handlers.NewAuthHandler(authService)
-	recipeHandler := handlers.NewRecipeHandler(recipeService)
+	recipeHandler := handlers.NewRecipeHandler(recipeService, cfg.Server.BaseURL)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 	tagHandler := handlers.NewTagHandler(tagService)
 	recipeGroupHandler := handlers.NewRecipeGroupHandler(recipeGroupService)
 	shareCodeHandler := handlers.NewShareCodeHandler(shareCodeService)
 	userInviteHandler := handlers.NewUserInviteHandler(userInviteService)
 	variationHandler := handlers.NewVariationHandler(variationService)
+	revisionHandler := handlers.NewRevisionHandler(revisionService)
 	uploadHandler := handlers.NewUploadHandler(storageService)
 	aiHandler := handlers.NewAIHandler(aiService)
+	importHandler := handlers.NewImportHandler(importService)
+	printHandler := handlers.NewPrintHandler(printService)
+	backupHandler := handlers.NewBackupHandler(backupService)
+	cookLogHandler := handlers.NewCookLogHandler(cookLogService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	trashHandler := handlers.NewTrashHandler(trashService)
 
 	authMiddleware := middleware.NewAuthMiddleware(authService)
+	requireAdmin := authMiddleware.RequireRole("admin")
 
 	mux := http.NewServeMux()
 
@@ -86,21 +118,52 @@ func main() {
 	mux.HandleFunc("GET /api/v1/recipes", recipeHandler.ListRecipes)
 	mux.HandleFunc("GET /api/v1/recipes/search", recipeHandler.SearchRecipes)
 	mux.HandleFunc("GET /api/v1/recipes/{id}", recipeHandler.GetRecipe)
-	// Slug endpoint moved after to avoid conflicts
-	// mux.HandleFunc("GET /api/v1/recipes/slug/{slug}", recipeHandler.GetRecipeBySlug)
+	mux.HandleFunc("GET /api/v1/recipes/{id}/scaled", recipeHandler.GetScaledRecipe)
+	mux.HandleFunc("GET /api/v1/recipes/{id}/steps", recipeHandler.GetRecipeSteps)
+	mux.HandleFunc("GET /api/v1/recipes/{id}/full", recipeHandler.GetFullRecipe)
+	mux.HandleFunc("GET /api/v1/recipes/{id}/jsonld", recipeHandler.GetRecipeJSONLD)
+	mux.HandleFunc("POST /api/v1/shopping-list", recipeHandler.ShoppingList)
 
 	mux.Handle("POST /api/v1/recipes", authMiddleware.Auth(http.HandlerFunc(recipeHandler.CreateRecipe)))
 	mux.Handle("PUT /api/v1/recipes/{id}", authMiddleware.Auth(http.HandlerFunc(recipeHandler.UpdateRecipe)))
 	mux.Handle("POST /api/v1/recipes/{id}/publish", authMiddleware.Auth(http.HandlerFunc(recipeHandler.PublishRecipe)))
+	mux.Handle("POST /api/v1/recipes/{id}/fork", authMiddleware.Auth(http.HandlerFunc(recipeHandler.ForkRecipe)))
 	mux.Handle("DELETE /api/v1/recipes/{id}", authMiddleware.Auth(http.HandlerFunc(recipeHandler.DeleteRecipe)))
 
+	// Trash routes
+	mux.Handle("GET /api/v1/trash", authMiddleware.Auth(http.HandlerFunc(trashHandler.ListTrash)))
+	mux.Handle("POST /api/v1/recipes/{id}/restore", authMiddleware.Auth(http.HandlerFunc(trashHandler.RestoreRecipe)))
+
 	// Variation routes
 	mux.HandleFunc("GET /api/v1/recipes/{id}/variations", variationHandler.ListVariations)
 	mux.HandleFunc("GET /api/v1/recipes/{id}/variations/{variationId}", variationHandler.GetVariation)
+	mux.HandleFunc("GET /api/v1/recipes/{id}/variations/{variationId}/scaled", variationHandler.GetScaledVariation)
 	mux.Handle("POST /api/v1/recipes/{id}/variations", authMiddleware.Auth(http.HandlerFunc(variationHandler.CreateVariation)))
 	mux.Handle("PUT /api/v1/recipes/{id}/variations/{variationId}", authMiddleware.Auth(http.HandlerFunc(variationHandler.UpdateVariation)))
 	mux.Handle("DELETE /api/v1/recipes/{id}/variations/{variationId}", authMiddleware.Auth(http.HandlerFunc(variationHandler.DeleteVariation)))
 
+	// Revision routes
+	mux.HandleFunc("GET /api/v1/recipes/{id}/revisions", revisionHandler.ListRevisions)
+	mux.HandleFunc("GET /api/v1/recipes/{id}/revisions/diff", revisionHandler.DiffRevisions)
+	mux.HandleFunc("GET /api/v1/recipes/{id}/revisions/{revision}", revisionHandler.GetRevision)
+	mux.Handle("POST /api/v1/recipes/{id}/revisions/{revision}/restore", authMiddleware.Auth(http.HandlerFunc(revisionHandler.RestoreRevision)))
+
+	// Cook Log routes
+	mux.HandleFunc("GET /api/v1/recipes/{id}/cook-logs", cookLogHandler.ListRecipeCookLogs)
+	mux.HandleFunc("GET /api/v1/users/{id}/cook-logs", cookLogHandler.ListUserCookLogs)
+	mux.HandleFunc("GET /api/v1/cook-logs/{id}", cookLogHandler.GetCookLog)
+	mux.Handle("POST /api/v1/recipes/{id}/cook-logs", authMiddleware.Auth(http.HandlerFunc(cookLogHandler.CreateCookLog)))
+	mux.Handle("DELETE /api/v1/cook-logs/{id}", authMiddleware.Auth(http.HandlerFunc(cookLogHandler.DeleteCookLog)))
+
+	// Comment routes
+	mux.HandleFunc("GET /api/v1/recipes/{id}/comments", commentHandler.ListRecipeComments)
+	mux.HandleFunc("GET /api/v1/recipes/{id}/variations/{variationId}/comments", commentHandler.ListVariationComments)
+	mux.HandleFunc("GET /api/v1/comments/{id}", commentHandler.GetComment)
+	mux.Handle("POST /api/v1/recipes/{id}/comments", authMiddleware.Auth(http.HandlerFunc(commentHandler.CreateRecipeComment)))
+	mux.Handle("POST /api/v1/recipes/{id}/variations/{variationId}/comments", authMiddleware.Auth(http.HandlerFunc(commentHandler.CreateVariationComment)))
+	mux.Handle("PUT /api/v1/comments/{id}", authMiddleware.Auth(http.HandlerFunc(commentHandler.UpdateComment)))
+	mux.Handle("DELETE /api/v1/comments/{id}", authMiddleware.Auth(http.HandlerFunc(commentHandler.DeleteComment)))
+
 	// Category routes
 	mux.HandleFunc("GET /api/v1/categories", categoryHandler.ListCategories)
 	mux.HandleFunc("GET /api/v1/categories/{id}", categoryHandler.GetCategory)
@@ -108,26 +171,39 @@ func main() {
 	mux.Handle("PUT /api/v1/categories/{id}", authMiddleware.Auth(http.HandlerFunc(categoryHandler.UpdateCategory)))
 	mux.Handle("DELETE /api/v1/categories/{id}", authMiddleware.Auth(http.HandlerFunc(categoryHandler.DeleteCategory)))
 
-	// Recipe Group routes
+	// Recipe Group routes. Groups are the admin-curated public collections.
 	mux.HandleFunc("GET /api/v1/groups", recipeGroupHandler.ListGroups)
 	mux.HandleFunc("GET /api/v1/groups/{id}", recipeGroupHandler.GetGroup)
-	mux.Handle("POST /api/v1/groups", authMiddleware.Auth(http.HandlerFunc(recipeGroupHandler.CreateGroup)))
-	mux.Handle("PUT /api/v1/groups/{id}", authMiddleware.Auth(http.HandlerFunc(recipeGroupHandler.UpdateGroup)))
-	mux.Handle("DELETE /api/v1/groups/{id}", authMiddleware.Auth(http.HandlerFunc(recipeGroupHandler.DeleteGroup)))
+	mux.Handle("POST /api/v1/groups", authMiddleware.Auth(requireAdmin(http.HandlerFunc(recipeGroupHandler.CreateGroup))))
+	mux.Handle("PUT /api/v1/groups/{id}", authMiddleware.Auth(requireAdmin(http.HandlerFunc(recipeGroupHandler.UpdateGroup))))
+	mux.Handle("DELETE /api/v1/groups/{id}", authMiddleware.Auth(requireAdmin(http.HandlerFunc(recipeGroupHandler.DeleteGroup))))
 	mux.Handle("GET /api/v1/groups/{id}/recipes", authMiddleware.Auth(http.HandlerFunc(recipeGroupHandler.GetGroupRecipes)))
-	mux.Handle("POST /api/v1/groups/{id}/recipes", authMiddleware.Auth(http.HandlerFunc(recipeGroupHandler.AddRecipeToGroup)))
-	mux.Handle("DELETE /api/v1/groups/{id}/recipes/{recipeId}", authMiddleware.Auth(http.HandlerFunc(recipeGroupHandler.RemoveRecipeFromGroup)))
+	mux.Handle("POST /api/v1/groups/{id}/recipes", authMiddleware.Auth(requireAdmin(http.HandlerFunc(recipeGroupHandler.AddRecipeToGroup))))
+	mux.Handle("DELETE /api/v1/groups/{id}/recipes/{recipeId}", authMiddleware.Auth(requireAdmin(http.HandlerFunc(recipeGroupHandler.RemoveRecipeFromGroup))))
+
+	// Favorite and collection routes
+	mux.Handle("GET /api/v1/favorites", authMiddleware.Auth(http.HandlerFunc(collectionHandler.ListFavorites)))
+	mux.Handle("PUT /api/v1/recipes/{id}/favorite", authMiddleware.Auth(http.HandlerFunc(collectionHandler.AddFavorite)))
+	mux.Handle("DELETE /api/v1/recipes/{id}/favorite", authMiddleware.Auth(http.HandlerFunc(collectionHandler.RemoveFavorite)))
+	mux.Handle("GET /api/v1/collections", authMiddleware.Auth(http.HandlerFunc(collectionHandler.ListCollections)))
+	mux.Handle("POST /api/v1/collections", authMiddleware.Auth(http.HandlerFunc(collectionHandler.CreateCollection)))
+	mux.Handle("GET /api/v1/collections/{id}", authMiddleware.Auth(http.HandlerFunc(collectionHandler.GetCollection)))
+	mux.Handle("PUT /api/v1/collections/{id}", authMiddleware.Auth(http.HandlerFunc(collectionHandler.UpdateCollection)))
+	mux.Handle("DELETE /api/v1/collections/{id}", authMiddleware.Auth(http.HandlerFunc(collectionHandler.DeleteCollection)))
+	mux.Handle("POST /api/v1/collections/{id}/recipes", authMiddleware.Auth(http.HandlerFunc(collectionHandler.AddRecipe)))
+	mux.Handle("DELETE /api/v1/collections/{id}/recipes/{recipeId}", authMiddleware.Auth(http.HandlerFunc(collectionHandler.RemoveRecipe)))
+	mux.Handle("PUT /api/v1/collections/{id}/order", authMiddleware.Auth(http.HandlerFunc(collectionHandler.ReorderCollection)))
 
 	// Tag routes
 	mux.HandleFunc("GET /api/v1/tags", tagHandler.ListTags)
 	mux.HandleFunc("GET /api/v1/tags/{id}", tagHandler.GetTag)
-	// Recipe tags endpoint commented out due to routing conflicts
-	// mux.HandleFunc("GET /api/v1/recipes/{recipeId}/tags", tagHandler.GetRecipeTags)
+	mux.HandleFunc("GET /api/v1/recipes/{recipeId}/tags", tagHandler.GetRecipeTags)
 	mux.Handle("POST /api/v1/tags", authMiddleware.Auth(http.HandlerFunc(tagHandler.CreateTag)))
 	mux.Handle("PUT /api/v1/tags/{id}", authMiddleware.Auth(http.HandlerFunc(tagHandler.UpdateTag)))
 	mux.Handle("DELETE /api/v1/tags/{id}", authMiddleware.Auth(http.HandlerFunc(tagHandler.DeleteTag)))
-	// mux.Handle("POST /api/v1/recipes/{recipeId}/tags/{tagId}", authMiddleware.Auth(http.HandlerFunc(tagHandler.AddTagToRecipe)))
-	// mux.Handle("DELETE /api/v1/recipes/{recipeId}/tags/{tagId}", authMiddleware.Auth(http.HandlerFunc(tagHandler.RemoveTagFromRecipe)))
+	mux.Handle("PUT /api/v1/recipes/{recipeId}/tags", authMiddleware.Auth(http.HandlerFunc(tagHandler.SetRecipeTags)))
+	mux.Handle("POST /api/v1/recipes/{recipeId}/tags/{tagId}", authMiddleware.Auth(http.HandlerFunc(tagHandler.AddTagToRecipe)))
+	mux.Handle("DELETE /api/v1/recipes/{recipeId}/tags/{tagId}", authMiddleware.Auth(http.HandlerFunc(tagHandler.RemoveTagFromRecipe)))
 
 	// Upload routes
 	mux.Handle("POST /api/v1/upload/image", authMiddleware.Auth(http.HandlerFunc(uploadHandler.UploadImage)))
@@ -152,11 +228,36 @@ func main() {
 	mux.Handle("POST /api/v1/ai/extract", authMiddleware.Auth(http.HandlerFunc(aiHandler.ExtractFromImage)))
 	mux.Handle("POST /api/v1/ai/enhance", authMiddleware.Auth(http.HandlerFunc(aiHandler.EnhanceRecipe)))
 
+	// Import routes
+	mux.Handle("POST /api/v1/import/url", authMiddleware.Auth(http.HandlerFunc(importHandler.ImportURL)))
+	mux.Handle("POST /api/v1/import/file", authMiddleware.Auth(http.HandlerFunc(importHandler.ImportFile)))
+
+	// Print routes
+	mux.HandleFunc("GET /api/v1/recipes/{id}/pdf", printHandler.GetRecipeCard)
+	mux.HandleFunc("GET /api/v1/categories/{id}/cookbook", printHandler.GetCategoryCookbook)
+	mux.Handle("GET /api/v1/groups/{id}/cookbook", authMiddleware.Auth(http.HandlerFunc(printHandler.GetGroupCookbook)))
+
+	// Admin routes
+	mux.Handle("GET /api/v1/admin/export", authMiddleware.Auth(requireAdmin(http.HandlerFunc(backupHandler.Export))))
+	mux.Handle("POST /api/v1/admin/import", authMiddleware.Auth(requireAdmin(http.HandlerFunc(backupHandler.Import))))
+	mux.Handle("GET /api/v1/admin/comments", authMiddleware.Auth(requireAdmin(http.HandlerFunc(commentHandler.ListRecentComments))))
+
 	// Static file server for uploads
 	fs := http.FileServer(http.Dir(cfg.Storage.LocalPath))
 	mux.Handle("GET /uploads/", http.StripPrefix("/uploads/", fs))
 
-	handler := middleware.Logging(middleware.CORS(mux))
+	// Slug lookups live on their own mux: net/http rejects
+	// "/recipes/slug/{slug}" next to "/recipes/{id}/scaled" and friends as
+	// conflicting patterns, so the root mux hands the prefix over instead.
+	slugMux := http.NewServeMux()
+	slugMux.HandleFunc("GET /api/v1/recipes/slug/{slug}", recipeHandler.GetRecipeBySlug)
+	slugMux.HandleFunc("GET /api/v1/recipes/slug/{slug}/full", recipeHandler.GetFullRecipeBySlug)
+
+	root := http.NewServeMux()
+	root.Handle("/api/v1/recipes/slug/", slugMux)
+	root.Handle("/", mux)
+
+	handler := middleware.Logging(middleware.CORS(root))
 
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Server.Port),
@@ -175,6 +276,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	close(stopPurge)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -184,3 +286,26 @@ func main() {
 
 	log.Println("Server stopped")
 }
+
+// purgeTrash permanently deletes recipes that have been in the trash
+// longer than the configured retention, checking at startup and then on
+// every interval until stop is closed.
+func purgeTrash(trashService *services.TrashService, cfg config.TrashConfig, stop <-chan struct{}) {
+	retention := time.Duration(cfg.RetentionDays) * 24 * time.Hour
+	ticker := time.NewTicker(time.Duration(max(cfg.PurgeIntervalMinutes, 1)) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		if count, err := trashService.PurgeExpired(retention); err != nil {
+			log.Printf("Failed to purge trash: %v", err)
+		} else if count > 0 {
+			log.Printf("Purged %d recipes from the trash", count)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
@@ -13,6 +13,7 @@ type Config struct {
 	AI       AIConfig
 	Storage  StorageConfig
 	Email    EmailConfig
+	Trash    TrashConfig
 }
 
 type ServerConfig struct {
@@ -61,6 +62,11 @@ type EmailConfig struct {
 	From     string
 }
 
+type TrashConfig struct {
+	RetentionDays        int
+	PurgeIntervalMinutes int
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{}
 
@@ -110,6 +116,11 @@ func Load() (*Config, error) {
 		From:     getEnv("EMAIL_FROM", ""),
 	}
 
+	cfg.Trash = TrashConfig{
+		RetentionDays:        getEnvInt("TRASH_RETENTION_DAYS", 30),
+		PurgeIntervalMinutes: getEnvInt("TRASH_PURGE_INTERVAL_MINUTES", 60),
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
@@ -126,6 +137,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("REFRESH_SECRET must be set in production")
 	}
 
+	if c.Trash.RetentionDays < 0 {
+		return fmt.Errorf("TRASH_RETENTION_DAYS must not be negative")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,17 @@
+-- Recipe Ingredients (parsed from the ## Ingredients section of markdown_content)
+CREATE TABLE IF NOT EXISTS recipe_ingredients (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    order_index INT NOT NULL DEFAULT 0,
+    raw_text TEXT NOT NULL,
+    quantity DOUBLE PRECISION,
+    quantity_max DOUBLE PRECISION,
+    unit VARCHAR(32),
+    name TEXT NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_recipe ON recipe_ingredients(recipe_id, order_index);
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_name ON recipe_ingredients(name);
@@ -0,0 +1,19 @@
+-- Recipe Ingredients (SQLite compatible)
+CREATE TABLE IF NOT EXISTS recipe_ingredients (
+    id TEXT PRIMARY KEY,
+    recipe_id TEXT NOT NULL,
+    order_index INTEGER NOT NULL DEFAULT 0,
+    raw_text TEXT NOT NULL,
+    quantity REAL,
+    quantity_max REAL,
+    unit TEXT,
+    name TEXT NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_recipe ON recipe_ingredients(recipe_id, order_index);
+CREATE INDEX IF NOT EXISTS idx_recipe_ingredients_name ON recipe_ingredients(name);
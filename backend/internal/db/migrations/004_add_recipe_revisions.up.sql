@@ -0,0 +1,24 @@
+-- Recipe Revisions (full snapshot of the editable fields after every change)
+CREATE TABLE IF NOT EXISTS recipe_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    revision_number INT NOT NULL,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    title VARCHAR(255) NOT NULL,
+    markdown_content TEXT NOT NULL,
+    category_id UUID,
+    description TEXT,
+    prep_time_minutes INT,
+    cook_time_minutes INT,
+    servings INT,
+    difficulty VARCHAR(20),
+    featured_image_path VARCHAR(500),
+    is_published BOOLEAN DEFAULT false,
+    restored_from INT,
+    created_at TIMESTAMP DEFAULT NOW(),
+
+    CONSTRAINT unique_recipe_revision UNIQUE (recipe_id, revision_number)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipe_revisions_recipe ON recipe_revisions(recipe_id, revision_number DESC);
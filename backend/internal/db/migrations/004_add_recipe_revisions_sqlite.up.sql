@@ -0,0 +1,27 @@
+-- Recipe Revisions (SQLite compatible)
+CREATE TABLE IF NOT EXISTS recipe_revisions (
+    id TEXT PRIMARY KEY,
+    recipe_id TEXT NOT NULL,
+    revision_number INTEGER NOT NULL,
+    author_id TEXT,
+    title TEXT NOT NULL,
+    markdown_content TEXT NOT NULL,
+    category_id TEXT,
+    description TEXT,
+    prep_time_minutes INTEGER,
+    cook_time_minutes INTEGER,
+    servings INTEGER,
+    difficulty TEXT,
+    featured_image_path TEXT,
+    is_published INTEGER DEFAULT 0,
+    restored_from INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL,
+
+    CONSTRAINT unique_recipe_revision UNIQUE (recipe_id, revision_number)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipe_revisions_recipe ON recipe_revisions(recipe_id, revision_number DESC);
@@ -0,0 +1,37 @@
+-- Recipe Search (one document per recipe, ranked with tsvector)
+CREATE TABLE IF NOT EXISTS recipe_search (
+    recipe_id UUID PRIMARY KEY REFERENCES recipes(id) ON DELETE CASCADE,
+    title TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL DEFAULT '',
+    tags TEXT NOT NULL DEFAULT '',
+    category TEXT NOT NULL DEFAULT '',
+    document tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english'::regconfig, title), 'A') ||
+        setweight(to_tsvector('english'::regconfig, tags), 'B') ||
+        setweight(to_tsvector('english'::regconfig, category), 'B') ||
+        setweight(to_tsvector('english'::regconfig, description), 'C') ||
+        setweight(to_tsvector('english'::regconfig, content), 'D')
+    ) STORED
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipe_search_document ON recipe_search USING GIN (document);
+
+-- Backfill existing recipes
+INSERT INTO recipe_search (recipe_id, title, description, content, tags, category)
+SELECT
+    r.id,
+    r.title,
+    COALESCE(r.description, ''),
+    r.markdown_content,
+    COALESCE((
+        SELECT string_agg(t.name, ' ')
+        FROM recipe_tags rt
+        JOIN tags t ON t.id = rt.tag_id
+        WHERE rt.recipe_id = r.id
+    ), ''),
+    COALESCE(c.name, '')
+FROM recipes r
+LEFT JOIN categories c ON c.id = r.category_id
+ON CONFLICT (recipe_id) DO NOTHING;
@@ -0,0 +1,30 @@
+-- Recipe Search documents (SQLite compatible)
+-- The FTS5 index over this table lives in 014_add_recipe_search_fts5_sqlite.up.sql
+-- and needs a SQLite build with FTS5 enabled.
+CREATE TABLE IF NOT EXISTS recipe_search (
+    recipe_id TEXT PRIMARY KEY,
+    title TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL DEFAULT '',
+    tags TEXT NOT NULL DEFAULT '',
+    category TEXT NOT NULL DEFAULT '',
+
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE
+);
+
+-- Backfill existing recipes
+INSERT OR IGNORE INTO recipe_search (recipe_id, title, description, content, tags, category)
+SELECT
+    r.id,
+    r.title,
+    COALESCE(r.description, ''),
+    r.markdown_content,
+    COALESCE((
+        SELECT group_concat(t.name, ' ')
+        FROM recipe_tags rt
+        JOIN tags t ON t.id = rt.tag_id
+        WHERE rt.recipe_id = r.id
+    ), ''),
+    COALESCE(c.name, '')
+FROM recipes r
+LEFT JOIN categories c ON c.id = r.category_id;
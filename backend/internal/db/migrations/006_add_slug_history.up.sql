@@ -0,0 +1,9 @@
+-- Slug History (former recipe slugs, so old links redirect after a rename)
+CREATE TABLE IF NOT EXISTS slug_history (
+    slug VARCHAR(255) PRIMARY KEY,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_slug_history_recipe ON slug_history(recipe_id);
@@ -0,0 +1,11 @@
+-- Slug History (SQLite compatible)
+CREATE TABLE IF NOT EXISTS slug_history (
+    slug TEXT PRIMARY KEY,
+    recipe_id TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_slug_history_recipe ON slug_history(recipe_id);
@@ -0,0 +1,18 @@
+-- Recipe Dietary (allergens and diets classified from the ingredient list)
+CREATE TABLE IF NOT EXISTS recipe_dietary (
+    recipe_id UUID PRIMARY KEY REFERENCES recipes(id) ON DELETE CASCADE,
+    gluten BOOLEAN NOT NULL DEFAULT false,
+    dairy BOOLEAN NOT NULL DEFAULT false,
+    egg BOOLEAN NOT NULL DEFAULT false,
+    nuts BOOLEAN NOT NULL DEFAULT false,
+    peanuts BOOLEAN NOT NULL DEFAULT false,
+    shellfish BOOLEAN NOT NULL DEFAULT false,
+    soy BOOLEAN NOT NULL DEFAULT false,
+    sesame BOOLEAN NOT NULL DEFAULT false,
+    vegetarian BOOLEAN NOT NULL DEFAULT false,
+    vegan BOOLEAN NOT NULL DEFAULT false,
+    gluten_free BOOLEAN NOT NULL DEFAULT false,
+    dairy_free BOOLEAN NOT NULL DEFAULT false,
+    nut_free BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,20 @@
+-- Recipe Dietary (SQLite compatible)
+CREATE TABLE IF NOT EXISTS recipe_dietary (
+    recipe_id TEXT PRIMARY KEY,
+    gluten INTEGER NOT NULL DEFAULT 0,
+    dairy INTEGER NOT NULL DEFAULT 0,
+    egg INTEGER NOT NULL DEFAULT 0,
+    nuts INTEGER NOT NULL DEFAULT 0,
+    peanuts INTEGER NOT NULL DEFAULT 0,
+    shellfish INTEGER NOT NULL DEFAULT 0,
+    soy INTEGER NOT NULL DEFAULT 0,
+    sesame INTEGER NOT NULL DEFAULT 0,
+    vegetarian INTEGER NOT NULL DEFAULT 0,
+    vegan INTEGER NOT NULL DEFAULT 0,
+    gluten_free INTEGER NOT NULL DEFAULT 0,
+    dairy_free INTEGER NOT NULL DEFAULT 0,
+    nut_free INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE
+);
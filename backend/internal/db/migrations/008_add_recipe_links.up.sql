@@ -0,0 +1,13 @@
+-- Recipe Links ([[recipe:slug]] references from one recipe to another)
+CREATE TABLE IF NOT EXISTS recipe_links (
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    slug VARCHAR(255) NOT NULL,
+    linked_recipe_id UUID REFERENCES recipes(id) ON DELETE SET NULL,
+    order_index INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (recipe_id, slug)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipe_links_linked ON recipe_links(linked_recipe_id);
+CREATE INDEX IF NOT EXISTS idx_recipe_links_slug ON recipe_links(slug);
@@ -0,0 +1,16 @@
+-- Recipe Links (SQLite compatible)
+CREATE TABLE IF NOT EXISTS recipe_links (
+    recipe_id TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    linked_recipe_id TEXT,
+    order_index INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (recipe_id, slug),
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE,
+    FOREIGN KEY (linked_recipe_id) REFERENCES recipes(id) ON DELETE SET NULL
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipe_links_linked ON recipe_links(linked_recipe_id);
+CREATE INDEX IF NOT EXISTS idx_recipe_links_slug ON recipe_links(slug);
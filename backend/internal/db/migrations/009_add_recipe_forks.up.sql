@@ -0,0 +1,9 @@
+-- Recipe Forks (recipes copied from another recipe into a new one)
+CREATE TABLE IF NOT EXISTS recipe_forks (
+    recipe_id UUID PRIMARY KEY REFERENCES recipes(id) ON DELETE CASCADE,
+    forked_from_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipe_forks_forked_from ON recipe_forks(forked_from_id);
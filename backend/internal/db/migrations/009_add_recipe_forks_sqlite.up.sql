@@ -0,0 +1,12 @@
+-- Recipe Forks (SQLite compatible)
+CREATE TABLE IF NOT EXISTS recipe_forks (
+    recipe_id TEXT PRIMARY KEY,
+    forked_from_id TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE,
+    FOREIGN KEY (forked_from_id) REFERENCES recipes(id) ON DELETE CASCADE
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipe_forks_forked_from ON recipe_forks(forked_from_id);
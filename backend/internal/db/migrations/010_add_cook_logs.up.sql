@@ -0,0 +1,23 @@
+-- Cook Logs (each time a user made a recipe)
+CREATE TABLE IF NOT EXISTS cook_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    variation_id UUID REFERENCES recipe_variations(id) ON DELETE SET NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    cooked_on DATE NOT NULL,
+    rating INT CHECK (rating BETWEEN 1 AND 5),
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS cook_log_photos (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    cook_log_id UUID NOT NULL REFERENCES cook_logs(id) ON DELETE CASCADE,
+    file_path VARCHAR(500) NOT NULL,
+    order_index INT DEFAULT 0
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_cook_logs_recipe ON cook_logs(recipe_id, cooked_on DESC);
+CREATE INDEX IF NOT EXISTS idx_cook_logs_user ON cook_logs(user_id, cooked_on DESC);
+CREATE INDEX IF NOT EXISTS idx_cook_log_photos_log ON cook_log_photos(cook_log_id, order_index);
@@ -0,0 +1,29 @@
+-- Cook Logs (SQLite compatible)
+CREATE TABLE IF NOT EXISTS cook_logs (
+    id TEXT PRIMARY KEY,
+    recipe_id TEXT NOT NULL,
+    variation_id TEXT,
+    user_id TEXT NOT NULL,
+    cooked_on DATE NOT NULL,
+    rating INTEGER CHECK (rating BETWEEN 1 AND 5),
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE,
+    FOREIGN KEY (variation_id) REFERENCES recipe_variations(id) ON DELETE SET NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS cook_log_photos (
+    id TEXT PRIMARY KEY,
+    cook_log_id TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    order_index INTEGER DEFAULT 0,
+
+    FOREIGN KEY (cook_log_id) REFERENCES cook_logs(id) ON DELETE CASCADE
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_cook_logs_recipe ON cook_logs(recipe_id, cooked_on DESC);
+CREATE INDEX IF NOT EXISTS idx_cook_logs_user ON cook_logs(user_id, cooked_on DESC);
+CREATE INDEX IF NOT EXISTS idx_cook_log_photos_log ON cook_log_photos(cook_log_id, order_index);
@@ -0,0 +1,32 @@
+-- Favorites (recipes each user has starred)
+CREATE TABLE IF NOT EXISTS recipe_favorites (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (user_id, recipe_id)
+);
+
+-- Collections (user-owned recipe lists, unlike the admin-curated recipe_groups)
+CREATE TABLE IF NOT EXISTS collections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    visibility VARCHAR(20) NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'shared')),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS collection_recipes (
+    collection_id UUID NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    recipe_id UUID NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    order_index INT NOT NULL DEFAULT 0,
+    added_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (collection_id, recipe_id)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipe_favorites_user ON recipe_favorites(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_collections_owner ON collections(owner_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_collections_visibility ON collections(visibility, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_collection_recipes_order ON collection_recipes(collection_id, order_index);
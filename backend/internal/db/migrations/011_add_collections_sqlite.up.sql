@@ -0,0 +1,39 @@
+-- Favorites and Collections (SQLite compatible)
+CREATE TABLE IF NOT EXISTS recipe_favorites (
+    user_id TEXT NOT NULL,
+    recipe_id TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, recipe_id),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id TEXT PRIMARY KEY,
+    owner_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    visibility TEXT NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'shared')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS collection_recipes (
+    collection_id TEXT NOT NULL,
+    recipe_id TEXT NOT NULL,
+    order_index INTEGER NOT NULL DEFAULT 0,
+    added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (collection_id, recipe_id),
+
+    FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipe_favorites_user ON recipe_favorites(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_collections_owner ON collections(owner_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_collections_visibility ON collections(visibility, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_collection_recipes_order ON collection_recipes(collection_id, order_index);
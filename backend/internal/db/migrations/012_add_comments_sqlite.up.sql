@@ -0,0 +1,27 @@
+-- Comments (SQLite compatible)
+CREATE TABLE IF NOT EXISTS comments (
+    id TEXT PRIMARY KEY,
+    recipe_id TEXT NOT NULL,
+    variation_id TEXT,
+    parent_id TEXT,
+    thread_id TEXT,
+    author_id TEXT,
+    body TEXT NOT NULL,
+    is_deleted BOOLEAN NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    edited_at TIMESTAMP,
+
+    FOREIGN KEY (recipe_id) REFERENCES recipes(id) ON DELETE CASCADE,
+    FOREIGN KEY (variation_id) REFERENCES recipe_variations(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (thread_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_comments_recipe ON comments(recipe_id, created_at DESC) WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_variation ON comments(variation_id, created_at DESC) WHERE parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_comments_thread ON comments(thread_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_parent ON comments(parent_id);
+CREATE INDEX IF NOT EXISTS idx_comments_created ON comments(created_at DESC);
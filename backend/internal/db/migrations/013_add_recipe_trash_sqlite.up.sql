@@ -0,0 +1,6 @@
+-- Recipe Trash (SQLite compatible)
+ALTER TABLE recipes ADD COLUMN deleted_at TIMESTAMP;
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_recipes_deleted ON recipes(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_recipes_trash ON recipes(author_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,33 @@
+-- Recipe Search full-text index (SQLite with FTS5)
+-- External-content FTS5 table kept in sync with recipe_search by triggers.
+-- Builds of SQLite without FTS5 skip this file and fall back to LIKE matching.
+CREATE VIRTUAL TABLE IF NOT EXISTS recipe_search_fts USING fts5(
+    title,
+    description,
+    content,
+    tags,
+    category,
+    content='recipe_search',
+    content_rowid='rowid',
+    tokenize='porter unicode61'
+);
+
+CREATE TRIGGER IF NOT EXISTS recipe_search_ai AFTER INSERT ON recipe_search BEGIN
+    INSERT INTO recipe_search_fts (rowid, title, description, content, tags, category)
+    VALUES (new.rowid, new.title, new.description, new.content, new.tags, new.category);
+END;
+
+CREATE TRIGGER IF NOT EXISTS recipe_search_ad AFTER DELETE ON recipe_search BEGIN
+    INSERT INTO recipe_search_fts (recipe_search_fts, rowid, title, description, content, tags, category)
+    VALUES ('delete', old.rowid, old.title, old.description, old.content, old.tags, old.category);
+END;
+
+CREATE TRIGGER IF NOT EXISTS recipe_search_au AFTER UPDATE ON recipe_search BEGIN
+    INSERT INTO recipe_search_fts (recipe_search_fts, rowid, title, description, content, tags, category)
+    VALUES ('delete', old.rowid, old.title, old.description, old.content, old.tags, old.category);
+    INSERT INTO recipe_search_fts (rowid, title, description, content, tags, category)
+    VALUES (new.rowid, new.title, new.description, new.content, new.tags, new.category);
+END;
+
+-- Index rows that were backfilled before the triggers existed
+INSERT INTO recipe_search_fts (recipe_search_fts) VALUES ('rebuild');
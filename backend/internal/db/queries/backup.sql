@@ -0,0 +1,110 @@
+-- name: ListAllUsers :many
+SELECT * FROM users
+ORDER BY created_at, id;
+
+-- name: RestoreUser :exec
+INSERT INTO users (id, email, password_hash, role, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListAllTags :many
+SELECT * FROM tags
+ORDER BY created_at, id;
+
+-- name: ListAllRecipeGroups :many
+SELECT * FROM recipe_groups
+ORDER BY created_at, id;
+
+-- name: ListAllRecipeGroupings :many
+SELECT * FROM recipe_groupings
+ORDER BY group_id, order_index;
+
+-- name: ListAllRecipes :many
+SELECT * FROM recipes
+ORDER BY created_at, id;
+
+-- name: RestoreRecipe :exec
+INSERT INTO recipes (id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at, deleted_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17);
+
+-- name: ListAllRecipeTags :many
+SELECT * FROM recipe_tags
+ORDER BY recipe_id, tag_id;
+
+-- name: ListAllVariations :many
+SELECT * FROM recipe_variations
+ORDER BY created_at, id;
+
+-- name: RestoreVariation :execrows
+INSERT INTO recipe_variations (id, recipe_id, author_id, markdown_content, prep_time_minutes, cook_time_minutes, servings, difficulty, notes, is_published, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT DO NOTHING;
+
+-- name: ListAllShareCodes :many
+SELECT * FROM share_codes
+ORDER BY created_at, id;
+
+-- name: RestoreShareCode :execrows
+INSERT INTO share_codes (id, recipe_id, code, expires_at, max_uses, use_count, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT DO NOTHING;
+
+-- name: ListAllRecipeImages :many
+SELECT * FROM recipe_images
+ORDER BY recipe_id, order_index, id;
+
+-- name: RestoreRecipeImage :exec
+INSERT INTO recipe_images (id, recipe_id, file_path, webp_path, thumbnail_path, caption, order_index, uploaded_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListAllRecipeRevisions :many
+SELECT * FROM recipe_revisions
+ORDER BY recipe_id, revision_number;
+
+-- name: RestoreRecipeRevision :exec
+INSERT INTO recipe_revisions (id, recipe_id, revision_number, author_id, title, markdown_content, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, restored_from, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16);
+
+-- name: ListAllCookLogs :many
+SELECT * FROM cook_logs
+ORDER BY cooked_on, created_at, id;
+
+-- name: ListAllCookLogPhotos :many
+SELECT * FROM cook_log_photos
+ORDER BY cook_log_id, order_index;
+
+-- name: RestoreCookLog :exec
+INSERT INTO cook_logs (id, recipe_id, variation_id, user_id, cooked_on, rating, notes, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListAllCollections :many
+SELECT * FROM collections
+ORDER BY created_at, id;
+
+-- name: ListAllCollectionRecipes :many
+SELECT * FROM collection_recipes
+ORDER BY collection_id, order_index;
+
+-- name: ListAllRecipeFavorites :many
+SELECT * FROM recipe_favorites
+ORDER BY user_id, created_at;
+
+-- name: RestoreCollection :exec
+INSERT INTO collections (id, owner_id, name, description, visibility, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: RestoreCollectionRecipe :exec
+INSERT INTO collection_recipes (collection_id, recipe_id, order_index, added_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: RestoreRecipeFavorite :execrows
+INSERT INTO recipe_favorites (user_id, recipe_id, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: ListAllComments :many
+SELECT * FROM comments
+ORDER BY created_at, id;
+
+-- name: RestoreComment :exec
+INSERT INTO comments (id, recipe_id, variation_id, parent_id, thread_id, author_id, body, is_deleted, created_at, updated_at, edited_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
@@ -0,0 +1,61 @@
+-- name: CreateCollection :one
+INSERT INTO collections (id, owner_id, name, description, visibility)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetCollectionByID :one
+SELECT * FROM collections
+WHERE id = $1 LIMIT 1;
+
+-- name: ListVisibleCollections :many
+SELECT * FROM collections
+WHERE (owner_id = sqlc.arg('user_id') OR visibility = 'shared')
+  AND (sqlc.narg('after_id') IS NULL
+   OR (created_at, id) < (sqlc.narg('after_created_at'), sqlc.narg('after_id')))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountVisibleCollections :one
+SELECT COUNT(*) FROM collections
+WHERE owner_id = $1 OR visibility = 'shared';
+
+-- name: UpdateCollection :one
+UPDATE collections
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    description = COALESCE(sqlc.narg('description'), description),
+    visibility = COALESCE(sqlc.narg('visibility'), visibility),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: DeleteCollection :exec
+DELETE FROM collections WHERE id = $1;
+
+-- name: AddRecipeToCollection :exec
+INSERT INTO collection_recipes (collection_id, recipe_id, order_index)
+VALUES ($1, $2, (SELECT COALESCE(MAX(order_index) + 1, 0) FROM collection_recipes WHERE collection_id = $1))
+ON CONFLICT (collection_id, recipe_id) DO NOTHING;
+
+-- name: RemoveRecipeFromCollection :exec
+DELETE FROM collection_recipes
+WHERE collection_id = $1 AND recipe_id = $2;
+
+-- name: SetCollectionRecipeOrder :exec
+UPDATE collection_recipes
+SET order_index = sqlc.arg('order_index')
+WHERE collection_id = sqlc.arg('collection_id') AND recipe_id = sqlc.arg('recipe_id');
+
+-- name: ListCollectionRecipeIDs :many
+SELECT recipe_id FROM collection_recipes
+WHERE collection_id = $1
+ORDER BY order_index;
+
+-- name: ListCollectionRecipes :many
+SELECT r.*
+FROM recipes r
+JOIN collection_recipes cr ON cr.recipe_id = r.id
+WHERE cr.collection_id = sqlc.arg('collection_id')
+  AND (r.is_published = true OR r.author_id = sqlc.arg('viewer_id'))
+  AND r.deleted_at IS NULL
+ORDER BY cr.order_index;
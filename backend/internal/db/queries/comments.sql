@@ -0,0 +1,72 @@
+-- name: CreateComment :one
+INSERT INTO comments (id, recipe_id, variation_id, parent_id, thread_id, author_id, body)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetCommentByID :one
+SELECT * FROM comments
+WHERE id = $1 LIMIT 1;
+
+-- name: UpdateCommentBody :one
+UPDATE comments
+SET body = sqlc.arg('body'),
+    edited_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: MarkCommentDeleted :exec
+UPDATE comments
+SET body = '',
+    is_deleted = true,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteComment :exec
+DELETE FROM comments WHERE id = $1;
+
+-- name: CountCommentReplies :one
+SELECT COUNT(*) FROM comments WHERE parent_id = $1;
+
+-- name: ListRecipeComments :many
+SELECT * FROM comments
+WHERE recipe_id = sqlc.arg('recipe_id')
+  AND variation_id IS NULL
+  AND parent_id IS NULL
+  AND (sqlc.narg('after_id') IS NULL
+   OR (created_at, id) < (sqlc.narg('after_created_at'), sqlc.narg('after_id')))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountRecipeComments :one
+SELECT COUNT(*) FROM comments
+WHERE recipe_id = $1 AND variation_id IS NULL AND parent_id IS NULL;
+
+-- name: ListVariationComments :many
+SELECT * FROM comments
+WHERE variation_id = sqlc.arg('variation_id')
+  AND parent_id IS NULL
+  AND (sqlc.narg('after_id') IS NULL
+   OR (created_at, id) < (sqlc.narg('after_created_at'), sqlc.narg('after_id')))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountVariationComments :one
+SELECT COUNT(*) FROM comments
+WHERE variation_id = $1 AND parent_id IS NULL;
+
+-- name: ListThreadReplies :many
+SELECT * FROM comments
+WHERE thread_id = $1
+ORDER BY created_at, id;
+
+-- name: ListRecentComments :many
+SELECT * FROM comments
+WHERE is_deleted = false
+  AND (sqlc.narg('after_id') IS NULL
+   OR (created_at, id) < (sqlc.narg('after_created_at'), sqlc.narg('after_id')))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountRecentComments :one
+SELECT COUNT(*) FROM comments WHERE is_deleted = false;
@@ -0,0 +1,53 @@
+-- name: CreateCookLog :one
+INSERT INTO cook_logs (id, recipe_id, variation_id, user_id, cooked_on, rating, notes)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetCookLogByID :one
+SELECT * FROM cook_logs
+WHERE id = $1 LIMIT 1;
+
+-- name: DeleteCookLog :exec
+DELETE FROM cook_logs WHERE id = $1;
+
+-- name: CreateCookLogPhoto :exec
+INSERT INTO cook_log_photos (id, cook_log_id, file_path, order_index)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListCookLogPhotos :many
+SELECT file_path FROM cook_log_photos
+WHERE cook_log_id = $1
+ORDER BY order_index;
+
+-- name: ListRecipeCookLogs :many
+SELECT * FROM cook_logs
+WHERE recipe_id = sqlc.arg('recipe_id')
+  AND (sqlc.narg('after_id') IS NULL
+   OR (cooked_on, created_at, id) < (sqlc.narg('after_cooked_on'), sqlc.narg('after_created_at'), sqlc.narg('after_id')))
+ORDER BY cooked_on DESC, created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountRecipeCookLogs :one
+SELECT COUNT(*) FROM cook_logs WHERE recipe_id = $1;
+
+-- name: ListUserCookLogs :many
+SELECT l.id, l.recipe_id, l.variation_id, l.user_id, l.cooked_on, l.rating, l.notes, l.created_at,
+       r.title AS recipe_title, r.slug AS recipe_slug
+FROM cook_logs l
+JOIN recipes r ON r.id = l.recipe_id
+WHERE l.user_id = sqlc.arg('user_id')
+  AND r.deleted_at IS NULL
+  AND (sqlc.narg('after_id') IS NULL
+   OR (l.cooked_on, l.created_at, l.id) < (sqlc.narg('after_cooked_on'), sqlc.narg('after_created_at'), sqlc.narg('after_id')))
+ORDER BY l.cooked_on DESC, l.created_at DESC, l.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountUserCookLogs :one
+SELECT COUNT(*) FROM cook_logs l
+JOIN recipes r ON r.id = l.recipe_id
+WHERE l.user_id = $1 AND r.deleted_at IS NULL;
+
+-- name: ListRecipeCookRatings :many
+SELECT variation_id, rating, cooked_on FROM cook_logs
+WHERE recipe_id = $1
+ORDER BY cooked_on, created_at;
@@ -0,0 +1,33 @@
+-- name: AddFavorite :exec
+INSERT INTO recipe_favorites (user_id, recipe_id)
+VALUES ($1, $2)
+ON CONFLICT (user_id, recipe_id) DO NOTHING;
+
+-- name: RemoveFavorite :exec
+DELETE FROM recipe_favorites
+WHERE user_id = $1 AND recipe_id = $2;
+
+-- name: ListFavoriteRecipes :many
+SELECT r.*, f.created_at AS favorited_at
+FROM recipes r
+JOIN recipe_favorites f ON f.recipe_id = r.id
+WHERE f.user_id = sqlc.arg('user_id')
+  AND (r.is_published = true OR r.author_id = sqlc.arg('user_id'))
+  AND r.deleted_at IS NULL
+  AND (sqlc.narg('after_id') IS NULL
+   OR (f.created_at, f.recipe_id) < (sqlc.narg('after_created_at'), sqlc.narg('after_id')))
+ORDER BY f.created_at DESC, f.recipe_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountFavoriteRecipes :one
+SELECT COUNT(*)
+FROM recipes r
+JOIN recipe_favorites f ON f.recipe_id = r.id
+WHERE f.user_id = $1
+  AND (r.is_published = true OR r.author_id = $1)
+  AND r.deleted_at IS NULL;
+
+-- name: IsFavorite :one
+SELECT EXISTS (
+    SELECT 1 FROM recipe_favorites WHERE user_id = $1 AND recipe_id = $2
+);
@@ -0,0 +1,28 @@
+-- name: UpsertRecipeDietary :exec
+INSERT INTO recipe_dietary (recipe_id, gluten, dairy, egg, nuts, peanuts, shellfish, soy, sesame, vegetarian, vegan, gluten_free, dairy_free, nut_free)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+ON CONFLICT (recipe_id) DO UPDATE SET
+    gluten = EXCLUDED.gluten,
+    dairy = EXCLUDED.dairy,
+    egg = EXCLUDED.egg,
+    nuts = EXCLUDED.nuts,
+    peanuts = EXCLUDED.peanuts,
+    shellfish = EXCLUDED.shellfish,
+    soy = EXCLUDED.soy,
+    sesame = EXCLUDED.sesame,
+    vegetarian = EXCLUDED.vegetarian,
+    vegan = EXCLUDED.vegan,
+    gluten_free = EXCLUDED.gluten_free,
+    dairy_free = EXCLUDED.dairy_free,
+    nut_free = EXCLUDED.nut_free,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetRecipeDietary :one
+SELECT * FROM recipe_dietary
+WHERE recipe_id = $1 LIMIT 1;
+
+-- name: ListRecipesWithoutDietary :many
+SELECT r.id, r.markdown_content FROM recipes r
+LEFT JOIN recipe_dietary d ON d.recipe_id = r.id
+WHERE d.recipe_id IS NULL
+ORDER BY r.id;
@@ -0,0 +1,28 @@
+-- name: CreateRecipeFork :exec
+INSERT INTO recipe_forks (recipe_id, forked_from_id)
+VALUES ($1, $2);
+
+-- name: GetRecipeForkedFrom :one
+SELECT forked_from_id FROM recipe_forks
+WHERE recipe_id = $1 LIMIT 1;
+
+-- name: ListRecipeForks :many
+SELECT r.id, r.slug, r.title
+FROM recipe_forks f
+JOIN recipes r ON r.id = f.recipe_id
+WHERE f.forked_from_id = $1 AND r.is_published = true AND r.deleted_at IS NULL
+ORDER BY f.created_at, r.id;
+
+-- name: CopyRecipeTags :exec
+INSERT INTO recipe_tags (recipe_id, tag_id)
+SELECT sqlc.arg('fork_id'), tag_id FROM recipe_tags
+WHERE recipe_tags.recipe_id = sqlc.arg('recipe_id');
+
+-- name: CopyRecipeGroupings :exec
+INSERT INTO recipe_groupings (group_id, recipe_id, order_index)
+SELECT group_id, sqlc.arg('fork_id'), order_index FROM recipe_groupings
+WHERE recipe_groupings.recipe_id = sqlc.arg('recipe_id');
+
+-- name: CopyRecipeImage :exec
+INSERT INTO recipe_images (id, recipe_id, file_path, webp_path, thumbnail_path, caption, order_index)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
@@ -13,7 +13,13 @@ WHERE slug = $1 LIMIT 1;
 
 -- name: ListRecipeGroups :many
 SELECT * FROM recipe_groups
-ORDER BY created_at DESC;
+WHERE sqlc.narg('after_id') IS NULL
+   OR (created_at, id) < (sqlc.narg('after_created_at'), sqlc.narg('after_id'))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountRecipeGroups :one
+SELECT COUNT(*) FROM recipe_groups;
 
 -- name: UpdateRecipeGroup :one
 UPDATE recipe_groups
@@ -43,6 +49,7 @@ FROM recipes r
 JOIN recipe_groupings rg ON r.id = rg.recipe_id
 WHERE rg.group_id = $1
   AND r.is_published = true
+  AND r.deleted_at IS NULL
 ORDER BY rg.order_index;
 
 -- name: GetRecipeGroupWithRecipes :one
@@ -63,7 +70,7 @@ SELECT
     ) as recipes
 FROM recipe_groups g
 LEFT JOIN recipe_groupings rg ON g.id = rg.group_id
-LEFT JOIN recipes r ON rg.recipe_id = r.id
+LEFT JOIN recipes r ON rg.recipe_id = r.id AND r.deleted_at IS NULL
 WHERE g.id = $1
 GROUP BY g.id;
 
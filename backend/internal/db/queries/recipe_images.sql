@@ -41,5 +41,5 @@ SELECT
     ) as body_images
 FROM recipes r
 LEFT JOIN recipe_images ri ON r.id = ri.recipe_id
-WHERE r.id = $1
+WHERE r.id = $1 AND r.deleted_at IS NULL
 GROUP BY r.id;
@@ -0,0 +1,12 @@
+-- name: CreateRecipeIngredient :one
+INSERT INTO recipe_ingredients (id, recipe_id, order_index, raw_text, quantity, quantity_max, unit, name, note)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetRecipeIngredients :many
+SELECT * FROM recipe_ingredients
+WHERE recipe_id = $1
+ORDER BY order_index;
+
+-- name: DeleteRecipeIngredients :exec
+DELETE FROM recipe_ingredients WHERE recipe_id = $1;
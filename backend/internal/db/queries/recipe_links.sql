@@ -0,0 +1,24 @@
+-- name: CreateRecipeLink :exec
+INSERT INTO recipe_links (recipe_id, slug, linked_recipe_id, order_index)
+VALUES ($1, $2, $3, $4);
+
+-- name: DeleteRecipeLinks :exec
+DELETE FROM recipe_links WHERE recipe_id = $1;
+
+-- name: ResolveRecipeLinks :exec
+UPDATE recipe_links SET linked_recipe_id = $1
+WHERE slug = $2 AND linked_recipe_id IS NULL;
+
+-- name: GetRecipeLinks :many
+SELECT l.slug, l.linked_recipe_id, r.slug AS linked_slug, r.title AS linked_title
+FROM recipe_links l
+LEFT JOIN recipes r ON r.id = l.linked_recipe_id AND r.deleted_at IS NULL
+WHERE l.recipe_id = $1
+ORDER BY l.order_index;
+
+-- name: GetRecipeBacklinks :many
+SELECT DISTINCT r.id, r.slug, r.title
+FROM recipe_links l
+JOIN recipes r ON r.id = l.recipe_id
+WHERE l.linked_recipe_id = $1 AND r.is_published = true AND r.deleted_at IS NULL
+ORDER BY r.title;
@@ -0,0 +1,22 @@
+-- name: CreateRecipeRevision :one
+INSERT INTO recipe_revisions (
+    id, recipe_id, revision_number, author_id, title, markdown_content, category_id, description,
+    prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, restored_from
+)
+VALUES (
+    $1, $2, (SELECT COALESCE(MAX(revision_number), 0) + 1 FROM recipe_revisions WHERE recipe_id = $2), $3, $4, $5, $6, $7,
+    $8, $9, $10, $11, $12, $13, $14
+)
+RETURNING *;
+
+-- name: GetRecipeRevision :one
+SELECT * FROM recipe_revisions
+WHERE recipe_id = $1 AND revision_number = $2;
+
+-- name: ListRecipeRevisions :many
+SELECT * FROM recipe_revisions
+WHERE recipe_id = $1
+ORDER BY revision_number DESC;
+
+-- name: CountRecipeRevisions :one
+SELECT COUNT(*) FROM recipe_revisions WHERE recipe_id = $1;
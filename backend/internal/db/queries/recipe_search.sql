@@ -0,0 +1,21 @@
+-- name: UpsertRecipeSearchDocument :exec
+INSERT INTO recipe_search (recipe_id, title, description, content, tags, category)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (recipe_id) DO UPDATE SET
+    title = excluded.title,
+    description = excluded.description,
+    content = excluded.content,
+    tags = excluded.tags,
+    category = excluded.category;
+
+-- name: DeleteRecipeSearchDocument :exec
+DELETE FROM recipe_search WHERE recipe_id = $1;
+
+-- name: ListRecipeIDsWithTag :many
+SELECT rt.recipe_id FROM recipe_tags rt
+JOIN recipes r ON r.id = rt.recipe_id
+WHERE rt.tag_id = $1 AND r.deleted_at IS NULL;
+
+-- name: ListRecipeIDsInCategory :many
+SELECT id FROM recipes
+WHERE category_id = $1 AND deleted_at IS NULL;
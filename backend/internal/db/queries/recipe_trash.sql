@@ -0,0 +1,61 @@
+-- name: TrashRecipe :exec
+UPDATE recipes
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreTrashedRecipe :exec
+UPDATE recipes
+SET deleted_at = NULL
+WHERE id = $1;
+
+-- name: GetTrashedRecipeByID :one
+SELECT * FROM recipes
+WHERE id = $1 AND deleted_at IS NOT NULL LIMIT 1;
+
+-- name: ListTrashedRecipes :many
+SELECT * FROM recipes
+WHERE author_id = sqlc.arg('author_id')
+  AND deleted_at IS NOT NULL
+  AND (sqlc.narg('after_id') IS NULL
+   OR (deleted_at, id) < (sqlc.narg('after_deleted_at'), sqlc.narg('after_id')))
+ORDER BY deleted_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountTrashedRecipes :one
+SELECT COUNT(*) FROM recipes
+WHERE author_id = $1 AND deleted_at IS NOT NULL;
+
+-- name: ListRecipesTrashedBefore :many
+SELECT * FROM recipes
+WHERE deleted_at IS NOT NULL AND deleted_at < $1
+ORDER BY deleted_at, id;
+
+-- name: PurgeTrashedRecipe :execrows
+DELETE FROM recipes
+WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at < $2;
+
+-- name: ListRecipeUploadReferences :many
+SELECT COALESCE(featured_image_path, '') || ' ' || markdown_content AS reference FROM recipes WHERE id = $1
+UNION ALL
+SELECT COALESCE(featured_image_path, '') || ' ' || markdown_content FROM recipe_revisions WHERE recipe_id = $1
+UNION ALL
+SELECT file_path || ' ' || COALESCE(webp_path, '') || ' ' || COALESCE(thumbnail_path, '') FROM recipe_images WHERE recipe_id = $1
+UNION ALL
+SELECT markdown_content FROM recipe_variations WHERE recipe_id = $1
+UNION ALL
+SELECT p.file_path FROM cook_log_photos p
+JOIN cook_logs l ON l.id = p.cook_log_id
+WHERE l.recipe_id = $1;
+
+-- name: CountUploadReferences :one
+SELECT COUNT(*) FROM (
+    SELECT id FROM recipes WHERE featured_image_path LIKE sqlc.arg('pattern') OR markdown_content LIKE sqlc.arg('pattern')
+    UNION ALL
+    SELECT id FROM recipe_revisions WHERE featured_image_path LIKE sqlc.arg('pattern') OR markdown_content LIKE sqlc.arg('pattern')
+    UNION ALL
+    SELECT id FROM recipe_images WHERE file_path LIKE sqlc.arg('pattern') OR webp_path LIKE sqlc.arg('pattern') OR thumbnail_path LIKE sqlc.arg('pattern')
+    UNION ALL
+    SELECT id FROM recipe_variations WHERE markdown_content LIKE sqlc.arg('pattern')
+    UNION ALL
+    SELECT id FROM cook_log_photos WHERE file_path LIKE sqlc.arg('pattern')
+) refs;
@@ -1,11 +1,19 @@
 -- name: GetRecipeByID :one
 SELECT * FROM recipes
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: GetRecipeBySlug :one
 SELECT * FROM recipes
+WHERE slug = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetRecipeIDBySlug :one
+SELECT id FROM recipes
 WHERE slug = $1 LIMIT 1;
 
+-- name: GetRecipeSlugByID :one
+SELECT slug FROM recipes
+WHERE id = $1 LIMIT 1;
+
 -- name: CreateRecipe :one
 INSERT INTO recipes (id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
@@ -15,6 +23,7 @@ RETURNING *;
 UPDATE recipes
 SET
     title = COALESCE(sqlc.narg('title'), title),
+    slug = COALESCE(sqlc.narg('slug'), slug),
     markdown_content = COALESCE(sqlc.narg('markdown_content'), markdown_content),
     category_id = COALESCE(sqlc.narg('category_id'), category_id),
     description = COALESCE(sqlc.narg('description'), description),
@@ -28,6 +37,23 @@ SET
 WHERE id = $1
 RETURNING *;
 
+-- name: ReplaceRecipeContent :one
+UPDATE recipes
+SET
+    title = sqlc.arg('title'),
+    slug = sqlc.arg('slug'),
+    markdown_content = sqlc.arg('markdown_content'),
+    category_id = sqlc.arg('category_id'),
+    description = sqlc.arg('description'),
+    prep_time_minutes = sqlc.arg('prep_time_minutes'),
+    cook_time_minutes = sqlc.arg('cook_time_minutes'),
+    servings = sqlc.arg('servings'),
+    difficulty = sqlc.arg('difficulty'),
+    featured_image_path = sqlc.arg('featured_image_path'),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
 -- name: UpdateRecipePublishedStatus :one
 UPDATE recipes
 SET
@@ -43,6 +69,7 @@ DELETE FROM recipes WHERE id = $1;
 -- name: ListRecipes :many
 SELECT * FROM recipes
 WHERE is_published = true
+  AND deleted_at IS NULL
 ORDER BY published_at DESC
 LIMIT $1 OFFSET $2;
 
@@ -50,19 +77,14 @@ LIMIT $1 OFFSET $2;
 SELECT * FROM recipes
 WHERE is_published = true
   AND category_id = $1
-ORDER BY published_at DESC
-LIMIT $2 OFFSET $3;
-
--- name: SearchRecipes :many
-SELECT * FROM recipes
-WHERE is_published = true
-  AND (title ILIKE '%' || $1 || '%' OR description ILIKE '%' || $1 || '%')
+  AND deleted_at IS NULL
 ORDER BY published_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: ListRecipesByAuthor :many
 SELECT * FROM recipes
 WHERE author_id = $1
+  AND deleted_at IS NULL
 ORDER BY updated_at DESC
 LIMIT $2 OFFSET $3;
 
@@ -8,6 +8,7 @@ SELECT sc.*, r.title as recipe_title, r.slug as recipe_slug
 FROM share_codes sc
 JOIN recipes r ON sc.recipe_id = r.id
 WHERE sc.code = $1
+  AND r.deleted_at IS NULL
   AND (sc.expires_at IS NULL OR sc.expires_at > NOW())
   AND (sc.max_uses IS NULL OR sc.use_count < sc.max_uses)
 LIMIT 1;
@@ -0,0 +1,11 @@
+-- name: RecordSlugHistory :exec
+INSERT INTO slug_history (slug, recipe_id)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO UPDATE SET recipe_id = EXCLUDED.recipe_id, created_at = CURRENT_TIMESTAMP;
+
+-- name: GetSlugHistory :one
+SELECT * FROM slug_history
+WHERE slug = $1 LIMIT 1;
+
+-- name: DeleteSlugHistory :exec
+DELETE FROM slug_history WHERE slug = $1;
@@ -7,13 +7,23 @@ RETURNING *;
 SELECT * FROM tags
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTagByName :one
+SELECT * FROM tags
+WHERE name = $1 LIMIT 1;
+
 -- name: GetTagBySlug :one
 SELECT * FROM tags
 WHERE slug = $1 LIMIT 1;
 
 -- name: ListTags :many
 SELECT * FROM tags
-ORDER BY name ASC;
+WHERE sqlc.narg('after_id') IS NULL
+   OR (name, id) > (sqlc.narg('after_name'), sqlc.narg('after_id'))
+ORDER BY name ASC, id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: CountTags :one
+SELECT COUNT(*) FROM tags;
 
 -- name: UpdateTag :one
 UPDATE tags
@@ -36,6 +46,10 @@ ON CONFLICT DO NOTHING;
 DELETE FROM recipe_tags
 WHERE recipe_id = $1 AND tag_id = $2;
 
+-- name: ClearRecipeTags :exec
+DELETE FROM recipe_tags
+WHERE recipe_id = $1;
+
 -- name: GetRecipeTags :many
 SELECT t.* FROM tags t
 JOIN recipe_tags rt ON t.id = rt.tag_id
@@ -3,6 +3,10 @@ INSERT INTO user_invites (code, email, role, created_by, expires_at)
 VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
+-- name: GetInviteByID :one
+SELECT * FROM user_invites
+WHERE id = $1 LIMIT 1;
+
 -- name: GetInviteByCode :one
 SELECT * FROM user_invites
 WHERE code = $1
@@ -20,7 +24,13 @@ RETURNING *;
 
 -- name: ListInvites :many
 SELECT * FROM user_invites
-ORDER BY created_at DESC;
+WHERE sqlc.narg('after_id') IS NULL
+   OR (created_at, id) < (sqlc.narg('after_created_at'), sqlc.narg('after_id'))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountInvites :one
+SELECT COUNT(*) FROM user_invites;
 
 -- name: DeleteInvite :exec
 DELETE FROM user_invites WHERE id = $1;
@@ -7,6 +7,7 @@ package sqlc
 import (
 	"database/sql"
 	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -27,6 +28,55 @@ type Category struct {
 	CreatedAt   sql.NullTime   `json:"created_at"`
 }
 
+type Collection struct {
+	ID          uuid.UUID      `json:"id"`
+	OwnerID     uuid.UUID      `json:"owner_id"`
+	Name        string         `json:"name"`
+	Description sql.NullString `json:"description"`
+	Visibility  string         `json:"visibility"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+	UpdatedAt   sql.NullTime   `json:"updated_at"`
+}
+
+type CollectionRecipe struct {
+	CollectionID uuid.UUID    `json:"collection_id"`
+	RecipeID     uuid.UUID    `json:"recipe_id"`
+	OrderIndex   int32        `json:"order_index"`
+	AddedAt      sql.NullTime `json:"added_at"`
+}
+
+type Comment struct {
+	ID          uuid.UUID     `json:"id"`
+	RecipeID    uuid.UUID     `json:"recipe_id"`
+	VariationID uuid.NullUUID `json:"variation_id"`
+	ParentID    uuid.NullUUID `json:"parent_id"`
+	ThreadID    uuid.NullUUID `json:"thread_id"`
+	AuthorID    uuid.NullUUID `json:"author_id"`
+	Body        string        `json:"body"`
+	IsDeleted   bool          `json:"is_deleted"`
+	CreatedAt   sql.NullTime  `json:"created_at"`
+	UpdatedAt   sql.NullTime  `json:"updated_at"`
+	EditedAt    sql.NullTime  `json:"edited_at"`
+}
+
+type CookLog struct {
+	ID          uuid.UUID      `json:"id"`
+	RecipeID    uuid.UUID      `json:"recipe_id"`
+	VariationID uuid.NullUUID  `json:"variation_id"`
+	UserID      uuid.UUID      `json:"user_id"`
+	CookedOn    time.Time      `json:"cooked_on"`
+	Rating      sql.NullInt32  `json:"rating"`
+	Notes       sql.NullString `json:"notes"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+}
+
+type CookLogPhoto struct {
+	ID         uuid.UUID     `json:"id"`
+	CookLogID  uuid.UUID     `json:"cook_log_id"`
+	FilePath   string        `json:"file_path"`
+	OrderIndex sql.NullInt32 `json:"order_index"`
+}
+
 type Recipe struct {
 	ID                uuid.UUID      `json:"id"`
 	Title             string         `json:"title"`
@@ -44,6 +94,37 @@ type Recipe struct {
 	CreatedAt         sql.NullTime   `json:"created_at"`
 	UpdatedAt         sql.NullTime   `json:"updated_at"`
 	PublishedAt       sql.NullTime   `json:"published_at"`
+	DeletedAt         sql.NullTime   `json:"deleted_at"`
+}
+
+type RecipeDietary struct {
+	RecipeID   uuid.UUID    `json:"recipe_id"`
+	Gluten     bool         `json:"gluten"`
+	Dairy      bool         `json:"dairy"`
+	Egg        bool         `json:"egg"`
+	Nuts       bool         `json:"nuts"`
+	Peanuts    bool         `json:"peanuts"`
+	Shellfish  bool         `json:"shellfish"`
+	Soy        bool         `json:"soy"`
+	Sesame     bool         `json:"sesame"`
+	Vegetarian bool         `json:"vegetarian"`
+	Vegan      bool         `json:"vegan"`
+	GlutenFree bool         `json:"gluten_free"`
+	DairyFree  bool         `json:"dairy_free"`
+	NutFree    bool         `json:"nut_free"`
+	UpdatedAt  sql.NullTime `json:"updated_at"`
+}
+
+type RecipeFork struct {
+	RecipeID     uuid.UUID    `json:"recipe_id"`
+	ForkedFromID uuid.UUID    `json:"forked_from_id"`
+	CreatedAt    sql.NullTime `json:"created_at"`
+}
+
+type RecipeFavorite struct {
+	UserID    uuid.UUID    `json:"user_id"`
+	RecipeID  uuid.UUID    `json:"recipe_id"`
+	CreatedAt sql.NullTime `json:"created_at"`
 }
 
 type RecipeGroup struct {
@@ -61,6 +142,19 @@ type RecipeGrouping struct {
 	OrderIndex sql.NullInt32 `json:"order_index"`
 }
 
+type RecipeIngredient struct {
+	ID          uuid.UUID       `json:"id"`
+	RecipeID    uuid.UUID       `json:"recipe_id"`
+	OrderIndex  int32           `json:"order_index"`
+	RawText     string          `json:"raw_text"`
+	Quantity    sql.NullFloat64 `json:"quantity"`
+	QuantityMax sql.NullFloat64 `json:"quantity_max"`
+	Unit        sql.NullString  `json:"unit"`
+	Name        string          `json:"name"`
+	Note        sql.NullString  `json:"note"`
+	CreatedAt   sql.NullTime    `json:"created_at"`
+}
+
 type RecipeImage struct {
 	ID            uuid.UUID      `json:"id"`
 	RecipeID      uuid.NullUUID  `json:"recipe_id"`
@@ -72,6 +166,43 @@ type RecipeImage struct {
 	UploadedAt    sql.NullTime   `json:"uploaded_at"`
 }
 
+type RecipeLink struct {
+	RecipeID       uuid.UUID     `json:"recipe_id"`
+	Slug           string        `json:"slug"`
+	LinkedRecipeID uuid.NullUUID `json:"linked_recipe_id"`
+	OrderIndex     int32         `json:"order_index"`
+	CreatedAt      sql.NullTime  `json:"created_at"`
+}
+
+type RecipeRevision struct {
+	ID                uuid.UUID      `json:"id"`
+	RecipeID          uuid.UUID      `json:"recipe_id"`
+	RevisionNumber    int32          `json:"revision_number"`
+	AuthorID          uuid.NullUUID  `json:"author_id"`
+	Title             string         `json:"title"`
+	MarkdownContent   string         `json:"markdown_content"`
+	CategoryID        uuid.NullUUID  `json:"category_id"`
+	Description       sql.NullString `json:"description"`
+	PrepTimeMinutes   sql.NullInt32  `json:"prep_time_minutes"`
+	CookTimeMinutes   sql.NullInt32  `json:"cook_time_minutes"`
+	Servings          sql.NullInt32  `json:"servings"`
+	Difficulty        sql.NullString `json:"difficulty"`
+	FeaturedImagePath sql.NullString `json:"featured_image_path"`
+	IsPublished       sql.NullBool   `json:"is_published"`
+	RestoredFrom      sql.NullInt32  `json:"restored_from"`
+	CreatedAt         sql.NullTime   `json:"created_at"`
+}
+
+type RecipeSearch struct {
+	RecipeID    uuid.UUID   `json:"recipe_id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Content     string      `json:"content"`
+	Tags        string      `json:"tags"`
+	Category    string      `json:"category"`
+	Document    interface{} `json:"document"`
+}
+
 type RecipeTag struct {
 	RecipeID uuid.UUID `json:"recipe_id"`
 	TagID    uuid.UUID `json:"tag_id"`
@@ -96,6 +227,12 @@ type SharedRecipe struct {
 	ImportedAt        sql.NullTime   `json:"imported_at"`
 }
 
+type SlugHistory struct {
+	Slug      string       `json:"slug"`
+	RecipeID  uuid.UUID    `json:"recipe_id"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
 type Tag struct {
 	ID        uuid.UUID      `json:"id"`
 	Name      string         `json:"name"`
@@ -6,72 +6,182 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddFavorite(ctx context.Context, arg AddFavoriteParams) error
+	AddRecipeToCollection(ctx context.Context, arg AddRecipeToCollectionParams) error
 	AddRecipeToGroup(ctx context.Context, arg AddRecipeToGroupParams) error
 	AddTagToRecipe(ctx context.Context, arg AddTagToRecipeParams) error
+	ClearRecipeTags(ctx context.Context, recipeID uuid.UUID) error
+	CopyRecipeGroupings(ctx context.Context, arg CopyRecipeGroupingsParams) error
+	CopyRecipeImage(ctx context.Context, arg CopyRecipeImageParams) error
+	CopyRecipeTags(ctx context.Context, arg CopyRecipeTagsParams) error
+	CountCommentReplies(ctx context.Context, parentID uuid.NullUUID) (int64, error)
+	CountFavoriteRecipes(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountInvites(ctx context.Context) (int64, error)
+	CountRecentComments(ctx context.Context) (int64, error)
+	CountRecipeComments(ctx context.Context, recipeID uuid.UUID) (int64, error)
+	CountRecipeCookLogs(ctx context.Context, recipeID uuid.UUID) (int64, error)
+	CountRecipeGroups(ctx context.Context) (int64, error)
+	CountRecipeRevisions(ctx context.Context, recipeID uuid.UUID) (int64, error)
+	CountTags(ctx context.Context) (int64, error)
+	CountTrashedRecipes(ctx context.Context, authorID uuid.NullUUID) (int64, error)
+	CountUploadReferences(ctx context.Context, pattern string) (int64, error)
+	CountUserCookLogs(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountVariationComments(ctx context.Context, variationID uuid.NullUUID) (int64, error)
+	CountVisibleCollections(ctx context.Context, ownerID uuid.UUID) (int64, error)
 	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
+	CreateCollection(ctx context.Context, arg CreateCollectionParams) (Collection, error)
+	CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error)
+	CreateCookLog(ctx context.Context, arg CreateCookLogParams) (CookLog, error)
+	CreateCookLogPhoto(ctx context.Context, arg CreateCookLogPhotoParams) error
 	CreateRecipe(ctx context.Context, arg CreateRecipeParams) (Recipe, error)
+	CreateRecipeFork(ctx context.Context, arg CreateRecipeForkParams) error
 	CreateRecipeGroup(ctx context.Context, arg CreateRecipeGroupParams) (RecipeGroup, error)
 	CreateRecipeImage(ctx context.Context, arg CreateRecipeImageParams) (RecipeImage, error)
+	CreateRecipeIngredient(ctx context.Context, arg CreateRecipeIngredientParams) (RecipeIngredient, error)
+	CreateRecipeLink(ctx context.Context, arg CreateRecipeLinkParams) error
+	CreateRecipeRevision(ctx context.Context, arg CreateRecipeRevisionParams) (RecipeRevision, error)
 	CreateShareCode(ctx context.Context, arg CreateShareCodeParams) (ShareCode, error)
 	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateUserInvite(ctx context.Context, arg CreateUserInviteParams) (UserInvite, error)
 	CreateVariation(ctx context.Context, arg CreateVariationParams) (RecipeVariation, error)
 	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	DeleteCollection(ctx context.Context, id uuid.UUID) error
+	DeleteComment(ctx context.Context, id uuid.UUID) error
+	DeleteCookLog(ctx context.Context, id uuid.UUID) error
 	DeleteInvite(ctx context.Context, id uuid.UUID) error
 	DeleteRecipe(ctx context.Context, id uuid.UUID) error
 	DeleteRecipeGroup(ctx context.Context, id uuid.UUID) error
 	DeleteRecipeImage(ctx context.Context, id uuid.UUID) error
+	DeleteRecipeIngredients(ctx context.Context, recipeID uuid.UUID) error
+	DeleteRecipeLinks(ctx context.Context, recipeID uuid.UUID) error
+	DeleteRecipeSearchDocument(ctx context.Context, recipeID uuid.UUID) error
 	DeleteSetting(ctx context.Context, key string) error
 	DeleteShareCode(ctx context.Context, id uuid.UUID) error
+	DeleteSlugHistory(ctx context.Context, slug string) error
 	DeleteTag(ctx context.Context, id uuid.UUID) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
 	DeleteVariation(ctx context.Context, id uuid.UUID) error
 	GetCategoryByID(ctx context.Context, id uuid.UUID) (Category, error)
 	GetCategoryBySlug(ctx context.Context, slug string) (Category, error)
+	GetCollectionByID(ctx context.Context, id uuid.UUID) (Collection, error)
+	GetCommentByID(ctx context.Context, id uuid.UUID) (Comment, error)
+	GetCookLogByID(ctx context.Context, id uuid.UUID) (CookLog, error)
 	GetGroupsForRecipe(ctx context.Context, recipeID uuid.UUID) ([]RecipeGroup, error)
 	GetInviteByCode(ctx context.Context, code string) (UserInvite, error)
+	GetInviteByID(ctx context.Context, id uuid.UUID) (UserInvite, error)
+	GetRecipeBacklinks(ctx context.Context, linkedRecipeID uuid.NullUUID) ([]GetRecipeBacklinksRow, error)
 	GetRecipeByID(ctx context.Context, id uuid.UUID) (Recipe, error)
 	GetRecipeBySlug(ctx context.Context, slug string) (Recipe, error)
+	GetRecipeDietary(ctx context.Context, recipeID uuid.UUID) (RecipeDietary, error)
+	GetRecipeForkedFrom(ctx context.Context, recipeID uuid.UUID) (uuid.UUID, error)
 	GetRecipeGroupByID(ctx context.Context, id uuid.UUID) (RecipeGroup, error)
 	GetRecipeGroupBySlug(ctx context.Context, slug string) (RecipeGroup, error)
 	GetRecipeGroupWithRecipes(ctx context.Context, id uuid.UUID) (GetRecipeGroupWithRecipesRow, error)
+	GetRecipeIDBySlug(ctx context.Context, slug string) (uuid.UUID, error)
+	GetRecipeSlugByID(ctx context.Context, id uuid.UUID) (string, error)
 	GetRecipeImageByID(ctx context.Context, id uuid.UUID) (RecipeImage, error)
 	GetRecipeImages(ctx context.Context, recipeID uuid.NullUUID) ([]RecipeImage, error)
+	GetRecipeIngredients(ctx context.Context, recipeID uuid.UUID) ([]RecipeIngredient, error)
+	GetRecipeLinks(ctx context.Context, recipeID uuid.UUID) ([]GetRecipeLinksRow, error)
+	GetRecipeRevision(ctx context.Context, arg GetRecipeRevisionParams) (RecipeRevision, error)
 	GetRecipeTags(ctx context.Context, recipeID uuid.UUID) ([]Tag, error)
 	GetRecipeWithImages(ctx context.Context, id uuid.UUID) (GetRecipeWithImagesRow, error)
 	GetRecipesInGroup(ctx context.Context, groupID uuid.UUID) ([]Recipe, error)
 	GetSetting(ctx context.Context, key string) (AppSetting, error)
 	GetShareCodeByCode(ctx context.Context, code string) (GetShareCodeByCodeRow, error)
 	GetShareCodesForRecipe(ctx context.Context, recipeID uuid.NullUUID) ([]ShareCode, error)
+	GetSlugHistory(ctx context.Context, slug string) (SlugHistory, error)
 	GetTagByID(ctx context.Context, id uuid.UUID) (Tag, error)
+	GetTagByName(ctx context.Context, name string) (Tag, error)
 	GetTagBySlug(ctx context.Context, slug string) (Tag, error)
+	GetTrashedRecipeByID(ctx context.Context, id uuid.UUID) (Recipe, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetVariationByID(ctx context.Context, id uuid.UUID) (RecipeVariation, error)
 	GetVariationByRecipeAndAuthor(ctx context.Context, recipeID uuid.UUID, authorID uuid.UUID) (RecipeVariation, error)
 	GetVariationsByRecipe(ctx context.Context, recipeID uuid.UUID) ([]RecipeVariation, error)
 	GetVariationsByRecipeWithAuthor(ctx context.Context, recipeID uuid.UUID) ([]GetVariationsByRecipeWithAuthorRow, error)
-	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	IncrementShareCodeUse(ctx context.Context, id uuid.UUID) error
+	IsFavorite(ctx context.Context, arg IsFavoriteParams) (bool, error)
+	ListAllCollectionRecipes(ctx context.Context) ([]CollectionRecipe, error)
+	ListAllCollections(ctx context.Context) ([]Collection, error)
+	ListAllComments(ctx context.Context) ([]Comment, error)
+	ListAllCookLogPhotos(ctx context.Context) ([]CookLogPhoto, error)
+	ListAllCookLogs(ctx context.Context) ([]CookLog, error)
+	ListAllRecipeFavorites(ctx context.Context) ([]RecipeFavorite, error)
+	ListAllRecipeGroupings(ctx context.Context) ([]RecipeGrouping, error)
+	ListAllRecipeGroups(ctx context.Context) ([]RecipeGroup, error)
+	ListAllRecipeImages(ctx context.Context) ([]RecipeImage, error)
+	ListAllRecipeRevisions(ctx context.Context) ([]RecipeRevision, error)
+	ListAllRecipeTags(ctx context.Context) ([]RecipeTag, error)
+	ListAllRecipes(ctx context.Context) ([]Recipe, error)
+	ListAllShareCodes(ctx context.Context) ([]ShareCode, error)
+	ListAllTags(ctx context.Context) ([]Tag, error)
+	ListAllUsers(ctx context.Context) ([]User, error)
+	ListAllVariations(ctx context.Context) ([]RecipeVariation, error)
 	ListCategories(ctx context.Context) ([]Category, error)
-	ListInvites(ctx context.Context) ([]UserInvite, error)
-	ListRecipeGroups(ctx context.Context) ([]RecipeGroup, error)
+	ListCollectionRecipeIDs(ctx context.Context, collectionID uuid.UUID) ([]uuid.UUID, error)
+	ListCollectionRecipes(ctx context.Context, arg ListCollectionRecipesParams) ([]Recipe, error)
+	ListCookLogPhotos(ctx context.Context, cookLogID uuid.UUID) ([]string, error)
+	ListFavoriteRecipes(ctx context.Context, arg ListFavoriteRecipesParams) ([]ListFavoriteRecipesRow, error)
+	ListInvites(ctx context.Context, arg ListInvitesParams) ([]UserInvite, error)
+	ListRecentComments(ctx context.Context, arg ListRecentCommentsParams) ([]Comment, error)
+	ListRecipeComments(ctx context.Context, arg ListRecipeCommentsParams) ([]Comment, error)
+	ListRecipeCookLogs(ctx context.Context, arg ListRecipeCookLogsParams) ([]CookLog, error)
+	ListRecipeCookRatings(ctx context.Context, recipeID uuid.UUID) ([]ListRecipeCookRatingsRow, error)
+	ListRecipeForks(ctx context.Context, forkedFromID uuid.UUID) ([]ListRecipeForksRow, error)
+	ListRecipeGroups(ctx context.Context, arg ListRecipeGroupsParams) ([]RecipeGroup, error)
+	ListRecipeIDsInCategory(ctx context.Context, categoryID uuid.NullUUID) ([]uuid.UUID, error)
+	ListRecipeIDsWithTag(ctx context.Context, tagID uuid.UUID) ([]uuid.UUID, error)
+	ListRecipeRevisions(ctx context.Context, recipeID uuid.UUID) ([]RecipeRevision, error)
+	ListRecipeUploadReferences(ctx context.Context, id uuid.UUID) ([]string, error)
 	ListRecipes(ctx context.Context, arg ListRecipesParams) ([]Recipe, error)
-	ListVariationsByAuthor(ctx context.Context, arg ListVariationsByAuthorParams) ([]RecipeVariation, error)
 	ListRecipesByAuthor(ctx context.Context, arg ListRecipesByAuthorParams) ([]Recipe, error)
 	ListRecipesByCategory(ctx context.Context, arg ListRecipesByCategoryParams) ([]Recipe, error)
+	ListRecipesTrashedBefore(ctx context.Context, deletedAt sql.NullTime) ([]Recipe, error)
+	ListRecipesWithoutDietary(ctx context.Context) ([]ListRecipesWithoutDietaryRow, error)
 	ListSettings(ctx context.Context) ([]AppSetting, error)
-	ListTags(ctx context.Context) ([]Tag, error)
+	ListTags(ctx context.Context, arg ListTagsParams) ([]Tag, error)
+	ListThreadReplies(ctx context.Context, threadID uuid.NullUUID) ([]Comment, error)
+	ListTrashedRecipes(ctx context.Context, arg ListTrashedRecipesParams) ([]Recipe, error)
+	ListUserCookLogs(ctx context.Context, arg ListUserCookLogsParams) ([]ListUserCookLogsRow, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	ListVariationComments(ctx context.Context, arg ListVariationCommentsParams) ([]Comment, error)
+	ListVariationsByAuthor(ctx context.Context, arg ListVariationsByAuthorParams) ([]RecipeVariation, error)
+	ListVisibleCollections(ctx context.Context, arg ListVisibleCollectionsParams) ([]Collection, error)
+	MarkCommentDeleted(ctx context.Context, id uuid.UUID) error
+	RecordSlugHistory(ctx context.Context, arg RecordSlugHistoryParams) error
+	PurgeTrashedRecipe(ctx context.Context, arg PurgeTrashedRecipeParams) (int64, error)
+	RemoveFavorite(ctx context.Context, arg RemoveFavoriteParams) error
+	RemoveRecipeFromCollection(ctx context.Context, arg RemoveRecipeFromCollectionParams) error
 	RemoveRecipeFromGroup(ctx context.Context, arg RemoveRecipeFromGroupParams) error
 	RemoveTagFromRecipe(ctx context.Context, arg RemoveTagFromRecipeParams) error
-	SearchRecipes(ctx context.Context, arg SearchRecipesParams) ([]Recipe, error)
+	ReplaceRecipeContent(ctx context.Context, arg ReplaceRecipeContentParams) (Recipe, error)
+	ResolveRecipeLinks(ctx context.Context, arg ResolveRecipeLinksParams) error
+	RestoreCollection(ctx context.Context, arg RestoreCollectionParams) error
+	RestoreCollectionRecipe(ctx context.Context, arg RestoreCollectionRecipeParams) error
+	RestoreComment(ctx context.Context, arg RestoreCommentParams) error
+	RestoreCookLog(ctx context.Context, arg RestoreCookLogParams) error
+	RestoreRecipe(ctx context.Context, arg RestoreRecipeParams) error
+	RestoreRecipeFavorite(ctx context.Context, arg RestoreRecipeFavoriteParams) (int64, error)
+	RestoreRecipeImage(ctx context.Context, arg RestoreRecipeImageParams) error
+	RestoreRecipeRevision(ctx context.Context, arg RestoreRecipeRevisionParams) error
+	RestoreShareCode(ctx context.Context, arg RestoreShareCodeParams) (int64, error)
+	RestoreTrashedRecipe(ctx context.Context, id uuid.UUID) error
+	RestoreUser(ctx context.Context, arg RestoreUserParams) error
+	RestoreVariation(ctx context.Context, arg RestoreVariationParams) (int64, error)
+	SetCollectionRecipeOrder(ctx context.Context, arg SetCollectionRecipeOrderParams) error
+	TrashRecipe(ctx context.Context, id uuid.UUID) error
 	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
+	UpdateCollection(ctx context.Context, arg UpdateCollectionParams) (Collection, error)
+	UpdateCommentBody(ctx context.Context, arg UpdateCommentBodyParams) (Comment, error)
 	UpdateRecipe(ctx context.Context, arg UpdateRecipeParams) (Recipe, error)
 	UpdateRecipeFeaturedImage(ctx context.Context, arg UpdateRecipeFeaturedImageParams) (Recipe, error)
 	UpdateRecipeGroup(ctx context.Context, arg UpdateRecipeGroupParams) (RecipeGroup, error)
@@ -80,6 +190,8 @@ type Querier interface {
 	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateVariation(ctx context.Context, arg UpdateVariationParams) (RecipeVariation, error)
+	UpsertRecipeDietary(ctx context.Context, arg UpsertRecipeDietaryParams) error
+	UpsertRecipeSearchDocument(ctx context.Context, arg UpsertRecipeSearchDocumentParams) error
 	UpsertSetting(ctx context.Context, arg UpsertSettingParams) (AppSetting, error)
 	UseInvite(ctx context.Context, arg UseInviteParams) (UserInvite, error)
 }
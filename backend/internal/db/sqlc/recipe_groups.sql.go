@@ -29,6 +29,17 @@ func (q *Queries) AddRecipeToGroup(ctx context.Context, arg AddRecipeToGroupPara
 	return err
 }
 
+const countRecipeGroups = `-- name: CountRecipeGroups :one
+SELECT COUNT(*) FROM recipe_groups
+`
+
+func (q *Queries) CountRecipeGroups(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRecipeGroups)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createRecipeGroup = `-- name: CreateRecipeGroup :one
 INSERT INTO recipe_groups (id, name, slug, description, icon)
 VALUES ($1, $2, $3, $4, $5)
@@ -166,7 +177,7 @@ SELECT
     ) as recipes
 FROM recipe_groups g
 LEFT JOIN recipe_groupings rg ON g.id = rg.group_id
-LEFT JOIN recipes r ON rg.recipe_id = r.id
+LEFT JOIN recipes r ON rg.recipe_id = r.id AND r.deleted_at IS NULL
 WHERE g.id = $1
 GROUP BY g.id
 `
@@ -197,11 +208,12 @@ func (q *Queries) GetRecipeGroupWithRecipes(ctx context.Context, id uuid.UUID) (
 }
 
 const getRecipesInGroup = `-- name: GetRecipesInGroup :many
-SELECT r.id, r.title, r.slug, r.markdown_content, r.author_id, r.category_id, r.description, r.prep_time_minutes, r.cook_time_minutes, r.servings, r.difficulty, r.featured_image_path, r.is_published, r.created_at, r.updated_at, r.published_at 
+SELECT r.id, r.title, r.slug, r.markdown_content, r.author_id, r.category_id, r.description, r.prep_time_minutes, r.cook_time_minutes, r.servings, r.difficulty, r.featured_image_path, r.is_published, r.created_at, r.updated_at, r.published_at, r.deleted_at 
 FROM recipes r
 JOIN recipe_groupings rg ON r.id = rg.recipe_id
 WHERE rg.group_id = $1
   AND r.is_published = true
+  AND r.deleted_at IS NULL
 ORDER BY rg.order_index
 `
 
@@ -231,6 +243,7 @@ func (q *Queries) GetRecipesInGroup(ctx context.Context, groupID uuid.UUID) ([]R
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.PublishedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -247,11 +260,20 @@ func (q *Queries) GetRecipesInGroup(ctx context.Context, groupID uuid.UUID) ([]R
 
 const listRecipeGroups = `-- name: ListRecipeGroups :many
 SELECT id, name, slug, description, icon, created_at FROM recipe_groups
-ORDER BY created_at DESC
+WHERE $1 IS NULL
+   OR (created_at, id) < ($2, $1)
+ORDER BY created_at DESC, id DESC
+LIMIT $3
 `
 
-func (q *Queries) ListRecipeGroups(ctx context.Context) ([]RecipeGroup, error) {
-	rows, err := q.db.QueryContext(ctx, listRecipeGroups)
+type ListRecipeGroupsParams struct {
+	AfterID        interface{} `json:"after_id"`
+	AfterCreatedAt interface{} `json:"after_created_at"`
+	Limit          int32       `json:"limit"`
+}
+
+func (q *Queries) ListRecipeGroups(ctx context.Context, arg ListRecipeGroupsParams) ([]RecipeGroup, error) {
+	rows, err := q.db.QueryContext(ctx, listRecipeGroups, arg.AfterID, arg.AfterCreatedAt, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -120,7 +120,7 @@ func (q *Queries) GetRecipeImages(ctx context.Context, recipeID uuid.NullUUID) (
 
 const getRecipeWithImages = `-- name: GetRecipeWithImages :one
 SELECT 
-    r.id, r.title, r.slug, r.markdown_content, r.author_id, r.category_id, r.description, r.prep_time_minutes, r.cook_time_minutes, r.servings, r.difficulty, r.featured_image_path, r.is_published, r.created_at, r.updated_at, r.published_at,
+    r.id, r.title, r.slug, r.markdown_content, r.author_id, r.category_id, r.description, r.prep_time_minutes, r.cook_time_minutes, r.servings, r.difficulty, r.featured_image_path, r.is_published, r.created_at, r.updated_at, r.published_at, r.deleted_at,
     COALESCE(
         json_agg(
             json_build_object(
@@ -136,7 +136,7 @@ SELECT
     ) as body_images
 FROM recipes r
 LEFT JOIN recipe_images ri ON r.id = ri.recipe_id
-WHERE r.id = $1
+WHERE r.id = $1 AND r.deleted_at IS NULL
 GROUP BY r.id
 `
 
@@ -157,6 +157,7 @@ type GetRecipeWithImagesRow struct {
 	CreatedAt         sql.NullTime   `json:"created_at"`
 	UpdatedAt         sql.NullTime   `json:"updated_at"`
 	PublishedAt       sql.NullTime   `json:"published_at"`
+	DeletedAt         sql.NullTime   `json:"deleted_at"`
 	BodyImages        interface{}    `json:"body_images"`
 }
 
@@ -180,6 +181,7 @@ func (q *Queries) GetRecipeWithImages(ctx context.Context, id uuid.UUID) (GetRec
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PublishedAt,
+		&i.DeletedAt,
 		&i.BodyImages,
 	)
 	return i, err
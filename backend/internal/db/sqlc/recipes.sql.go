@@ -15,7 +15,7 @@ import (
 const createRecipe = `-- name: CreateRecipe :one
 INSERT INTO recipes (id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published)
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-RETURNING id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at
+RETURNING id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at, deleted_at
 `
 
 type CreateRecipeParams struct {
@@ -68,6 +68,7 @@ func (q *Queries) CreateRecipe(ctx context.Context, arg CreateRecipeParams) (Rec
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PublishedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -82,8 +83,8 @@ func (q *Queries) DeleteRecipe(ctx context.Context, id uuid.UUID) error {
 }
 
 const getRecipeByID = `-- name: GetRecipeByID :one
-SELECT id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at FROM recipes
-WHERE id = $1 LIMIT 1
+SELECT id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at, deleted_at FROM recipes
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetRecipeByID(ctx context.Context, id uuid.UUID) (Recipe, error) {
@@ -106,13 +107,14 @@ func (q *Queries) GetRecipeByID(ctx context.Context, id uuid.UUID) (Recipe, erro
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PublishedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getRecipeBySlug = `-- name: GetRecipeBySlug :one
-SELECT id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at FROM recipes
-WHERE slug = $1 LIMIT 1
+SELECT id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at, deleted_at FROM recipes
+WHERE slug = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetRecipeBySlug(ctx context.Context, slug string) (Recipe, error) {
@@ -135,13 +137,39 @@ func (q *Queries) GetRecipeBySlug(ctx context.Context, slug string) (Recipe, err
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PublishedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const getRecipeIDBySlug = `-- name: GetRecipeIDBySlug :one
+SELECT id FROM recipes
+WHERE slug = $1 LIMIT 1
+`
+
+func (q *Queries) GetRecipeIDBySlug(ctx context.Context, slug string) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getRecipeIDBySlug, slug)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getRecipeSlugByID = `-- name: GetRecipeSlugByID :one
+SELECT slug FROM recipes
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetRecipeSlugByID(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getRecipeSlugByID, id)
+	var slug string
+	err := row.Scan(&slug)
+	return slug, err
+}
+
 const listRecipes = `-- name: ListRecipes :many
-SELECT id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at FROM recipes
+SELECT id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at, deleted_at FROM recipes
 WHERE is_published = true
+  AND deleted_at IS NULL
 ORDER BY published_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -177,6 +205,7 @@ func (q *Queries) ListRecipes(ctx context.Context, arg ListRecipesParams) ([]Rec
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.PublishedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -192,8 +221,9 @@ func (q *Queries) ListRecipes(ctx context.Context, arg ListRecipesParams) ([]Rec
 }
 
 const listRecipesByAuthor = `-- name: ListRecipesByAuthor :many
-SELECT id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at FROM recipes
+SELECT id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at, deleted_at FROM recipes
 WHERE author_id = $1
+  AND deleted_at IS NULL
 ORDER BY updated_at DESC
 LIMIT $2 OFFSET $3
 `
@@ -230,6 +260,7 @@ func (q *Queries) ListRecipesByAuthor(ctx context.Context, arg ListRecipesByAuth
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.PublishedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -245,9 +276,10 @@ func (q *Queries) ListRecipesByAuthor(ctx context.Context, arg ListRecipesByAuth
 }
 
 const listRecipesByCategory = `-- name: ListRecipesByCategory :many
-SELECT id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at FROM recipes
+SELECT id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at, deleted_at FROM recipes
 WHERE is_published = true
   AND category_id = $1
+  AND deleted_at IS NULL
 ORDER BY published_at DESC
 LIMIT $2 OFFSET $3
 `
@@ -284,6 +316,7 @@ func (q *Queries) ListRecipesByCategory(ctx context.Context, arg ListRecipesByCa
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.PublishedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -298,81 +331,98 @@ func (q *Queries) ListRecipesByCategory(ctx context.Context, arg ListRecipesByCa
 	return items, nil
 }
 
-const searchRecipes = `-- name: SearchRecipes :many
-SELECT id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at FROM recipes
-WHERE is_published = true
-  AND (title ILIKE '%' || $1 || '%' OR description ILIKE '%' || $1 || '%')
-ORDER BY published_at DESC
-LIMIT $2 OFFSET $3
+const replaceRecipeContent = `-- name: ReplaceRecipeContent :one
+UPDATE recipes
+SET
+    title = $1,
+    slug = $2,
+    markdown_content = $3,
+    category_id = $4,
+    description = $5,
+    prep_time_minutes = $6,
+    cook_time_minutes = $7,
+    servings = $8,
+    difficulty = $9,
+    featured_image_path = $10,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $11
+RETURNING id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at, deleted_at
 `
 
-type SearchRecipesParams struct {
-	Column1 sql.NullString `json:"column_1"`
-	Limit   int32          `json:"limit"`
-	Offset  int32          `json:"offset"`
+type ReplaceRecipeContentParams struct {
+	Title             string         `json:"title"`
+	Slug              string         `json:"slug"`
+	MarkdownContent   string         `json:"markdown_content"`
+	CategoryID        uuid.NullUUID  `json:"category_id"`
+	Description       sql.NullString `json:"description"`
+	PrepTimeMinutes   sql.NullInt32  `json:"prep_time_minutes"`
+	CookTimeMinutes   sql.NullInt32  `json:"cook_time_minutes"`
+	Servings          sql.NullInt32  `json:"servings"`
+	Difficulty        sql.NullString `json:"difficulty"`
+	FeaturedImagePath sql.NullString `json:"featured_image_path"`
+	ID                uuid.UUID      `json:"id"`
 }
 
-func (q *Queries) SearchRecipes(ctx context.Context, arg SearchRecipesParams) ([]Recipe, error) {
-	rows, err := q.db.QueryContext(ctx, searchRecipes, arg.Column1, arg.Limit, arg.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Recipe
-	for rows.Next() {
-		var i Recipe
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Slug,
-			&i.MarkdownContent,
-			&i.AuthorID,
-			&i.CategoryID,
-			&i.Description,
-			&i.PrepTimeMinutes,
-			&i.CookTimeMinutes,
-			&i.Servings,
-			&i.Difficulty,
-			&i.FeaturedImagePath,
-			&i.IsPublished,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.PublishedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) ReplaceRecipeContent(ctx context.Context, arg ReplaceRecipeContentParams) (Recipe, error) {
+	row := q.db.QueryRowContext(ctx, replaceRecipeContent,
+		arg.Title,
+		arg.Slug,
+		arg.MarkdownContent,
+		arg.CategoryID,
+		arg.Description,
+		arg.PrepTimeMinutes,
+		arg.CookTimeMinutes,
+		arg.Servings,
+		arg.Difficulty,
+		arg.FeaturedImagePath,
+		arg.ID,
+	)
+	var i Recipe
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.MarkdownContent,
+		&i.AuthorID,
+		&i.CategoryID,
+		&i.Description,
+		&i.PrepTimeMinutes,
+		&i.CookTimeMinutes,
+		&i.Servings,
+		&i.Difficulty,
+		&i.FeaturedImagePath,
+		&i.IsPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PublishedAt,
+		&i.DeletedAt,
+	)
+	return i, err
 }
 
 const updateRecipe = `-- name: UpdateRecipe :one
 UPDATE recipes
 SET
     title = COALESCE($2, title),
-    markdown_content = COALESCE($3, markdown_content),
-    category_id = COALESCE($4, category_id),
-    description = COALESCE($5, description),
-    prep_time_minutes = COALESCE($6, prep_time_minutes),
-    cook_time_minutes = COALESCE($7, cook_time_minutes),
-    servings = COALESCE($8, servings),
-    difficulty = COALESCE($9, difficulty),
-    featured_image_path = COALESCE($10, featured_image_path),
-    is_published = COALESCE($11, is_published),
+    slug = COALESCE($3, slug),
+    markdown_content = COALESCE($4, markdown_content),
+    category_id = COALESCE($5, category_id),
+    description = COALESCE($6, description),
+    prep_time_minutes = COALESCE($7, prep_time_minutes),
+    cook_time_minutes = COALESCE($8, cook_time_minutes),
+    servings = COALESCE($9, servings),
+    difficulty = COALESCE($10, difficulty),
+    featured_image_path = COALESCE($11, featured_image_path),
+    is_published = COALESCE($12, is_published),
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at
+RETURNING id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at, deleted_at
 `
 
 type UpdateRecipeParams struct {
 	ID                uuid.UUID      `json:"id"`
 	Title             sql.NullString `json:"title"`
+	Slug              sql.NullString `json:"slug"`
 	MarkdownContent   sql.NullString `json:"markdown_content"`
 	CategoryID        uuid.NullUUID  `json:"category_id"`
 	Description       sql.NullString `json:"description"`
@@ -388,6 +438,7 @@ func (q *Queries) UpdateRecipe(ctx context.Context, arg UpdateRecipeParams) (Rec
 	row := q.db.QueryRowContext(ctx, updateRecipe,
 		arg.ID,
 		arg.Title,
+		arg.Slug,
 		arg.MarkdownContent,
 		arg.CategoryID,
 		arg.Description,
@@ -416,6 +467,7 @@ func (q *Queries) UpdateRecipe(ctx context.Context, arg UpdateRecipeParams) (Rec
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PublishedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -426,7 +478,7 @@ SET
     featured_image_path = $2,
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at
+RETURNING id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at, deleted_at
 `
 
 type UpdateRecipeFeaturedImageParams struct {
@@ -454,6 +506,7 @@ func (q *Queries) UpdateRecipeFeaturedImage(ctx context.Context, arg UpdateRecip
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PublishedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -465,7 +518,7 @@ SET
     published_at = CASE WHEN $2 = true THEN NOW() ELSE published_at END,
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at
+RETURNING id, title, slug, markdown_content, author_id, category_id, description, prep_time_minutes, cook_time_minutes, servings, difficulty, featured_image_path, is_published, created_at, updated_at, published_at, deleted_at
 `
 
 type UpdateRecipePublishedStatusParams struct {
@@ -493,6 +546,7 @@ func (q *Queries) UpdateRecipePublishedStatus(ctx context.Context, arg UpdateRec
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.PublishedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
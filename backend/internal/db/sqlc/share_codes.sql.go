@@ -59,6 +59,7 @@ SELECT sc.id, sc.recipe_id, sc.code, sc.expires_at, sc.max_uses, sc.use_count, s
 FROM share_codes sc
 JOIN recipes r ON sc.recipe_id = r.id
 WHERE sc.code = $1
+  AND r.deleted_at IS NULL
   AND (sc.expires_at IS NULL OR sc.expires_at > NOW())
   AND (sc.max_uses IS NULL OR sc.use_count < sc.max_uses)
 LIMIT 1
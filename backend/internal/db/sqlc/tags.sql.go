@@ -28,6 +28,27 @@ func (q *Queries) AddTagToRecipe(ctx context.Context, arg AddTagToRecipeParams)
 	return err
 }
 
+const clearRecipeTags = `-- name: ClearRecipeTags :exec
+DELETE FROM recipe_tags
+WHERE recipe_id = $1
+`
+
+func (q *Queries) ClearRecipeTags(ctx context.Context, recipeID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, clearRecipeTags, recipeID)
+	return err
+}
+
+const countTags = `-- name: CountTags :one
+SELECT COUNT(*) FROM tags
+`
+
+func (q *Queries) CountTags(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTags)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTag = `-- name: CreateTag :one
 INSERT INTO tags (id, name, slug, color)
 VALUES ($1, $2, $3, $4)
@@ -121,6 +142,24 @@ func (q *Queries) GetTagByID(ctx context.Context, id uuid.UUID) (Tag, error) {
 	return i, err
 }
 
+const getTagByName = `-- name: GetTagByName :one
+SELECT id, name, slug, color, created_at FROM tags
+WHERE name = $1 LIMIT 1
+`
+
+func (q *Queries) GetTagByName(ctx context.Context, name string) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, getTagByName, name)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Color,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getTagBySlug = `-- name: GetTagBySlug :one
 SELECT id, name, slug, color, created_at FROM tags
 WHERE slug = $1 LIMIT 1
@@ -141,11 +180,20 @@ func (q *Queries) GetTagBySlug(ctx context.Context, slug string) (Tag, error) {
 
 const listTags = `-- name: ListTags :many
 SELECT id, name, slug, color, created_at FROM tags
-ORDER BY name ASC
+WHERE $1 IS NULL
+   OR (name, id) > ($2, $1)
+ORDER BY name ASC, id ASC
+LIMIT $3
 `
 
-func (q *Queries) ListTags(ctx context.Context) ([]Tag, error) {
-	rows, err := q.db.QueryContext(ctx, listTags)
+type ListTagsParams struct {
+	AfterID   interface{} `json:"after_id"`
+	AfterName interface{} `json:"after_name"`
+	Limit     int32       `json:"limit"`
+}
+
+func (q *Queries) ListTags(ctx context.Context, arg ListTagsParams) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, listTags, arg.AfterID, arg.AfterName, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -12,6 +12,17 @@ import (
 	"github.com/google/uuid"
 )
 
+const countInvites = `-- name: CountInvites :one
+SELECT COUNT(*) FROM user_invites
+`
+
+func (q *Queries) CountInvites(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countInvites)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUserInvite = `-- name: CreateUserInvite :one
 INSERT INTO user_invites (code, email, role, created_by, expires_at)
 VALUES ($1, $2, $3, $4, $5)
@@ -83,13 +94,44 @@ func (q *Queries) GetInviteByCode(ctx context.Context, code string) (UserInvite,
 	return i, err
 }
 
+const getInviteByID = `-- name: GetInviteByID :one
+SELECT id, code, email, role, created_by, expires_at, used_at, used_by, created_at FROM user_invites
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetInviteByID(ctx context.Context, id uuid.UUID) (UserInvite, error) {
+	row := q.db.QueryRowContext(ctx, getInviteByID, id)
+	var i UserInvite
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Email,
+		&i.Role,
+		&i.CreatedBy,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.UsedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const listInvites = `-- name: ListInvites :many
 SELECT id, code, email, role, created_by, expires_at, used_at, used_by, created_at FROM user_invites
-ORDER BY created_at DESC
+WHERE $1 IS NULL
+   OR (created_at, id) < ($2, $1)
+ORDER BY created_at DESC, id DESC
+LIMIT $3
 `
 
-func (q *Queries) ListInvites(ctx context.Context) ([]UserInvite, error) {
-	rows, err := q.db.QueryContext(ctx, listInvites)
+type ListInvitesParams struct {
+	AfterID        interface{} `json:"after_id"`
+	AfterCreatedAt interface{} `json:"after_created_at"`
+	Limit          int32       `json:"limit"`
+}
+
+func (q *Queries) ListInvites(ctx context.Context, arg ListInvitesParams) ([]UserInvite, error) {
+	rows, err := q.db.QueryContext(ctx, listInvites, arg.AfterID, arg.AfterCreatedAt, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
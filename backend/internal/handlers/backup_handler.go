@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/homecooking/backend/internal/services"
+)
+
+// maxBackupUpload bounds the size of an uploaded backup, uploads included.
+const maxBackupUpload = 2 << 30
+
+type BackupHandler struct {
+	backupService *services.BackupService
+}
+
+func NewBackupHandler(backupService *services.BackupService) *BackupHandler {
+	return &BackupHandler{
+		backupService: backupService,
+	}
+}
+
+// Export downloads the whole instance as a zip archive. Password hashes are
+// only included with include_passwords=true.
+func (h *BackupHandler) Export(w http.ResponseWriter, r *http.Request) {
+	includePasswords := false
+	if value := r.URL.Query().Get("include_passwords"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Invalid include_passwords value", http.StatusBadRequest)
+			return
+		}
+		includePasswords = parsed
+	}
+
+	export, err := h.backupService.Export(includePasswords)
+	if err != nil {
+		http.Error(w, "Failed to export backup", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	if err := export.WriteArchive(w); err != nil {
+		// The headers are already out, so the client only sees a
+		// truncated archive.
+		log.Printf("Failed to write backup: %v", err)
+	}
+}
+
+// Import restores an archive made by Export, uploaded as the file field of
+// a multipart form.
+func (h *BackupHandler) Import(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBackupUpload)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "No file provided", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.backupService.Import(file, header.Size)
+	if err != nil {
+		switch {
+		case err.Error() == "unsupported backup version":
+			http.Error(w, "Unsupported backup version", http.StatusUnprocessableEntity)
+		case strings.HasPrefix(err.Error(), "invalid "):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, "Failed to import backup", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
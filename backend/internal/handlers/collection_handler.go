@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/services"
+)
+
+type CollectionHandler struct {
+	collectionService *services.CollectionService
+}
+
+func NewCollectionHandler(collectionService *services.CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// ListFavorites returns the caller's favorite recipes.
+func (h *CollectionHandler) ListFavorites(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value("user").(*models.User)
+	limit, cursor := pageParams(r)
+
+	page, err := h.collectionService.ListFavorites(user.ID.String(), limit, cursor)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to fetch favorites", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func (h *CollectionHandler) AddFavorite(w http.ResponseWriter, r *http.Request) {
+	recipeID := r.PathValue("id")
+	if recipeID == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	if err := h.collectionService.AddFavorite(recipeID, user.ID.String()); err != nil {
+		if err.Error() == "recipe not found" {
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to add favorite", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CollectionHandler) RemoveFavorite(w http.ResponseWriter, r *http.Request) {
+	recipeID := r.PathValue("id")
+	if recipeID == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	if err := h.collectionService.RemoveFavorite(recipeID, user.ID.String()); err != nil {
+		if err.Error() == "recipe not found" {
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to remove favorite", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListCollections returns the caller's collections and everyone's shared
+// ones.
+func (h *CollectionHandler) ListCollections(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value("user").(*models.User)
+	limit, cursor := pageParams(r)
+
+	page, err := h.collectionService.ListCollections(user.ID.String(), limit, cursor)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to fetch collections", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func (h *CollectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	collection, err := h.collectionService.CreateCollection(&req, user.ID.String())
+	if err != nil {
+		if isCollectionInputError(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to create collection", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(collection)
+}
+
+func (h *CollectionHandler) GetCollection(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Collection ID required", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	collection, err := h.collectionService.GetCollection(id, user.ID.String())
+	if err != nil {
+		if err.Error() == "collection not found" {
+			http.Error(w, "Collection not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to fetch collection", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(collection)
+}
+
+func (h *CollectionHandler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Collection ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	collection, err := h.collectionService.UpdateCollection(id, &req, user.ID.String())
+	if err != nil {
+		h.writeCollectionError(w, err, "Failed to update collection")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(collection)
+}
+
+func (h *CollectionHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Collection ID required", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	if err := h.collectionService.DeleteCollection(id, user.ID.String()); err != nil {
+		h.writeCollectionError(w, err, "Failed to delete collection")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CollectionHandler) AddRecipe(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Collection ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		RecipeID string `json:"recipe_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.RecipeID == "" {
+		http.Error(w, "recipe_id is required", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	if err := h.collectionService.AddRecipe(id, req.RecipeID, user.ID.String()); err != nil {
+		h.writeCollectionError(w, err, "Failed to add recipe to collection")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CollectionHandler) RemoveRecipe(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	recipeID := r.PathValue("recipeId")
+	if id == "" || recipeID == "" {
+		http.Error(w, "Collection ID and recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	if err := h.collectionService.RemoveRecipe(id, recipeID, user.ID.String()); err != nil {
+		h.writeCollectionError(w, err, "Failed to remove recipe from collection")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReorderCollection sets the manual order of a collection's recipes.
+func (h *CollectionHandler) ReorderCollection(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Collection ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ReorderCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	collection, err := h.collectionService.ReorderCollection(id, &req, user.ID.String())
+	if err != nil {
+		h.writeCollectionError(w, err, "Failed to reorder collection")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(collection)
+}
+
+func (h *CollectionHandler) writeCollectionError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case err.Error() == "collection not found":
+		http.Error(w, "Collection not found", http.StatusNotFound)
+	case err.Error() == "recipe not found":
+		http.Error(w, "Recipe not found", http.StatusNotFound)
+	case strings.HasPrefix(err.Error(), "unauthorized:"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case isCollectionInputError(err):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+func isCollectionInputError(err error) bool {
+	return err.Error() == "name is required" ||
+		strings.HasPrefix(err.Error(), "visibility ") ||
+		strings.HasPrefix(err.Error(), "recipe_ids ")
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/services"
+)
+
+type CommentHandler struct {
+	commentService *services.CommentService
+}
+
+func NewCommentHandler(commentService *services.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+func (h *CommentHandler) ListRecipeComments(w http.ResponseWriter, r *http.Request) {
+	recipeID := r.PathValue("id")
+	if recipeID == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	limit, cursor := pageParams(r)
+
+	page, err := h.commentService.ListRecipeComments(recipeID, limit, cursor)
+	if err != nil {
+		h.writeListError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func (h *CommentHandler) ListVariationComments(w http.ResponseWriter, r *http.Request) {
+	recipeID := r.PathValue("id")
+	variationID := r.PathValue("variationId")
+	if recipeID == "" || variationID == "" {
+		http.Error(w, "Recipe ID and variation ID required", http.StatusBadRequest)
+		return
+	}
+
+	limit, cursor := pageParams(r)
+
+	page, err := h.commentService.ListVariationComments(recipeID, variationID, limit, cursor)
+	if err != nil {
+		h.writeListError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// ListRecentComments returns the latest comments across all recipes for
+// moderators.
+func (h *CommentHandler) ListRecentComments(w http.ResponseWriter, r *http.Request) {
+	limit, cursor := pageParams(r)
+
+	page, err := h.commentService.ListRecentComments(limit, cursor)
+	if err != nil {
+		h.writeListError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func (h *CommentHandler) CreateRecipeComment(w http.ResponseWriter, r *http.Request) {
+	recipeID := r.PathValue("id")
+	if recipeID == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	comment, err := h.commentService.CreateRecipeComment(recipeID, &req, user.ID.String())
+	if err != nil {
+		h.writeCreateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+func (h *CommentHandler) CreateVariationComment(w http.ResponseWriter, r *http.Request) {
+	recipeID := r.PathValue("id")
+	variationID := r.PathValue("variationId")
+	if recipeID == "" || variationID == "" {
+		http.Error(w, "Recipe ID and variation ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	comment, err := h.commentService.CreateVariationComment(recipeID, variationID, &req, user.ID.String())
+	if err != nil {
+		h.writeCreateError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+func (h *CommentHandler) GetComment(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Comment ID required", http.StatusBadRequest)
+		return
+	}
+
+	comment, err := h.commentService.GetComment(id)
+	if err != nil {
+		http.Error(w, "Comment not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
+func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Comment ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	comment, err := h.commentService.UpdateComment(id, &req, user.ID.String())
+	if err != nil {
+		switch {
+		case err.Error() == "comment not found":
+			http.Error(w, "Comment not found", http.StatusNotFound)
+		case strings.HasPrefix(err.Error(), "unauthorized:"):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case strings.HasPrefix(err.Error(), "body "):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to update comment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
+// DeleteComment removes one of the caller's comments. Admins may remove
+// anyone's.
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Comment ID required", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	if err := h.commentService.DeleteComment(id, user.ID.String(), user.Role == "admin"); err != nil {
+		switch {
+		case err.Error() == "comment not found":
+			http.Error(w, "Comment not found", http.StatusNotFound)
+		case strings.HasPrefix(err.Error(), "unauthorized:"):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "Failed to delete comment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CommentHandler) writeListError(w http.ResponseWriter, err error) {
+	switch err.Error() {
+	case "recipe not found":
+		http.Error(w, "Recipe not found", http.StatusNotFound)
+	case "variation not found":
+		http.Error(w, "Variation not found", http.StatusNotFound)
+	case "invalid cursor":
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Failed to fetch comments", http.StatusInternalServerError)
+	}
+}
+
+func (h *CommentHandler) writeCreateError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "recipe not found":
+		http.Error(w, "Recipe not found", http.StatusNotFound)
+	case err.Error() == "variation not found":
+		http.Error(w, "Variation not found", http.StatusNotFound)
+	case err.Error() == "parent comment not found",
+		strings.HasPrefix(err.Error(), "body "):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Failed to save comment", http.StatusInternalServerError)
+	}
+}
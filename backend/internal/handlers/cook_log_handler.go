@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/services"
+)
+
+// maxCookLogUpload bounds a cook log request with its photos.
+const maxCookLogUpload = 64 << 20
+
+type CookLogHandler struct {
+	cookLogService *services.CookLogService
+}
+
+func NewCookLogHandler(cookLogService *services.CookLogService) *CookLogHandler {
+	return &CookLogHandler{
+		cookLogService: cookLogService,
+	}
+}
+
+// CreateCookLog records that the caller made a recipe. The log comes as
+// JSON, or as a multipart form with the same fields plus "photos" files.
+func (h *CookLogHandler) CreateCookLog(w http.ResponseWriter, r *http.Request) {
+	recipeID := r.PathValue("id")
+	if recipeID == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateCookLogRequest
+	var photos []*multipart.FileHeader
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		r.Body = http.MaxBytesReader(w, r.Body, maxCookLogUpload)
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			http.Error(w, "Failed to parse form", http.StatusBadRequest)
+			return
+		}
+		req.CookedOn = r.FormValue("cooked_on")
+		if value := r.FormValue("variation_id"); value != "" {
+			req.VariationID = &value
+		}
+		if value := r.FormValue("rating"); value != "" {
+			rating, err := strconv.Atoi(value)
+			if err != nil {
+				http.Error(w, "Invalid rating value", http.StatusBadRequest)
+				return
+			}
+			req.Rating = &rating
+		}
+		if value := r.FormValue("notes"); value != "" {
+			req.Notes = &value
+		}
+		photos = r.MultipartForm.File["photos"]
+		for _, photo := range photos {
+			if contentType := photo.Header.Get("Content-Type"); contentType != "" && !isValidImageType(contentType) {
+				http.Error(w, "Invalid file type. Only JPEG, PNG, GIF, and WebP are allowed", http.StatusBadRequest)
+				return
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	cookLog, err := h.cookLogService.CreateCookLog(recipeID, &req, photos, user.ID.String())
+	if err != nil {
+		switch {
+		case err.Error() == "recipe not found":
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+		case err.Error() == "variation not found",
+			strings.HasPrefix(err.Error(), "cooked_on "),
+			strings.HasPrefix(err.Error(), "rating "),
+			strings.HasPrefix(err.Error(), "at most "),
+			strings.HasPrefix(err.Error(), "invalid photo "):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to save cook log", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cookLog)
+}
+
+func (h *CookLogHandler) ListRecipeCookLogs(w http.ResponseWriter, r *http.Request) {
+	recipeID := r.PathValue("id")
+	if recipeID == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	limit, cursor := pageParams(r)
+
+	page, err := h.cookLogService.ListRecipeCookLogs(recipeID, limit, cursor)
+	if err != nil {
+		switch err.Error() {
+		case "recipe not found":
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+		case "invalid cursor":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to fetch cook logs", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// ListUserCookLogs returns a user's cooking history.
+func (h *CookLogHandler) ListUserCookLogs(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("id")
+	if userID == "" {
+		http.Error(w, "User ID required", http.StatusBadRequest)
+		return
+	}
+
+	limit, cursor := pageParams(r)
+
+	page, err := h.cookLogService.ListUserCookLogs(userID, limit, cursor)
+	if err != nil {
+		switch err.Error() {
+		case "user not found":
+			http.Error(w, "User not found", http.StatusNotFound)
+		case "invalid cursor":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to fetch cook logs", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func (h *CookLogHandler) GetCookLog(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Cook log ID required", http.StatusBadRequest)
+		return
+	}
+
+	cookLog, err := h.cookLogService.GetCookLog(id)
+	if err != nil {
+		http.Error(w, "Cook log not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cookLog)
+}
+
+func (h *CookLogHandler) DeleteCookLog(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Cook log ID required", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	if err := h.cookLogService.DeleteCookLog(id, user.ID.String()); err != nil {
+		switch err.Error() {
+		case "unauthorized: you can only delete your own cook logs":
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case "cook log not found":
+			http.Error(w, "Cook log not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to delete cook log", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
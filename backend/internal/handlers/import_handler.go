@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/services"
+)
+
+// maxImportUpload bounds the size of an uploaded export, images included.
+const maxImportUpload = 100 << 20
+
+type ImportHandler struct {
+	importService *services.ImportService
+}
+
+func NewImportHandler(importService *services.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+func (h *ImportHandler) ImportURL(w http.ResponseWriter, r *http.Request) {
+	var req models.ImportURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	recipe, err := h.importService.ImportURL(req.URL, user.ID.String())
+	if err != nil {
+		switch {
+		case err.Error() == "invalid URL":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case err.Error() == "no recipe found on page" || err.Error() == "title is required":
+			http.Error(w, "No recipe found on page", http.StatusUnprocessableEntity)
+		case strings.HasPrefix(err.Error(), "failed to fetch page"):
+			http.Error(w, "Failed to fetch page", http.StatusBadGateway)
+		default:
+			http.Error(w, "Failed to import recipe", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(recipe)
+}
+
+// ImportFile imports an uploaded export of another recipe manager. The
+// multipart form carries the file, optionally its format, and the dry_run
+// and publish flags. A dry run previews the recipes without saving them.
+func (h *ImportHandler) ImportFile(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportUpload)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "No file provided", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusBadRequest)
+		return
+	}
+
+	req := models.ImportFileRequest{
+		Format:   r.FormValue("format"),
+		Filename: header.Filename,
+	}
+	for name, flag := range map[string]*bool{"dry_run": &req.DryRun, "publish": &req.Publish} {
+		if value := r.FormValue(name); value != "" {
+			if *flag, err = strconv.ParseBool(value); err != nil {
+				http.Error(w, "Invalid "+name+" value", http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	result, err := h.importService.ImportFile(&req, data, user.ID.String())
+	if err != nil {
+		switch {
+		case err.Error() == "unsupported import format":
+			http.Error(w, "Unsupported import format", http.StatusBadRequest)
+		case err.Error() == "no recipes found in file":
+			http.Error(w, "No recipes found in file", http.StatusUnprocessableEntity)
+		case strings.HasPrefix(err.Error(), "invalid ") || strings.Contains(err.Error(), ": invalid "):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, "Failed to import recipes", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !req.DryRun {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// pageParams reads the limit and cursor query parameters shared by the list
+// endpoints. Invalid limits are ignored; the services apply the default and
+// maximum page size.
+func pageParams(r *http.Request) (int, string) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	return limit, r.URL.Query().Get("cursor")
+}
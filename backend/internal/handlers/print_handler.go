@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/services"
+)
+
+type PrintHandler struct {
+	printService *services.PrintService
+}
+
+func NewPrintHandler(printService *services.PrintService) *PrintHandler {
+	return &PrintHandler{
+		printService: printService,
+	}
+}
+
+// GetRecipeCard returns the recipe as a printable PDF card. size= picks the
+// page size: a5 (the default), a4 or letter.
+func (h *PrintHandler) GetRecipeCard(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	doc, err := h.printService.RecipeCard(id, r.URL.Query().Get("size"))
+	writePDF(w, doc, err)
+}
+
+// GetGroupCookbook returns the group's published recipes as a PDF cookbook
+// with a cover, contents and a chapter per category. size= defaults to a4.
+func (h *PrintHandler) GetGroupCookbook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Group ID required", http.StatusBadRequest)
+		return
+	}
+
+	doc, err := h.printService.GroupCookbook(id, r.URL.Query().Get("size"))
+	writePDF(w, doc, err)
+}
+
+// GetCategoryCookbook returns the category's published recipes as a PDF
+// cookbook. size= defaults to a4.
+func (h *PrintHandler) GetCategoryCookbook(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Category ID required", http.StatusBadRequest)
+		return
+	}
+
+	doc, err := h.printService.CategoryCookbook(id, r.URL.Query().Get("size"))
+	writePDF(w, doc, err)
+}
+
+func writePDF(w http.ResponseWriter, doc *models.PDFDocument, err error) {
+	if err != nil {
+		switch err.Error() {
+		case "invalid page size":
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case "recipe not found", "group not found", "category not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case "no recipes to print":
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			http.Error(w, "Failed to render PDF", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", doc.Filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(doc.Content)))
+	w.Write(doc.Content)
+}
@@ -19,16 +19,20 @@ func NewRecipeGroupHandler(service *services.RecipeGroupService) *RecipeGroupHan
 }
 
 func (h *RecipeGroupHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
-	groups, err := h.service.List()
+	limit, cursor := pageParams(r)
+
+	groups, err := h.service.List(limit, cursor)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if err.Error() == "invalid cursor" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"groups": groups,
-	})
+	json.NewEncoder(w).Encode(groups)
 }
 
 func (h *RecipeGroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
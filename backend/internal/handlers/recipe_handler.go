@@ -1,9 +1,15 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/homecooking/backend/internal/models"
 	"github.com/homecooking/backend/internal/services"
@@ -11,36 +17,55 @@ import (
 
 type RecipeHandler struct {
 	recipeService *services.RecipeService
+	baseURL       string
 }
 
-func NewRecipeHandler(recipeService *services.RecipeService) *RecipeHandler {
+func NewRecipeHandler(recipeService *services.RecipeService, baseURL string) *RecipeHandler {
 	return &RecipeHandler{
 		recipeService: recipeService,
+		baseURL:       baseURL,
 	}
 }
 
 func (h *RecipeHandler) ListRecipes(w http.ResponseWriter, r *http.Request) {
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	limit := 20
-	offset := 0
+	limit, cursor := pageParams(r)
+
+	query := r.URL.Query()
+	filter := &models.RecipeFilter{
+		DietaryFilter: dietaryParams(r),
+		Category:      query.Get("category"),
+		Tags:          listParam(r, "tag"),
+		Difficulty:    query.Get("difficulty"),
+		AuthorID:      query.Get("author"),
+		Group:         query.Get("group"),
+		Sort:          query.Get("sort"),
+	}
 
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil {
-			limit = l
-		}
+	switch query.Get("tag_match") {
+	case "", "any":
+	case "all":
+		filter.MatchAllTags = true
+	default:
+		http.Error(w, "Invalid tag_match", http.StatusBadRequest)
+		return
 	}
 
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil {
-			offset = o
+	if maxTimeStr := query.Get("max_time"); maxTimeStr != "" {
+		maxTime, err := strconv.Atoi(maxTimeStr)
+		if err != nil || maxTime <= 0 {
+			http.Error(w, "Invalid max_time", http.StatusBadRequest)
+			return
 		}
+		filter.MaxTotalTime = &maxTime
 	}
 
-	recipes, err := h.recipeService.ListRecipes(limit, offset)
+	recipes, err := h.recipeService.FilterRecipes(filter, limit, cursor)
 	if err != nil {
-		http.Error(w, "Failed to fetch recipes", http.StatusInternalServerError)
+		if err.Error() == "invalid sort option" || err.Error() == "invalid author ID" || err.Error() == "invalid cursor" || isDietaryFilterError(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to fetch recipes", http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -55,12 +80,36 @@ func (h *RecipeHandler) GetRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	units, err := services.ParseUnitSystem(r.URL.Query().Get("units"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	renderHTML, err := renderParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept")
+	if wantsJSONLD(r) {
+		h.writeRecipeJSONLD(w, id, units)
+		return
+	}
+
 	recipe, err := h.recipeService.GetRecipe(id)
 	if err != nil {
 		http.Error(w, "Recipe not found", http.StatusNotFound)
 		return
 	}
 
+	recipe.MarkdownContent = services.ConvertContent(recipe.MarkdownContent, units)
+	recipe.Ingredients = services.ConvertIngredients(recipe.Ingredients, units)
+	if renderHTML {
+		recipe.HTMLContent = services.RenderMarkdown(recipe.MarkdownContent)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(recipe)
 }
@@ -72,16 +121,227 @@ func (h *RecipeHandler) GetRecipeBySlug(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	units, err := services.ParseUnitSystem(r.URL.Query().Get("units"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	renderHTML, err := renderParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	recipe, err := h.recipeService.GetRecipeBySlug(slug)
 	if err != nil {
 		http.Error(w, "Recipe not found", http.StatusNotFound)
 		return
 	}
 
+	// Found through a slug the recipe used before it was renamed.
+	if recipe.Slug != slug {
+		location := "/api/v1/recipes/slug/" + url.PathEscape(recipe.Slug)
+		if r.URL.RawQuery != "" {
+			location += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, location, http.StatusMovedPermanently)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept")
+	if wantsJSONLD(r) {
+		h.writeRecipeJSONLD(w, recipe.ID.String(), units)
+		return
+	}
+
+	recipe.MarkdownContent = services.ConvertContent(recipe.MarkdownContent, units)
+	recipe.Ingredients = services.ConvertIngredients(recipe.Ingredients, units)
+	if renderHTML {
+		recipe.HTMLContent = services.RenderMarkdown(recipe.MarkdownContent)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(recipe)
 }
 
+// GetRecipeJSONLD returns the recipe as a schema.org Recipe, the same
+// representation GetRecipe gives for Accept: application/ld+json.
+func (h *RecipeHandler) GetRecipeJSONLD(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	units, err := services.ParseUnitSystem(r.URL.Query().Get("units"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.writeRecipeJSONLD(w, id, units)
+}
+
+// jsonLDIncludes are the sub-resources the JSON-LD representation uses.
+var jsonLDIncludes = []string{models.RecipeIncludeCategory, models.RecipeIncludeTags}
+
+func (h *RecipeHandler) writeRecipeJSONLD(w http.ResponseWriter, id string, units services.UnitSystem) {
+	full, err := h.recipeService.GetFullRecipe(id, jsonLDIncludes)
+	if err != nil {
+		http.Error(w, "Recipe not found", http.StatusNotFound)
+		return
+	}
+
+	full.Recipe.MarkdownContent = services.ConvertContent(full.Recipe.MarkdownContent, units)
+	full.Recipe.Ingredients = services.ConvertIngredients(full.Recipe.Ingredients, units)
+
+	w.Header().Set("Content-Type", "application/ld+json")
+	json.NewEncoder(w).Encode(services.RecipeJSONLD(full, h.baseURL))
+}
+
+// wantsJSONLD reports whether the Accept header asks for JSON-LD.
+func wantsJSONLD(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(accept); err == nil && mediaType == "application/ld+json" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFullRecipe returns the recipe together with its category, author, tags,
+// images, groups and published variations. include=tags,images narrows the
+// response to the listed sub-resources.
+func (h *RecipeHandler) GetFullRecipe(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	units, err := services.ParseUnitSystem(r.URL.Query().Get("units"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	renderHTML, err := renderParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	full, err := h.recipeService.GetFullRecipe(id, listParam(r, "include"))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid include") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+		}
+		return
+	}
+
+	writeFullRecipe(w, full, units, renderHTML)
+}
+
+func (h *RecipeHandler) GetFullRecipeBySlug(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+	if slug == "" {
+		http.Error(w, "Slug required", http.StatusBadRequest)
+		return
+	}
+
+	units, err := services.ParseUnitSystem(r.URL.Query().Get("units"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	renderHTML, err := renderParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	full, err := h.recipeService.GetFullRecipeBySlug(slug, listParam(r, "include"))
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid include") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+		}
+		return
+	}
+
+	if full.Recipe.Slug != slug {
+		location := "/api/v1/recipes/slug/" + url.PathEscape(full.Recipe.Slug) + "/full"
+		if r.URL.RawQuery != "" {
+			location += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, location, http.StatusMovedPermanently)
+		return
+	}
+
+	writeFullRecipe(w, full, units, renderHTML)
+}
+
+func writeFullRecipe(w http.ResponseWriter, full *models.RecipeWithVariations, units services.UnitSystem, renderHTML bool) {
+	full.Recipe.MarkdownContent = services.ConvertContent(full.Recipe.MarkdownContent, units)
+	full.Recipe.Ingredients = services.ConvertIngredients(full.Recipe.Ingredients, units)
+	if renderHTML {
+		full.Recipe.HTMLContent = services.RenderMarkdown(full.Recipe.MarkdownContent)
+	}
+	for i := range full.Variations {
+		full.Variations[i].MarkdownContent = services.ConvertContent(full.Variations[i].MarkdownContent, units)
+		if renderHTML {
+			full.Variations[i].HTMLContent = services.RenderMarkdown(full.Variations[i].MarkdownContent)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(full)
+}
+
+// listParam reads a query parameter as a comma-separated list, also
+// accepting the parameter more than once.
+func listParam(r *http.Request, name string) []string {
+	var list []string
+	for _, value := range r.URL.Query()[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// renderParam reads render=html, which asks for the markdown to be rendered
+// into html_content as well.
+func renderParam(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("render") {
+	case "":
+		return false, nil
+	case "html":
+		return true, nil
+	}
+	return false, errors.New("invalid render option")
+}
+
+// dietaryParams reads the free_from= allergens and diet= labels shared by
+// the list and search endpoints.
+func dietaryParams(r *http.Request) models.DietaryFilter {
+	return models.DietaryFilter{
+		FreeFrom: listParam(r, "free_from"),
+		Diets:    listParam(r, "diet"),
+	}
+}
+
+func isDietaryFilterError(err error) bool {
+	return strings.HasPrefix(err.Error(), "invalid allergen") || strings.HasPrefix(err.Error(), "invalid diet")
+}
+
 func (h *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateRecipeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -184,6 +444,42 @@ func (h *RecipeHandler) PublishRecipe(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(recipe)
 }
 
+// ForkRecipe copies a recipe into a new one owned by the caller. The body
+// is optional; without a title the fork keeps the original's.
+func (h *RecipeHandler) ForkRecipe(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ForkRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	recipe, err := h.recipeService.ForkRecipe(id, &req, user.ID.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+		} else if err.Error() == "can only fork published recipes" {
+			http.Error(w, err.Error(), http.StatusForbidden)
+		} else if err.Error() == "title is required" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to fork recipe", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(recipe)
+}
+
 func (h *RecipeHandler) SearchRecipes(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
@@ -191,30 +487,112 @@ func (h *RecipeHandler) SearchRecipes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	limit := 20
-	offset := 0
+	limit, cursor := pageParams(r)
 
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil {
-			limit = l
+	recipes, err := h.recipeService.SearchRecipes(query, dietaryParams(r), limit, cursor)
+	if err != nil {
+		if err.Error() == "invalid cursor" || isDietaryFilterError(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to search recipes", http.StatusInternalServerError)
 		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recipes)
+}
+
+func (h *RecipeHandler) GetScaledRecipe(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
 	}
 
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil {
-			offset = o
+	servings, err := strconv.Atoi(r.URL.Query().Get("servings"))
+	if err != nil || servings <= 0 {
+		http.Error(w, "Invalid servings", http.StatusBadRequest)
+		return
+	}
+
+	units, err := services.ParseUnitSystem(r.URL.Query().Get("units"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scaled, err := h.recipeService.ScaleRecipe(id, servings)
+	if err != nil {
+		if err.Error() == "recipe has no servings to scale from" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Recipe not found", http.StatusNotFound)
 		}
+		return
 	}
 
-	recipes, err := h.recipeService.SearchRecipes(query, limit, offset)
+	scaled.MarkdownContent = services.ConvertContent(scaled.MarkdownContent, units)
+	scaled.Ingredients = services.ConvertIngredients(scaled.Ingredients, units)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scaled)
+}
+
+// GetRecipeSteps returns the recipe's method one step at a time, with the
+// timers and ingredients of each step, for cook mode.
+func (h *RecipeHandler) GetRecipeSteps(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	units, err := services.ParseUnitSystem(r.URL.Query().Get("units"))
 	if err != nil {
-		http.Error(w, "Failed to search recipes", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	steps, err := h.recipeService.GetSteps(id)
+	if err != nil {
+		http.Error(w, "Recipe not found", http.StatusNotFound)
+		return
+	}
+
+	for i := range steps.Steps {
+		steps.Steps[i].Ingredients = services.ConvertIngredients(steps.Steps[i].Ingredients, units)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(recipes)
+	json.NewEncoder(w).Encode(steps)
+}
+
+// ShoppingList adds up the ingredients of several recipes, each at the
+// servings asked for, with their sub-recipes' ingredients included.
+func (h *RecipeHandler) ShoppingList(w http.ResponseWriter, r *http.Request) {
+	units, err := services.ParseUnitSystem(r.URL.Query().Get("units"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req models.ShoppingListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.recipeService.ShoppingList(&req, units)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
 }
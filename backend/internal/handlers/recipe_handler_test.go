@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/repository"
+	"github.com/homecooking/backend/internal/services"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRecipeHandler(db *sql.DB, q *sqlc.Queries) *RecipeHandler {
+	return NewRecipeHandler(services.NewRecipeService(
+		repository.NewRecipeRepository(db, q),
+		repository.NewRecipeIngredientRepository(db, q),
+		repository.NewRecipeRevisionRepository(db, q),
+		repository.NewTagRepository(db, q),
+	), "http://localhost:4321")
+}
+
+func TestRecipeHandler_JSONLD_MalformedID(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	handler := newTestRecipeHandler(db, q)
+
+	req := httptest.NewRequest("GET", "/api/v1/recipes/not-a-uuid/jsonld", nil)
+	req.SetPathValue("id", "not-a-uuid")
+	w := httptest.NewRecorder()
+	handler.GetRecipeJSONLD(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req = httptest.NewRequest("GET", "/api/v1/recipes/not-a-uuid", nil)
+	req.SetPathValue("id", "not-a-uuid")
+	req.Header.Set("Accept", "application/ld+json")
+	w = httptest.NewRecorder()
+	handler.GetRecipe(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRecipeHandler_GetRecipeSteps_MalformedID(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	handler := newTestRecipeHandler(db, q)
+
+	req := httptest.NewRequest("GET", "/api/v1/recipes/not-a-uuid/steps", nil)
+	req.SetPathValue("id", "not-a-uuid")
+	w := httptest.NewRecorder()
+	handler.GetRecipeSteps(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -69,7 +69,11 @@ func (h *ShareCodeHandler) GetShareCodesForRecipe(w http.ResponseWriter, r *http
 
 	shareCodes, err := h.shareCodeService.GetShareCodesForRecipe(recipeID)
 	if err != nil {
-		http.Error(w, "Failed to fetch share codes", http.StatusInternalServerError)
+		if err.Error() == "recipe not found" {
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to fetch share codes", http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -97,6 +101,7 @@ type AccessRecipeByShareCodeResponse struct {
 	Title             string     `json:"title"`
 	Slug              string     `json:"slug"`
 	MarkdownContent   string     `json:"markdown_content"`
+	HTMLContent       string     `json:"html_content"`
 	Description       *string    `json:"description"`
 	PrepTimeMinutes   *int       `json:"prep_time_minutes"`
 	CookTimeMinutes   *int       `json:"cook_time_minutes"`
@@ -107,6 +112,8 @@ type AccessRecipeByShareCodeResponse struct {
 	CategoryID        *uuid.UUID `json:"category_id"`
 }
 
+// AccessRecipeByShareCode returns the shared recipe with its markdown
+// rendered, for the share page.
 func (h *ShareCodeHandler) AccessRecipeByShareCode(w http.ResponseWriter, r *http.Request) {
 	code := r.PathValue("code")
 	if code == "" {
@@ -114,18 +121,35 @@ func (h *ShareCodeHandler) AccessRecipeByShareCode(w http.ResponseWriter, r *htt
 		return
 	}
 
-	shareCode, err := h.shareCodeService.GetShareCode(code)
+	recipe, err := h.shareCodeService.AccessRecipe(code)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
 
-	if err := h.shareCodeService.UseShareCode(code); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(shareCode)
+	json.NewEncoder(w).Encode(AccessRecipeByShareCodeResponse{
+		ID:                recipe.ID,
+		Title:             recipe.Title,
+		Slug:              recipe.Slug,
+		MarkdownContent:   recipe.MarkdownContent,
+		HTMLContent:       services.RenderMarkdown(recipe.MarkdownContent),
+		Description:       recipe.Description,
+		PrepTimeMinutes:   intPtr(recipe.PrepTimeMinutes),
+		CookTimeMinutes:   intPtr(recipe.CookTimeMinutes),
+		Servings:          intPtr(recipe.Servings),
+		Difficulty:        recipe.Difficulty,
+		FeaturedImagePath: recipe.FeaturedImagePath,
+		PublishedAt:       recipe.PublishedAt,
+		CategoryID:        recipe.CategoryID,
+	})
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
 }
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/homecooking/backend/internal/models"
 	"github.com/homecooking/backend/internal/services"
@@ -19,9 +20,15 @@ func NewTagHandler(tagService *services.TagService) *TagHandler {
 }
 
 func (h *TagHandler) ListTags(w http.ResponseWriter, r *http.Request) {
-	tags, err := h.tagService.ListTags()
+	limit, cursor := pageParams(r)
+
+	tags, err := h.tagService.ListTags(limit, cursor)
 	if err != nil {
-		http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+		if err.Error() == "invalid cursor" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -112,6 +119,10 @@ func (h *TagHandler) GetRecipeTags(w http.ResponseWriter, r *http.Request) {
 
 	tags, err := h.tagService.GetRecipeTags(recipeID)
 	if err != nil {
+		if err.Error() == "recipe not found" {
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+			return
+		}
 		http.Error(w, "Failed to fetch recipe tags", http.StatusInternalServerError)
 		return
 	}
@@ -120,6 +131,31 @@ func (h *TagHandler) GetRecipeTags(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(tags)
 }
 
+func (h *TagHandler) SetRecipeTags(w http.ResponseWriter, r *http.Request) {
+	recipeID := r.PathValue("recipeId")
+	if recipeID == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetRecipeTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	tags, err := h.tagService.SetRecipeTags(recipeID, req.Tags, user.ID.String())
+	if err != nil {
+		writeRecipeTagError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tags)
+}
+
 func (h *TagHandler) AddTagToRecipe(w http.ResponseWriter, r *http.Request) {
 	recipeID := r.PathValue("recipeId")
 	tagID := r.PathValue("tagId")
@@ -129,9 +165,11 @@ func (h *TagHandler) AddTagToRecipe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.tagService.AddTagToRecipe(recipeID, tagID)
+	user := r.Context().Value("user").(*models.User)
+
+	err := h.tagService.AddTagToRecipe(recipeID, tagID, user.ID.String())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeRecipeTagError(w, err)
 		return
 	}
 
@@ -147,11 +185,24 @@ func (h *TagHandler) RemoveTagFromRecipe(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := h.tagService.RemoveTagFromRecipe(recipeID, tagID)
+	user := r.Context().Value("user").(*models.User)
+
+	err := h.tagService.RemoveTagFromRecipe(recipeID, tagID, user.ID.String())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeRecipeTagError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func writeRecipeTagError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "recipe not found":
+		http.Error(w, "Recipe not found", http.StatusNotFound)
+	case strings.HasPrefix(err.Error(), "unauthorized:"):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/services"
+)
+
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+func NewTrashHandler(trashService *services.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// ListTrash returns the caller's deleted recipes.
+func (h *TrashHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value("user").(*models.User)
+	limit, cursor := pageParams(r)
+
+	page, err := h.trashService.ListTrash(user.ID.String(), limit, cursor)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to fetch trash", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+func (h *TrashHandler) RestoreRecipe(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		http.Error(w, "Recipe ID required", http.StatusBadRequest)
+		return
+	}
+
+	user := r.Context().Value("user").(*models.User)
+
+	recipe, err := h.trashService.RestoreRecipe(id, user.ID.String())
+	if err != nil {
+		switch err.Error() {
+		case "recipe not found":
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+		case "unauthorized: you can only restore your own recipes":
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "Failed to restore recipe", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recipe)
+}
@@ -62,9 +62,15 @@ func (h *UserInviteHandler) GetInvite(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserInviteHandler) ListInvites(w http.ResponseWriter, r *http.Request) {
-	invites, err := h.inviteService.ListInvites()
+	limit, cursor := pageParams(r)
+
+	invites, err := h.inviteService.ListInvites(limit, cursor)
 	if err != nil {
-		http.Error(w, "Failed to fetch invites", http.StatusInternalServerError)
+		if err.Error() == "invalid cursor" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to fetch invites", http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -26,12 +26,35 @@ func (h *VariationHandler) ListVariations(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	units, err := services.ParseUnitSystem(r.URL.Query().Get("units"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	renderHTML, err := renderParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	variations, err := h.variationService.GetVariationsByRecipe(recipeID)
 	if err != nil {
-		http.Error(w, "Failed to fetch variations", http.StatusInternalServerError)
+		if err.Error() == "recipe not found" {
+			http.Error(w, "Recipe not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to fetch variations", http.StatusInternalServerError)
+		}
 		return
 	}
 
+	for _, variation := range variations {
+		variation.MarkdownContent = services.ConvertContent(variation.MarkdownContent, units)
+		if renderHTML {
+			variation.HTMLContent = services.RenderMarkdown(variation.MarkdownContent)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(variations)
 }
@@ -43,12 +66,29 @@ func (h *VariationHandler) GetVariation(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	units, err := services.ParseUnitSystem(r.URL.Query().Get("units"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	renderHTML, err := renderParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	variation, err := h.variationService.GetVariation(variationID)
 	if err != nil {
 		http.Error(w, "Variation not found", http.StatusNotFound)
 		return
 	}
 
+	variation.MarkdownContent = services.ConvertContent(variation.MarkdownContent, units)
+	if renderHTML {
+		variation.HTMLContent = services.RenderMarkdown(variation.MarkdownContent)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(variation)
 }
@@ -72,6 +112,8 @@ func (h *VariationHandler) CreateVariation(w http.ResponseWriter, r *http.Reques
 	if err != nil {
 		if err.Error() == "markdown content is required" {
 			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else if err.Error() == "recipe not found" {
+			http.Error(w, "Recipe not found", http.StatusNotFound)
 		} else {
 			http.Error(w, "Failed to create variation", http.StatusInternalServerError)
 		}
@@ -168,3 +210,40 @@ func (h *VariationHandler) GetVariationsByAuthor(w http.ResponseWriter, r *http.
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(variations)
 }
+
+func (h *VariationHandler) GetScaledVariation(w http.ResponseWriter, r *http.Request) {
+	recipeID := r.PathValue("id")
+	variationID := r.PathValue("variationId")
+	if variationID == "" {
+		http.Error(w, "Variation ID required", http.StatusBadRequest)
+		return
+	}
+
+	servings, err := strconv.Atoi(r.URL.Query().Get("servings"))
+	if err != nil || servings <= 0 {
+		http.Error(w, "Invalid servings", http.StatusBadRequest)
+		return
+	}
+
+	units, err := services.ParseUnitSystem(r.URL.Query().Get("units"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	scaled, err := h.variationService.ScaleVariation(recipeID, variationID, servings)
+	if err != nil {
+		if err.Error() == "recipe has no servings to scale from" {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, "Variation not found", http.StatusNotFound)
+		}
+		return
+	}
+
+	scaled.MarkdownContent = services.ConvertContent(scaled.MarkdownContent, units)
+	scaled.Ingredients = services.ConvertIngredients(scaled.Ingredients, units)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scaled)
+}
@@ -19,7 +19,7 @@ func TestRecipeServiceIntegration(t *testing.T) {
 	defer TeardownTestServer(server)
 
 	user := GetTestUser(t, server, "recipe@example.com", "password123")
-	recipeService := services.NewRecipeService(repository.NewRecipeRepository(server.DB, server.Queries))
+	recipeService := server.NewRecipeService()
 
 	createReq := models.CreateRecipeRequest{
 		Title:           "Integration Test Recipe",
@@ -45,7 +45,7 @@ func TestRecipeListIntegration(t *testing.T) {
 	defer TeardownTestServer(server)
 
 	user := GetTestUser(t, server, "list@example.com", "password123")
-	recipeService := services.NewRecipeService(repository.NewRecipeRepository(server.DB, server.Queries))
+	recipeService := server.NewRecipeService()
 
 	recipes := []models.CreateRecipeRequest{
 		{Title: "Recipe 1", MarkdownContent: "Content 1", IsPublished: true},
@@ -68,7 +68,7 @@ func TestRecipeDeleteIntegration(t *testing.T) {
 	defer TeardownTestServer(server)
 
 	user := GetTestUser(t, server, "delete@example.com", "password123")
-	recipeService := services.NewRecipeService(repository.NewRecipeRepository(server.DB, server.Queries))
+	recipeService := server.NewRecipeService()
 
 	createReq := models.CreateRecipeRequest{
 		Title:           "Delete Me",
@@ -92,7 +92,7 @@ func TestRecipeOwnershipIntegration(t *testing.T) {
 
 	user1 := GetTestUser(t, server, "owner1@example.com", "password123")
 	user2 := GetTestUser(t, server, "owner2@example.com", "password123")
-	recipeService := services.NewRecipeService(repository.NewRecipeRepository(server.DB, server.Queries))
+	recipeService := server.NewRecipeService()
 
 	createReq := models.CreateRecipeRequest{
 		Title:           "My Recipe",
@@ -112,7 +112,7 @@ func TestCategoryServiceIntegration(t *testing.T) {
 	server := SetupTestServer(t)
 	defer TeardownTestServer(server)
 
-	categoryService := services.NewCategoryService(repository.NewCategoryRepository(server.DB, server.Queries))
+	categoryService := services.NewCategoryService(repository.NewCategoryRepository(server.DB, server.Queries), repository.NewRecipeRepository(server.DB, server.Queries))
 
 	category := &models.Category{
 		Name:        "Integration Category",
@@ -140,7 +140,7 @@ func TestTagServiceIntegration(t *testing.T) {
 	server := SetupTestServer(t)
 	defer TeardownTestServer(server)
 
-	tagService := services.NewTagService(repository.NewTagRepository(server.DB, server.Queries))
+	tagService := services.NewTagService(repository.NewTagRepository(server.DB, server.Queries), repository.NewRecipeRepository(server.DB, server.Queries))
 
 	tag := &models.Tag{
 		Name:  "Integration Tag",
@@ -157,9 +157,9 @@ func TestTagServiceIntegration(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, created.ID, retrieved.ID)
 
-	list, err := tagService.ListTags()
+	list, err := tagService.ListTags(20, "")
 	require.NoError(t, err)
-	assert.Len(t, list, 1)
+	assert.Len(t, list.Items, 1)
 }
 
 func TestRecipeGroupServiceIntegration(t *testing.T) {
@@ -183,7 +183,7 @@ func TestRecipeGroupServiceIntegration(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, created.ID, retrieved.ID)
 
-	list, err := groupService.List()
+	list, err := groupService.List(20, "")
 	require.NoError(t, err)
-	assert.Len(t, list, 1)
+	assert.Len(t, list.Items, 1)
 }
@@ -61,6 +61,16 @@ func SetupTestServer(t *testing.T) *TestServer {
 	}
 }
 
+// NewRecipeService wires a RecipeService against the test database
+func (s *TestServer) NewRecipeService() *services.RecipeService {
+	return services.NewRecipeService(
+		repository.NewRecipeRepository(s.DB, s.Queries),
+		repository.NewRecipeIngredientRepository(s.DB, s.Queries),
+		repository.NewRecipeRevisionRepository(s.DB, s.Queries),
+		repository.NewTagRepository(s.DB, s.Queries),
+	)
+}
+
 // TeardownTestServer closes the test server resources
 func TeardownTestServer(server *TestServer) {
 	if server.DB != nil {
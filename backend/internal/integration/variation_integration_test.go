@@ -15,7 +15,7 @@ func TestVariationService_CreateVariation(t *testing.T) {
 	defer TeardownTestServer(server)
 
 	user := GetTestUser(t, server, "variation@example.com", "password123")
-	recipeService := services.NewRecipeService(repository.NewRecipeRepository(server.DB, server.Queries))
+	recipeService := server.NewRecipeService()
 	variationService := services.NewVariationService(
 		repository.NewVariationRepository(server.DB, server.Queries),
 		repository.NewRecipeRepository(server.DB, server.Queries),
@@ -60,7 +60,7 @@ func TestVariationService_GetVariationsByRecipe(t *testing.T) {
 
 	user1 := CreateTestUser(t, server, "user1@example.com", "password123")
 	user2 := CreateTestUser(t, server, "user2@example.com", "password123")
-	recipeService := services.NewRecipeService(repository.NewRecipeRepository(server.DB, server.Queries))
+	recipeService := server.NewRecipeService()
 	variationService := services.NewVariationService(
 		repository.NewVariationRepository(server.DB, server.Queries),
 		repository.NewRecipeRepository(server.DB, server.Queries),
@@ -101,7 +101,7 @@ func TestVariationService_UpdateVariation(t *testing.T) {
 	defer TeardownTestServer(server)
 
 	user := CreateTestUser(t, server, "variation@example.com", "password123")
-	recipeService := services.NewRecipeService(repository.NewRecipeRepository(server.DB, server.Queries))
+	recipeService := server.NewRecipeService()
 	variationService := services.NewVariationService(
 		repository.NewVariationRepository(server.DB, server.Queries),
 		repository.NewRecipeRepository(server.DB, server.Queries),
@@ -144,7 +144,7 @@ func TestVariationService_DeleteVariation(t *testing.T) {
 	defer TeardownTestServer(server)
 
 	user := CreateTestUser(t, server, "variation@example.com", "password123")
-	recipeService := services.NewRecipeService(repository.NewRecipeRepository(server.DB, server.Queries))
+	recipeService := server.NewRecipeService()
 	variationService := services.NewVariationService(
 		repository.NewVariationRepository(server.DB, server.Queries),
 		repository.NewRecipeRepository(server.DB, server.Queries),
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BackupVersion is the layout version of the archives the admin export
+// writes. Imports refuse archives from newer versions.
+const BackupVersion = 1
+
+// BackupManifest describes an export archive.
+type BackupManifest struct {
+	Version        int            `json:"version"`
+	ExportedAt     time.Time      `json:"exported_at"`
+	PasswordHashes bool           `json:"password_hashes"`
+	Counts         map[string]int `json:"counts"`
+}
+
+// BackupUser is a user as exported. The password hash is left out of
+// exports made without password hashes.
+type BackupUser struct {
+	ID           uuid.UUID `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"password_hash,omitempty"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// BackupRecipe is a recipe with the IDs of its tags.
+type BackupRecipe struct {
+	Recipe
+	TagIDs []uuid.UUID `json:"tag_ids"`
+}
+
+// BackupGroup is a recipe group with its recipes in order.
+type BackupGroup struct {
+	RecipeGroup
+	Recipes []BackupGroupRecipe `json:"recipes"`
+}
+
+type BackupGroupRecipe struct {
+	RecipeID   uuid.UUID `json:"recipe_id"`
+	OrderIndex int       `json:"order_index"`
+}
+
+// BackupCollection is a collection with its recipes in order.
+type BackupCollection struct {
+	Collection
+	Recipes []BackupCollectionRecipe `json:"recipes"`
+}
+
+type BackupCollectionRecipe struct {
+	RecipeID   uuid.UUID `json:"recipe_id"`
+	OrderIndex int       `json:"order_index"`
+	AddedAt    time.Time `json:"added_at"`
+}
+
+// BackupFavorite is a recipe a user marked as a favorite.
+type BackupFavorite struct {
+	UserID    uuid.UUID `json:"user_id"`
+	RecipeID  uuid.UUID `json:"recipe_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BackupComment is a comment with the top-level comment of its thread,
+// which the API leaves out.
+type BackupComment struct {
+	Comment
+	ThreadID *uuid.UUID `json:"thread_id"`
+}
+
+// BackupData is everything an export archive holds apart from the files.
+type BackupData struct {
+	Users           []BackupUser       `json:"users"`
+	Categories      []Category         `json:"categories"`
+	Tags            []Tag              `json:"tags"`
+	Recipes         []BackupRecipe     `json:"recipes"`
+	RecipeImages    []RecipeImage      `json:"recipe_images"`
+	RecipeRevisions []RecipeRevision   `json:"recipe_revisions"`
+	Variations      []RecipeVariation  `json:"variations"`
+	Groups          []BackupGroup      `json:"groups"`
+	ShareCodes      []ShareCode        `json:"share_codes"`
+	CookLogs        []CookLog          `json:"cook_logs"`
+	Collections     []BackupCollection `json:"collections"`
+	Favorites       []BackupFavorite   `json:"favorites"`
+	Comments        []BackupComment    `json:"comments"`
+}
+
+// BackupImportResult counts what an import did with each kind of record:
+// created as they were, matched to an existing record, created under a new
+// ID or slug because theirs was taken, or skipped as duplicates.
+type BackupImportResult struct {
+	Created  map[string]int `json:"created"`
+	Matched  map[string]int `json:"matched"`
+	Remapped map[string]int `json:"remapped"`
+	Skipped  map[string]int `json:"skipped"`
+	Files    int            `json:"files"`
+	// UsersWithoutPassword lists imported users that have to reset their
+	// password because the archive had no hash for them.
+	UsersWithoutPassword []string `json:"users_without_password"`
+	// RecipeIDs are the recipes the import created.
+	RecipeIDs []uuid.UUID `json:"-"`
+}
+
+func NewBackupImportResult() *BackupImportResult {
+	return &BackupImportResult{
+		Created:              map[string]int{},
+		Matched:              map[string]int{},
+		Remapped:             map[string]int{},
+		Skipped:              map[string]int{},
+		UsersWithoutPassword: []string{},
+	}
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Collection visibilities. A private collection is seen only by its owner; a
+// shared one is listed for every signed-in user but edited only by its owner.
+const (
+	CollectionPrivate = "private"
+	CollectionShared  = "shared"
+)
+
+// Collection is a user's own ordered list of recipes. Recipe groups remain
+// the admin-curated public collections.
+type Collection struct {
+	ID          uuid.UUID `json:"id"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	Visibility  string    `json:"visibility"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CollectionWithRecipes struct {
+	Collection
+	Recipes []*Recipe `json:"recipes"`
+}
+
+// CreateCollectionRequest describes a new collection. Visibility defaults to
+// private.
+type CreateCollectionRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Visibility  string  `json:"visibility"`
+}
+
+type UpdateCollectionRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Visibility  *string `json:"visibility"`
+}
+
+// ReorderCollectionRequest lists every recipe of a collection in its new
+// order.
+type ReorderCollectionRequest struct {
+	RecipeIDs []string `json:"recipe_ids"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxCommentLength bounds the body of a comment, in characters.
+const MaxCommentLength = 5000
+
+// Comment is a remark on a recipe, or on one of its variations when
+// VariationID is set. Replies hang off ParentID and are returned nested
+// under the comment they answer. A deleted comment that still has replies
+// is kept with an empty body so the thread stays readable.
+type Comment struct {
+	ID          uuid.UUID  `json:"id"`
+	RecipeID    uuid.UUID  `json:"recipe_id"`
+	VariationID *uuid.UUID `json:"variation_id"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	AuthorID    *uuid.UUID `json:"author_id"`
+	Body        string     `json:"body"`
+	IsDeleted   bool       `json:"is_deleted"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	EditedAt    *time.Time `json:"edited_at"`
+	Replies     []*Comment `json:"replies,omitempty"`
+
+	// ThreadID is the top-level comment a reply belongs to.
+	ThreadID *uuid.UUID `json:"-"`
+}
+
+// CreateCommentRequest describes a new comment. ParentID, when given, makes
+// it a reply.
+type CreateCommentRequest struct {
+	Body     string  `json:"body"`
+	ParentID *string `json:"parent_id"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body"`
+}
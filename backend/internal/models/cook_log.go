@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CookLogDateFormat is the layout of a cook log's date.
+const CookLogDateFormat = "2006-01-02"
+
+// MaxCookLogPhotos bounds how many photos one cook log keeps.
+const MaxCookLogPhotos = 10
+
+// CookLog records one time a user made a recipe, optionally as one of its
+// variations. Photos are upload paths such as "/uploads/cooklog_x.jpg".
+type CookLog struct {
+	ID          uuid.UUID  `json:"id"`
+	RecipeID    uuid.UUID  `json:"recipe_id"`
+	VariationID *uuid.UUID `json:"variation_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	CookedOn    string     `json:"cooked_on"`
+	Rating      *int       `json:"rating"`
+	Notes       *string    `json:"notes"`
+	Photos      []string   `json:"photos"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// RecipeTitle and RecipeSlug are filled in on a user's history.
+	RecipeTitle string `json:"recipe_title,omitempty"`
+	RecipeSlug  string `json:"recipe_slug,omitempty"`
+}
+
+// CreateCookLogRequest describes a cook. CookedOn is a date such as
+// "2024-03-01" and defaults to today; Rating, when given, is 1 to 5.
+type CreateCookLogRequest struct {
+	CookedOn    string  `json:"cooked_on"`
+	VariationID *string `json:"variation_id"`
+	Rating      *int    `json:"rating"`
+	Notes       *string `json:"notes"`
+}
+
+// CookStats sums up a recipe's cook logs, with a breakdown for each
+// variation that was cooked. AverageRating is nil until a cook is rated.
+type CookStats struct {
+	TimesCooked   int                  `json:"times_cooked"`
+	AverageRating *float64             `json:"average_rating"`
+	LastCookedOn  *string              `json:"last_cooked_on"`
+	Variations    []VariationCookStats `json:"variations,omitempty"`
+}
+
+type VariationCookStats struct {
+	VariationID   uuid.UUID `json:"variation_id"`
+	TimesCooked   int       `json:"times_cooked"`
+	AverageRating *float64  `json:"average_rating"`
+	LastCookedOn  *string   `json:"last_cooked_on"`
+}
@@ -0,0 +1,52 @@
+package models
+
+// Allergens detected in recipe ingredients.
+const (
+	AllergenGluten    = "gluten"
+	AllergenDairy     = "dairy"
+	AllergenEgg       = "egg"
+	AllergenNuts      = "nuts"
+	AllergenPeanuts   = "peanuts"
+	AllergenShellfish = "shellfish"
+	AllergenSoy       = "soy"
+	AllergenSesame    = "sesame"
+)
+
+// Allergens lists every allergen in the order they are reported.
+var Allergens = []string{
+	AllergenGluten,
+	AllergenDairy,
+	AllergenEgg,
+	AllergenNuts,
+	AllergenPeanuts,
+	AllergenShellfish,
+	AllergenSoy,
+	AllergenSesame,
+}
+
+// Diets a recipe can be labelled with.
+const (
+	DietVegetarian = "vegetarian"
+	DietVegan      = "vegan"
+	DietGlutenFree = "gluten-free"
+	DietDairyFree  = "dairy-free"
+	DietNutFree    = "nut-free"
+)
+
+// Diets lists every diet in the order they are reported.
+var Diets = []string{
+	DietVegetarian,
+	DietVegan,
+	DietGlutenFree,
+	DietDairyFree,
+	DietNutFree,
+}
+
+// RecipeDietary is the allergen and diet classification of a recipe's
+// ingredient list. It is keyword based and errs towards reporting an
+// allergen, so an empty Allergens list means nothing known was found rather
+// than a guarantee.
+type RecipeDietary struct {
+	Allergens []string `json:"allergens"`
+	Diets     []string `json:"diets"`
+}
@@ -0,0 +1,50 @@
+package models
+
+type ImportURLRequest struct {
+	URL string `json:"url"`
+}
+
+// ImportFileRequest describes an uploaded export of another recipe manager.
+// Format is detected from the filename when it is empty.
+type ImportFileRequest struct {
+	Format   string `json:"format"`
+	Filename string `json:"filename"`
+	DryRun   bool   `json:"dry_run"`
+	Publish  bool   `json:"publish"`
+}
+
+// ImportedRecipe is a recipe read from an export, before it is saved. The
+// category is given by name and created when no category has its slug.
+type ImportedRecipe struct {
+	Recipe   CreateRecipeRequest `json:"recipe"`
+	Category string              `json:"category,omitempty"`
+	Images   []ImportedImage     `json:"-"`
+	Warnings []string            `json:"warnings,omitempty"`
+}
+
+// ImportedImage is a photo that came with an imported recipe. Ext is the
+// file extension it is saved with, including the dot.
+type ImportedImage struct {
+	Data []byte
+	Ext  string
+}
+
+// ImportResult reports on a file import. A dry run previews the recipes
+// without saving them; otherwise Created holds each saved recipe and Error
+// why a recipe couldn't be saved.
+type ImportResult struct {
+	Format   string       `json:"format"`
+	DryRun   bool         `json:"dry_run"`
+	Recipes  []ImportItem `json:"recipes"`
+	Imported int          `json:"imported"`
+	Failed   int          `json:"failed"`
+}
+
+type ImportItem struct {
+	Recipe     CreateRecipeRequest `json:"recipe"`
+	Category   string              `json:"category,omitempty"`
+	ImageCount int                 `json:"image_count"`
+	Warnings   []string            `json:"warnings,omitempty"`
+	Created    *Recipe             `json:"created,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
@@ -0,0 +1,29 @@
+package models
+
+// Confidence levels of a nutrition estimate.
+const (
+	NutritionConfidenceHigh   = "high"
+	NutritionConfidenceMedium = "medium"
+	NutritionConfidenceLow    = "low"
+)
+
+// Nutrition is an estimate computed from a recipe's ingredient list against
+// the bundled nutrient table. Unmatched lists the ingredient lines that could
+// not be counted, either because the ingredient is unknown or because its
+// amount can't be turned into grams; Confidence drops as that list grows.
+type Nutrition struct {
+	Servings   int             `json:"servings"`
+	PerServing NutrientAmounts `json:"per_serving"`
+	Total      NutrientAmounts `json:"total"`
+	Confidence string          `json:"confidence"`
+	Unmatched  []string        `json:"unmatched"`
+}
+
+type NutrientAmounts struct {
+	Calories      float64 `json:"calories"`
+	ProteinG      float64 `json:"protein_g"`
+	FatG          float64 `json:"fat_g"`
+	CarbohydrateG float64 `json:"carbohydrate_g"`
+	FiberG        float64 `json:"fiber_g"`
+	SodiumMg      float64 `json:"sodium_mg"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// Page is the envelope returned by every list endpoint. NextCursor is an
+// opaque token for the following page and is null on the last one; Total
+// counts all matching items, not just those on the page.
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+	Total      int     `json:"total"`
+}
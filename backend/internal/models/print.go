@@ -0,0 +1,7 @@
+package models
+
+// PDFDocument is a rendered PDF and the filename to download it as.
+type PDFDocument struct {
+	Filename string
+	Content  []byte
+}
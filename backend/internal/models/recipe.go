@@ -23,19 +23,35 @@ type Recipe struct {
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 	PublishedAt       *time.Time `json:"published_at"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty"`
+
+	Ingredients []RecipeIngredient `json:"ingredients,omitempty"`
+	Nutrition   *Nutrition         `json:"nutrition,omitempty"`
+	Dietary     *RecipeDietary     `json:"dietary,omitempty"`
+	Uses        []RecipeLink       `json:"uses,omitempty"`
+	UsedBy      []RecipeLink       `json:"used_by,omitempty"`
+
+	ForkedFromID *uuid.UUID   `json:"forked_from_id,omitempty"`
+	Forks        []RecipeLink `json:"forks,omitempty"`
+
+	CookStats *CookStats `json:"cook_stats,omitempty"`
+
+	// HTMLContent is the rendered markdown, filled in on request.
+	HTMLContent string `json:"html_content,omitempty"`
 }
 
 type CreateRecipeRequest struct {
-	Title             string  `json:"title"`
-	MarkdownContent   string  `json:"markdown_content"`
-	CategoryID        *string `json:"category_id"`
-	Description       *string `json:"description"`
-	PrepTimeMinutes   *int32  `json:"prep_time_minutes"`
-	CookTimeMinutes   *int32  `json:"cook_time_minutes"`
-	Servings          *int32  `json:"servings"`
-	Difficulty        *string `json:"difficulty"`
-	FeaturedImagePath *string `json:"featured_image_path"`
-	IsPublished       bool    `json:"is_published"`
+	Title             string   `json:"title"`
+	MarkdownContent   string   `json:"markdown_content"`
+	CategoryID        *string  `json:"category_id"`
+	Description       *string  `json:"description"`
+	PrepTimeMinutes   *int32   `json:"prep_time_minutes"`
+	CookTimeMinutes   *int32   `json:"cook_time_minutes"`
+	Servings          *int32   `json:"servings"`
+	Difficulty        *string  `json:"difficulty"`
+	FeaturedImagePath *string  `json:"featured_image_path"`
+	IsPublished       bool     `json:"is_published"`
+	Tags              []string `json:"tags"`
 }
 
 type UpdateRecipeRequest struct {
@@ -51,6 +67,12 @@ type UpdateRecipeRequest struct {
 	IsPublished       *bool   `json:"is_published"`
 }
 
+// ForkRecipeRequest names the fork. It keeps the original's title when
+// Title is omitted.
+type ForkRecipeRequest struct {
+	Title *string `json:"title"`
+}
+
 type RecipeWithImages struct {
 	Recipe     Recipe        `json:"recipe"`
 	Category   *Category     `json:"category"`
@@ -60,6 +82,70 @@ type RecipeWithImages struct {
 	Groups     []RecipeGroup `json:"groups"`
 }
 
+type RecipeIngredient struct {
+	ID          uuid.UUID `json:"id"`
+	RecipeID    uuid.UUID `json:"recipe_id"`
+	OrderIndex  int       `json:"order_index"`
+	RawText     string    `json:"raw_text"`
+	Quantity    *float64  `json:"quantity"`
+	QuantityMax *float64  `json:"quantity_max"`
+	Unit        *string   `json:"unit"`
+	Name        string    `json:"name"`
+	Note        *string   `json:"note"`
+
+	// FromRecipe is set on ingredients inlined from a linked sub-recipe.
+	FromRecipe *RecipeLink `json:"from_recipe,omitempty"`
+}
+
+// RecipeLink is a recipe referenced from another recipe's markdown as
+// [[recipe:slug]]. RecipeID is nil while no recipe answers to the slug.
+type RecipeLink struct {
+	RecipeID *uuid.UUID `json:"recipe_id"`
+	Slug     string     `json:"slug"`
+	Title    string     `json:"title,omitempty"`
+}
+
+type RecipeSearchResult struct {
+	Recipe
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+type ScaledRecipe struct {
+	RecipeID         uuid.UUID          `json:"recipe_id"`
+	VariationID      *uuid.UUID         `json:"variation_id,omitempty"`
+	OriginalServings int                `json:"original_servings"`
+	Servings         int                `json:"servings"`
+	Factor           float64            `json:"factor"`
+	MarkdownContent  string             `json:"markdown_content"`
+	Ingredients      []RecipeIngredient `json:"ingredients"`
+}
+
+// RecipeSteps is a recipe's method split into single steps for cook mode.
+type RecipeSteps struct {
+	RecipeID uuid.UUID    `json:"recipe_id"`
+	Title    string       `json:"title"`
+	Steps    []RecipeStep `json:"steps"`
+}
+
+// RecipeStep is one step of a recipe's method. Section is the sub-heading
+// the step sits under, if any.
+type RecipeStep struct {
+	Number      int                `json:"number"`
+	Section     string             `json:"section,omitempty"`
+	Text        string             `json:"text"`
+	Timers      []StepTimer        `json:"timers"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+}
+
+// StepTimer is a duration mentioned in a step, such as "20 minutes". A
+// range such as "1-2 hours" has a longer MaxSeconds than MinSeconds.
+type StepTimer struct {
+	Text       string `json:"text"`
+	MinSeconds int    `json:"min_seconds"`
+	MaxSeconds int    `json:"max_seconds"`
+}
+
 type RecipeImage struct {
 	ID            uuid.UUID `json:"id"`
 	RecipeID      uuid.UUID `json:"recipe_id"`
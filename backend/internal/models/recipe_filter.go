@@ -0,0 +1,55 @@
+package models
+
+// Sort orders accepted by the recipe list endpoint.
+const (
+	RecipeSortNewest   = "newest"
+	RecipeSortTitle    = "title"
+	RecipeSortQuickest = "quickest"
+	RecipeSortUpdated  = "updated"
+)
+
+// RecipeFilter narrows the published recipe list. Category, tags and group
+// may be given either as IDs or as slugs.
+type RecipeFilter struct {
+	DietaryFilter
+	Category     string
+	Tags         []string
+	MatchAllTags bool
+	Difficulty   string
+	MaxTotalTime *int
+	AuthorID     string
+	Group        string
+	Sort         string
+}
+
+// DietaryFilter keeps the recipes that contain none of the allergens in
+// FreeFrom and suit every diet in Diets. Recipes that have not been
+// classified yet never match a non-empty filter.
+type DietaryFilter struct {
+	FreeFrom []string
+	Diets    []string
+}
+
+func (f DietaryFilter) IsEmpty() bool {
+	return len(f.FreeFrom) == 0 && len(f.Diets) == 0
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// RecipeFacets counts the recipes per category, tag and difficulty. Each
+// facet honours every active filter except its own, so the other options
+// stay visible while one is selected.
+type RecipeFacets struct {
+	Categories   []FacetCount `json:"categories"`
+	Tags         []FacetCount `json:"tags"`
+	Difficulties []FacetCount `json:"difficulties"`
+}
+
+type RecipeList struct {
+	Page[*Recipe]
+	Facets *RecipeFacets `json:"facets"`
+}
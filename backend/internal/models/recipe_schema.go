@@ -0,0 +1,44 @@
+package models
+
+// SchemaRecipe is a recipe in the schema.org Recipe vocabulary, ready to be
+// served as JSON-LD.
+type SchemaRecipe struct {
+	Context            string              `json:"@context"`
+	Type               string              `json:"@type"`
+	Name               string              `json:"name"`
+	Description        string              `json:"description,omitempty"`
+	Image              []string            `json:"image,omitempty"`
+	DatePublished      string              `json:"datePublished,omitempty"`
+	DateModified       string              `json:"dateModified,omitempty"`
+	PrepTime           string              `json:"prepTime,omitempty"`
+	CookTime           string              `json:"cookTime,omitempty"`
+	TotalTime          string              `json:"totalTime,omitempty"`
+	RecipeYield        string              `json:"recipeYield,omitempty"`
+	RecipeCategory     string              `json:"recipeCategory,omitempty"`
+	Keywords           string              `json:"keywords,omitempty"`
+	SuitableForDiet    []string            `json:"suitableForDiet,omitempty"`
+	Nutrition          *SchemaNutrition    `json:"nutrition,omitempty"`
+	IsBasedOn          string              `json:"isBasedOn,omitempty"`
+	RecipeIngredient   []string            `json:"recipeIngredient"`
+	RecipeInstructions []SchemaInstruction `json:"recipeInstructions"`
+}
+
+// SchemaInstruction is a HowToStep, or a HowToSection grouping steps under a
+// name.
+type SchemaInstruction struct {
+	Type            string              `json:"@type"`
+	Name            string              `json:"name,omitempty"`
+	Text            string              `json:"text,omitempty"`
+	ItemListElement []SchemaInstruction `json:"itemListElement,omitempty"`
+}
+
+// SchemaNutrition is a NutritionInformation for one serving.
+type SchemaNutrition struct {
+	Type                string `json:"@type"`
+	Calories            string `json:"calories"`
+	ProteinContent      string `json:"proteinContent"`
+	FatContent          string `json:"fatContent"`
+	CarbohydrateContent string `json:"carbohydrateContent"`
+	FiberContent        string `json:"fiberContent"`
+	SodiumContent       string `json:"sodiumContent"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecipeRevision is a full snapshot of a recipe's editable fields, written
+// every time the recipe is created, updated or restored.
+type RecipeRevision struct {
+	ID                uuid.UUID  `json:"id"`
+	RecipeID          uuid.UUID  `json:"recipe_id"`
+	RevisionNumber    int        `json:"revision_number"`
+	AuthorID          *uuid.UUID `json:"author_id"`
+	Title             string     `json:"title"`
+	MarkdownContent   string     `json:"markdown_content"`
+	CategoryID        *uuid.UUID `json:"category_id"`
+	Description       *string    `json:"description"`
+	PrepTimeMinutes   *int32     `json:"prep_time_minutes"`
+	CookTimeMinutes   *int32     `json:"cook_time_minutes"`
+	Servings          *int32     `json:"servings"`
+	Difficulty        *string    `json:"difficulty"`
+	FeaturedImagePath *string    `json:"featured_image_path"`
+	IsPublished       bool       `json:"is_published"`
+	RestoredFrom      *int       `json:"restored_from,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type RevisionDiff struct {
+	RecipeID      uuid.UUID  `json:"recipe_id"`
+	From          int        `json:"from"`
+	To            int        `json:"to"`
+	ChangedFields []string   `json:"changed_fields"`
+	Lines         []DiffLine `json:"lines"`
+}
+
+// DiffLine is one line of a markdown diff. Op is "equal", "add" or "remove";
+// OldLine and NewLine are 1-based line numbers in the respective revision.
+type DiffLine struct {
+	Op      string `json:"op"`
+	Text    string `json:"text"`
+	OldLine *int   `json:"old_line,omitempty"`
+	NewLine *int   `json:"new_line,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "github.com/google/uuid"
+
+// MaxShoppingListRecipes bounds how many recipes one shopping list covers.
+const MaxShoppingListRecipes = 50
+
+type ShoppingListRequest struct {
+	Recipes []ShoppingListRecipe `json:"recipes"`
+}
+
+// ShoppingListRecipe is a recipe to shop for. Servings defaults to the
+// recipe's own.
+type ShoppingListRecipe struct {
+	RecipeID string `json:"recipe_id"`
+	Servings *int   `json:"servings"`
+}
+
+type ShoppingList struct {
+	Items []ShoppingListItem `json:"items"`
+}
+
+// ShoppingListItem is an ingredient added up over the recipes that need it,
+// sub-recipes included. Quantity is nil for ingredients given without one,
+// such as "salt to taste".
+type ShoppingListItem struct {
+	Name      string      `json:"name"`
+	Quantity  *float64    `json:"quantity"`
+	Unit      *string     `json:"unit"`
+	RecipeIDs []uuid.UUID `json:"recipe_ids"`
+}
@@ -24,6 +24,12 @@ type Tag struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// SetRecipeTagsRequest names the complete tag set of a recipe. Unknown names
+// become new tags.
+type SetRecipeTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
 type RecipeGroup struct {
 	ID          uuid.UUID `json:"id"`
 	Name        string    `json:"name"`
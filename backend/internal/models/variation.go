@@ -19,6 +19,11 @@ type RecipeVariation struct {
 	IsPublished     bool      `json:"is_published"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
+
+	Dietary *RecipeDietary `json:"dietary,omitempty"`
+
+	// HTMLContent is the rendered markdown, filled in on request.
+	HTMLContent string `json:"html_content,omitempty"`
 }
 
 type CreateVariationRequest struct {
@@ -55,3 +60,24 @@ type RecipeWithVariations struct {
 	Groups     []RecipeGroup         `json:"groups"`
 	Variations []VariationWithAuthor `json:"variations"`
 }
+
+// Sub-resources of RecipeWithVariations that can be picked with include=.
+const (
+	RecipeIncludeCategory   = "category"
+	RecipeIncludeAuthor     = "author"
+	RecipeIncludeTags       = "tags"
+	RecipeIncludeImages     = "images"
+	RecipeIncludeGroups     = "groups"
+	RecipeIncludeVariations = "variations"
+)
+
+// RecipeIncludes lists every sub-resource, which is what an empty include=
+// returns.
+var RecipeIncludes = []string{
+	RecipeIncludeCategory,
+	RecipeIncludeAuthor,
+	RecipeIncludeTags,
+	RecipeIncludeImages,
+	RecipeIncludeGroups,
+	RecipeIncludeVariations,
+}
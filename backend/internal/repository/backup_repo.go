@@ -0,0 +1,915 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+type BackupRepository struct {
+	db *sql.DB
+	q  *sqlc.Queries
+}
+
+func NewBackupRepository(db *sql.DB, q *sqlc.Queries) *BackupRepository {
+	return &BackupRepository{
+		db: db,
+		q:  q,
+	}
+}
+
+// Export reads every record an archive holds. The reads share one read-only
+// transaction so the export is a consistent snapshot.
+func (r *BackupRepository) Export(includePasswords bool) (*models.BackupData, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	data := &models.BackupData{}
+
+	users, err := qtx.ListAllUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		backup := models.BackupUser{
+			ID:        user.ID,
+			Email:     user.Email,
+			Role:      user.Role,
+			CreatedAt: user.CreatedAt.Time,
+			UpdatedAt: user.UpdatedAt.Time,
+		}
+		if includePasswords {
+			backup.PasswordHash = user.PasswordHash
+		}
+		data.Users = append(data.Users, backup)
+	}
+
+	categoryRepo := &CategoryRepository{db: r.db, q: qtx}
+	categories, err := qtx.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		data.Categories = append(data.Categories, *categoryRepo.sqlcToModel(category))
+	}
+
+	tagRepo := &TagRepository{db: r.db, q: qtx}
+	tags, err := qtx.ListAllTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		data.Tags = append(data.Tags, *tagRepo.sqlcToModel(tag))
+	}
+
+	recipeTags, err := qtx.ListAllRecipeTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tagIDs := map[uuid.UUID][]uuid.UUID{}
+	for _, recipeTag := range recipeTags {
+		tagIDs[recipeTag.RecipeID] = append(tagIDs[recipeTag.RecipeID], recipeTag.TagID)
+	}
+
+	recipeRepo := &RecipeRepository{db: r.db, q: qtx}
+	recipes, err := qtx.ListAllRecipes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, recipe := range recipes {
+		data.Recipes = append(data.Recipes, models.BackupRecipe{
+			Recipe: *recipeRepo.sqlcToModel(recipe),
+			TagIDs: tagIDs[recipe.ID],
+		})
+	}
+
+	images, err := qtx.ListAllRecipeImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		if !image.RecipeID.Valid {
+			continue
+		}
+		data.RecipeImages = append(data.RecipeImages, sqlcToModelRecipeImage(image))
+	}
+
+	revisionRepo := &RecipeRevisionRepository{db: r.db, q: qtx}
+	revisions, err := qtx.ListAllRecipeRevisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, revision := range revisions {
+		data.RecipeRevisions = append(data.RecipeRevisions, *revisionRepo.sqlcToModel(revision))
+	}
+
+	variationRepo := &VariationRepository{db: r.db, q: qtx}
+	variations, err := qtx.ListAllVariations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, variation := range variations {
+		data.Variations = append(data.Variations, *variationRepo.sqlcToModel(variation))
+	}
+
+	groupings, err := qtx.ListAllRecipeGroupings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groupRecipes := map[uuid.UUID][]models.BackupGroupRecipe{}
+	for _, grouping := range groupings {
+		groupRecipes[grouping.GroupID] = append(groupRecipes[grouping.GroupID], models.BackupGroupRecipe{
+			RecipeID:   grouping.RecipeID,
+			OrderIndex: int(grouping.OrderIndex.Int32),
+		})
+	}
+
+	groups, err := qtx.ListAllRecipeGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		data.Groups = append(data.Groups, models.BackupGroup{
+			RecipeGroup: *sqlcToModelRecipeGroup(group),
+			Recipes:     groupRecipes[group.ID],
+		})
+	}
+
+	shareCodes, err := qtx.ListAllShareCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, shareCode := range shareCodes {
+		if !shareCode.RecipeID.Valid {
+			continue
+		}
+		backup := models.ShareCode{
+			ID:        shareCode.ID,
+			RecipeID:  shareCode.RecipeID.UUID,
+			Code:      shareCode.Code,
+			ExpiresAt: nullTimeToTimePtr(shareCode.ExpiresAt),
+			UseCount:  int(shareCode.UseCount.Int32),
+			CreatedAt: shareCode.CreatedAt.Time,
+		}
+		if shareCode.MaxUses.Valid {
+			backup.MaxUses = int32PtrToInt(shareCode.MaxUses.Int32)
+		}
+		data.ShareCodes = append(data.ShareCodes, backup)
+	}
+
+	photos, err := qtx.ListAllCookLogPhotos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logPhotos := map[uuid.UUID][]string{}
+	for _, photo := range photos {
+		logPhotos[photo.CookLogID] = append(logPhotos[photo.CookLogID], photo.FilePath)
+	}
+
+	cookLogRepo := &CookLogRepository{db: r.db, q: qtx}
+	cookLogs, err := qtx.ListAllCookLogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, cookLog := range cookLogs {
+		backup := cookLogRepo.sqlcToModel(cookLog)
+		backup.Photos = logPhotos[cookLog.ID]
+		if backup.Photos == nil {
+			backup.Photos = []string{}
+		}
+		data.CookLogs = append(data.CookLogs, *backup)
+	}
+
+	collectionRecipes, err := qtx.ListAllCollectionRecipes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	collectionMembers := map[uuid.UUID][]models.BackupCollectionRecipe{}
+	for _, member := range collectionRecipes {
+		collectionMembers[member.CollectionID] = append(collectionMembers[member.CollectionID], models.BackupCollectionRecipe{
+			RecipeID:   member.RecipeID,
+			OrderIndex: int(member.OrderIndex),
+			AddedAt:    member.AddedAt.Time,
+		})
+	}
+
+	collections, err := qtx.ListAllCollections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, collection := range collections {
+		data.Collections = append(data.Collections, models.BackupCollection{
+			Collection: *sqlcToModelCollection(collection),
+			Recipes:    collectionMembers[collection.ID],
+		})
+	}
+
+	favorites, err := qtx.ListAllRecipeFavorites(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, favorite := range favorites {
+		data.Favorites = append(data.Favorites, models.BackupFavorite{
+			UserID:    favorite.UserID,
+			RecipeID:  favorite.RecipeID,
+			CreatedAt: favorite.CreatedAt.Time,
+		})
+	}
+
+	comments, err := qtx.ListAllComments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		backup := sqlcToModelComment(comment)
+		data.Comments = append(data.Comments, models.BackupComment{
+			Comment:  *backup,
+			ThreadID: backup.ThreadID,
+		})
+	}
+
+	return data, nil
+}
+
+// Restore writes the records of an archive in one transaction, so a failed
+// import leaves nothing behind. Users are matched to existing ones by email
+// and categories, tags and groups by slug. Other records keep their IDs and
+// slugs unless those are taken, in which case they get new ones and every
+// reference to them follows. Variations an author already has for a recipe,
+// share codes already in use and favorites a user already has are skipped,
+// as are images, revisions and other records of recipes the archive doesn't
+// carry.
+func (r *BackupRepository) Restore(data *models.BackupData) (*models.BackupImportResult, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	restore := &backupRestore{
+		ctx:        ctx,
+		q:          r.q.WithTx(tx),
+		result:     models.NewBackupImportResult(),
+		users:      map[uuid.UUID]uuid.UUID{},
+		categories: map[uuid.UUID]uuid.UUID{},
+		tags:       map[uuid.UUID]uuid.UUID{},
+		recipes:    map[uuid.UUID]uuid.UUID{},
+		variations: map[uuid.UUID]uuid.UUID{},
+		comments:   map[uuid.UUID]uuid.UUID{},
+	}
+	steps := []func(*models.BackupData) error{
+		restore.restoreUsers,
+		restore.restoreCategories,
+		restore.restoreTags,
+		restore.restoreRecipes,
+		restore.restoreRecipeImages,
+		restore.restoreRecipeRevisions,
+		restore.restoreVariations,
+		restore.restoreGroups,
+		restore.restoreShareCodes,
+		restore.restoreCookLogs,
+		restore.restoreCollections,
+		restore.restoreFavorites,
+		restore.restoreComments,
+	}
+	for _, step := range steps {
+		if err := step(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return restore.result, nil
+}
+
+// backupRestore holds the state of a running restore: the transaction's
+// queries and what each archived ID became.
+type backupRestore struct {
+	ctx    context.Context
+	q      *sqlc.Queries
+	result *models.BackupImportResult
+
+	users      map[uuid.UUID]uuid.UUID
+	categories map[uuid.UUID]uuid.UUID
+	tags       map[uuid.UUID]uuid.UUID
+	recipes    map[uuid.UUID]uuid.UUID
+	variations map[uuid.UUID]uuid.UUID
+	comments   map[uuid.UUID]uuid.UUID
+}
+
+func (b *backupRestore) restoreUsers(data *models.BackupData) error {
+	for _, user := range data.Users {
+		existing, err := b.q.GetUserByEmail(b.ctx, user.Email)
+		if err == nil {
+			b.users[user.ID] = existing.ID
+			b.result.Matched["users"]++
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		id, err := freeID(b.ctx, user.ID, b.q.GetUserByID)
+		if err != nil {
+			return err
+		}
+		if err := b.q.RestoreUser(b.ctx, sqlc.RestoreUserParams{
+			ID:           id,
+			Email:        user.Email,
+			PasswordHash: user.PasswordHash,
+			Role:         user.Role,
+			CreatedAt:    backupTime(user.CreatedAt),
+			UpdatedAt:    backupTime(user.UpdatedAt),
+		}); err != nil {
+			return fmt.Errorf("user %s: %w", user.Email, err)
+		}
+		b.users[user.ID] = id
+		b.count("users", user.ID, id)
+		if user.PasswordHash == "" {
+			b.result.UsersWithoutPassword = append(b.result.UsersWithoutPassword, user.Email)
+		}
+	}
+	return nil
+}
+
+func (b *backupRestore) restoreCategories(data *models.BackupData) error {
+	for _, category := range data.Categories {
+		existing, err := b.q.GetCategoryBySlug(b.ctx, category.Slug)
+		if err == nil {
+			b.categories[category.ID] = existing.ID
+			b.result.Matched["categories"]++
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		id, err := freeID(b.ctx, category.ID, b.q.GetCategoryByID)
+		if err != nil {
+			return err
+		}
+		if _, err := b.q.CreateCategory(b.ctx, sqlc.CreateCategoryParams{
+			ID:          id,
+			Name:        category.Name,
+			Slug:        category.Slug,
+			Icon:        sqlNullString(category.Icon),
+			Description: sqlNullString(category.Description),
+			OrderIndex:  sqlInt32(int32(category.OrderIndex)),
+		}); err != nil {
+			return fmt.Errorf("category %s: %w", category.Slug, err)
+		}
+		b.categories[category.ID] = id
+		b.count("categories", category.ID, id)
+	}
+	return nil
+}
+
+// restoreTags matches tags by slug and then by name, both of which are
+// unique, and creates the rest.
+func (b *backupRestore) restoreTags(data *models.BackupData) error {
+	for _, tag := range data.Tags {
+		existing, err := b.q.GetTagBySlug(b.ctx, tag.Slug)
+		if errors.Is(err, sql.ErrNoRows) {
+			existing, err = b.q.GetTagByName(b.ctx, tag.Name)
+		}
+		if err == nil {
+			b.tags[tag.ID] = existing.ID
+			b.result.Matched["tags"]++
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		id, err := freeID(b.ctx, tag.ID, b.q.GetTagByID)
+		if err != nil {
+			return err
+		}
+		if _, err := b.q.CreateTag(b.ctx, sqlc.CreateTagParams{
+			ID:    id,
+			Name:  tag.Name,
+			Slug:  tag.Slug,
+			Color: sqlString(tag.Color),
+		}); err != nil {
+			return fmt.Errorf("tag %s: %w", tag.Slug, err)
+		}
+		b.tags[tag.ID] = id
+		b.count("tags", tag.ID, id)
+	}
+	return nil
+}
+
+func (b *backupRestore) restoreRecipes(data *models.BackupData) error {
+	for _, recipe := range data.Recipes {
+		id, err := freeID(b.ctx, recipe.ID, b.q.GetRecipeSlugByID)
+		if err != nil {
+			return err
+		}
+		slug, err := b.freeRecipeSlug(recipe.Slug)
+		if err != nil {
+			return err
+		}
+
+		if err := b.q.RestoreRecipe(b.ctx, sqlc.RestoreRecipeParams{
+			ID:                id,
+			Title:             recipe.Title,
+			Slug:              slug,
+			MarkdownContent:   recipe.MarkdownContent,
+			AuthorID:          b.reference(b.users, recipe.AuthorID),
+			CategoryID:        b.reference(b.categories, recipe.CategoryID),
+			Description:       sqlNullString(recipe.Description),
+			PrepTimeMinutes:   sqlNullInt32(recipe.PrepTimeMinutes),
+			CookTimeMinutes:   sqlNullInt32(recipe.CookTimeMinutes),
+			Servings:          sqlNullInt32(recipe.Servings),
+			Difficulty:        sqlNullString(recipe.Difficulty),
+			FeaturedImagePath: sqlNullString(recipe.FeaturedImagePath),
+			IsPublished:       sqlNullBool(recipe.IsPublished),
+			CreatedAt:         backupTime(recipe.CreatedAt),
+			UpdatedAt:         backupTime(recipe.UpdatedAt),
+			PublishedAt:       sqlNullTimePtr(recipe.PublishedAt),
+			DeletedAt:         sqlNullTimePtr(recipe.DeletedAt),
+		}); err != nil {
+			return fmt.Errorf("recipe %s: %w", recipe.Slug, err)
+		}
+		b.recipes[recipe.ID] = id
+		b.result.RecipeIDs = append(b.result.RecipeIDs, id)
+		if id != recipe.ID || slug != recipe.Slug {
+			b.result.Remapped["recipes"]++
+		} else {
+			b.result.Created["recipes"]++
+		}
+
+		for _, tagID := range recipe.TagIDs {
+			mapped, ok := b.tags[tagID]
+			if !ok {
+				continue
+			}
+			if err := b.q.AddTagToRecipe(b.ctx, sqlc.AddTagToRecipeParams{
+				RecipeID: id,
+				TagID:    mapped,
+			}); err != nil {
+				return fmt.Errorf("recipe %s: %w", recipe.Slug, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *backupRestore) restoreRecipeImages(data *models.BackupData) error {
+	for _, image := range data.RecipeImages {
+		recipeID, ok := b.recipes[image.RecipeID]
+		if !ok {
+			b.result.Skipped["recipe_images"]++
+			continue
+		}
+
+		id, err := freeID(b.ctx, image.ID, b.q.GetRecipeImageByID)
+		if err != nil {
+			return err
+		}
+		if err := b.q.RestoreRecipeImage(b.ctx, sqlc.RestoreRecipeImageParams{
+			ID:            id,
+			RecipeID:      sqlNullUUIDPtr(recipeID),
+			FilePath:      image.FilePath,
+			WebpPath:      sqlNullString(image.WebPPath),
+			ThumbnailPath: sqlNullString(image.ThumbnailPath),
+			Caption:       sqlNullString(image.Caption),
+			OrderIndex:    sqlInt32(int32(image.OrderIndex)),
+			UploadedAt:    backupTime(image.UploadedAt),
+		}); err != nil {
+			return fmt.Errorf("recipe image %s: %w", image.ID, err)
+		}
+		b.count("recipe_images", image.ID, id)
+	}
+	return nil
+}
+
+func (b *backupRestore) restoreRecipeRevisions(data *models.BackupData) error {
+	for _, revision := range data.RecipeRevisions {
+		recipeID, ok := b.recipes[revision.RecipeID]
+		if !ok {
+			b.result.Skipped["recipe_revisions"]++
+			continue
+		}
+
+		// Revisions are looked up by recipe and number, never by ID, so
+		// each gets a fresh one.
+		restoredFrom := sql.NullInt32{}
+		if revision.RestoredFrom != nil {
+			restoredFrom = sqlInt32(int32(*revision.RestoredFrom))
+		}
+		if err := b.q.RestoreRecipeRevision(b.ctx, sqlc.RestoreRecipeRevisionParams{
+			ID:                uuid.New(),
+			RecipeID:          recipeID,
+			RevisionNumber:    int32(revision.RevisionNumber),
+			AuthorID:          b.reference(b.users, revision.AuthorID),
+			Title:             revision.Title,
+			MarkdownContent:   revision.MarkdownContent,
+			CategoryID:        b.reference(b.categories, revision.CategoryID),
+			Description:       sqlNullString(revision.Description),
+			PrepTimeMinutes:   sqlNullInt32(revision.PrepTimeMinutes),
+			CookTimeMinutes:   sqlNullInt32(revision.CookTimeMinutes),
+			Servings:          sqlNullInt32(revision.Servings),
+			Difficulty:        sqlNullString(revision.Difficulty),
+			FeaturedImagePath: sqlNullString(revision.FeaturedImagePath),
+			IsPublished:       sqlNullBool(revision.IsPublished),
+			RestoredFrom:      restoredFrom,
+			CreatedAt:         backupTime(revision.CreatedAt),
+		}); err != nil {
+			return fmt.Errorf("recipe revision %s #%d: %w", revision.RecipeID, revision.RevisionNumber, err)
+		}
+		b.result.Created["recipe_revisions"]++
+	}
+	return nil
+}
+
+func (b *backupRestore) restoreVariations(data *models.BackupData) error {
+	for _, variation := range data.Variations {
+		recipeID, hasRecipe := b.recipes[variation.RecipeID]
+		authorID, hasAuthor := b.users[variation.AuthorID]
+		if !hasRecipe || !hasAuthor {
+			b.result.Skipped["variations"]++
+			continue
+		}
+
+		id, err := freeID(b.ctx, variation.ID, b.q.GetVariationByID)
+		if err != nil {
+			return err
+		}
+		restored, err := b.q.RestoreVariation(b.ctx, sqlc.RestoreVariationParams{
+			ID:              id,
+			RecipeID:        recipeID,
+			AuthorID:        authorID,
+			MarkdownContent: variation.MarkdownContent,
+			PrepTimeMinutes: sqlNullInt32(variation.PrepTimeMinutes),
+			CookTimeMinutes: sqlNullInt32(variation.CookTimeMinutes),
+			Servings:        sqlNullInt32(variation.Servings),
+			Difficulty:      sqlNullString(variation.Difficulty),
+			Notes:           sqlNullString(variation.Notes),
+			IsPublished:     sqlNullBool(variation.IsPublished),
+			CreatedAt:       backupTime(variation.CreatedAt),
+			UpdatedAt:       backupTime(variation.UpdatedAt),
+		})
+		if err != nil {
+			return fmt.Errorf("variation %s: %w", variation.ID, err)
+		}
+		if restored == 0 {
+			b.result.Skipped["variations"]++
+			continue
+		}
+		b.variations[variation.ID] = id
+		b.count("variations", variation.ID, id)
+	}
+	return nil
+}
+
+func (b *backupRestore) restoreGroups(data *models.BackupData) error {
+	for _, group := range data.Groups {
+		var groupID uuid.UUID
+		existing, err := b.q.GetRecipeGroupBySlug(b.ctx, group.Slug)
+		switch {
+		case err == nil:
+			groupID = existing.ID
+			b.result.Matched["groups"]++
+		case errors.Is(err, sql.ErrNoRows):
+			groupID, err = freeID(b.ctx, group.ID, b.q.GetRecipeGroupByID)
+			if err != nil {
+				return err
+			}
+			if _, err := b.q.CreateRecipeGroup(b.ctx, sqlc.CreateRecipeGroupParams{
+				ID:          groupID,
+				Name:        group.Name,
+				Slug:        group.Slug,
+				Description: sqlNullString(group.Description),
+				Icon:        sqlNullString(group.Icon),
+			}); err != nil {
+				return fmt.Errorf("group %s: %w", group.Slug, err)
+			}
+			b.count("groups", group.ID, groupID)
+		default:
+			return err
+		}
+
+		for _, member := range group.Recipes {
+			recipeID, ok := b.recipes[member.RecipeID]
+			if !ok {
+				continue
+			}
+			if err := b.q.AddRecipeToGroup(b.ctx, sqlc.AddRecipeToGroupParams{
+				GroupID:    groupID,
+				RecipeID:   recipeID,
+				OrderIndex: sqlInt32(int32(member.OrderIndex)),
+			}); err != nil {
+				return fmt.Errorf("group %s: %w", group.Slug, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *backupRestore) restoreShareCodes(data *models.BackupData) error {
+	for _, shareCode := range data.ShareCodes {
+		recipeID, ok := b.recipes[shareCode.RecipeID]
+		if !ok {
+			b.result.Skipped["share_codes"]++
+			continue
+		}
+
+		// Share codes are looked up by code, never by ID, so each gets
+		// a fresh one.
+		maxUses := sql.NullInt32{}
+		if shareCode.MaxUses != nil {
+			maxUses = sqlInt32(int32(*shareCode.MaxUses))
+		}
+		restored, err := b.q.RestoreShareCode(b.ctx, sqlc.RestoreShareCodeParams{
+			ID:        uuid.New(),
+			RecipeID:  sqlNullUUIDPtr(recipeID),
+			Code:      shareCode.Code,
+			ExpiresAt: sqlNullTimePtr(shareCode.ExpiresAt),
+			MaxUses:   maxUses,
+			UseCount:  sqlInt32(int32(shareCode.UseCount)),
+			CreatedAt: backupTime(shareCode.CreatedAt),
+		})
+		if err != nil {
+			return fmt.Errorf("share code %s: %w", shareCode.Code, err)
+		}
+		if restored == 0 {
+			b.result.Skipped["share_codes"]++
+			continue
+		}
+		b.result.Created["share_codes"]++
+	}
+	return nil
+}
+
+func (b *backupRestore) restoreCookLogs(data *models.BackupData) error {
+	for _, cookLog := range data.CookLogs {
+		recipeID, hasRecipe := b.recipes[cookLog.RecipeID]
+		userID, hasUser := b.users[cookLog.UserID]
+		if !hasRecipe || !hasUser {
+			b.result.Skipped["cook_logs"]++
+			continue
+		}
+		cookedOn, err := time.Parse(models.CookLogDateFormat, cookLog.CookedOn)
+		if err != nil {
+			return fmt.Errorf("cook log %s: %w", cookLog.ID, err)
+		}
+
+		id, err := freeID(b.ctx, cookLog.ID, b.q.GetCookLogByID)
+		if err != nil {
+			return err
+		}
+		var rating sql.NullInt32
+		if cookLog.Rating != nil {
+			rating = sqlInt32(int32(*cookLog.Rating))
+		}
+		if err := b.q.RestoreCookLog(b.ctx, sqlc.RestoreCookLogParams{
+			ID:          id,
+			RecipeID:    recipeID,
+			VariationID: b.reference(b.variations, cookLog.VariationID),
+			UserID:      userID,
+			CookedOn:    cookedOn,
+			Rating:      rating,
+			Notes:       sqlNullString(cookLog.Notes),
+			CreatedAt:   backupTime(cookLog.CreatedAt),
+		}); err != nil {
+			return fmt.Errorf("cook log %s: %w", cookLog.ID, err)
+		}
+		b.count("cook_logs", cookLog.ID, id)
+
+		for i, photo := range cookLog.Photos {
+			if err := b.q.CreateCookLogPhoto(b.ctx, sqlc.CreateCookLogPhotoParams{
+				ID:         uuid.New(),
+				CookLogID:  id,
+				FilePath:   photo,
+				OrderIndex: sqlInt32(int32(i)),
+			}); err != nil {
+				return fmt.Errorf("cook log %s: %w", cookLog.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *backupRestore) restoreCollections(data *models.BackupData) error {
+	for _, collection := range data.Collections {
+		ownerID, ok := b.users[collection.OwnerID]
+		if !ok {
+			b.result.Skipped["collections"]++
+			continue
+		}
+
+		id, err := freeID(b.ctx, collection.ID, b.q.GetCollectionByID)
+		if err != nil {
+			return err
+		}
+		if err := b.q.RestoreCollection(b.ctx, sqlc.RestoreCollectionParams{
+			ID:          id,
+			OwnerID:     ownerID,
+			Name:        collection.Name,
+			Description: sqlNullString(collection.Description),
+			Visibility:  collection.Visibility,
+			CreatedAt:   backupTime(collection.CreatedAt),
+			UpdatedAt:   backupTime(collection.UpdatedAt),
+		}); err != nil {
+			return fmt.Errorf("collection %s: %w", collection.ID, err)
+		}
+		b.count("collections", collection.ID, id)
+
+		for _, member := range collection.Recipes {
+			recipeID, ok := b.recipes[member.RecipeID]
+			if !ok {
+				continue
+			}
+			if err := b.q.RestoreCollectionRecipe(b.ctx, sqlc.RestoreCollectionRecipeParams{
+				CollectionID: id,
+				RecipeID:     recipeID,
+				OrderIndex:   int32(member.OrderIndex),
+				AddedAt:      backupTime(member.AddedAt),
+			}); err != nil {
+				return fmt.Errorf("collection %s: %w", collection.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *backupRestore) restoreFavorites(data *models.BackupData) error {
+	for _, favorite := range data.Favorites {
+		userID, hasUser := b.users[favorite.UserID]
+		recipeID, hasRecipe := b.recipes[favorite.RecipeID]
+		if !hasUser || !hasRecipe {
+			b.result.Skipped["favorites"]++
+			continue
+		}
+
+		restored, err := b.q.RestoreRecipeFavorite(b.ctx, sqlc.RestoreRecipeFavoriteParams{
+			UserID:    userID,
+			RecipeID:  recipeID,
+			CreatedAt: backupTime(favorite.CreatedAt),
+		})
+		if err != nil {
+			return fmt.Errorf("favorite %s: %w", favorite.RecipeID, err)
+		}
+		if restored == 0 {
+			b.result.Skipped["favorites"]++
+			continue
+		}
+		b.result.Created["favorites"]++
+	}
+	return nil
+}
+
+// restoreComments restores comments after the ones they reply to, so that
+// replies can point at their parent and thread under their new IDs. Replies
+// whose parent, thread or variation wasn't restored are skipped.
+func (b *backupRestore) restoreComments(data *models.BackupData) error {
+	archived := map[uuid.UUID]bool{}
+	for _, comment := range data.Comments {
+		archived[comment.ID] = true
+	}
+	waiting := func(id *uuid.UUID) bool {
+		if id == nil || !archived[*id] {
+			return false
+		}
+		_, restored := b.comments[*id]
+		return !restored
+	}
+
+	pending := data.Comments
+	for len(pending) > 0 {
+		var deferred []models.BackupComment
+		for _, comment := range pending {
+			if waiting(comment.ParentID) || waiting(comment.ThreadID) {
+				deferred = append(deferred, comment)
+				continue
+			}
+			if err := b.restoreComment(comment); err != nil {
+				return err
+			}
+		}
+		if len(deferred) == len(pending) {
+			b.result.Skipped["comments"] += len(deferred)
+			break
+		}
+		pending = deferred
+	}
+	return nil
+}
+
+func (b *backupRestore) restoreComment(comment models.BackupComment) error {
+	missing := func(ids map[uuid.UUID]uuid.UUID, archived *uuid.UUID) bool {
+		if archived == nil {
+			return false
+		}
+		_, ok := ids[*archived]
+		return !ok
+	}
+	recipeID, ok := b.recipes[comment.RecipeID]
+	if !ok || missing(b.variations, comment.VariationID) ||
+		missing(b.comments, comment.ParentID) || missing(b.comments, comment.ThreadID) {
+		b.result.Skipped["comments"]++
+		return nil
+	}
+
+	id, err := freeID(b.ctx, comment.ID, b.q.GetCommentByID)
+	if err != nil {
+		return err
+	}
+	if err := b.q.RestoreComment(b.ctx, sqlc.RestoreCommentParams{
+		ID:          id,
+		RecipeID:    recipeID,
+		VariationID: b.reference(b.variations, comment.VariationID),
+		ParentID:    b.reference(b.comments, comment.ParentID),
+		ThreadID:    b.reference(b.comments, comment.ThreadID),
+		AuthorID:    b.reference(b.users, comment.AuthorID),
+		Body:        comment.Body,
+		IsDeleted:   comment.IsDeleted,
+		CreatedAt:   backupTime(comment.CreatedAt),
+		UpdatedAt:   backupTime(comment.UpdatedAt),
+		EditedAt:    sqlNullTimePtr(comment.EditedAt),
+	}); err != nil {
+		return fmt.Errorf("comment %s: %w", comment.ID, err)
+	}
+	b.comments[comment.ID] = id
+	b.count("comments", comment.ID, id)
+	return nil
+}
+
+// count records a created record as remapped when it had to take a new ID.
+func (b *backupRestore) count(kind string, archived, id uuid.UUID) {
+	if archived != id {
+		b.result.Remapped[kind]++
+		return
+	}
+	b.result.Created[kind]++
+}
+
+// reference translates an archived foreign key. References to records the
+// archive didn't carry are dropped.
+func (b *backupRestore) reference(ids map[uuid.UUID]uuid.UUID, archived *uuid.UUID) uuid.NullUUID {
+	if archived == nil {
+		return uuid.NullUUID{}
+	}
+	id, ok := ids[*archived]
+	if !ok {
+		return uuid.NullUUID{}
+	}
+	return sqlNullUUIDPtr(id)
+}
+
+// freeRecipeSlug returns slug, or slug with the first free numeric suffix
+// when a recipe already uses it.
+func (b *backupRestore) freeRecipeSlug(slug string) (string, error) {
+	candidate := slug
+	for n := 2; ; n++ {
+		_, err := b.q.GetRecipeIDBySlug(b.ctx, candidate)
+		if errors.Is(err, sql.ErrNoRows) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%d", slug, n)
+	}
+}
+
+// freeID returns id when no record has it yet and a new ID otherwise.
+func freeID[T any](ctx context.Context, id uuid.UUID, get func(context.Context, uuid.UUID) (T, error)) (uuid.UUID, error) {
+	if id == uuid.Nil {
+		return uuid.New(), nil
+	}
+	_, err := get(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return id, nil
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.New(), nil
+}
+
+// backupTime keeps an archived timestamp, using the current time when the
+// archive has none.
+func backupTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+type CollectionRepository struct {
+	db *sql.DB
+	q  *sqlc.Queries
+}
+
+func NewCollectionRepository(db *sql.DB, q *sqlc.Queries) *CollectionRepository {
+	return &CollectionRepository{
+		db: db,
+		q:  q,
+	}
+}
+
+func (r *CollectionRepository) Create(collection *models.Collection) (*models.Collection, error) {
+	ctx := context.Background()
+	result, err := r.q.CreateCollection(ctx, sqlc.CreateCollectionParams{
+		ID:          uuid.New(),
+		OwnerID:     collection.OwnerID,
+		Name:        collection.Name,
+		Description: sqlNullString(collection.Description),
+		Visibility:  collection.Visibility,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sqlcToModelCollection(result), nil
+}
+
+func (r *CollectionRepository) GetByID(id string) (*models.Collection, error) {
+	ctx := context.Background()
+	result, err := r.q.GetCollectionByID(ctx, uuid.MustParse(id))
+	if err != nil {
+		return nil, err
+	}
+	return sqlcToModelCollection(result), nil
+}
+
+// ListVisible pages through the collections userID owns or that are shared,
+// newest first.
+func (r *CollectionRepository) ListVisible(userID string, limit int, cursor string) (*models.Page[*models.Collection], error) {
+	ctx := context.Background()
+	after, err := decodeTimeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID := uuid.MustParse(userID)
+	results, err := r.q.ListVisibleCollections(ctx, sqlc.ListVisibleCollectionsParams{
+		UserID:         userUUID,
+		AfterID:        afterID(after),
+		AfterCreatedAt: afterTime(after),
+		Limit:          int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.q.CountVisibleCollections(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := make([]*models.Collection, len(results))
+	for i, result := range results {
+		collections[i] = sqlcToModelCollection(result)
+	}
+	return newPage(collections, limit, total, func(collection *models.Collection) pageCursor {
+		return timeCursor(collection.ID, collection.CreatedAt)
+	}), nil
+}
+
+func (r *CollectionRepository) Update(id string, req *models.UpdateCollectionRequest) (*models.Collection, error) {
+	ctx := context.Background()
+	result, err := r.q.UpdateCollection(ctx, sqlc.UpdateCollectionParams{
+		Name:        sqlNullString(req.Name),
+		Description: sqlNullString(req.Description),
+		Visibility:  sqlNullString(req.Visibility),
+		ID:          uuid.MustParse(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sqlcToModelCollection(result), nil
+}
+
+// Delete removes the collection. The recipes in it are left alone.
+func (r *CollectionRepository) Delete(id string) error {
+	ctx := context.Background()
+	return r.q.DeleteCollection(ctx, uuid.MustParse(id))
+}
+
+// AddRecipe appends the recipe to the end of the collection. Adding a
+// recipe that is already there keeps its place.
+func (r *CollectionRepository) AddRecipe(collectionID, recipeID string) error {
+	ctx := context.Background()
+	return r.q.AddRecipeToCollection(ctx, sqlc.AddRecipeToCollectionParams{
+		CollectionID: uuid.MustParse(collectionID),
+		RecipeID:     uuid.MustParse(recipeID),
+	})
+}
+
+func (r *CollectionRepository) RemoveRecipe(collectionID, recipeID string) error {
+	ctx := context.Background()
+	return r.q.RemoveRecipeFromCollection(ctx, sqlc.RemoveRecipeFromCollectionParams{
+		CollectionID: uuid.MustParse(collectionID),
+		RecipeID:     uuid.MustParse(recipeID),
+	})
+}
+
+// RecipeIDs returns the IDs of every recipe in the collection in order,
+// whether or not the caller may see them.
+func (r *CollectionRepository) RecipeIDs(collectionID string) ([]uuid.UUID, error) {
+	ctx := context.Background()
+	return r.q.ListCollectionRecipeIDs(ctx, uuid.MustParse(collectionID))
+}
+
+// Reorder gives the recipes of the collection the order of recipeIDs in a
+// single transaction.
+func (r *CollectionRepository) Reorder(collectionID string, recipeIDs []uuid.UUID) error {
+	ctx := context.Background()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	collectionUUID := uuid.MustParse(collectionID)
+	for i, recipeID := range recipeIDs {
+		err := qtx.SetCollectionRecipeOrder(ctx, sqlc.SetCollectionRecipeOrderParams{
+			OrderIndex:   int32(i),
+			CollectionID: collectionUUID,
+			RecipeID:     recipeID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetRecipes returns the recipes of the collection in order, leaving out
+// drafts that viewerID didn't write.
+func (r *CollectionRepository) GetRecipes(collectionID, viewerID string) ([]*models.Recipe, error) {
+	ctx := context.Background()
+	results, err := r.q.ListCollectionRecipes(ctx, sqlc.ListCollectionRecipesParams{
+		CollectionID: uuid.MustParse(collectionID),
+		ViewerID:     uuid.MustParse(viewerID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	recipes := make([]*models.Recipe, len(results))
+	for i, result := range results {
+		recipes[i] = sqlcToModelRecipe(result)
+	}
+	return recipes, nil
+}
+
+func (r *CollectionRepository) AddFavorite(userID, recipeID string) error {
+	ctx := context.Background()
+	return r.q.AddFavorite(ctx, sqlc.AddFavoriteParams{
+		UserID:   uuid.MustParse(userID),
+		RecipeID: uuid.MustParse(recipeID),
+	})
+}
+
+func (r *CollectionRepository) RemoveFavorite(userID, recipeID string) error {
+	ctx := context.Background()
+	return r.q.RemoveFavorite(ctx, sqlc.RemoveFavoriteParams{
+		UserID:   uuid.MustParse(userID),
+		RecipeID: uuid.MustParse(recipeID),
+	})
+}
+
+func (r *CollectionRepository) IsFavorite(userID, recipeID string) (bool, error) {
+	ctx := context.Background()
+	return r.q.IsFavorite(ctx, sqlc.IsFavoriteParams{
+		UserID:   uuid.MustParse(userID),
+		RecipeID: uuid.MustParse(recipeID),
+	})
+}
+
+// ListFavorites pages through the recipes userID marked as favorites, most
+// recently added first. The cursor holds when the last recipe of a page was
+// added.
+func (r *CollectionRepository) ListFavorites(userID string, limit int, cursor string) (*models.Page[*models.Recipe], error) {
+	ctx := context.Background()
+	after, err := decodeTimeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID := uuid.MustParse(userID)
+	results, err := r.q.ListFavoriteRecipes(ctx, sqlc.ListFavoriteRecipesParams{
+		UserID:         userUUID,
+		AfterID:        afterID(after),
+		AfterCreatedAt: afterTime(after),
+		Limit:          int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.q.CountFavoriteRecipes(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make([]*models.Recipe, len(results))
+	favoritedAt := make(map[uuid.UUID]time.Time, len(results))
+	for i, result := range results {
+		recipes[i] = sqlcToModelRecipe(sqlc.Recipe{
+			ID:                result.ID,
+			Title:             result.Title,
+			Slug:              result.Slug,
+			MarkdownContent:   result.MarkdownContent,
+			AuthorID:          result.AuthorID,
+			CategoryID:        result.CategoryID,
+			Description:       result.Description,
+			PrepTimeMinutes:   result.PrepTimeMinutes,
+			CookTimeMinutes:   result.CookTimeMinutes,
+			Servings:          result.Servings,
+			Difficulty:        result.Difficulty,
+			FeaturedImagePath: result.FeaturedImagePath,
+			IsPublished:       result.IsPublished,
+			CreatedAt:         result.CreatedAt,
+			UpdatedAt:         result.UpdatedAt,
+			PublishedAt:       result.PublishedAt,
+			DeletedAt:         result.DeletedAt,
+		})
+		favoritedAt[result.ID] = result.FavoritedAt.Time
+	}
+	return newPage(recipes, limit, total, func(recipe *models.Recipe) pageCursor {
+		return timeCursor(recipe.ID, favoritedAt[recipe.ID])
+	}), nil
+}
+
+func sqlcToModelCollection(row sqlc.Collection) *models.Collection {
+	return &models.Collection{
+		ID:          row.ID,
+		OwnerID:     row.OwnerID,
+		Name:        row.Name,
+		Description: nullStringToPtr(row.Description),
+		Visibility:  row.Visibility,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
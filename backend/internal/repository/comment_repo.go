@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+type CommentRepository struct {
+	db *sql.DB
+	q  *sqlc.Queries
+}
+
+func NewCommentRepository(db *sql.DB, q *sqlc.Queries) *CommentRepository {
+	return &CommentRepository{
+		db: db,
+		q:  q,
+	}
+}
+
+func (r *CommentRepository) Create(comment *models.Comment) (*models.Comment, error) {
+	ctx := context.Background()
+	result, err := r.q.CreateComment(ctx, sqlc.CreateCommentParams{
+		ID:          uuid.New(),
+		RecipeID:    comment.RecipeID,
+		VariationID: sqlNullUUID(comment.VariationID),
+		ParentID:    sqlNullUUID(comment.ParentID),
+		ThreadID:    sqlNullUUID(comment.ThreadID),
+		AuthorID:    sqlNullUUID(comment.AuthorID),
+		Body:        comment.Body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sqlcToModelComment(result), nil
+}
+
+func (r *CommentRepository) GetByID(id string) (*models.Comment, error) {
+	ctx := context.Background()
+	result, err := r.q.GetCommentByID(ctx, uuid.MustParse(id))
+	if err != nil {
+		return nil, err
+	}
+	return sqlcToModelComment(result), nil
+}
+
+// UpdateBody replaces the text of a comment and marks it as edited.
+func (r *CommentRepository) UpdateBody(id string, body string) (*models.Comment, error) {
+	ctx := context.Background()
+	result, err := r.q.UpdateCommentBody(ctx, sqlc.UpdateCommentBodyParams{
+		Body: body,
+		ID:   uuid.MustParse(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sqlcToModelComment(result), nil
+}
+
+// CountReplies returns how many direct replies a comment has.
+func (r *CommentRepository) CountReplies(id string) (int64, error) {
+	ctx := context.Background()
+	return r.q.CountCommentReplies(ctx, sqlNullUUIDPtr(uuid.MustParse(id)))
+}
+
+// MarkDeleted blanks a comment but keeps its row, so replies to it stay in
+// place.
+func (r *CommentRepository) MarkDeleted(id string) error {
+	ctx := context.Background()
+	return r.q.MarkCommentDeleted(ctx, uuid.MustParse(id))
+}
+
+// Delete removes a comment along with any replies to it.
+func (r *CommentRepository) Delete(id string) error {
+	ctx := context.Background()
+	return r.q.DeleteComment(ctx, uuid.MustParse(id))
+}
+
+// ListByRecipe pages through the threads on a recipe itself, newest first,
+// each with its replies nested in order.
+func (r *CommentRepository) ListByRecipe(recipeID string, limit int, cursor string) (*models.Page[*models.Comment], error) {
+	ctx := context.Background()
+	after, err := decodeTimeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	recipeUUID := uuid.MustParse(recipeID)
+	results, err := r.q.ListRecipeComments(ctx, sqlc.ListRecipeCommentsParams{
+		RecipeID:       recipeUUID,
+		AfterID:        afterID(after),
+		AfterCreatedAt: afterTime(after),
+		Limit:          int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.q.CountRecipeComments(ctx, recipeUUID)
+	if err != nil {
+		return nil, err
+	}
+	return r.newThreadPage(results, limit, total)
+}
+
+// ListByVariation pages through the threads on a variation, newest first,
+// each with its replies nested in order.
+func (r *CommentRepository) ListByVariation(variationID string, limit int, cursor string) (*models.Page[*models.Comment], error) {
+	ctx := context.Background()
+	after, err := decodeTimeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	variationUUID := sqlNullUUIDPtr(uuid.MustParse(variationID))
+	results, err := r.q.ListVariationComments(ctx, sqlc.ListVariationCommentsParams{
+		VariationID:    variationUUID,
+		AfterID:        afterID(after),
+		AfterCreatedAt: afterTime(after),
+		Limit:          int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.q.CountVariationComments(ctx, variationUUID)
+	if err != nil {
+		return nil, err
+	}
+	return r.newThreadPage(results, limit, total)
+}
+
+// ListRecent pages through every comment on the site, newest first and
+// without nesting, for moderation.
+func (r *CommentRepository) ListRecent(limit int, cursor string) (*models.Page[*models.Comment], error) {
+	ctx := context.Background()
+	after, err := decodeTimeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := r.q.ListRecentComments(ctx, sqlc.ListRecentCommentsParams{
+		AfterID:        afterID(after),
+		AfterCreatedAt: afterTime(after),
+		Limit:          int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.q.CountRecentComments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]*models.Comment, len(results))
+	for i, result := range results {
+		comments[i] = sqlcToModelComment(result)
+	}
+	return newPage(comments, limit, total, func(comment *models.Comment) pageCursor {
+		return timeCursor(comment.ID, comment.CreatedAt)
+	}), nil
+}
+
+// newThreadPage builds the page of top-level comments and loads the
+// replies of the ones on it.
+func (r *CommentRepository) newThreadPage(results []sqlc.Comment, limit int, total int64) (*models.Page[*models.Comment], error) {
+	comments := make([]*models.Comment, len(results))
+	for i, result := range results {
+		comments[i] = sqlcToModelComment(result)
+	}
+	page := newPage(comments, limit, total, func(comment *models.Comment) pageCursor {
+		return timeCursor(comment.ID, comment.CreatedAt)
+	})
+	for _, comment := range page.Items {
+		if err := r.loadReplies(comment); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// loadReplies nests every reply in the thread under the comment it answers,
+// oldest first.
+func (r *CommentRepository) loadReplies(root *models.Comment) error {
+	results, err := r.q.ListThreadReplies(context.Background(), sqlNullUUIDPtr(root.ID))
+	if err != nil {
+		return err
+	}
+
+	byID := map[uuid.UUID]*models.Comment{root.ID: root}
+	for _, result := range results {
+		reply := sqlcToModelComment(result)
+		byID[reply.ID] = reply
+	}
+	for _, result := range results {
+		reply := byID[result.ID]
+		if parent, ok := byID[result.ParentID.UUID]; ok {
+			parent.Replies = append(parent.Replies, reply)
+		}
+	}
+	return nil
+}
+
+func sqlcToModelComment(row sqlc.Comment) *models.Comment {
+	return &models.Comment{
+		ID:          row.ID,
+		RecipeID:    row.RecipeID,
+		VariationID: nullUUIDToPtr(row.VariationID),
+		ParentID:    nullUUIDToPtr(row.ParentID),
+		ThreadID:    nullUUIDToPtr(row.ThreadID),
+		AuthorID:    nullUUIDToPtr(row.AuthorID),
+		Body:        row.Body,
+		IsDeleted:   row.IsDeleted,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+		EditedAt:    nullTimeToTimePtr(row.EditedAt),
+	}
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+type CookLogRepository struct {
+	db *sql.DB
+	q  *sqlc.Queries
+}
+
+func NewCookLogRepository(db *sql.DB, q *sqlc.Queries) *CookLogRepository {
+	return &CookLogRepository{
+		db: db,
+		q:  q,
+	}
+}
+
+// Create stores the cook log and its photos in a single transaction.
+func (r *CookLogRepository) Create(log *models.CookLog) (*models.CookLog, error) {
+	ctx := context.Background()
+	cookedOn, err := time.Parse(models.CookLogDateFormat, log.CookedOn)
+	if err != nil {
+		return nil, err
+	}
+
+	var rating sql.NullInt32
+	if log.Rating != nil {
+		rating = sqlInt32(int32(*log.Rating))
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	result, err := qtx.CreateCookLog(ctx, sqlc.CreateCookLogParams{
+		ID:          uuid.New(),
+		RecipeID:    log.RecipeID,
+		VariationID: sqlNullUUID(log.VariationID),
+		UserID:      log.UserID,
+		CookedOn:    cookedOn,
+		Rating:      rating,
+		Notes:       sqlNullString(log.Notes),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, photo := range log.Photos {
+		err := qtx.CreateCookLogPhoto(ctx, sqlc.CreateCookLogPhotoParams{
+			ID:         uuid.New(),
+			CookLogID:  result.ID,
+			FilePath:   photo,
+			OrderIndex: sqlInt32(int32(i)),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	created := r.sqlcToModel(result)
+	created.Photos = append([]string{}, log.Photos...)
+	return created, nil
+}
+
+func (r *CookLogRepository) GetByID(id string) (*models.CookLog, error) {
+	ctx := context.Background()
+	result, err := r.q.GetCookLogByID(ctx, uuid.MustParse(id))
+	if err != nil {
+		return nil, err
+	}
+	log := r.sqlcToModel(result)
+	if err := r.loadPhotos(log); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// Delete removes the cook log along with its photo rows. The photo files
+// are left to the caller.
+func (r *CookLogRepository) Delete(id string) error {
+	ctx := context.Background()
+	return r.q.DeleteCookLog(ctx, uuid.MustParse(id))
+}
+
+// ListByRecipe pages through a recipe's cook logs, most recently cooked
+// first.
+func (r *CookLogRepository) ListByRecipe(recipeID string, limit int, cursor string) (*models.Page[*models.CookLog], error) {
+	ctx := context.Background()
+	after, err := r.decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	recipeUUID := uuid.MustParse(recipeID)
+	results, err := r.q.ListRecipeCookLogs(ctx, sqlc.ListRecipeCookLogsParams{
+		RecipeID:       recipeUUID,
+		AfterID:        afterID(after),
+		AfterCookedOn:  afterDay(after),
+		AfterCreatedAt: afterTime(after),
+		Limit:          int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.q.CountRecipeCookLogs(ctx, recipeUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make([]*models.CookLog, len(results))
+	for i, result := range results {
+		logs[i] = r.sqlcToModel(result)
+	}
+	return r.newPage(logs, limit, total)
+}
+
+// ListByUser pages through the cook logs a user wrote, most recently
+// cooked first, with the title and slug of each recipe.
+func (r *CookLogRepository) ListByUser(userID string, limit int, cursor string) (*models.Page[*models.CookLog], error) {
+	ctx := context.Background()
+	after, err := r.decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID := uuid.MustParse(userID)
+	results, err := r.q.ListUserCookLogs(ctx, sqlc.ListUserCookLogsParams{
+		UserID:         userUUID,
+		AfterID:        afterID(after),
+		AfterCookedOn:  afterDay(after),
+		AfterCreatedAt: afterTime(after),
+		Limit:          int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.q.CountUserCookLogs(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make([]*models.CookLog, len(results))
+	for i, result := range results {
+		logs[i] = r.sqlcToModel(sqlc.CookLog{
+			ID:          result.ID,
+			RecipeID:    result.RecipeID,
+			VariationID: result.VariationID,
+			UserID:      result.UserID,
+			CookedOn:    result.CookedOn,
+			Rating:      result.Rating,
+			Notes:       result.Notes,
+			CreatedAt:   result.CreatedAt,
+		})
+		logs[i].RecipeTitle = result.RecipeTitle
+		logs[i].RecipeSlug = result.RecipeSlug
+	}
+	return r.newPage(logs, limit, total)
+}
+
+// decodeCursor decodes a cursor holding the day and time a log was cooked
+// and written.
+func (r *CookLogRepository) decodeCursor(cursor string) (*pageCursor, error) {
+	after, err := decodeTimeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		if after.Day == nil {
+			return nil, errors.New("invalid cursor")
+		}
+		if _, err := time.Parse(models.CookLogDateFormat, *after.Day); err != nil {
+			return nil, errors.New("invalid cursor")
+		}
+	}
+	return after, nil
+}
+
+// afterDay returns the day of the cursor for a list query. It is bound as a
+// time, the way cooked_on is written.
+func afterDay(cursor *pageCursor) interface{} {
+	if cursor == nil {
+		return nil
+	}
+	day, _ := time.Parse(models.CookLogDateFormat, *cursor.Day)
+	return day
+}
+
+// newPage builds the page and loads the photos of the logs on it.
+func (r *CookLogRepository) newPage(logs []*models.CookLog, limit int, total int64) (*models.Page[*models.CookLog], error) {
+	page := newPage(logs, limit, total, func(log *models.CookLog) pageCursor {
+		cursor := timeCursor(log.ID, log.CreatedAt)
+		cursor.Day = &log.CookedOn
+		return cursor
+	})
+	for _, log := range page.Items {
+		if err := r.loadPhotos(log); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func (r *CookLogRepository) loadPhotos(log *models.CookLog) error {
+	photos, err := r.q.ListCookLogPhotos(context.Background(), log.ID)
+	if err != nil {
+		return err
+	}
+	log.Photos = photos
+	if log.Photos == nil {
+		log.Photos = []string{}
+	}
+	return nil
+}
+
+func (r *CookLogRepository) sqlcToModel(dbLog sqlc.CookLog) *models.CookLog {
+	var rating *int
+	if dbLog.Rating.Valid {
+		n := int(dbLog.Rating.Int32)
+		rating = &n
+	}
+
+	return &models.CookLog{
+		ID:          dbLog.ID,
+		RecipeID:    dbLog.RecipeID,
+		VariationID: nullUUIDToPtr(dbLog.VariationID),
+		UserID:      dbLog.UserID,
+		CookedOn:    dbLog.CookedOn.Format(models.CookLogDateFormat),
+		Rating:      rating,
+		Notes:       nullStringToPtr(dbLog.Notes),
+		CreatedAt:   dbLog.CreatedAt.Time,
+	}
+}
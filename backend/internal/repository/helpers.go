@@ -29,6 +29,13 @@ func sqlNullInt32(i *int32) sql.NullInt32 {
 	return sql.NullInt32{Int32: *i, Valid: true}
 }
 
+func sqlNullFloat64(f *float64) sql.NullFloat64 {
+	if f == nil {
+		return sql.NullFloat64{Valid: false}
+	}
+	return sql.NullFloat64{Float64: *f, Valid: true}
+}
+
 func sqlNullUUID(u *uuid.UUID) uuid.NullUUID {
 	if u == nil {
 		return uuid.NullUUID{Valid: false}
@@ -54,6 +61,13 @@ func nullInt32ToPtr(ni sql.NullInt32) *int32 {
 	return &ni.Int32
 }
 
+func nullFloat64ToPtr(nf sql.NullFloat64) *float64 {
+	if !nf.Valid {
+		return nil
+	}
+	return &nf.Float64
+}
+
 func nullUUIDToPtr(nu uuid.NullUUID) *uuid.UUID {
 	if !nu.Valid {
 		return nil
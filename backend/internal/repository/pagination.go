@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/models"
+)
+
+// pageCursor marks the last item of a page by its sort key: the ID, which
+// ends every ordering as a tiebreak, and whichever other values the list
+// sorts by. Lists resume by comparing rows against these values rather than
+// against the item itself, so items added ahead of it never shift the
+// following pages, and a page still follows on when the item has since been
+// deleted or moved to the trash.
+type pageCursor struct {
+	ID      uuid.UUID  `json:"id"`
+	Rank    *float64   `json:"rank,omitempty"`
+	Text    *string    `json:"text,omitempty"`
+	Minutes *int       `json:"minutes,omitempty"`
+	Day     *string    `json:"day,omitempty"`
+	Time    *time.Time `json:"time,omitempty"`
+}
+
+// cursorTimeLayout is the layout timestamps in a cursor are bound in. It is
+// the one CURRENT_TIMESTAMP writes in SQLite, which compares timestamps as
+// text, and Postgres parses it like any other timestamp.
+const cursorTimeLayout = "2006-01-02 15:04:05.999999999"
+
+func encodeCursor(cursor pageCursor) *string {
+	data, _ := json.Marshal(cursor)
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return &encoded
+}
+
+// decodeCursor parses a cursor taken from a request. An empty string means
+// the first page and yields nil.
+func decodeCursor(value string) (*pageCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return &cursor, nil
+}
+
+// afterID returns the ID of the cursor for a sqlc list query.
+func afterID(cursor *pageCursor) interface{} {
+	if cursor == nil {
+		return nil
+	}
+	return cursor.ID
+}
+
+// afterTime returns the timestamp of the cursor for a sqlc list query.
+func afterTime(cursor *pageCursor) interface{} {
+	if cursor == nil {
+		return nil
+	}
+	return cursorTime(*cursor.Time)
+}
+
+func cursorTime(t time.Time) string {
+	return t.UTC().Format(cursorTimeLayout)
+}
+
+// timeCursor is the cursor of a list sorted by a timestamp.
+func timeCursor(id uuid.UUID, t time.Time) pageCursor {
+	return pageCursor{ID: id, Time: &t}
+}
+
+// decodeTimeCursor decodes the cursor of a list sorted by a timestamp.
+func decodeTimeCursor(value string) (*pageCursor, error) {
+	cursor, err := decodeCursor(value)
+	if err != nil {
+		return nil, err
+	}
+	if cursor != nil && cursor.Time == nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// newPage builds the page envelope from rows fetched with limit+1: the extra
+// row only signals that another page follows and is dropped.
+func newPage[T any](items []T, limit int, total int64, cursor func(T) pageCursor) *models.Page[T] {
+	page := &models.Page[T]{
+		Items: items,
+		Total: int(total),
+	}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = encodeCursor(cursor(page.Items[limit-1]))
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	return page
+}
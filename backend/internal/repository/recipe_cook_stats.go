@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/models"
+)
+
+// GetCookStats sums up the recipe's cook logs: how often it was cooked, the
+// average of the ratings given and when it was last cooked, overall and for
+// each variation cooked, most cooked first.
+func (r *RecipeRepository) GetCookStats(recipeID string) (*models.CookStats, error) {
+	ctx := context.Background()
+	results, err := r.q.ListRecipeCookRatings(ctx, uuid.MustParse(recipeID))
+	if err != nil {
+		return nil, err
+	}
+
+	overall := &cookTally{}
+	variations := map[uuid.UUID]*cookTally{}
+	var order []uuid.UUID
+	for _, result := range results {
+		cookedOn := result.CookedOn.Format(models.CookLogDateFormat)
+		var rating *int
+		if result.Rating.Valid {
+			n := int(result.Rating.Int32)
+			rating = &n
+		}
+		overall.add(cookedOn, rating)
+
+		if result.VariationID.Valid {
+			tally, ok := variations[result.VariationID.UUID]
+			if !ok {
+				tally = &cookTally{}
+				variations[result.VariationID.UUID] = tally
+				order = append(order, result.VariationID.UUID)
+			}
+			tally.add(cookedOn, rating)
+		}
+	}
+
+	stats := &models.CookStats{
+		TimesCooked:   overall.count,
+		AverageRating: overall.average(),
+		LastCookedOn:  overall.lastCookedOn(),
+	}
+	for _, id := range order {
+		tally := variations[id]
+		stats.Variations = append(stats.Variations, models.VariationCookStats{
+			VariationID:   id,
+			TimesCooked:   tally.count,
+			AverageRating: tally.average(),
+			LastCookedOn:  tally.lastCookedOn(),
+		})
+	}
+	sort.SliceStable(stats.Variations, func(i, j int) bool {
+		return stats.Variations[i].TimesCooked > stats.Variations[j].TimesCooked
+	})
+	return stats, nil
+}
+
+type cookTally struct {
+	count, rated, ratingTotal int
+	last                      string
+}
+
+func (t *cookTally) add(cookedOn string, rating *int) {
+	t.count++
+	if rating != nil {
+		t.rated++
+		t.ratingTotal += *rating
+	}
+	if cookedOn > t.last {
+		t.last = cookedOn
+	}
+}
+
+func (t *cookTally) average() *float64 {
+	if t.rated == 0 {
+		return nil
+	}
+	average := math.Round(float64(t.ratingTotal)/float64(t.rated)*10) / 10
+	return &average
+}
+
+func (t *cookTally) lastCookedOn() *string {
+	if t.count == 0 {
+		return nil
+	}
+	return &t.last
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+// SaveDietary stores the recipe's allergen and diet classification,
+// replacing the previous one.
+func (r *RecipeRepository) SaveDietary(recipeID string, dietary *models.RecipeDietary) error {
+	ctx := context.Background()
+	has := func(labels []string, label string) bool {
+		return slices.Contains(labels, label)
+	}
+	return r.q.UpsertRecipeDietary(ctx, sqlc.UpsertRecipeDietaryParams{
+		RecipeID:   uuid.MustParse(recipeID),
+		Gluten:     has(dietary.Allergens, models.AllergenGluten),
+		Dairy:      has(dietary.Allergens, models.AllergenDairy),
+		Egg:        has(dietary.Allergens, models.AllergenEgg),
+		Nuts:       has(dietary.Allergens, models.AllergenNuts),
+		Peanuts:    has(dietary.Allergens, models.AllergenPeanuts),
+		Shellfish:  has(dietary.Allergens, models.AllergenShellfish),
+		Soy:        has(dietary.Allergens, models.AllergenSoy),
+		Sesame:     has(dietary.Allergens, models.AllergenSesame),
+		Vegetarian: has(dietary.Diets, models.DietVegetarian),
+		Vegan:      has(dietary.Diets, models.DietVegan),
+		GlutenFree: has(dietary.Diets, models.DietGlutenFree),
+		DairyFree:  has(dietary.Diets, models.DietDairyFree),
+		NutFree:    has(dietary.Diets, models.DietNutFree),
+	})
+}
+
+// GetDietary returns the recipe's stored classification, or nil when the
+// recipe has not been classified yet.
+func (r *RecipeRepository) GetDietary(recipeID string) (*models.RecipeDietary, error) {
+	ctx := context.Background()
+	result, err := r.q.GetRecipeDietary(ctx, uuid.MustParse(recipeID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	flags := map[string]bool{
+		models.AllergenGluten:    result.Gluten,
+		models.AllergenDairy:     result.Dairy,
+		models.AllergenEgg:       result.Egg,
+		models.AllergenNuts:      result.Nuts,
+		models.AllergenPeanuts:   result.Peanuts,
+		models.AllergenShellfish: result.Shellfish,
+		models.AllergenSoy:       result.Soy,
+		models.AllergenSesame:    result.Sesame,
+		models.DietVegetarian:    result.Vegetarian,
+		models.DietVegan:         result.Vegan,
+		models.DietGlutenFree:    result.GlutenFree,
+		models.DietDairyFree:     result.DairyFree,
+		models.DietNutFree:       result.NutFree,
+	}
+	dietary := &models.RecipeDietary{Allergens: []string{}, Diets: []string{}}
+	for _, allergen := range models.Allergens {
+		if flags[allergen] {
+			dietary.Allergens = append(dietary.Allergens, allergen)
+		}
+	}
+	for _, diet := range models.Diets {
+		if flags[diet] {
+			dietary.Diets = append(dietary.Diets, diet)
+		}
+	}
+	return dietary, nil
+}
+
+// ListUnclassified returns the ID and markdown of every recipe without a
+// stored classification.
+func (r *RecipeRepository) ListUnclassified() ([]*models.Recipe, error) {
+	ctx := context.Background()
+	results, err := r.q.ListRecipesWithoutDietary(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make([]*models.Recipe, len(results))
+	for i, result := range results {
+		recipes[i] = &models.Recipe{
+			ID:              result.ID,
+			MarkdownContent: result.MarkdownContent,
+		}
+	}
+	return recipes, nil
+}
+
+// dietaryCondition is the WHERE condition on recipes aliased as r for a
+// dietary filter, or "" when the filter is empty. Every allergen and diet
+// is a column of recipe_dietary named after it; names that aren't known
+// allergens or diets are ignored, so nothing from the filter reaches the SQL
+// text.
+func dietaryCondition(filter models.DietaryFilter) string {
+	var checks []string
+	for _, allergen := range filter.FreeFrom {
+		if slices.Contains(models.Allergens, allergen) {
+			checks = append(checks, "d."+allergen+" = false")
+		}
+	}
+	for _, diet := range filter.Diets {
+		if slices.Contains(models.Diets, diet) {
+			checks = append(checks, "d."+strings.ReplaceAll(diet, "-", "_")+" = true")
+		}
+	}
+	if len(checks) == 0 {
+		return ""
+	}
+	return "r.id IN (SELECT d.recipe_id FROM recipe_dietary d WHERE " + strings.Join(checks, " AND ") + ")"
+}
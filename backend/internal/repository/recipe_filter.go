@@ -0,0 +1,425 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+// Filter facets, used to leave a facet's own condition out when counting it.
+const (
+	facetNone = iota
+	facetCategory
+	facetTags
+	facetDifficulty
+)
+
+const recipeColumns = `r.id, r.title, r.slug, r.markdown_content, r.author_id, r.category_id, r.description,
+    r.prep_time_minutes, r.cook_time_minutes, r.servings, r.difficulty, r.featured_image_path,
+    r.is_published, r.created_at, r.updated_at, r.published_at, r.deleted_at`
+
+// recipeSort is a keyset ordering: every key, ending with the ID as a
+// tiebreak, is sorted in the same direction so that a page can resume with a
+// single row comparison against the sort key of the last recipe seen. Keys
+// are written for the table alias %[1]s.
+type recipeSort struct {
+	keys []string
+	desc bool
+	// cursor records the sort key of a recipe, given its title lowercased
+	// by the database.
+	cursor func(recipe *models.Recipe, title string) pageCursor
+	// values returns the keys a cursor holds, without the ID, and false if
+	// it holds none for this ordering.
+	values func(cursor *pageCursor) ([]interface{}, bool)
+}
+
+var recipeSorts = map[string]recipeSort{
+	models.RecipeSortNewest: {
+		keys: []string{"COALESCE(%[1]s.published_at, %[1]s.created_at)", "%[1]s.id"},
+		desc: true,
+		cursor: func(recipe *models.Recipe, _ string) pageCursor {
+			if recipe.PublishedAt != nil {
+				return timeCursor(recipe.ID, *recipe.PublishedAt)
+			}
+			return timeCursor(recipe.ID, recipe.CreatedAt)
+		},
+		values: timeKey,
+	},
+	models.RecipeSortTitle: {
+		keys: []string{"LOWER(%[1]s.title)", "%[1]s.id"},
+		cursor: func(recipe *models.Recipe, title string) pageCursor {
+			return pageCursor{ID: recipe.ID, Text: &title}
+		},
+		values: func(cursor *pageCursor) ([]interface{}, bool) {
+			if cursor.Text == nil {
+				return nil, false
+			}
+			return []interface{}{*cursor.Text}, true
+		},
+	},
+	models.RecipeSortQuickest: {
+		keys: []string{
+			"CASE WHEN %[1]s.prep_time_minutes IS NULL AND %[1]s.cook_time_minutes IS NULL THEN 1 ELSE 0 END",
+			"COALESCE(%[1]s.prep_time_minutes, 0) + COALESCE(%[1]s.cook_time_minutes, 0)",
+			"%[1]s.id",
+		},
+		// Recipes without any times sort last and carry no minutes.
+		cursor: func(recipe *models.Recipe, _ string) pageCursor {
+			if recipe.PrepTimeMinutes == nil && recipe.CookTimeMinutes == nil {
+				return pageCursor{ID: recipe.ID}
+			}
+			minutes := 0
+			if recipe.PrepTimeMinutes != nil {
+				minutes += int(*recipe.PrepTimeMinutes)
+			}
+			if recipe.CookTimeMinutes != nil {
+				minutes += int(*recipe.CookTimeMinutes)
+			}
+			return pageCursor{ID: recipe.ID, Minutes: &minutes}
+		},
+		values: func(cursor *pageCursor) ([]interface{}, bool) {
+			if cursor.Minutes == nil {
+				return []interface{}{1, 0}, true
+			}
+			return []interface{}{0, *cursor.Minutes}, true
+		},
+	},
+	models.RecipeSortUpdated: {
+		keys: []string{"%[1]s.updated_at", "%[1]s.id"},
+		desc: true,
+		cursor: func(recipe *models.Recipe, _ string) pageCursor {
+			return timeCursor(recipe.ID, recipe.UpdatedAt)
+		},
+		values: timeKey,
+	},
+}
+
+func timeKey(cursor *pageCursor) ([]interface{}, bool) {
+	if cursor.Time == nil {
+		return nil, false
+	}
+	return []interface{}{cursorTime(*cursor.Time)}, true
+}
+
+func (s recipeSort) columns(alias string) string {
+	columns := make([]string, len(s.keys))
+	for i, key := range s.keys {
+		columns[i] = fmt.Sprintf(key, alias)
+	}
+	return strings.Join(columns, ", ")
+}
+
+func (s recipeSort) orderBy() string {
+	direction := " ASC"
+	if s.desc {
+		direction = " DESC"
+	}
+	columns := make([]string, len(s.keys))
+	for i, key := range s.keys {
+		columns[i] = fmt.Sprintf(key, "r") + direction
+	}
+	return strings.Join(columns, ", ")
+}
+
+// after is the condition that skips every recipe up to and including the
+// one the cursor marks in this sort order.
+func (s recipeSort) after(cursor *pageCursor, args *queryArgs) string {
+	op := ">"
+	if s.desc {
+		op = "<"
+	}
+	values, _ := s.values(cursor)
+	placeholders := make([]string, 0, len(values)+1)
+	for _, value := range values {
+		placeholders = append(placeholders, args.add(value))
+	}
+	placeholders = append(placeholders, args.add(cursor.ID))
+	return "(" + s.columns("r") + ") " + op + " (" + strings.Join(placeholders, ", ") + ")"
+}
+
+// queryArgs collects positional arguments. Placeholders are numbered in the
+// order they are added, which SQLite requires as well as Postgres.
+type queryArgs struct {
+	values []interface{}
+}
+
+func (a *queryArgs) add(value interface{}) string {
+	a.values = append(a.values, value)
+	return fmt.Sprintf("$%d", len(a.values))
+}
+
+// list adds every value and returns their placeholders separated by commas.
+func (a *queryArgs) list(values []string) string {
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		placeholders[i] = a.add(value)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// ListFiltered returns a page of published recipes matching the filter in
+// the requested sort order, starting after the recipe the cursor points at.
+func (r *RecipeRepository) ListFiltered(filter *models.RecipeFilter, limit int, cursor string) (*models.Page[*models.Recipe], error) {
+	ctx := context.Background()
+
+	sort, ok := recipeSorts[filter.Sort]
+	if !ok {
+		sort = recipeSorts[models.RecipeSortNewest]
+	}
+
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		if _, ok := sort.values(after); !ok {
+			return nil, errors.New("invalid cursor")
+		}
+	}
+
+	tags, err := r.resolveFilterTags(filter.Tags)
+	if err != nil {
+		return nil, err
+	}
+	args := &queryArgs{}
+	where := recipeFilterConditions(filter, tags, facetNone, args)
+	conditions := strings.Join(where, "\n  AND ")
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM recipes r WHERE `+conditions, args.values...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	if after != nil {
+		conditions += "\n  AND " + sort.after(after, args)
+	}
+	query := `SELECT ` + recipeColumns + `, LOWER(r.title)
+FROM recipes r
+WHERE ` + conditions + `
+ORDER BY ` + sort.orderBy() + `
+LIMIT ` + args.add(limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args.values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := []*models.Recipe{}
+	titles := map[uuid.UUID]string{}
+	for rows.Next() {
+		var title string
+		recipe, err := scanRecipe(rows, &title)
+		if err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, r.sqlcToModel(recipe))
+		titles[recipe.ID] = title
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return newPage(recipes, limit, total, func(recipe *models.Recipe) pageCursor {
+		return sort.cursor(recipe, titles[recipe.ID])
+	}), nil
+}
+
+// FacetCounts counts the published recipes matching the filter per
+// category, tag and difficulty.
+func (r *RecipeRepository) FacetCounts(filter *models.RecipeFilter) (*models.RecipeFacets, error) {
+	tags, err := r.resolveFilterTags(filter.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := r.facetQuery(filter, tags, facetCategory, `SELECT c.slug, c.name, COUNT(*)
+FROM recipes r
+JOIN categories c ON c.id = r.category_id`, "c.slug, c.name")
+	if err != nil {
+		return nil, err
+	}
+
+	tagCounts, err := r.facetQuery(filter, tags, facetTags, `SELECT t.slug, t.name, COUNT(*)
+FROM recipes r
+JOIN recipe_tags rt ON rt.recipe_id = r.id
+JOIN tags t ON t.id = rt.tag_id`, "t.slug, t.name")
+	if err != nil {
+		return nil, err
+	}
+
+	difficulties, err := r.facetQuery(filter, tags, facetDifficulty, `SELECT r.difficulty, r.difficulty, COUNT(*)
+FROM recipes r`, "r.difficulty")
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RecipeFacets{
+		Categories:   categories,
+		Tags:         tagCounts,
+		Difficulties: difficulties,
+	}, nil
+}
+
+func (r *RecipeRepository) facetQuery(filter *models.RecipeFilter, tags *filterTags, facet int, selectFrom, groupBy string) ([]models.FacetCount, error) {
+	ctx := context.Background()
+
+	args := &queryArgs{}
+	where := recipeFilterConditions(filter, tags, facet, args)
+	if facet == facetDifficulty {
+		where = append(where, "r.difficulty IS NOT NULL")
+	}
+
+	query := selectFrom + `
+WHERE ` + strings.Join(where, "\n  AND ") + `
+GROUP BY ` + groupBy + `
+ORDER BY COUNT(*) DESC, ` + groupBy
+
+	rows, err := r.db.QueryContext(ctx, query, args.values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []models.FacetCount{}
+	for rows.Next() {
+		var count models.FacetCount
+		if err := rows.Scan(&count.Value, &count.Label, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// filterTags holds the tags of a filter resolved to their IDs.
+type filterTags struct {
+	// ids lists each tag that exists once, however often and by whichever
+	// of its ID or slug the filter named it.
+	ids []string
+	// missing is set when the filter names a tag that doesn't exist.
+	missing bool
+}
+
+// resolveFilterTags looks up the tags named by ID or slug, so that a tag
+// named twice is only counted once when every tag has to match.
+func (r *RecipeRepository) resolveFilterTags(values []string) (*filterTags, error) {
+	ctx := context.Background()
+	tags := &filterTags{}
+	seen := map[uuid.UUID]bool{}
+	for _, value := range values {
+		var tag sqlc.Tag
+		var err error
+		if id, parseErr := uuid.Parse(value); parseErr == nil {
+			tag, err = r.q.GetTagByID(ctx, id)
+		} else {
+			tag, err = r.q.GetTagBySlug(ctx, value)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			tags.missing = true
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			tags.ids = append(tags.ids, tag.ID.String())
+		}
+	}
+	return tags, nil
+}
+
+// recipeFilterConditions turns the filter into WHERE conditions on recipes
+// aliased as r. The condition belonging to skip is left out.
+func recipeFilterConditions(filter *models.RecipeFilter, tags *filterTags, skip int, args *queryArgs) []string {
+	where := []string{"r.is_published = true", "r.deleted_at IS NULL"}
+
+	if filter.Category != "" && skip != facetCategory {
+		where = append(where, "r.category_id IN (SELECT c.id FROM categories c WHERE "+idOrSlugCondition("c", filter.Category, args)+")")
+	}
+
+	if len(filter.Tags) > 0 && skip != facetTags {
+		switch {
+		case len(tags.ids) == 0 || (filter.MatchAllTags && tags.missing):
+			where = append(where, "false")
+		case filter.MatchAllTags:
+			where = append(where, `r.id IN (SELECT rt.recipe_id FROM recipe_tags rt
+    WHERE rt.tag_id IN (`+args.list(tags.ids)+`)
+    GROUP BY rt.recipe_id HAVING COUNT(DISTINCT rt.tag_id) = `+args.add(len(tags.ids))+")")
+		default:
+			where = append(where, "r.id IN (SELECT rt.recipe_id FROM recipe_tags rt WHERE rt.tag_id IN ("+args.list(tags.ids)+"))")
+		}
+	}
+
+	if filter.Difficulty != "" && skip != facetDifficulty {
+		where = append(where, "LOWER(r.difficulty) = LOWER("+args.add(filter.Difficulty)+")")
+	}
+
+	if filter.MaxTotalTime != nil {
+		where = append(where, "(r.prep_time_minutes IS NOT NULL OR r.cook_time_minutes IS NOT NULL)",
+			"COALESCE(r.prep_time_minutes, 0) + COALESCE(r.cook_time_minutes, 0) <= "+args.add(*filter.MaxTotalTime))
+	}
+
+	if filter.AuthorID != "" {
+		where = append(where, "r.author_id = "+args.add(filter.AuthorID))
+	}
+
+	if filter.Group != "" {
+		where = append(where, "r.id IN (SELECT rg.recipe_id FROM recipe_groupings rg JOIN recipe_groups g ON g.id = rg.group_id WHERE "+idOrSlugCondition("g", filter.Group, args)+")")
+	}
+
+	if condition := dietaryCondition(filter.DietaryFilter); condition != "" {
+		where = append(where, condition)
+	}
+
+	return where
+}
+
+// idOrSlugCondition matches a table by ID when value parses as a UUID and by
+// slug otherwise; Postgres rejects comparing a UUID column with a slug.
+func idOrSlugCondition(alias, value string, args *queryArgs) string {
+	if _, err := uuid.Parse(value); err == nil {
+		return alias + ".id = " + args.add(value)
+	}
+	return alias + ".slug = " + args.add(value)
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanRecipe reads the recipeColumns of a recipe followed by any extra
+// columns selected after them.
+func scanRecipe(row rowScanner, extra ...interface{}) (sqlc.Recipe, error) {
+	var recipe sqlc.Recipe
+	dest := append([]interface{}{
+		&recipe.ID,
+		&recipe.Title,
+		&recipe.Slug,
+		&recipe.MarkdownContent,
+		&recipe.AuthorID,
+		&recipe.CategoryID,
+		&recipe.Description,
+		&recipe.PrepTimeMinutes,
+		&recipe.CookTimeMinutes,
+		&recipe.Servings,
+		&recipe.Difficulty,
+		&recipe.FeaturedImagePath,
+		&recipe.IsPublished,
+		&recipe.CreatedAt,
+		&recipe.UpdatedAt,
+		&recipe.PublishedAt,
+		&recipe.DeletedAt,
+	}, extra...)
+	err := row.Scan(dest...)
+	return recipe, err
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+// Fork creates fork as a copy of the recipe with the given ID, in a single
+// transaction that also copies the original's tags, group memberships and
+// images and records where the fork came from. Image files are shared
+// with the original rather than copied.
+func (r *RecipeRepository) Fork(originalID string, fork *models.Recipe) (*models.Recipe, error) {
+	ctx := context.Background()
+	originalUUID := uuid.MustParse(originalID)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	result, err := qtx.CreateRecipe(ctx, createRecipeParams(fork))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := qtx.CopyRecipeTags(ctx, sqlc.CopyRecipeTagsParams{ForkID: result.ID, RecipeID: originalUUID}); err != nil {
+		return nil, err
+	}
+	if err := qtx.CopyRecipeGroupings(ctx, sqlc.CopyRecipeGroupingsParams{ForkID: result.ID, RecipeID: originalUUID}); err != nil {
+		return nil, err
+	}
+
+	images, err := qtx.GetRecipeImages(ctx, sqlNullUUIDPtr(originalUUID))
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		err := qtx.CopyRecipeImage(ctx, sqlc.CopyRecipeImageParams{
+			ID:            uuid.New(),
+			RecipeID:      sqlNullUUIDPtr(result.ID),
+			FilePath:      image.FilePath,
+			WebpPath:      image.WebpPath,
+			ThumbnailPath: image.ThumbnailPath,
+			Caption:       image.Caption,
+			OrderIndex:    image.OrderIndex,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = qtx.CreateRecipeFork(ctx, sqlc.CreateRecipeForkParams{
+		RecipeID:     result.ID,
+		ForkedFromID: originalUUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.sqlcToModel(result), nil
+}
+
+// GetForkedFrom returns the ID of the recipe the recipe was forked from, or
+// nil when it isn't a fork.
+func (r *RecipeRepository) GetForkedFrom(recipeID string) (*uuid.UUID, error) {
+	ctx := context.Background()
+	forkedFromID, err := r.q.GetRecipeForkedFrom(ctx, uuid.MustParse(recipeID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &forkedFromID, nil
+}
+
+// GetForks returns the published recipes forked from the recipe, oldest
+// first.
+func (r *RecipeRepository) GetForks(recipeID string) ([]models.RecipeLink, error) {
+	ctx := context.Background()
+	results, err := r.q.ListRecipeForks(ctx, uuid.MustParse(recipeID))
+	if err != nil {
+		return nil, err
+	}
+
+	forks := make([]models.RecipeLink, len(results))
+	for i, result := range results {
+		id := result.ID
+		forks[i] = models.RecipeLink{RecipeID: &id, Slug: result.Slug, Title: result.Title}
+	}
+	return forks, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+// LoadFull assembles the recipe together with the requested sub-resources.
+// The lookups share one read-only transaction so they see the same snapshot
+// of the recipe. Sub-resources left out of include stay nil.
+func (r *RecipeRepository) LoadFull(recipe *models.Recipe, include []string) (*models.RecipeWithVariations, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	categoryRepo := &CategoryRepository{db: r.db, q: qtx}
+	userRepo := &UserRepository{db: r.db, q: qtx}
+	tagRepo := &TagRepository{db: r.db, q: qtx}
+	variationRepo := &VariationRepository{db: r.db, q: qtx}
+	full := &models.RecipeWithVariations{Recipe: *recipe}
+
+	if slices.Contains(include, models.RecipeIncludeCategory) && recipe.CategoryID != nil {
+		category, err := categoryRepo.GetByID(recipe.CategoryID.String())
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		full.Category = category
+	}
+
+	if slices.Contains(include, models.RecipeIncludeAuthor) && recipe.AuthorID != nil {
+		author, err := userRepo.GetByID(recipe.AuthorID.String())
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		full.Author = author
+	}
+
+	if slices.Contains(include, models.RecipeIncludeTags) {
+		tags, err := tagRepo.GetRecipeTags(recipe.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		full.Tags = make([]models.Tag, len(tags))
+		for i, tag := range tags {
+			full.Tags[i] = *tag
+		}
+	}
+
+	if slices.Contains(include, models.RecipeIncludeImages) {
+		images, err := qtx.GetRecipeImages(ctx, uuid.NullUUID{UUID: recipe.ID, Valid: true})
+		if err != nil {
+			return nil, err
+		}
+		full.BodyImages = make([]models.RecipeImage, len(images))
+		for i, image := range images {
+			full.BodyImages[i] = sqlcToModelRecipeImage(image)
+		}
+	}
+
+	if slices.Contains(include, models.RecipeIncludeGroups) {
+		groups, err := qtx.GetGroupsForRecipe(ctx, recipe.ID)
+		if err != nil {
+			return nil, err
+		}
+		full.Groups = make([]models.RecipeGroup, len(groups))
+		for i, group := range groups {
+			full.Groups[i] = *sqlcToModelRecipeGroup(group)
+		}
+	}
+
+	if slices.Contains(include, models.RecipeIncludeVariations) {
+		variations, err := variationRepo.GetVariationsWithAuthor(recipe.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		full.Variations = []models.VariationWithAuthor{}
+		for _, variation := range variations {
+			if variation.IsPublished {
+				full.Variations = append(full.Variations, *variation)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return full, nil
+}
+
+func sqlcToModelRecipeImage(row sqlc.RecipeImage) models.RecipeImage {
+	return models.RecipeImage{
+		ID:            row.ID,
+		RecipeID:      row.RecipeID.UUID,
+		FilePath:      row.FilePath,
+		WebPPath:      nullStringToPtr(row.WebpPath),
+		ThumbnailPath: nullStringToPtr(row.ThumbnailPath),
+		Caption:       nullStringToPtr(row.Caption),
+		OrderIndex:    int(row.OrderIndex.Int32),
+		UploadedAt:    row.UploadedAt.Time,
+	}
+}
@@ -64,9 +64,24 @@ func (r *RecipeGroupRepository) GetBySlug(slug string) (*models.RecipeGroup, err
 	return sqlcToModelRecipeGroup(result), nil
 }
 
-func (r *RecipeGroupRepository) List() ([]*models.RecipeGroup, error) {
+// List returns a page of groups, newest first, starting after the group the
+// cursor points at.
+func (r *RecipeGroupRepository) List(limit int, cursor string) (*models.Page[*models.RecipeGroup], error) {
 	ctx := context.Background()
-	results, err := r.q.ListRecipeGroups(ctx)
+	after, err := decodeTimeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := r.q.ListRecipeGroups(ctx, sqlc.ListRecipeGroupsParams{
+		AfterID:        afterID(after),
+		AfterCreatedAt: afterTime(after),
+		Limit:          int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.q.CountRecipeGroups(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +89,9 @@ func (r *RecipeGroupRepository) List() ([]*models.RecipeGroup, error) {
 	for i, row := range results {
 		groups[i] = sqlcToModelRecipeGroup(row)
 	}
-	return groups, nil
+	return newPage(groups, limit, total, func(group *models.RecipeGroup) pageCursor {
+		return timeCursor(group.ID, group.CreatedAt)
+	}), nil
 }
 
 func (r *RecipeGroupRepository) Update(id string, group *models.RecipeGroup) (*models.RecipeGroup, error) {
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+type RecipeIngredientRepository struct {
+	db *sql.DB
+	q  *sqlc.Queries
+}
+
+func NewRecipeIngredientRepository(db *sql.DB, q *sqlc.Queries) *RecipeIngredientRepository {
+	return &RecipeIngredientRepository{
+		db: db,
+		q:  q,
+	}
+}
+
+func (r *RecipeIngredientRepository) GetByRecipe(recipeID string) ([]models.RecipeIngredient, error) {
+	ctx := context.Background()
+	results, err := r.q.GetRecipeIngredients(ctx, uuid.MustParse(recipeID))
+	if err != nil {
+		return nil, err
+	}
+
+	ingredients := make([]models.RecipeIngredient, len(results))
+	for i, result := range results {
+		ingredients[i] = r.sqlcToModel(result)
+	}
+	return ingredients, nil
+}
+
+// ReplaceForRecipe swaps the stored ingredient list of a recipe for the given
+// one in a single transaction.
+func (r *RecipeIngredientRepository) ReplaceForRecipe(recipeID string, ingredients []models.RecipeIngredient) ([]models.RecipeIngredient, error) {
+	ctx := context.Background()
+	recipeUUID := uuid.MustParse(recipeID)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	if err := qtx.DeleteRecipeIngredients(ctx, recipeUUID); err != nil {
+		return nil, err
+	}
+
+	saved := make([]models.RecipeIngredient, len(ingredients))
+	for i, ingredient := range ingredients {
+		result, err := qtx.CreateRecipeIngredient(ctx, sqlc.CreateRecipeIngredientParams{
+			ID:          uuid.New(),
+			RecipeID:    recipeUUID,
+			OrderIndex:  int32(i),
+			RawText:     ingredient.RawText,
+			Quantity:    sqlNullFloat64(ingredient.Quantity),
+			QuantityMax: sqlNullFloat64(ingredient.QuantityMax),
+			Unit:        sqlNullString(ingredient.Unit),
+			Name:        ingredient.Name,
+			Note:        sqlNullString(ingredient.Note),
+		})
+		if err != nil {
+			return nil, err
+		}
+		saved[i] = r.sqlcToModel(result)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (r *RecipeIngredientRepository) sqlcToModel(dbIngredient sqlc.RecipeIngredient) models.RecipeIngredient {
+	return models.RecipeIngredient{
+		ID:          dbIngredient.ID,
+		RecipeID:    dbIngredient.RecipeID,
+		OrderIndex:  int(dbIngredient.OrderIndex),
+		RawText:     dbIngredient.RawText,
+		Quantity:    nullFloat64ToPtr(dbIngredient.Quantity),
+		QuantityMax: nullFloat64ToPtr(dbIngredient.QuantityMax),
+		Unit:        nullStringToPtr(dbIngredient.Unit),
+		Name:        dbIngredient.Name,
+		Note:        nullStringToPtr(dbIngredient.Note),
+	}
+}
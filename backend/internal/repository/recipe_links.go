@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+// ReplaceLinks swaps the recipe's stored sub-recipe links for the given
+// ones in a single transaction.
+func (r *RecipeRepository) ReplaceLinks(recipeID string, links []models.RecipeLink) error {
+	ctx := context.Background()
+	recipeUUID := uuid.MustParse(recipeID)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	if err := qtx.DeleteRecipeLinks(ctx, recipeUUID); err != nil {
+		return err
+	}
+	for i, link := range links {
+		err := qtx.CreateRecipeLink(ctx, sqlc.CreateRecipeLinkParams{
+			RecipeID:       recipeUUID,
+			Slug:           link.Slug,
+			LinkedRecipeID: sqlNullUUID(link.RecipeID),
+			OrderIndex:     int32(i),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ResolveLinks points links to slug that didn't lead anywhere yet at the
+// recipe that now uses it.
+func (r *RecipeRepository) ResolveLinks(slug, recipeID string) error {
+	ctx := context.Background()
+	return r.q.ResolveRecipeLinks(ctx, sqlc.ResolveRecipeLinksParams{
+		LinkedRecipeID: sqlNullUUIDPtr(uuid.MustParse(recipeID)),
+		Slug:           slug,
+	})
+}
+
+// GetLinks returns the recipes the recipe links to, in the order the links
+// appear. Links to a slug no recipe has have no recipe ID or title.
+func (r *RecipeRepository) GetLinks(recipeID string) ([]models.RecipeLink, error) {
+	ctx := context.Background()
+	results, err := r.q.GetRecipeLinks(ctx, uuid.MustParse(recipeID))
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]models.RecipeLink, len(results))
+	for i, result := range results {
+		links[i] = models.RecipeLink{Slug: result.Slug}
+		if result.LinkedSlug.Valid {
+			links[i].RecipeID = nullUUIDToPtr(result.LinkedRecipeID)
+			links[i].Slug = result.LinkedSlug.String
+			links[i].Title = result.LinkedTitle.String
+		}
+	}
+	return links, nil
+}
+
+// GetBacklinks returns the published recipes that link to the recipe.
+func (r *RecipeRepository) GetBacklinks(recipeID string) ([]models.RecipeLink, error) {
+	ctx := context.Background()
+	results, err := r.q.GetRecipeBacklinks(ctx, sqlNullUUIDPtr(uuid.MustParse(recipeID)))
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]models.RecipeLink, len(results))
+	for i, result := range results {
+		id := result.ID
+		links[i] = models.RecipeLink{RecipeID: &id, Slug: result.Slug, Title: result.Title}
+	}
+	return links, nil
+}
@@ -23,13 +23,50 @@ func NewRecipeRepository(db *sql.DB, q *sqlc.Queries) *RecipeRepository {
 
 func (r *RecipeRepository) Create(recipe *models.Recipe) (*models.Recipe, error) {
 	ctx := context.Background()
+	result, err := r.q.CreateRecipe(ctx, createRecipeParams(recipe))
+	if err != nil {
+		return nil, err
+	}
+	return r.sqlcToModel(result), nil
+}
+
+// CreateWithTags inserts the recipe and tags it in a single transaction, so
+// a tag that can't be saved doesn't leave an untagged recipe behind. Tags
+// without an ID are created first.
+func (r *RecipeRepository) CreateWithTags(recipe *models.Recipe, tags []*models.Tag) (*models.Recipe, error) {
+	ctx := context.Background()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	result, err := qtx.CreateRecipe(ctx, createRecipeParams(recipe))
+	if err != nil {
+		return nil, err
+	}
+	tagRepo := &TagRepository{db: r.db, q: qtx}
+	if _, err := tagRepo.attachToRecipe(result.ID, tags); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.sqlcToModel(result), nil
+}
 
+// createRecipeParams maps a new recipe onto the insert, giving it an ID
+// unless it has one.
+func createRecipeParams(recipe *models.Recipe) sqlc.CreateRecipeParams {
 	id := recipe.ID
 	if (uuid.UUID{}) == id {
 		id = uuid.New()
 	}
 
-	result, err := r.q.CreateRecipe(ctx, sqlc.CreateRecipeParams{
+	return sqlc.CreateRecipeParams{
 		ID:                id,
 		Title:             recipe.Title,
 		Slug:              recipe.Slug,
@@ -43,11 +80,7 @@ func (r *RecipeRepository) Create(recipe *models.Recipe) (*models.Recipe, error)
 		Difficulty:        sqlNullString(recipe.Difficulty),
 		FeaturedImagePath: sqlNullString(recipe.FeaturedImagePath),
 		IsPublished:       sql.NullBool{Bool: recipe.IsPublished, Valid: true},
-	})
-	if err != nil {
-		return nil, err
 	}
-	return r.sqlcToModel(result), nil
 }
 
 func (r *RecipeRepository) GetByID(id string) (*models.Recipe, error) {
@@ -68,6 +101,13 @@ func (r *RecipeRepository) GetBySlug(slug string) (*models.Recipe, error) {
 	return r.sqlcToModel(result), nil
 }
 
+// GetIDBySlug returns the ID of the recipe holding the slug, whether or not
+// it is in the trash.
+func (r *RecipeRepository) GetIDBySlug(slug string) (uuid.UUID, error) {
+	ctx := context.Background()
+	return r.q.GetRecipeIDBySlug(ctx, slug)
+}
+
 func (r *RecipeRepository) List(limit, offset int) ([]*models.Recipe, error) {
 	ctx := context.Background()
 	results, err := r.q.ListRecipes(ctx, sqlc.ListRecipesParams{
@@ -85,30 +125,37 @@ func (r *RecipeRepository) List(limit, offset int) ([]*models.Recipe, error) {
 	return recipes, nil
 }
 
-func (r *RecipeRepository) Search(query string, limit, offset int) ([]*models.Recipe, error) {
+func (r *RecipeRepository) Update(id string, recipe *models.Recipe) (*models.Recipe, error) {
 	ctx := context.Background()
-	results, err := r.q.SearchRecipes(ctx, sqlc.SearchRecipesParams{
-		Column1: sql.NullString{String: query, Valid: true},
-		Limit:   int32(limit),
-		Offset:  int32(offset),
+	result, err := r.q.UpdateRecipe(ctx, sqlc.UpdateRecipeParams{
+		ID:                uuid.MustParse(id),
+		Title:             sqlNullStringPtr(recipe.Title),
+		Slug:              sqlNullStringPtr(recipe.Slug),
+		MarkdownContent:   sqlNullStringPtr(recipe.MarkdownContent),
+		CategoryID:        sqlNullUUID(recipe.CategoryID),
+		Description:       sqlNullString(recipe.Description),
+		PrepTimeMinutes:   sqlNullInt32(recipe.PrepTimeMinutes),
+		CookTimeMinutes:   sqlNullInt32(recipe.CookTimeMinutes),
+		Servings:          sqlNullInt32(recipe.Servings),
+		Difficulty:        sqlNullString(recipe.Difficulty),
+		FeaturedImagePath: sqlNullString(recipe.FeaturedImagePath),
+		IsPublished:       sqlNullBoolPtr(recipe.IsPublished),
 	})
 	if err != nil {
 		return nil, err
 	}
-
-	recipes := make([]*models.Recipe, len(results))
-	for i, result := range results {
-		recipes[i] = r.sqlcToModel(result)
-	}
-	return recipes, nil
+	return r.sqlcToModel(result), nil
 }
 
-func (r *RecipeRepository) Update(id string, recipe *models.Recipe) (*models.Recipe, error) {
+// ReplaceContent overwrites every editable field of the recipe apart from
+// its published state. Unlike Update, fields recipe leaves nil are cleared.
+func (r *RecipeRepository) ReplaceContent(id string, recipe *models.Recipe) (*models.Recipe, error) {
 	ctx := context.Background()
-	result, err := r.q.UpdateRecipe(ctx, sqlc.UpdateRecipeParams{
+	result, err := r.q.ReplaceRecipeContent(ctx, sqlc.ReplaceRecipeContentParams{
 		ID:                uuid.MustParse(id),
-		Title:             sqlNullStringPtr(recipe.Title),
-		MarkdownContent:   sqlNullStringPtr(recipe.MarkdownContent),
+		Title:             recipe.Title,
+		Slug:              recipe.Slug,
+		MarkdownContent:   recipe.MarkdownContent,
 		CategoryID:        sqlNullUUID(recipe.CategoryID),
 		Description:       sqlNullString(recipe.Description),
 		PrepTimeMinutes:   sqlNullInt32(recipe.PrepTimeMinutes),
@@ -116,7 +163,6 @@ func (r *RecipeRepository) Update(id string, recipe *models.Recipe) (*models.Rec
 		Servings:          sqlNullInt32(recipe.Servings),
 		Difficulty:        sqlNullString(recipe.Difficulty),
 		FeaturedImagePath: sqlNullString(recipe.FeaturedImagePath),
-		IsPublished:       sqlNullBoolPtr(recipe.IsPublished),
 	})
 	if err != nil {
 		return nil, err
@@ -151,6 +197,10 @@ func (r *RecipeRepository) UpdateFeaturedImage(id string, imagePath *string) err
 }
 
 func (r *RecipeRepository) sqlcToModel(dbRecipe sqlc.Recipe) *models.Recipe {
+	return sqlcToModelRecipe(dbRecipe)
+}
+
+func sqlcToModelRecipe(dbRecipe sqlc.Recipe) *models.Recipe {
 	return &models.Recipe{
 		ID:                dbRecipe.ID,
 		Title:             dbRecipe.Title,
@@ -168,5 +218,6 @@ func (r *RecipeRepository) sqlcToModel(dbRecipe sqlc.Recipe) *models.Recipe {
 		CreatedAt:         dbRecipe.CreatedAt.Time,
 		UpdatedAt:         dbRecipe.UpdatedAt.Time,
 		PublishedAt:       nullTimeToTimePtr(dbRecipe.PublishedAt),
+		DeletedAt:         nullTimeToTimePtr(dbRecipe.DeletedAt),
 	}
 }
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+type RecipeRevisionRepository struct {
+	db *sql.DB
+	q  *sqlc.Queries
+}
+
+func NewRecipeRevisionRepository(db *sql.DB, q *sqlc.Queries) *RecipeRevisionRepository {
+	return &RecipeRevisionRepository{
+		db: db,
+		q:  q,
+	}
+}
+
+// Create stores a new revision. The revision number is assigned by the
+// database as one past the recipe's latest revision.
+func (r *RecipeRevisionRepository) Create(revision *models.RecipeRevision) (*models.RecipeRevision, error) {
+	ctx := context.Background()
+
+	var restoredFrom sql.NullInt32
+	if revision.RestoredFrom != nil {
+		restoredFrom = sqlInt32(int32(*revision.RestoredFrom))
+	}
+
+	result, err := r.q.CreateRecipeRevision(ctx, sqlc.CreateRecipeRevisionParams{
+		ID:                uuid.New(),
+		RecipeID:          revision.RecipeID,
+		AuthorID:          sqlNullUUID(revision.AuthorID),
+		Title:             revision.Title,
+		MarkdownContent:   revision.MarkdownContent,
+		CategoryID:        sqlNullUUID(revision.CategoryID),
+		Description:       sqlNullString(revision.Description),
+		PrepTimeMinutes:   sqlNullInt32(revision.PrepTimeMinutes),
+		CookTimeMinutes:   sqlNullInt32(revision.CookTimeMinutes),
+		Servings:          sqlNullInt32(revision.Servings),
+		Difficulty:        sqlNullString(revision.Difficulty),
+		FeaturedImagePath: sqlNullString(revision.FeaturedImagePath),
+		IsPublished:       sqlNullBool(revision.IsPublished),
+		RestoredFrom:      restoredFrom,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.sqlcToModel(result), nil
+}
+
+func (r *RecipeRevisionRepository) GetByNumber(recipeID string, revisionNumber int) (*models.RecipeRevision, error) {
+	ctx := context.Background()
+	result, err := r.q.GetRecipeRevision(ctx, sqlc.GetRecipeRevisionParams{
+		RecipeID:       uuid.MustParse(recipeID),
+		RevisionNumber: int32(revisionNumber),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.sqlcToModel(result), nil
+}
+
+func (r *RecipeRevisionRepository) ListByRecipe(recipeID string) ([]*models.RecipeRevision, error) {
+	ctx := context.Background()
+	results, err := r.q.ListRecipeRevisions(ctx, uuid.MustParse(recipeID))
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*models.RecipeRevision, len(results))
+	for i, result := range results {
+		revisions[i] = r.sqlcToModel(result)
+	}
+	return revisions, nil
+}
+
+func (r *RecipeRevisionRepository) CountByRecipe(recipeID string) (int, error) {
+	ctx := context.Background()
+	count, err := r.q.CountRecipeRevisions(ctx, uuid.MustParse(recipeID))
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (r *RecipeRevisionRepository) sqlcToModel(dbRevision sqlc.RecipeRevision) *models.RecipeRevision {
+	var restoredFrom *int
+	if dbRevision.RestoredFrom.Valid {
+		n := int(dbRevision.RestoredFrom.Int32)
+		restoredFrom = &n
+	}
+
+	return &models.RecipeRevision{
+		ID:                dbRevision.ID,
+		RecipeID:          dbRevision.RecipeID,
+		RevisionNumber:    int(dbRevision.RevisionNumber),
+		AuthorID:          nullUUIDToPtr(dbRevision.AuthorID),
+		Title:             dbRevision.Title,
+		MarkdownContent:   dbRevision.MarkdownContent,
+		CategoryID:        nullUUIDToPtr(dbRevision.CategoryID),
+		Description:       nullStringToPtr(dbRevision.Description),
+		PrepTimeMinutes:   nullInt32ToPtr(dbRevision.PrepTimeMinutes),
+		CookTimeMinutes:   nullInt32ToPtr(dbRevision.CookTimeMinutes),
+		Servings:          nullInt32ToPtr(dbRevision.Servings),
+		Difficulty:        nullStringToPtr(dbRevision.Difficulty),
+		FeaturedImagePath: nullStringToPtr(dbRevision.FeaturedImagePath),
+		IsPublished:       dbRevision.IsPublished.Bool,
+		RestoredFrom:      restoredFrom,
+		CreatedAt:         dbRevision.CreatedAt.Time,
+	}
+}
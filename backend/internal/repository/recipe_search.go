@@ -0,0 +1,374 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Highlight markers used inside the database so that the surrounding text can
+// be HTML-escaped before the <mark> tags are put in.
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+)
+
+// The search queries differ per database, so unlike the rest of the
+// repository they are not generated by sqlc. Each selects every match with
+// its rank; searchPage adds the ordering, cursor and limit around them.
+const searchRecipesPostgres = `SELECT ` + recipeColumns + `,
+    ts_rank(s.document, query)::float8 AS rank,
+    ts_headline('english', s.description || ' ' || s.content, query, $1) AS snippet
+FROM recipe_search s
+JOIN recipes r ON r.id = s.recipe_id,
+    websearch_to_tsquery('english', $2) AS query
+WHERE s.document @@ query
+  AND r.is_published = true
+  AND r.deleted_at IS NULL`
+
+const countSearchRecipesPostgres = `SELECT COUNT(*)
+FROM recipe_search s
+JOIN recipes r ON r.id = s.recipe_id
+WHERE s.document @@ websearch_to_tsquery('english', $1)
+  AND r.is_published = true
+  AND r.deleted_at IS NULL`
+
+// SQLite numbers $N parameters in order of first appearance, so they are
+// written in that order here.
+const searchRecipesFTS5 = `SELECT ` + recipeColumns + `,
+    -bm25(recipe_search_fts, 10.0, 3.0, 1.0, 5.0, 4.0) AS rank,
+    snippet(recipe_search_fts, -1, $1, $2, '…', 16) AS snippet
+FROM recipe_search_fts
+JOIN recipe_search s ON s.rowid = recipe_search_fts.rowid
+JOIN recipes r ON r.id = s.recipe_id
+WHERE recipe_search_fts MATCH $3
+  AND r.is_published = true
+  AND r.deleted_at IS NULL`
+
+const countSearchRecipesFTS5 = `SELECT COUNT(*)
+FROM recipe_search_fts
+JOIN recipe_search s ON s.rowid = recipe_search_fts.rowid
+JOIN recipes r ON r.id = s.recipe_id
+WHERE recipe_search_fts MATCH $1
+  AND r.is_published = true
+  AND r.deleted_at IS NULL`
+
+// Search runs a relevance-ranked full-text search over published recipes
+// matching the dietary filter and returns the page after the cursor.
+// Postgres uses the tsvector column on recipe_search, SQLite uses the FTS5
+// index when it is available and plain LIKE matching otherwise.
+func (r *RecipeRepository) Search(query string, dietary models.DietaryFilter, limit int, cursor string) (*models.Page[*models.RecipeSearchResult], error) {
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil && after.Rank == nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return newPage([]*models.RecipeSearchResult{}, limit, 0, searchCursor), nil
+	}
+
+	// The dietary condition has no parameters, so it can follow the fixed
+	// ones in each query.
+	var extra string
+	if condition := dietaryCondition(dietary); condition != "" {
+		extra = "\n  AND " + condition
+	}
+
+	if _, ok := r.db.Driver().(*sqlite3.SQLiteDriver); !ok {
+		options := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=30, MinWords=10", highlightStart, highlightStop)
+		args := &queryArgs{values: []interface{}{options, query}}
+		return r.querySearch(searchRecipesPostgres+extra, args, countSearchRecipesPostgres+extra, query, after, limit)
+	}
+
+	hasFTS, err := r.hasFTS5Index()
+	if err != nil {
+		return nil, err
+	}
+	if hasFTS {
+		match := fts5Query(terms)
+		args := &queryArgs{values: []interface{}{highlightStart, highlightStop, match}}
+		return r.querySearch(searchRecipesFTS5+extra, args, countSearchRecipesFTS5+extra, match, after, limit)
+	}
+	return r.searchLike(terms, extra, after, limit)
+}
+
+func searchCursor(result *models.RecipeSearchResult) pageCursor {
+	rank := result.Rank
+	return pageCursor{ID: result.ID, Rank: &rank}
+}
+
+// searchPage orders the matches selected by query by rank, then ID, and
+// keeps those after the cursor. The matches are wrapped in a subquery so the
+// computed rank can be compared.
+func searchPage(query string, args *queryArgs, after *pageCursor, limit int) string {
+	paged := `SELECT * FROM (` + query + `) results`
+	if after != nil {
+		rank, id := args.add(*after.Rank), args.add(after.ID)
+		paged += `
+WHERE results.rank < ` + rank + ` OR (results.rank = ` + rank + ` AND results.id < ` + id + `)`
+	}
+	return paged + `
+ORDER BY results.rank DESC, results.id DESC
+LIMIT ` + args.add(limit+1)
+}
+
+// IndexForSearch writes the recipe's current title, description, content,
+// tag names and category name to the search index.
+func (r *RecipeRepository) IndexForSearch(id string) error {
+	ctx := context.Background()
+	recipeID := uuid.MustParse(id)
+
+	recipe, err := r.q.GetRecipeByID(ctx, recipeID)
+	if err != nil {
+		return err
+	}
+
+	tags, err := r.q.GetRecipeTags(ctx, recipeID)
+	if err != nil {
+		return err
+	}
+	tagNames := make([]string, len(tags))
+	for i, tag := range tags {
+		tagNames[i] = tag.Name
+	}
+
+	var category string
+	if recipe.CategoryID.Valid {
+		c, err := r.q.GetCategoryByID(ctx, recipe.CategoryID.UUID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		category = c.Name
+	}
+
+	return r.q.UpsertRecipeSearchDocument(ctx, sqlc.UpsertRecipeSearchDocumentParams{
+		RecipeID:    recipeID,
+		Title:       recipe.Title,
+		Description: recipe.Description.String,
+		Content:     recipe.MarkdownContent,
+		Tags:        strings.Join(tagNames, " "),
+		Category:    category,
+	})
+}
+
+func (r *RecipeRepository) RemoveFromSearch(id string) error {
+	ctx := context.Background()
+	return r.q.DeleteRecipeSearchDocument(ctx, uuid.MustParse(id))
+}
+
+// IDsWithTag returns the recipes outside the trash that carry the tag, whose
+// search entries name it.
+func (r *RecipeRepository) IDsWithTag(tagID string) ([]uuid.UUID, error) {
+	ctx := context.Background()
+	return r.q.ListRecipeIDsWithTag(ctx, uuid.MustParse(tagID))
+}
+
+// IDsInCategory returns the recipes outside the trash that are filed under
+// the category, whose search entries name it.
+func (r *RecipeRepository) IDsInCategory(categoryID string) ([]uuid.UUID, error) {
+	ctx := context.Background()
+	return r.q.ListRecipeIDsInCategory(ctx, sqlNullUUIDPtr(uuid.MustParse(categoryID)))
+}
+
+func (r *RecipeRepository) hasFTS5Index() (bool, error) {
+	ctx := context.Background()
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE name = 'recipe_search_fts'`).Scan(&count)
+	return count > 0, err
+}
+
+func (r *RecipeRepository) querySearch(query string, args *queryArgs, countQuery, match string, after *pageCursor, limit int) (*models.Page[*models.RecipeSearchResult], error) {
+	ctx := context.Background()
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, countQuery, match).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, searchPage(query, args, after, limit), args.values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*models.RecipeSearchResult{}
+	for rows.Next() {
+		var rank float64
+		var snippet sql.NullString
+		recipe, err := scanRecipe(rows, &rank, &snippet)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &models.RecipeSearchResult{
+			Recipe:  *r.sqlcToModel(recipe),
+			Rank:    rank,
+			Snippet: highlightToHTML(snippet.String),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return newPage(results, limit, total, searchCursor), nil
+}
+
+// searchLike is the fallback for SQLite builds without FTS5. Every term has
+// to appear somewhere in the document; matches in the title, tags and
+// category weigh more than matches in the body. extra is appended to the
+// WHERE clause.
+func (r *RecipeRepository) searchLike(terms []string, extra string, after *pageCursor, limit int) (*models.Page[*models.RecipeSearchResult], error) {
+	ctx := context.Background()
+
+	args := &queryArgs{}
+	var rank []string
+	for _, term := range terms {
+		pattern := args.add("%" + term + "%")
+		rank = append(rank, `(CASE WHEN LOWER(s.title) LIKE `+pattern+` THEN 10 ELSE 0 END
+    + CASE WHEN LOWER(s.tags) LIKE `+pattern+` THEN 5 ELSE 0 END
+    + CASE WHEN LOWER(s.category) LIKE `+pattern+` THEN 4 ELSE 0 END
+    + CASE WHEN LOWER(s.description) LIKE `+pattern+` THEN 3 ELSE 0 END
+    + CASE WHEN LOWER(s.content) LIKE `+pattern+` THEN 1 ELSE 0 END)`)
+	}
+
+	var where []string
+	for i := range terms {
+		pattern := fmt.Sprintf("$%d", i+1)
+		where = append(where, `(LOWER(s.title) LIKE `+pattern+` OR LOWER(s.description) LIKE `+pattern+` OR LOWER(s.content) LIKE `+pattern+`
+    OR LOWER(s.tags) LIKE `+pattern+` OR LOWER(s.category) LIKE `+pattern+`)`)
+	}
+
+	from := `
+FROM recipe_search s
+JOIN recipes r ON r.id = s.recipe_id
+WHERE r.is_published = true
+  AND r.deleted_at IS NULL
+  AND ` + strings.Join(where, "\n  AND ") + extra
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+from, args.values...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + recipeColumns + `, s.description, s.content, ` + strings.Join(rank, " + ") + ` AS rank` + from
+	rows, err := r.db.QueryContext(ctx, searchPage(query, args, after, limit), args.values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*models.RecipeSearchResult{}
+	for rows.Next() {
+		var description, content string
+		var rank float64
+		recipe, err := scanRecipe(rows, &description, &content, &rank)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &models.RecipeSearchResult{
+			Recipe:  *r.sqlcToModel(recipe),
+			Rank:    rank,
+			Snippet: highlightToHTML(likeSnippet(description+" "+content, terms)),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return newPage(results, limit, total, searchCursor), nil
+}
+
+// searchTerms lowercases the query and splits it into words, dropping any
+// punctuation that would otherwise be parsed as search syntax.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// fts5Query turns search terms into an FTS5 expression that matches
+// documents containing every term, each as a prefix.
+func fts5Query(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + term + `"*`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// likeSnippet cuts a window of text around the first matching term and marks
+// every occurrence of the terms inside it.
+func likeSnippet(text string, terms []string) string {
+	const window = 80
+
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// Lowercasing changed byte offsets; match on the lowercased text.
+		text = lower
+	}
+	first := -1
+	for _, term := range terms {
+		if idx := strings.Index(lower, term); idx >= 0 && (first < 0 || idx < first) {
+			first = idx
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start := max(0, first-window/2)
+	end := min(len(text), first+window)
+	for start > 0 && !isRuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	excerpt, lowerExcerpt := text[start:end], lower[start:end]
+	for i := 0; i < len(excerpt); {
+		matched := false
+		for _, term := range terms {
+			if strings.HasPrefix(lowerExcerpt[i:], term) {
+				b.WriteString(highlightStart + excerpt[i:i+len(term)] + highlightStop)
+				i += len(term)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(excerpt[i])
+			i++
+		}
+	}
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// highlightToHTML escapes a snippet and swaps the internal markers for
+// <mark> tags.
+func highlightToHTML(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, highlightStart, "<mark>")
+	return strings.ReplaceAll(escaped, highlightStop, "</mark>")
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"crème", "brûlée", "2"}, searchTerms("Crème-Brûlée  (2)!"))
+	assert.Empty(t, searchTerms(` "* `))
+}
+
+func TestFts5Query(t *testing.T) {
+	assert.Equal(t, `"lemon"* "tart"*`, fts5Query([]string{"lemon", "tart"}))
+}
+
+func TestLikeSnippet(t *testing.T) {
+	snippet := highlightToHTML(likeSnippet("Whisk the eggs & sugar until pale.", []string{"sugar"}))
+	assert.Equal(t, "Whisk the eggs &amp; <mark>sugar</mark> until pale.", snippet)
+
+	assert.Empty(t, likeSnippet("Nothing here", []string{"sugar"}))
+}
+
+func TestLikeSnippet_Window(t *testing.T) {
+	text := "Start. Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor. " +
+		"Add the butter. Then incididunt ut labore et dolore magna aliqua, ut enim ad minim veniam, quis nostrud exercitation."
+
+	snippet := likeSnippet(text, []string{"butter"})
+	assert.True(t, len(snippet) < len(text))
+	assert.Contains(t, snippet, highlightStart+"butter"+highlightStop)
+	assert.Equal(t, "…", snippet[:len("…")])
+}
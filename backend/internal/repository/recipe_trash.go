@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+// SoftDelete moves the recipe to the trash. Trashed recipes keep their
+// variations, images and other records but are left out of every lookup
+// and listing until they are restored or purged.
+func (r *RecipeRepository) SoftDelete(id string) error {
+	ctx := context.Background()
+	return r.q.TrashRecipe(ctx, uuid.MustParse(id))
+}
+
+// Restore takes the recipe out of the trash.
+func (r *RecipeRepository) Restore(id string) error {
+	ctx := context.Background()
+	return r.q.RestoreTrashedRecipe(ctx, uuid.MustParse(id))
+}
+
+// GetTrashedByID returns the recipe with the given ID only when it is in
+// the trash.
+func (r *RecipeRepository) GetTrashedByID(id string) (*models.Recipe, error) {
+	ctx := context.Background()
+	result, err := r.q.GetTrashedRecipeByID(ctx, uuid.MustParse(id))
+	if err != nil {
+		return nil, err
+	}
+	return r.sqlcToModel(result), nil
+}
+
+// ListTrashed pages through the trashed recipes of authorID, most recently
+// deleted first.
+func (r *RecipeRepository) ListTrashed(authorID string, limit int, cursor string) (*models.Page[*models.Recipe], error) {
+	ctx := context.Background()
+	after, err := decodeTimeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	authorUUID := uuid.NullUUID{UUID: uuid.MustParse(authorID), Valid: true}
+	results, err := r.q.ListTrashedRecipes(ctx, sqlc.ListTrashedRecipesParams{
+		AuthorID:       authorUUID,
+		AfterID:        afterID(after),
+		AfterDeletedAt: afterTime(after),
+		Limit:          int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.q.CountTrashedRecipes(ctx, authorUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make([]*models.Recipe, len(results))
+	for i, result := range results {
+		recipes[i] = r.sqlcToModel(result)
+	}
+	return newPage(recipes, limit, total, func(recipe *models.Recipe) pageCursor {
+		return timeCursor(recipe.ID, *recipe.DeletedAt)
+	}), nil
+}
+
+// ListTrashedBefore returns every recipe that was moved to the trash before
+// cutoff, oldest first.
+func (r *RecipeRepository) ListTrashedBefore(cutoff time.Time) ([]*models.Recipe, error) {
+	ctx := context.Background()
+	results, err := r.q.ListRecipesTrashedBefore(ctx, sql.NullTime{Time: cutoff.UTC(), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make([]*models.Recipe, len(results))
+	for i, result := range results {
+		recipes[i] = r.sqlcToModel(result)
+	}
+	return recipes, nil
+}
+
+// PurgeTrashed permanently deletes the recipe if it is still in the trash
+// and was moved there before cutoff. It reports whether it was deleted, so
+// a recipe restored in the meantime is left alone.
+func (r *RecipeRepository) PurgeTrashed(id string, cutoff time.Time) (bool, error) {
+	ctx := context.Background()
+	rows, err := r.q.PurgeTrashedRecipe(ctx, sqlc.PurgeTrashedRecipeParams{
+		ID:        uuid.MustParse(id),
+		DeletedAt: sql.NullTime{Time: cutoff.UTC(), Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// UploadReferences returns the text of every field of the recipe and the
+// records deleted along with it that can point at an upload: the recipe
+// and its revisions, images, variations and cook log photos.
+func (r *RecipeRepository) UploadReferences(id string) ([]string, error) {
+	ctx := context.Background()
+	return r.q.ListRecipeUploadReferences(ctx, uuid.MustParse(id))
+}
+
+// CountUploadReferences counts the records that still point at the upload
+// with the given file name.
+func (r *RecipeRepository) CountUploadReferences(filename string) (int64, error) {
+	ctx := context.Background()
+	return r.q.CountUploadReferences(ctx, "%/uploads/"+filename+"%")
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+)
+
+// RecordOldSlug remembers a slug the recipe no longer uses so that links to
+// it can be redirected. A slug given up by several recipes in turn points at
+// the most recent one.
+func (r *RecipeRepository) RecordOldSlug(slug, recipeID string) error {
+	ctx := context.Background()
+	return r.q.RecordSlugHistory(ctx, sqlc.RecordSlugHistoryParams{
+		Slug:     slug,
+		RecipeID: uuid.MustParse(recipeID),
+	})
+}
+
+// ReleaseOldSlug drops the redirect for a slug that is in use again.
+func (r *RecipeRepository) ReleaseOldSlug(slug string) error {
+	ctx := context.Background()
+	return r.q.DeleteSlugHistory(ctx, slug)
+}
+
+// GetByOldSlug returns the recipe that last used slug before being renamed.
+func (r *RecipeRepository) GetByOldSlug(slug string) (*models.Recipe, error) {
+	ctx := context.Background()
+	history, err := r.q.GetSlugHistory(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.q.GetRecipeByID(ctx, history.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+	return r.sqlcToModel(result), nil
+}
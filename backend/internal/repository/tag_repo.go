@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/google/uuid"
 	"github.com/homecooking/backend/internal/db/sqlc"
@@ -59,9 +60,39 @@ func (r *TagRepository) GetBySlug(slug string) (*models.Tag, error) {
 	return r.sqlcToModel(result), nil
 }
 
-func (r *TagRepository) List() ([]*models.Tag, error) {
+func (r *TagRepository) GetByName(name string) (*models.Tag, error) {
 	ctx := context.Background()
-	results, err := r.q.ListTags(ctx)
+	result, err := r.q.GetTagByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return r.sqlcToModel(result), nil
+}
+
+// List returns a page of tags ordered by name, starting after the name the
+// cursor holds.
+func (r *TagRepository) List(limit int, cursor string) (*models.Page[*models.Tag], error) {
+	ctx := context.Background()
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil && after.Text == nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	params := sqlc.ListTagsParams{
+		AfterID: afterID(after),
+		Limit:   int32(limit + 1),
+	}
+	if after != nil {
+		params.AfterName = *after.Text
+	}
+	results, err := r.q.ListTags(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.q.CountTags(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +101,9 @@ func (r *TagRepository) List() ([]*models.Tag, error) {
 	for i, result := range results {
 		tags[i] = r.sqlcToModel(result)
 	}
-	return tags, nil
+	return newPage(tags, limit, total, func(tag *models.Tag) pageCursor {
+		return pageCursor{ID: tag.ID, Text: &tag.Name}
+	}), nil
 }
 
 func (r *TagRepository) Update(id string, tag *models.Tag) (*models.Tag, error) {
@@ -108,6 +141,60 @@ func (r *TagRepository) RemoveFromRecipe(recipeID string, tagID string) error {
 	})
 }
 
+// ReplaceForRecipe makes tags the complete tag set of a recipe in a single
+// transaction. Tags without an ID are created first.
+func (r *TagRepository) ReplaceForRecipe(recipeID string, tags []*models.Tag) ([]*models.Tag, error) {
+	ctx := context.Background()
+	recipeUUID := uuid.MustParse(recipeID)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := r.q.WithTx(tx)
+	txRepo := &TagRepository{db: r.db, q: qtx}
+	if err := qtx.ClearRecipeTags(ctx, recipeUUID); err != nil {
+		return nil, err
+	}
+
+	saved, err := txRepo.attachToRecipe(recipeUUID, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// attachToRecipe adds tags to a recipe, creating those without an ID. It is
+// meant for a repository bound to the caller's transaction, so that a tag
+// that can't be created leaves nothing behind.
+func (r *TagRepository) attachToRecipe(recipeID uuid.UUID, tags []*models.Tag) ([]*models.Tag, error) {
+	ctx := context.Background()
+	saved := make([]*models.Tag, len(tags))
+	for i, tag := range tags {
+		if (uuid.UUID{}) == tag.ID {
+			created, err := r.Create(tag)
+			if err != nil {
+				return nil, err
+			}
+			tag = created
+		}
+		if err := r.q.AddTagToRecipe(ctx, sqlc.AddTagToRecipeParams{
+			RecipeID: recipeID,
+			TagID:    tag.ID,
+		}); err != nil {
+			return nil, err
+		}
+		saved[i] = tag
+	}
+	return saved, nil
+}
+
 func (r *TagRepository) GetRecipeTags(recipeID string) ([]*models.Tag, error) {
 	ctx := context.Background()
 	results, err := r.q.GetRecipeTags(ctx, uuid.MustParse(recipeID))
@@ -46,9 +46,24 @@ func (r *UserInviteRepository) GetByCode(code string) (*models.UserInvite, error
 	return r.sqlcToModel(result), nil
 }
 
-func (r *UserInviteRepository) List() ([]*models.UserInvite, error) {
+// List returns a page of invites, newest first, starting after the invite
+// the cursor points at.
+func (r *UserInviteRepository) List(limit int, cursor string) (*models.Page[*models.UserInvite], error) {
 	ctx := context.Background()
-	results, err := r.q.ListInvites(ctx)
+	after, err := decodeTimeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := r.q.ListInvites(ctx, sqlc.ListInvitesParams{
+		AfterID:        afterID(after),
+		AfterCreatedAt: afterTime(after),
+		Limit:          int32(limit + 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+	total, err := r.q.CountInvites(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +72,9 @@ func (r *UserInviteRepository) List() ([]*models.UserInvite, error) {
 	for i, result := range results {
 		invites[i] = r.sqlcToModel(result)
 	}
-	return invites, nil
+	return newPage(invites, limit, total, func(invite *models.UserInvite) pageCursor {
+		return timeCursor(invite.ID, invite.CreatedAt)
+	}), nil
 }
 
 func (r *UserInviteRepository) Use(id string, usedBy string) (*models.UserInvite, error) {
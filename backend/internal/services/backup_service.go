@@ -0,0 +1,447 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+)
+
+// Limits on what a backup archive may unpack to. Backups carry every
+// upload of the instance, so they are allowed far more than recipe exports.
+const (
+	maxBackupEntries   = 100000
+	maxBackupEntrySize = 256 << 20
+	maxBackupSize      = 3 << 30
+)
+
+// backupFiles names the archive entry each kind of record is stored in.
+var backupFiles = []string{
+	"users",
+	"categories",
+	"tags",
+	"recipes",
+	"recipe_images",
+	"recipe_revisions",
+	"variations",
+	"groups",
+	"share_codes",
+	"cook_logs",
+	"collections",
+	"favorites",
+	"comments",
+}
+
+var uploadPathPattern = regexp.MustCompile(`/uploads/([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+type BackupService struct {
+	backupRepo     *repository.BackupRepository
+	recipeService  *RecipeService
+	storageService *StorageService
+}
+
+func NewBackupService(backupRepo *repository.BackupRepository, recipeService *RecipeService, storageService *StorageService) *BackupService {
+	return &BackupService{
+		backupRepo:     backupRepo,
+		recipeService:  recipeService,
+		storageService: storageService,
+	}
+}
+
+// BackupExport is a snapshot of the whole instance, ready to be written out
+// as an archive.
+type BackupExport struct {
+	// Filename is the name to download the archive as.
+	Filename string
+
+	manifest models.BackupManifest
+	data     *models.BackupData
+	storage  *StorageService
+}
+
+// Export takes a snapshot of the whole instance. Password hashes are only
+// included when asked for.
+func (s *BackupService) Export(includePasswords bool) (*BackupExport, error) {
+	data, err := s.backupRepo.Export(includePasswords)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return &BackupExport{
+		Filename: "homecooking-backup-" + now.Format("20060102-150405") + ".zip",
+		manifest: models.BackupManifest{
+			Version:        models.BackupVersion,
+			ExportedAt:     now,
+			PasswordHashes: includePasswords,
+			Counts: map[string]int{
+				"users":            len(data.Users),
+				"categories":       len(data.Categories),
+				"tags":             len(data.Tags),
+				"recipes":          len(data.Recipes),
+				"recipe_images":    len(data.RecipeImages),
+				"recipe_revisions": len(data.RecipeRevisions),
+				"variations":       len(data.Variations),
+				"groups":           len(data.Groups),
+				"share_codes":      len(data.ShareCodes),
+				"cook_logs":        len(data.CookLogs),
+				"collections":      len(data.Collections),
+				"favorites":        len(data.Favorites),
+				"comments":         len(data.Comments),
+			},
+		},
+		data:    data,
+		storage: s.storageService,
+	}, nil
+}
+
+// WriteArchive streams the snapshot to w as a zip archive: a manifest, a
+// JSON file per kind of record and, under files/, every upload a record
+// refers to. Uploads are copied from disk one at a time, so the archive is
+// never held in memory.
+func (e *BackupExport) WriteArchive(w io.Writer) error {
+	archive := zip.NewWriter(w)
+	if err := writeZipJSON(archive, "manifest.json", e.manifest); err != nil {
+		return err
+	}
+	records := backupRecords(e.data)
+	for _, name := range backupFiles {
+		if err := writeZipJSON(archive, name+".json", records[name]); err != nil {
+			return err
+		}
+	}
+	for _, filename := range referencedUploads(e.data) {
+		if err := e.writeUpload(archive, filename); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// writeUpload copies an upload into the archive. Uploads that have gone
+// missing from disk are left out rather than failing the export.
+func (e *BackupExport) writeUpload(archive *zip.Writer, filename string) error {
+	file, err := e.storage.OpenImage("/uploads/" + filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w, err := archive.Create("files/" + filename)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// Import restores an archive written by Export, read in place from r.
+// Records whose IDs or slugs are taken are given new ones, and uploads whose
+// names are taken by other files are stored under new names with the
+// recipes' references rewritten. Only the JSON entries are held in memory;
+// uploads are streamed to disk one at a time. Nothing is written when the
+// archive can't be restored in full. The restored recipes' ingredients,
+// links, first revisions and search entries are rebuilt after the records
+// are committed, so an error at that stage leaves the records restored.
+func (s *BackupService) Import(r io.ReaderAt, size int64) (*models.BackupImportResult, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup: %w", err)
+	}
+	if len(archive.File) > maxBackupEntries {
+		return nil, errors.New("invalid backup: too many files")
+	}
+
+	remaining := int64(maxBackupSize)
+	entries, uploads, err := readBackup(archive, &remaining)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestJSON, ok := entries["manifest.json"]
+	if !ok {
+		return nil, errors.New("invalid backup: no manifest")
+	}
+	var manifest models.BackupManifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup: manifest: %w", err)
+	}
+	if manifest.Version < 1 {
+		return nil, errors.New("invalid backup: no version")
+	}
+	if manifest.Version > models.BackupVersion {
+		return nil, errors.New("unsupported backup version")
+	}
+
+	data := &models.BackupData{}
+	for name, target := range backupRecords(data) {
+		raw, ok := entries[name+".json"]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			return nil, fmt.Errorf("invalid backup: %s: %w", name, err)
+		}
+	}
+
+	renamed, written, err := s.restoreUploads(uploads, &remaining)
+	if err != nil {
+		return nil, err
+	}
+	removeWritten := func() {
+		for _, filename := range written {
+			s.storageService.DeleteImage(filename)
+		}
+	}
+	rewriteUploads(data, renamed)
+
+	result, err := s.backupRepo.Restore(data)
+	if err != nil {
+		removeWritten()
+		return nil, err
+	}
+	result.Files = len(written)
+
+	for _, id := range result.RecipeIDs {
+		if err := s.recipeService.RebuildRestored(id.String()); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// restoreUploads streams the files/ entries of an archive into the upload
+// directory, by way of a temporary directory next to it. It returns the
+// uploads stored under new names and the names of the files it wrote. On
+// failure, the files it wrote are removed again.
+func (s *BackupService) restoreUploads(uploads []*zip.File, remaining *int64) (map[string]string, []string, error) {
+	tempDir, err := s.storageService.CreateTempDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(tempDir)
+
+	renamed := map[string]string{}
+	var written []string
+	for _, file := range uploads {
+		filename := strings.TrimPrefix(path.Clean(file.Name), "files/")
+		stored, created, err := s.restoreUpload(tempDir, filename, file, remaining)
+		if err != nil {
+			for _, name := range written {
+				s.storageService.DeleteImage(name)
+			}
+			return nil, nil, fmt.Errorf("invalid backup: %s: %w", file.Name, err)
+		}
+		if created {
+			written = append(written, stored)
+		}
+		if stored != filename {
+			renamed[filename] = stored
+		}
+	}
+	return renamed, written, nil
+}
+
+func (s *BackupService) restoreUpload(tempDir, filename string, file *zip.File, remaining *int64) (string, bool, error) {
+	if file.UncompressedSize64 > maxBackupEntrySize {
+		return "", false, errors.New("file too large")
+	}
+	if file.UncompressedSize64 > uint64(*remaining) {
+		return "", false, errors.New("archive too large")
+	}
+	r, err := file.Open()
+	if err != nil {
+		return "", false, err
+	}
+	defer r.Close()
+
+	limit := min(maxBackupEntrySize, *remaining)
+	limited := &strictLimitReader{r: r, n: limit}
+	stored, created, err := s.storageService.RestoreFile(tempDir, filename, limited)
+	*remaining -= limit - limited.n
+	return stored, created, err
+}
+
+// strictLimitReader reads up to n bytes from r and fails, rather than
+// stopping short, when r holds more.
+type strictLimitReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *strictLimitReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	if int64(n) > l.n {
+		return 0, errors.New("file too large")
+	}
+	l.n -= int64(n)
+	return n, err
+}
+
+// backupRecords maps each archive entry to the slice of data it holds, for
+// encoding and decoding alike.
+func backupRecords(data *models.BackupData) map[string]interface{} {
+	return map[string]interface{}{
+		"users":            &data.Users,
+		"categories":       &data.Categories,
+		"tags":             &data.Tags,
+		"recipes":          &data.Recipes,
+		"recipe_images":    &data.RecipeImages,
+		"recipe_revisions": &data.RecipeRevisions,
+		"variations":       &data.Variations,
+		"groups":           &data.Groups,
+		"share_codes":      &data.ShareCodes,
+		"cook_logs":        &data.CookLogs,
+		"collections":      &data.Collections,
+		"favorites":        &data.Favorites,
+		"comments":         &data.Comments,
+	}
+}
+
+func writeZipJSON(archive *zip.Writer, name string, v interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// readBackup reads the JSON entries of a backup archive into memory, by
+// name, and returns the entries under files/ to be streamed out later.
+func readBackup(archive *zip.Reader, remaining *int64) (map[string][]byte, []*zip.File, error) {
+	entries := map[string][]byte{}
+	var uploads []*zip.File
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(file.Name)
+		if strings.HasPrefix(name, "files/") {
+			uploads = append(uploads, file)
+			continue
+		}
+		if path.Ext(name) != ".json" {
+			continue
+		}
+		data, err := readZipFile(file, maxBackupEntrySize, remaining)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid backup: %s: %w", file.Name, err)
+		}
+		entries[name] = data
+	}
+	return entries, uploads, nil
+}
+
+// referencedUploads lists the uploads that recipes, their images and
+// revisions, and variations use as images or link to from their markdown,
+// along with the photos of cook logs.
+func referencedUploads(data *models.BackupData) []string {
+	var texts []string
+	for _, recipe := range data.Recipes {
+		if recipe.FeaturedImagePath != nil {
+			texts = append(texts, *recipe.FeaturedImagePath)
+		}
+		texts = append(texts, recipe.MarkdownContent)
+	}
+	for _, image := range data.RecipeImages {
+		texts = append(texts, image.FilePath)
+		if image.WebPPath != nil {
+			texts = append(texts, *image.WebPPath)
+		}
+		if image.ThumbnailPath != nil {
+			texts = append(texts, *image.ThumbnailPath)
+		}
+	}
+	for _, revision := range data.RecipeRevisions {
+		if revision.FeaturedImagePath != nil {
+			texts = append(texts, *revision.FeaturedImagePath)
+		}
+		texts = append(texts, revision.MarkdownContent)
+	}
+	for _, variation := range data.Variations {
+		texts = append(texts, variation.MarkdownContent)
+	}
+	for _, cookLog := range data.CookLogs {
+		texts = append(texts, cookLog.Photos...)
+	}
+	return uploadFilenames(texts)
+}
+
+// uploadFilenames returns the names of the uploads the texts point at,
+// each once.
+func uploadFilenames(texts []string) []string {
+	var filenames []string
+	seen := map[string]bool{}
+	for _, text := range texts {
+		for _, match := range uploadPathPattern.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				filenames = append(filenames, match[1])
+			}
+		}
+	}
+	return filenames
+}
+
+// rewriteUploads points references to renamed uploads at their new names.
+func rewriteUploads(data *models.BackupData, renamed map[string]string) {
+	if len(renamed) == 0 {
+		return
+	}
+	rewrite := func(text string) string {
+		return uploadPathPattern.ReplaceAllStringFunc(text, func(match string) string {
+			if name, ok := renamed[strings.TrimPrefix(match, "/uploads/")]; ok {
+				return "/uploads/" + name
+			}
+			return match
+		})
+	}
+	rewritePtr := func(text *string) *string {
+		if text == nil {
+			return nil
+		}
+		rewritten := rewrite(*text)
+		return &rewritten
+	}
+	for i := range data.Recipes {
+		recipe := &data.Recipes[i]
+		recipe.MarkdownContent = rewrite(recipe.MarkdownContent)
+		recipe.FeaturedImagePath = rewritePtr(recipe.FeaturedImagePath)
+	}
+	for i := range data.RecipeImages {
+		image := &data.RecipeImages[i]
+		image.FilePath = rewrite(image.FilePath)
+		image.WebPPath = rewritePtr(image.WebPPath)
+		image.ThumbnailPath = rewritePtr(image.ThumbnailPath)
+	}
+	for i := range data.RecipeRevisions {
+		revision := &data.RecipeRevisions[i]
+		revision.MarkdownContent = rewrite(revision.MarkdownContent)
+		revision.FeaturedImagePath = rewritePtr(revision.FeaturedImagePath)
+	}
+	for i := range data.Variations {
+		data.Variations[i].MarkdownContent = rewrite(data.Variations[i].MarkdownContent)
+	}
+	for _, cookLog := range data.CookLogs {
+		for i, photo := range cookLog.Photos {
+			cookLog.Photos[i] = rewrite(photo)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBackupService(t *testing.T, db *sql.DB, q *sqlc.Queries) (*BackupService, string) {
+	uploads := t.TempDir()
+	service := NewBackupService(
+		repository.NewBackupRepository(db, q),
+		newTestRecipeService(db, q),
+		NewStorageService(uploads, 10*1024*1024),
+	)
+	return service, uploads
+}
+
+func importBackup(service *BackupService, content []byte) (*models.BackupImportResult, error) {
+	return service.Import(bytes.NewReader(content), int64(len(content)))
+}
+
+func TestBackupService_ExportImport(t *testing.T) {
+	sourceDB, sourceQ, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(sourceDB)
+
+	source, sourceUploads := newTestBackupService(t, sourceDB, sourceQ)
+	recipeService := newTestRecipeService(sourceDB, sourceQ)
+	categoryService := NewCategoryService(repository.NewCategoryRepository(sourceDB, sourceQ), repository.NewRecipeRepository(sourceDB, sourceQ))
+
+	authorID := createTestUser(sourceDB, sourceQ, "cook@example.com")
+	createTestUser(sourceDB, sourceQ, "guest@example.com")
+	soups, err := categoryService.CreateCategory(&models.Category{Name: "Soups"})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(sourceUploads, "recipe_photo.png"), testPNG(t), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceUploads, "recipe_step.png"), []byte("step photo"), 0644))
+	featured := "/uploads/recipe_photo.png"
+	categoryID := soups.ID.String()
+	recipe, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:             "Tomato Soup",
+		MarkdownContent:   "## Ingredients\n\n- 4 tomatoes\n\n## Instructions\n\n1. Simmer.\n\n![](/uploads/recipe_step.png)",
+		CategoryID:        &categoryID,
+		FeaturedImagePath: &featured,
+		IsPublished:       true,
+		Tags:              []string{"Quick"},
+	}, authorID)
+	require.NoError(t, err)
+
+	_, err = repository.NewRecipeRevisionRepository(sourceDB, sourceQ).Create(&models.RecipeRevision{
+		RecipeID:        recipe.ID,
+		Title:           "Tomato Soup with Basil",
+		MarkdownContent: recipe.MarkdownContent,
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(sourceUploads, "recipe_gallery.png"), []byte("gallery photo"), 0644))
+	_, err = sourceDB.Exec(`INSERT INTO recipe_images (id, recipe_id, file_path, order_index) VALUES (?, ?, ?, 0)`, uuid.New().String(), recipe.ID.String(), "/uploads/recipe_gallery.png")
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(sourceUploads, "cooklog_photo.png"), []byte("cook photo"), 0644))
+	rating := 4
+	_, err = repository.NewCookLogRepository(sourceDB, sourceQ).Create(&models.CookLog{
+		RecipeID: recipe.ID,
+		UserID:   uuid.MustParse(authorID),
+		CookedOn: "2024-03-01",
+		Rating:   &rating,
+		Photos:   []string{"/uploads/cooklog_photo.png"},
+	})
+	require.NoError(t, err)
+
+	collections := newTestCollectionService(sourceDB, sourceQ)
+	collection, err := collections.CreateCollection(&models.CreateCollectionRequest{Name: "Winter"}, authorID)
+	require.NoError(t, err)
+	require.NoError(t, collections.AddRecipe(collection.ID.String(), recipe.ID.String(), authorID))
+	require.NoError(t, collections.AddFavorite(recipe.ID.String(), authorID))
+
+	comments := newTestCommentService(sourceDB, sourceQ)
+	question, err := comments.CreateRecipeComment(recipe.ID.String(), &models.CreateCommentRequest{Body: "Fresh or canned?"}, authorID)
+	require.NoError(t, err)
+	questionID := question.ID.String()
+	answer, err := comments.CreateRecipeComment(recipe.ID.String(), &models.CreateCommentRequest{Body: "Either.", ParentID: &questionID}, authorID)
+	require.NoError(t, err)
+	answerID := answer.ID.String()
+	thanks, err := comments.CreateRecipeComment(recipe.ID.String(), &models.CreateCommentRequest{Body: "Thanks!", ParentID: &answerID}, authorID)
+	require.NoError(t, err)
+	// A reply that sorts before the comments it answers is still restored
+	// after them.
+	_, err = sourceDB.Exec(`UPDATE comments SET created_at = '2020-01-01 00:00:00' WHERE id = ?`, thanks.ID.String())
+	require.NoError(t, err)
+
+	_, err = sourceDB.Exec(`INSERT INTO recipe_groups (id, name, slug) VALUES (?, ?, ?)`, uuid.New().String(), "Weeknights", "weeknights")
+	require.NoError(t, err)
+	_, err = sourceDB.Exec(`INSERT INTO recipe_groupings (group_id, recipe_id, order_index) SELECT id, ?, 3 FROM recipe_groups`, recipe.ID.String())
+	require.NoError(t, err)
+	_, err = sourceDB.Exec(`INSERT INTO share_codes (id, recipe_id, code, use_count) VALUES (?, ?, ?, 0)`, uuid.New().String(), recipe.ID.String(), "SOUP1234")
+	require.NoError(t, err)
+
+	export, err := source.Export(false)
+	require.NoError(t, err)
+	assert.Contains(t, export.Filename, "homecooking-backup-")
+	var archive bytes.Buffer
+	require.NoError(t, export.WriteArchive(&archive))
+
+	reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	require.NoError(t, err)
+	remaining := int64(maxBackupSize)
+	entries, uploads, err := readBackup(reader, &remaining)
+	require.NoError(t, err)
+	var uploadNames []string
+	for _, file := range uploads {
+		uploadNames = append(uploadNames, file.Name)
+	}
+	var manifest models.BackupManifest
+	require.NoError(t, json.Unmarshal(entries["manifest.json"], &manifest))
+	assert.Equal(t, models.BackupVersion, manifest.Version)
+	assert.False(t, manifest.PasswordHashes)
+	assert.Equal(t, 2, manifest.Counts["users"])
+	assert.Equal(t, 1, manifest.Counts["recipes"])
+	assert.Equal(t, 1, manifest.Counts["recipe_images"])
+	assert.Equal(t, 2, manifest.Counts["recipe_revisions"])
+	assert.Equal(t, 1, manifest.Counts["cook_logs"])
+	assert.Equal(t, 1, manifest.Counts["collections"])
+	assert.Equal(t, 1, manifest.Counts["favorites"])
+	assert.Equal(t, 3, manifest.Counts["comments"])
+	assert.NotContains(t, string(entries["users.json"]), "password_hash")
+	assert.Contains(t, uploadNames, "files/recipe_photo.png")
+	assert.Contains(t, uploadNames, "files/recipe_step.png")
+	assert.Contains(t, uploadNames, "files/recipe_gallery.png")
+	assert.Contains(t, uploadNames, "files/cooklog_photo.png")
+
+	// The target already has the cook, a recipe under the same slug, the
+	// tag under another slug and a different file under one of the upload
+	// names.
+	targetDB, targetQ, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(targetDB)
+
+	target, targetUploads := newTestBackupService(t, targetDB, targetQ)
+	targetRecipes := newTestRecipeService(targetDB, targetQ)
+	existingCook := createTestUser(targetDB, targetQ, "cook@example.com")
+	_, err = targetRecipes.CreateRecipe(&models.CreateRecipeRequest{Title: "Tomato Soup", MarkdownContent: "Other soup."}, existingCook)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(targetUploads, "recipe_step.png"), []byte("another photo"), 0644))
+	speedy, err := repository.NewTagRepository(targetDB, targetQ).Create(&models.Tag{Name: "Quick", Slug: "speedy", Color: "#f97316"})
+	require.NoError(t, err)
+
+	result, err := importBackup(target, archive.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Matched["users"])
+	assert.Equal(t, 1, result.Created["users"])
+	assert.Equal(t, []string{"guest@example.com"}, result.UsersWithoutPassword)
+	assert.Equal(t, 1, result.Created["categories"])
+	assert.Equal(t, 1, result.Matched["tags"])
+	assert.Equal(t, 1, result.Remapped["recipes"])
+	assert.Equal(t, 1, result.Created["groups"])
+	assert.Equal(t, 1, result.Created["share_codes"])
+	assert.Equal(t, 1, result.Created["recipe_images"])
+	assert.Equal(t, 2, result.Created["recipe_revisions"])
+	assert.Equal(t, 1, result.Created["cook_logs"])
+	assert.Equal(t, 1, result.Created["collections"])
+	assert.Equal(t, 1, result.Created["favorites"])
+	assert.Equal(t, 3, result.Created["comments"])
+	assert.Equal(t, 4, result.Files)
+
+	restored, err := targetRecipes.GetRecipeBySlug("tomato-soup-2")
+	require.NoError(t, err)
+	assert.Equal(t, recipe.ID, restored.ID)
+	assert.Equal(t, existingCook, restored.AuthorID.String())
+	assert.Equal(t, soups.ID, *restored.CategoryID)
+	assert.Equal(t, featured, *restored.FeaturedImagePath)
+	assert.NotContains(t, restored.MarkdownContent, "/uploads/recipe_step.png")
+	assert.Len(t, restored.Ingredients, 1)
+
+	revisions, err := repository.NewRecipeRevisionRepository(targetDB, targetQ).ListByRecipe(restored.ID.String())
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, "Tomato Soup", revisions[len(revisions)-1].Title)
+
+	var galleryPath string
+	require.NoError(t, targetDB.QueryRow(`SELECT file_path FROM recipe_images WHERE recipe_id = ?`, restored.ID.String()).Scan(&galleryPath))
+	assert.Equal(t, "/uploads/recipe_gallery.png", galleryPath)
+
+	cookLogs, err := repository.NewCookLogRepository(targetDB, targetQ).ListByRecipe(restored.ID.String(), 10, "")
+	require.NoError(t, err)
+	require.Len(t, cookLogs.Items, 1)
+	assert.Equal(t, existingCook, cookLogs.Items[0].UserID.String())
+	assert.Equal(t, "2024-03-01", cookLogs.Items[0].CookedOn)
+	assert.Equal(t, []string{"/uploads/cooklog_photo.png"}, cookLogs.Items[0].Photos)
+
+	targetCollections := newTestCollectionService(targetDB, targetQ)
+	winter, err := targetCollections.GetCollection(collection.ID.String(), existingCook)
+	require.NoError(t, err)
+	assert.Equal(t, existingCook, winter.OwnerID.String())
+	assert.Equal(t, []string{"Tomato Soup"}, recipeTitles(winter.Recipes))
+	favorites, err := targetCollections.ListFavorites(existingCook, 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Tomato Soup"}, recipeTitles(favorites.Items))
+
+	tags, err := repository.NewTagRepository(targetDB, targetQ).GetRecipeTags(restored.ID.String())
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, speedy.ID, tags[0].ID)
+
+	step, err := os.ReadFile(filepath.Join(targetUploads, "recipe_step.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "another photo", string(step))
+
+	// Importing the same archive again keeps the records apart by giving
+	// the recipe a new ID and skipping the share code that is in use. Only
+	// the photo whose name was taken is stored again.
+	result, err = importBackup(target, archive.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Matched["users"])
+	assert.Equal(t, 1, result.Matched["categories"])
+	assert.Equal(t, 1, result.Matched["groups"])
+	assert.Equal(t, 1, result.Remapped["recipes"])
+	assert.Equal(t, 1, result.Skipped["share_codes"])
+	assert.Equal(t, 1, result.Remapped["recipe_images"])
+	assert.Equal(t, 1, result.Remapped["cook_logs"])
+	assert.Equal(t, 1, result.Remapped["collections"])
+	assert.Equal(t, 1, result.Created["favorites"])
+	assert.Equal(t, 3, result.Remapped["comments"])
+	assert.Equal(t, 1, result.Files)
+
+	again, err := targetRecipes.GetRecipeBySlug("tomato-soup-3")
+	require.NoError(t, err)
+	assert.NotEqual(t, recipe.ID, again.ID)
+
+	threads, err := newTestCommentService(targetDB, targetQ).ListRecipeComments(again.ID.String(), 10, "")
+	require.NoError(t, err)
+	require.Len(t, threads.Items, 1)
+	assert.NotEqual(t, question.ID, threads.Items[0].ID)
+	require.Len(t, threads.Items[0].Replies, 1)
+	assert.Equal(t, "Either.", threads.Items[0].Replies[0].Body)
+	require.Len(t, threads.Items[0].Replies[0].Replies, 1)
+	assert.Equal(t, "Thanks!", threads.Items[0].Replies[0].Replies[0].Body)
+}
+
+func TestBackupService_ImportRejectsNewerVersions(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service, _ := newTestBackupService(t, db, q)
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	require.NoError(t, writeZipJSON(archive, "manifest.json", models.BackupManifest{Version: models.BackupVersion + 1}))
+	require.NoError(t, archive.Close())
+
+	_, err = importBackup(service, buf.Bytes())
+	assert.EqualError(t, err, "unsupported backup version")
+
+	_, err = importBackup(service, []byte("not a zip"))
+	assert.ErrorContains(t, err, "invalid backup")
+}
+
+func TestBackupService_ImportRejectsNonImageUploads(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service, uploads := newTestBackupService(t, db, q)
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	require.NoError(t, writeZipJSON(archive, "manifest.json", models.BackupManifest{Version: models.BackupVersion}))
+	w, err := archive.Create("files/recipe_photo.png")
+	require.NoError(t, err)
+	_, err = w.Write(testPNG(t))
+	require.NoError(t, err)
+	w, err = archive.Create("files/page.html")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("<script>alert(1)</script>"))
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+
+	_, err = importBackup(service, buf.Bytes())
+	assert.ErrorContains(t, err, "invalid file type")
+
+	files, err := os.ReadDir(uploads)
+	require.NoError(t, err)
+	assert.Empty(t, files, "nothing is left behind in the upload directory")
+}
@@ -2,19 +2,21 @@ package services
 
 import (
 	"errors"
-	"strings"
 
+	"github.com/google/uuid"
 	"github.com/homecooking/backend/internal/models"
 	"github.com/homecooking/backend/internal/repository"
 )
 
 type CategoryService struct {
 	categoryRepo *repository.CategoryRepository
+	recipeRepo   *repository.RecipeRepository
 }
 
-func NewCategoryService(categoryRepo *repository.CategoryRepository) *CategoryService {
+func NewCategoryService(categoryRepo *repository.CategoryRepository, recipeRepo *repository.RecipeRepository) *CategoryService {
 	return &CategoryService{
 		categoryRepo: categoryRepo,
+		recipeRepo:   recipeRepo,
 	}
 }
 
@@ -22,9 +24,11 @@ func (s *CategoryService) CreateCategory(category *models.Category) (*models.Cat
 	if category.Name == "" {
 		return nil, errors.New("name is required")
 	}
-	if category.Slug == "" {
-		category.Slug = generateCategorySlug(category.Name)
+	slug, err := uniqueSlug(slugSource(category.Slug, category.Name), "category", slugOwner("", s.categoryIDBySlug))
+	if err != nil {
+		return nil, err
 	}
+	category.Slug = slug
 
 	return s.categoryRepo.Create(category)
 }
@@ -41,25 +45,49 @@ func (s *CategoryService) ListCategories() ([]*models.Category, error) {
 	return s.categoryRepo.List()
 }
 
+// UpdateCategory renames the category and updates the search entries of the
+// recipes filed under it.
 func (s *CategoryService) UpdateCategory(id string, category *models.Category) (*models.Category, error) {
 	if category.Name == "" {
 		return nil, errors.New("name is required")
 	}
-	if category.Slug == "" && category.Name != "" {
-		category.Slug = generateCategorySlug(category.Name)
+	slug, err := uniqueSlug(slugSource(category.Slug, category.Name), "category", slugOwner(id, s.categoryIDBySlug))
+	if err != nil {
+		return nil, err
 	}
+	category.Slug = slug
 
-	return s.categoryRepo.Update(id, category)
+	updated, err := s.categoryRepo.Update(id, category)
+	if err != nil {
+		return nil, err
+	}
+	recipeIDs, err := s.recipeRepo.IDsInCategory(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := reindexRecipes(s.recipeRepo, recipeIDs); err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
+// DeleteCategory deletes the category and takes it out of the search
+// entries of the recipes filed under it.
 func (s *CategoryService) DeleteCategory(id string) error {
-	return s.categoryRepo.Delete(id)
+	recipeIDs, err := s.recipeRepo.IDsInCategory(id)
+	if err != nil {
+		return err
+	}
+	if err := s.categoryRepo.Delete(id); err != nil {
+		return err
+	}
+	return reindexRecipes(s.recipeRepo, recipeIDs)
 }
 
-func generateCategorySlug(title string) string {
-	slug := strings.ToLower(title)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	slug = strings.ReplaceAll(slug, "'", "")
-	slug = strings.ReplaceAll(slug, "\"", "")
-	return slug
+func (s *CategoryService) categoryIDBySlug(slug string) (uuid.UUID, error) {
+	category, err := s.categoryRepo.GetBySlug(slug)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return category.ID, nil
 }
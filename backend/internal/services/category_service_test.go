@@ -24,7 +24,7 @@ func TestCreateCategory(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	category := testutil.TestCategory1
 
@@ -43,7 +43,7 @@ func TestCreateCategory_EmptyName(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	category := &models.Category{
 		Name: "",
@@ -60,7 +60,7 @@ func TestCreateCategory_AutoSlug(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	category := &models.Category{
 		Name: "Lunch and Dinner",
@@ -79,7 +79,7 @@ func TestGetCategory(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	category := &models.Category{
 		Name: "Breakfast",
@@ -102,7 +102,7 @@ func TestGetCategory_NotFound(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	_, err = service.GetCategory("00000000-0000-0000-0000-000000000000")
 	assert.Error(t, err)
@@ -114,7 +114,7 @@ func TestGetCategoryBySlug(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	category := testutil.TestCategory1
 	created, err := categoryRepo.Create(category)
@@ -132,7 +132,7 @@ func TestListCategories(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	category1 := testutil.TestCategory1
 	category2 := testutil.TestCategory2
@@ -156,7 +156,7 @@ func TestListCategories_Empty(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	categories, err := service.ListCategories()
 	require.NoError(t, err)
@@ -171,7 +171,7 @@ func TestUpdateCategory(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	category := &models.Category{
 		Name: "Breakfast",
@@ -201,7 +201,7 @@ func TestUpdateCategory_EmptyName(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	category := &models.Category{
 		Name: "Breakfast",
@@ -227,7 +227,7 @@ func TestUpdateCategory_AutoSlug(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	category := &models.Category{
 		Name: "Breakfast",
@@ -251,7 +251,7 @@ func TestUpdateCategory_NotFound(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	updatedCategory := &models.Category{
 		Name: "Updated Name",
@@ -267,7 +267,7 @@ func TestDeleteCategory(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	category := &models.Category{
 		Name: "Breakfast",
@@ -289,7 +289,7 @@ func TestDeleteCategory_NotFound(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	categoryRepo := repository.NewCategoryRepository(db, q)
-	service := NewCategoryService(categoryRepo)
+	service := NewCategoryService(categoryRepo, repository.NewRecipeRepository(db, q))
 
 	err = service.DeleteCategory("00000000-0000-0000-0000-000000000000")
 	assert.NoError(t, err)
@@ -310,7 +310,7 @@ func TestGenerateCategorySlug(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			slug := generateCategorySlug(tt.input)
+			slug := generateSlug(tt.input)
 			assert.Equal(t, tt.expected, slug)
 		})
 	}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+)
+
+// CollectionService manages the favorites and collections of individual
+// users. Only the owner changes a collection; shared ones can be viewed by
+// everyone signed in.
+type CollectionService struct {
+	collectionRepo *repository.CollectionRepository
+	recipeRepo     *repository.RecipeRepository
+}
+
+func NewCollectionService(collectionRepo *repository.CollectionRepository, recipeRepo *repository.RecipeRepository) *CollectionService {
+	return &CollectionService{
+		collectionRepo: collectionRepo,
+		recipeRepo:     recipeRepo,
+	}
+}
+
+func (s *CollectionService) CreateCollection(req *models.CreateCollectionRequest, userID string) (*models.Collection, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = models.CollectionPrivate
+	}
+	if !validVisibility(visibility) {
+		return nil, errors.New("visibility must be private or shared")
+	}
+
+	return s.collectionRepo.Create(&models.Collection{
+		OwnerID:     uuid.MustParse(userID),
+		Name:        name,
+		Description: req.Description,
+		Visibility:  visibility,
+	})
+}
+
+// GetCollection returns a collection with its recipes in order. Another
+// user's private collection is reported as not found.
+func (s *CollectionService) GetCollection(id string, userID string) (*models.CollectionWithRecipes, error) {
+	collection, err := s.visibleCollection(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	recipes, err := s.collectionRepo.GetRecipes(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CollectionWithRecipes{
+		Collection: *collection,
+		Recipes:    recipes,
+	}, nil
+}
+
+// ListCollections pages through the user's own collections and everyone's
+// shared ones, newest first.
+func (s *CollectionService) ListCollections(userID string, limit int, cursor string) (*models.Page[*models.Collection], error) {
+	return s.collectionRepo.ListVisible(userID, pageSize(limit), cursor)
+}
+
+func (s *CollectionService) UpdateCollection(id string, req *models.UpdateCollectionRequest, userID string) (*models.Collection, error) {
+	if _, err := s.ownCollection(id, userID); err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("name is required")
+		}
+		req.Name = &name
+	}
+	if req.Visibility != nil && !validVisibility(*req.Visibility) {
+		return nil, errors.New("visibility must be private or shared")
+	}
+	return s.collectionRepo.Update(id, req)
+}
+
+func (s *CollectionService) DeleteCollection(id string, userID string) error {
+	if _, err := s.ownCollection(id, userID); err != nil {
+		return err
+	}
+	return s.collectionRepo.Delete(id)
+}
+
+// AddRecipe appends a recipe the user can see to the end of their
+// collection.
+func (s *CollectionService) AddRecipe(collectionID, recipeID string, userID string) error {
+	if _, err := s.ownCollection(collectionID, userID); err != nil {
+		return err
+	}
+	if err := s.checkRecipe(recipeID, userID); err != nil {
+		return err
+	}
+	return s.collectionRepo.AddRecipe(collectionID, recipeID)
+}
+
+func (s *CollectionService) RemoveRecipe(collectionID, recipeID string, userID string) error {
+	if _, err := s.ownCollection(collectionID, userID); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(recipeID); err != nil {
+		return errors.New("recipe not found")
+	}
+	return s.collectionRepo.RemoveRecipe(collectionID, recipeID)
+}
+
+// ReorderCollection puts the recipes of the collection that the user can
+// see in the order of req.RecipeIDs, which must name each of them exactly
+// once. Recipes hidden from the user, such as drafts and trashed recipes,
+// keep their relative order after the visible ones.
+func (s *CollectionService) ReorderCollection(id string, req *models.ReorderCollectionRequest, userID string) (*models.CollectionWithRecipes, error) {
+	if _, err := s.ownCollection(id, userID); err != nil {
+		return nil, err
+	}
+	visible, err := s.collectionRepo.GetRecipes(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.collectionRepo.RecipeIDs(id)
+	if err != nil {
+		return nil, err
+	}
+
+	inCollection := make(map[uuid.UUID]bool, len(visible))
+	for _, recipe := range visible {
+		inCollection[recipe.ID] = true
+	}
+	order := make([]uuid.UUID, 0, len(current))
+	for _, value := range req.RecipeIDs {
+		recipeID, err := uuid.Parse(value)
+		if err != nil || !inCollection[recipeID] {
+			return nil, errors.New("recipe_ids must list every recipe in the collection exactly once")
+		}
+		delete(inCollection, recipeID)
+		order = append(order, recipeID)
+	}
+	if len(inCollection) > 0 {
+		return nil, errors.New("recipe_ids must list every recipe in the collection exactly once")
+	}
+
+	listed := make(map[uuid.UUID]bool, len(order))
+	for _, recipeID := range order {
+		listed[recipeID] = true
+	}
+	for _, recipeID := range current {
+		if !listed[recipeID] {
+			order = append(order, recipeID)
+		}
+	}
+
+	if err := s.collectionRepo.Reorder(id, order); err != nil {
+		return nil, err
+	}
+	return s.GetCollection(id, userID)
+}
+
+// AddFavorite marks a recipe the user can see as one of their favorites.
+// Marking it again changes nothing.
+func (s *CollectionService) AddFavorite(recipeID string, userID string) error {
+	if err := s.checkRecipe(recipeID, userID); err != nil {
+		return err
+	}
+	return s.collectionRepo.AddFavorite(userID, recipeID)
+}
+
+func (s *CollectionService) RemoveFavorite(recipeID string, userID string) error {
+	if _, err := uuid.Parse(recipeID); err != nil {
+		return errors.New("recipe not found")
+	}
+	return s.collectionRepo.RemoveFavorite(userID, recipeID)
+}
+
+// ListFavorites pages through the user's favorite recipes, most recently
+// added first. Recipes unpublished since are left out.
+func (s *CollectionService) ListFavorites(userID string, limit int, cursor string) (*models.Page[*models.Recipe], error) {
+	return s.collectionRepo.ListFavorites(userID, pageSize(limit), cursor)
+}
+
+func (s *CollectionService) visibleCollection(id string, userID string) (*models.Collection, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("collection not found")
+	}
+	collection, err := s.collectionRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("collection not found")
+	}
+	if collection.Visibility != models.CollectionShared && collection.OwnerID.String() != userID {
+		return nil, errors.New("collection not found")
+	}
+	return collection, nil
+}
+
+func (s *CollectionService) ownCollection(id string, userID string) (*models.Collection, error) {
+	collection, err := s.visibleCollection(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if collection.OwnerID.String() != userID {
+		return nil, errors.New("unauthorized: you can only edit your own collections")
+	}
+	return collection, nil
+}
+
+// checkRecipe reports a recipe as not found unless it is published or
+// written by userID.
+func (s *CollectionService) checkRecipe(recipeID string, userID string) error {
+	if _, err := uuid.Parse(recipeID); err != nil {
+		return errors.New("recipe not found")
+	}
+	recipe, err := s.recipeRepo.GetByID(recipeID)
+	if err != nil {
+		return errors.New("recipe not found")
+	}
+	if !recipe.IsPublished && (recipe.AuthorID == nil || recipe.AuthorID.String() != userID) {
+		return errors.New("recipe not found")
+	}
+	return nil
+}
+
+func validVisibility(visibility string) bool {
+	return visibility == models.CollectionPrivate || visibility == models.CollectionShared
+}
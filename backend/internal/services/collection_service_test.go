@@ -0,0 +1,227 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCollectionService(db *sql.DB, q *sqlc.Queries) *CollectionService {
+	return NewCollectionService(
+		repository.NewCollectionRepository(db, q),
+		repository.NewRecipeRepository(db, q),
+	)
+}
+
+func createPublishedRecipe(t *testing.T, recipeService *RecipeService, title, authorID string) *models.Recipe {
+	recipe, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           title,
+		MarkdownContent: "## Ingredients\n\n- 1 egg",
+		IsPublished:     true,
+	}, authorID)
+	require.NoError(t, err)
+	return recipe
+}
+
+func TestCollectionService_Favorites(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestCollectionService(db, q)
+	recipeService := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "author@example.com")
+	userID := createTestUser(db, q, "user@example.com")
+
+	soup := createPublishedRecipe(t, recipeService, "Soup", authorID)
+	stew := createPublishedRecipe(t, recipeService, "Stew", authorID)
+	draft, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Secret Draft",
+		MarkdownContent: "Not yet",
+	}, authorID)
+	require.NoError(t, err)
+
+	require.NoError(t, service.AddFavorite(soup.ID.String(), userID))
+	require.NoError(t, service.AddFavorite(stew.ID.String(), userID))
+	require.NoError(t, service.AddFavorite(soup.ID.String(), userID), "adding a favorite twice is a no-op")
+
+	err = service.AddFavorite(draft.ID.String(), userID)
+	assert.EqualError(t, err, "recipe not found", "another user's draft can't be favorited")
+	err = service.AddFavorite("not-a-uuid", userID)
+	assert.EqualError(t, err, "recipe not found")
+
+	page, err := service.ListFavorites(userID, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.Total)
+	require.Len(t, page.Items, 1)
+	require.NotNil(t, page.NextCursor)
+
+	next, err := service.ListFavorites(userID, 1, *page.NextCursor)
+	require.NoError(t, err)
+	require.Len(t, next.Items, 1)
+	assert.Nil(t, next.NextCursor)
+	assert.ElementsMatch(t, []string{"Soup", "Stew"}, []string{page.Items[0].Title, next.Items[0].Title})
+
+	others, err := service.ListFavorites(authorID, 20, "")
+	require.NoError(t, err)
+	assert.Empty(t, others.Items, "favorites are per user")
+
+	require.NoError(t, service.RemoveFavorite(soup.ID.String(), userID))
+	page, err = service.ListFavorites(userID, 20, "")
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "Stew", page.Items[0].Title)
+}
+
+func TestCollectionService_Visibility(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestCollectionService(db, q)
+	ownerID := createTestUser(db, q, "owner@example.com")
+	otherID := createTestUser(db, q, "other@example.com")
+
+	_, err = service.CreateCollection(&models.CreateCollectionRequest{Name: "  "}, ownerID)
+	assert.EqualError(t, err, "name is required")
+	_, err = service.CreateCollection(&models.CreateCollectionRequest{Name: "Mine", Visibility: "public"}, ownerID)
+	assert.EqualError(t, err, "visibility must be private or shared")
+
+	private, err := service.CreateCollection(&models.CreateCollectionRequest{Name: "Weeknight dinners"}, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CollectionPrivate, private.Visibility)
+	shared, err := service.CreateCollection(&models.CreateCollectionRequest{
+		Name:       "Party food",
+		Visibility: models.CollectionShared,
+	}, ownerID)
+	require.NoError(t, err)
+
+	_, err = service.GetCollection(private.ID.String(), otherID)
+	assert.EqualError(t, err, "collection not found")
+	_, err = service.GetCollection(shared.ID.String(), otherID)
+	assert.NoError(t, err)
+
+	ownPage, err := service.ListCollections(ownerID, 20, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, ownPage.Total)
+	otherPage, err := service.ListCollections(otherID, 20, "")
+	require.NoError(t, err)
+	require.Len(t, otherPage.Items, 1)
+	assert.Equal(t, "Party food", otherPage.Items[0].Name)
+
+	newName := "Renamed"
+	_, err = service.UpdateCollection(shared.ID.String(), &models.UpdateCollectionRequest{Name: &newName}, otherID)
+	assert.EqualError(t, err, "unauthorized: you can only edit your own collections")
+	err = service.DeleteCollection(private.ID.String(), otherID)
+	assert.EqualError(t, err, "collection not found")
+
+	visibility := models.CollectionShared
+	updated, err := service.UpdateCollection(private.ID.String(), &models.UpdateCollectionRequest{
+		Name:       &newName,
+		Visibility: &visibility,
+	}, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.Name)
+	assert.Equal(t, models.CollectionShared, updated.Visibility)
+
+	require.NoError(t, service.DeleteCollection(shared.ID.String(), ownerID))
+	_, err = service.GetCollection(shared.ID.String(), ownerID)
+	assert.EqualError(t, err, "collection not found")
+}
+
+func TestCollectionService_RecipesAndOrder(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestCollectionService(db, q)
+	recipeService := newTestRecipeService(db, q)
+	ownerID := createTestUser(db, q, "owner@example.com")
+	otherID := createTestUser(db, q, "other@example.com")
+
+	soup := createPublishedRecipe(t, recipeService, "Soup", otherID)
+	stew := createPublishedRecipe(t, recipeService, "Stew", otherID)
+	ownDraft, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "My Draft",
+		MarkdownContent: "Not yet",
+	}, ownerID)
+	require.NoError(t, err)
+
+	collection, err := service.CreateCollection(&models.CreateCollectionRequest{
+		Name:       "Favourites for guests",
+		Visibility: models.CollectionShared,
+	}, ownerID)
+	require.NoError(t, err)
+	id := collection.ID.String()
+
+	for _, recipe := range []*models.Recipe{soup, stew, ownDraft} {
+		require.NoError(t, service.AddRecipe(id, recipe.ID.String(), ownerID))
+	}
+	require.NoError(t, service.AddRecipe(id, soup.ID.String(), ownerID), "adding a recipe twice keeps its place")
+	err = service.AddRecipe(id, soup.ID.String(), otherID)
+	assert.EqualError(t, err, "unauthorized: you can only edit your own collections")
+
+	loaded, err := service.GetCollection(id, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Soup", "Stew", "My Draft"}, recipeTitles(loaded.Recipes))
+
+	viewed, err := service.GetCollection(id, otherID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Soup", "Stew"}, recipeTitles(viewed.Recipes), "drafts stay hidden from other viewers")
+
+	_, err = service.ReorderCollection(id, &models.ReorderCollectionRequest{
+		RecipeIDs: []string{stew.ID.String(), soup.ID.String()},
+	}, ownerID)
+	assert.EqualError(t, err, "recipe_ids must list every recipe in the collection exactly once")
+	_, err = service.ReorderCollection(id, &models.ReorderCollectionRequest{
+		RecipeIDs: []string{stew.ID.String(), stew.ID.String(), ownDraft.ID.String()},
+	}, ownerID)
+	assert.EqualError(t, err, "recipe_ids must list every recipe in the collection exactly once")
+
+	reordered, err := service.ReorderCollection(id, &models.ReorderCollectionRequest{
+		RecipeIDs: []string{ownDraft.ID.String(), stew.ID.String(), soup.ID.String()},
+	}, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"My Draft", "Stew", "Soup"}, recipeTitles(reordered.Recipes))
+
+	require.NoError(t, service.RemoveRecipe(id, stew.ID.String(), ownerID))
+	loaded, err = service.GetCollection(id, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"My Draft", "Soup"}, recipeTitles(loaded.Recipes))
+
+	require.NoError(t, service.AddRecipe(id, stew.ID.String(), ownerID))
+	loaded, err = service.GetCollection(id, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"My Draft", "Soup", "Stew"}, recipeTitles(loaded.Recipes), "new recipes go to the end")
+
+	require.NoError(t, recipeService.DeleteRecipe(soup.ID.String(), otherID))
+	_, err = service.ReorderCollection(id, &models.ReorderCollectionRequest{
+		RecipeIDs: []string{soup.ID.String(), stew.ID.String(), ownDraft.ID.String()},
+	}, ownerID)
+	assert.EqualError(t, err, "recipe_ids must list every recipe in the collection exactly once", "hidden recipes can't be listed")
+	reordered, err = service.ReorderCollection(id, &models.ReorderCollectionRequest{
+		RecipeIDs: []string{stew.ID.String(), ownDraft.ID.String()},
+	}, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Stew", "My Draft"}, recipeTitles(reordered.Recipes))
+
+	collectionRepo := repository.NewCollectionRepository(db, q)
+	recipeIDs, err := collectionRepo.RecipeIDs(id)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{stew.ID, ownDraft.ID, soup.ID}, recipeIDs, "hidden recipes keep their place after the visible ones")
+}
+
+func recipeTitles(recipes []*models.Recipe) []string {
+	titles := make([]string, len(recipes))
+	for i, recipe := range recipes {
+		titles[i] = recipe.Title
+	}
+	return titles
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+)
+
+type CommentService struct {
+	commentRepo   *repository.CommentRepository
+	recipeRepo    *repository.RecipeRepository
+	variationRepo *repository.VariationRepository
+}
+
+func NewCommentService(commentRepo *repository.CommentRepository, recipeRepo *repository.RecipeRepository, variationRepo *repository.VariationRepository) *CommentService {
+	return &CommentService{
+		commentRepo:   commentRepo,
+		recipeRepo:    recipeRepo,
+		variationRepo: variationRepo,
+	}
+}
+
+// CreateRecipeComment adds a comment, or a reply when req.ParentID is set,
+// to a recipe.
+func (s *CommentService) CreateRecipeComment(recipeID string, req *models.CreateCommentRequest, userID string) (*models.Comment, error) {
+	if err := s.checkRecipe(recipeID); err != nil {
+		return nil, err
+	}
+	return s.createComment(uuid.MustParse(recipeID), nil, req, userID)
+}
+
+// CreateVariationComment adds a comment, or a reply when req.ParentID is
+// set, to one of a recipe's variations.
+func (s *CommentService) CreateVariationComment(recipeID, variationID string, req *models.CreateCommentRequest, userID string) (*models.Comment, error) {
+	if err := s.checkVariation(recipeID, variationID); err != nil {
+		return nil, err
+	}
+	id := uuid.MustParse(variationID)
+	return s.createComment(uuid.MustParse(recipeID), &id, req, userID)
+}
+
+func (s *CommentService) GetComment(id string) (*models.Comment, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("comment not found")
+	}
+	comment, err := s.commentRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("comment not found")
+	}
+	return comment, nil
+}
+
+// UpdateComment changes the text of one of the user's own comments.
+func (s *CommentService) UpdateComment(id string, req *models.UpdateCommentRequest, userID string) (*models.Comment, error) {
+	comment, err := s.GetComment(id)
+	if err != nil || comment.IsDeleted {
+		return nil, errors.New("comment not found")
+	}
+	if comment.AuthorID == nil || comment.AuthorID.String() != userID {
+		return nil, errors.New("unauthorized: you can only edit your own comments")
+	}
+	body, err := commentBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	return s.commentRepo.UpdateBody(id, body)
+}
+
+// DeleteComment removes a comment written by userID, or any comment when
+// moderator is set. A comment that has replies is blanked instead, and a
+// blanked comment goes away with its last reply.
+func (s *CommentService) DeleteComment(id string, userID string, moderator bool) error {
+	comment, err := s.GetComment(id)
+	if err != nil || comment.IsDeleted {
+		return errors.New("comment not found")
+	}
+	if !moderator && (comment.AuthorID == nil || comment.AuthorID.String() != userID) {
+		return errors.New("unauthorized: you can only delete your own comments")
+	}
+
+	for {
+		replies, err := s.commentRepo.CountReplies(comment.ID.String())
+		if err != nil {
+			return err
+		}
+		if replies > 0 {
+			if comment.IsDeleted {
+				return nil
+			}
+			return s.commentRepo.MarkDeleted(comment.ID.String())
+		}
+		if err := s.commentRepo.Delete(comment.ID.String()); err != nil {
+			return err
+		}
+		if comment.ParentID == nil {
+			return nil
+		}
+		comment, err = s.commentRepo.GetByID(comment.ParentID.String())
+		if err != nil {
+			return err
+		}
+		if !comment.IsDeleted {
+			return nil
+		}
+	}
+}
+
+// ListRecipeComments pages through the threads on a recipe, newest first.
+// Comments on its variations are listed with each variation.
+func (s *CommentService) ListRecipeComments(recipeID string, limit int, cursor string) (*models.Page[*models.Comment], error) {
+	if err := s.checkRecipe(recipeID); err != nil {
+		return nil, err
+	}
+	return s.commentRepo.ListByRecipe(recipeID, pageSize(limit), cursor)
+}
+
+// ListVariationComments pages through the threads on a variation, newest
+// first.
+func (s *CommentService) ListVariationComments(recipeID, variationID string, limit int, cursor string) (*models.Page[*models.Comment], error) {
+	if err := s.checkVariation(recipeID, variationID); err != nil {
+		return nil, err
+	}
+	return s.commentRepo.ListByVariation(variationID, pageSize(limit), cursor)
+}
+
+// ListRecentComments pages through every comment on the site, newest first,
+// for moderators.
+func (s *CommentService) ListRecentComments(limit int, cursor string) (*models.Page[*models.Comment], error) {
+	return s.commentRepo.ListRecent(pageSize(limit), cursor)
+}
+
+func (s *CommentService) createComment(recipeID uuid.UUID, variationID *uuid.UUID, req *models.CreateCommentRequest, userID string) (*models.Comment, error) {
+	body, err := commentBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	authorID := uuid.MustParse(userID)
+	comment := &models.Comment{
+		RecipeID:    recipeID,
+		VariationID: variationID,
+		AuthorID:    &authorID,
+		Body:        body,
+	}
+
+	if req.ParentID != nil && *req.ParentID != "" {
+		parent, err := s.GetComment(*req.ParentID)
+		if err != nil || parent.IsDeleted || parent.RecipeID != recipeID || !sameUUID(parent.VariationID, variationID) {
+			return nil, errors.New("parent comment not found")
+		}
+		comment.ParentID = &parent.ID
+		comment.ThreadID = parent.ThreadID
+		if comment.ThreadID == nil {
+			comment.ThreadID = &parent.ID
+		}
+	}
+
+	return s.commentRepo.Create(comment)
+}
+
+func (s *CommentService) checkRecipe(recipeID string) error {
+	if _, err := uuid.Parse(recipeID); err != nil {
+		return errors.New("recipe not found")
+	}
+	if _, err := s.recipeRepo.GetByID(recipeID); err != nil {
+		return errors.New("recipe not found")
+	}
+	return nil
+}
+
+func (s *CommentService) checkVariation(recipeID, variationID string) error {
+	if err := s.checkRecipe(recipeID); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(variationID); err != nil {
+		return errors.New("variation not found")
+	}
+	variation, err := s.variationRepo.GetByID(variationID)
+	if err != nil || variation.RecipeID.String() != recipeID {
+		return errors.New("variation not found")
+	}
+	return nil
+}
+
+// commentBody trims a comment's text and checks its length.
+func commentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.New("body is required")
+	}
+	if utf8.RuneCountInString(body) > models.MaxCommentLength {
+		return "", fmt.Errorf("body can be at most %d characters", models.MaxCommentLength)
+	}
+	return body, nil
+}
+
+func sameUUID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCommentService(db *sql.DB, q *sqlc.Queries) *CommentService {
+	return NewCommentService(
+		repository.NewCommentRepository(db, q),
+		repository.NewRecipeRepository(db, q),
+		repository.NewVariationRepository(db, q),
+	)
+}
+
+func TestCommentService_Threads(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestCommentService(db, q)
+	recipeService := newTestRecipeService(db, q)
+	bakerID := createTestUser(db, q, "baker@example.com")
+	cousinID := createTestUser(db, q, "cousin@example.com")
+
+	bread := createPublishedRecipe(t, recipeService, "Bread", bakerID)
+	cake := createPublishedRecipe(t, recipeService, "Cake", bakerID)
+
+	_, err = service.CreateRecipeComment(bread.ID.String(), &models.CreateCommentRequest{Body: "   "}, cousinID)
+	assert.EqualError(t, err, "body is required")
+	_, err = service.CreateRecipeComment(bread.ID.String(), &models.CreateCommentRequest{
+		Body: strings.Repeat("a", models.MaxCommentLength+1),
+	}, cousinID)
+	assert.EqualError(t, err, "body can be at most 5000 characters")
+	_, err = service.CreateRecipeComment("not-a-uuid", &models.CreateCommentRequest{Body: "Hi"}, cousinID)
+	assert.EqualError(t, err, "recipe not found")
+
+	question, err := service.CreateRecipeComment(bread.ID.String(), &models.CreateCommentRequest{
+		Body: "  How long did you rest the dough?  ",
+	}, cousinID)
+	require.NoError(t, err)
+	assert.Equal(t, "How long did you rest the dough?", question.Body)
+	assert.Nil(t, question.ParentID)
+
+	parentID := question.ID.String()
+	answer, err := service.CreateRecipeComment(bread.ID.String(), &models.CreateCommentRequest{
+		Body:     "Overnight in the fridge.",
+		ParentID: &parentID,
+	}, bakerID)
+	require.NoError(t, err)
+	require.NotNil(t, answer.ParentID)
+	assert.Equal(t, question.ID, *answer.ParentID)
+
+	answerID := answer.ID.String()
+	_, err = service.CreateRecipeComment(bread.ID.String(), &models.CreateCommentRequest{
+		Body:     "Thanks!",
+		ParentID: &answerID,
+	}, cousinID)
+	require.NoError(t, err)
+
+	_, err = service.CreateRecipeComment(cake.ID.String(), &models.CreateCommentRequest{
+		Body:     "Wrong thread",
+		ParentID: &parentID,
+	}, cousinID)
+	assert.EqualError(t, err, "parent comment not found", "replies stay on the parent's recipe")
+
+	page, err := service.ListRecipeComments(bread.ID.String(), 20, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Total, "only top-level comments are counted")
+	require.Len(t, page.Items, 1)
+	thread := page.Items[0]
+	require.Len(t, thread.Replies, 1)
+	assert.Equal(t, "Overnight in the fridge.", thread.Replies[0].Body)
+	require.Len(t, thread.Replies[0].Replies, 1)
+	assert.Equal(t, "Thanks!", thread.Replies[0].Replies[0].Body)
+
+	cakePage, err := service.ListRecipeComments(cake.ID.String(), 20, "")
+	require.NoError(t, err)
+	assert.Empty(t, cakePage.Items)
+}
+
+func TestCommentService_Pagination(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestCommentService(db, q)
+	recipeService := newTestRecipeService(db, q)
+	userID := createTestUser(db, q, "user@example.com")
+	recipe := createPublishedRecipe(t, recipeService, "Soup", userID)
+
+	for _, body := range []string{"First", "Second", "Third"} {
+		_, err := service.CreateRecipeComment(recipe.ID.String(), &models.CreateCommentRequest{Body: body}, userID)
+		require.NoError(t, err)
+	}
+
+	var bodies []string
+	cursor := ""
+	for {
+		page, err := service.ListRecipeComments(recipe.ID.String(), 2, cursor)
+		require.NoError(t, err)
+		assert.Equal(t, 3, page.Total)
+		for _, comment := range page.Items {
+			bodies = append(bodies, comment.Body)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		cursor = *page.NextCursor
+	}
+	assert.ElementsMatch(t, []string{"First", "Second", "Third"}, bodies)
+
+	_, err = service.ListRecipeComments(recipe.ID.String(), 2, "bogus")
+	assert.EqualError(t, err, "invalid cursor")
+}
+
+func TestCommentService_EditAndDelete(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestCommentService(db, q)
+	recipeService := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "author@example.com")
+	otherID := createTestUser(db, q, "other@example.com")
+	recipe := createPublishedRecipe(t, recipeService, "Pie", authorID)
+
+	comment, err := service.CreateRecipeComment(recipe.ID.String(), &models.CreateCommentRequest{Body: "Too sweet"}, authorID)
+	require.NoError(t, err)
+	id := comment.ID.String()
+
+	_, err = service.UpdateComment(id, &models.UpdateCommentRequest{Body: "Mine now"}, otherID)
+	assert.EqualError(t, err, "unauthorized: you can only edit your own comments")
+	_, err = service.UpdateComment(id, &models.UpdateCommentRequest{Body: ""}, authorID)
+	assert.EqualError(t, err, "body is required")
+
+	edited, err := service.UpdateComment(id, &models.UpdateCommentRequest{Body: "A bit too sweet"}, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, "A bit too sweet", edited.Body)
+	assert.NotNil(t, edited.EditedAt)
+
+	reply, err := service.CreateRecipeComment(recipe.ID.String(), &models.CreateCommentRequest{
+		Body:     "Use less sugar",
+		ParentID: &id,
+	}, otherID)
+	require.NoError(t, err)
+
+	err = service.DeleteComment(id, otherID, false)
+	assert.EqualError(t, err, "unauthorized: you can only delete your own comments")
+
+	require.NoError(t, service.DeleteComment(id, authorID, false))
+	page, err := service.ListRecipeComments(recipe.ID.String(), 20, "")
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1, "a comment with replies is kept as a placeholder")
+	assert.True(t, page.Items[0].IsDeleted)
+	assert.Empty(t, page.Items[0].Body)
+	require.Len(t, page.Items[0].Replies, 1)
+
+	_, err = service.UpdateComment(id, &models.UpdateCommentRequest{Body: "Back"}, authorID)
+	assert.EqualError(t, err, "comment not found")
+
+	// An admin removes the reply, which takes the placeholder with it.
+	require.NoError(t, service.DeleteComment(reply.ID.String(), authorID, true))
+	page, err = service.ListRecipeComments(recipe.ID.String(), 20, "")
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	_, err = service.GetComment(id)
+	assert.EqualError(t, err, "comment not found")
+}
+
+func TestCommentService_Moderation(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestCommentService(db, q)
+	recipeService := newTestRecipeService(db, q)
+	userID := createTestUser(db, q, "user@example.com")
+	soup := createPublishedRecipe(t, recipeService, "Soup", userID)
+	stew := createPublishedRecipe(t, recipeService, "Stew", userID)
+
+	_, err = service.CreateRecipeComment(soup.ID.String(), &models.CreateCommentRequest{Body: "Lovely"}, userID)
+	require.NoError(t, err)
+	spam, err := service.CreateRecipeComment(stew.ID.String(), &models.CreateCommentRequest{Body: "Buy now"}, userID)
+	require.NoError(t, err)
+
+	recent, err := service.ListRecentComments(20, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, recent.Total)
+
+	require.NoError(t, service.DeleteComment(spam.ID.String(), "", true))
+	recent, err = service.ListRecentComments(20, "")
+	require.NoError(t, err)
+	require.Len(t, recent.Items, 1)
+	assert.Equal(t, "Lovely", recent.Items[0].Body)
+
+	_, err = service.ListVariationComments(soup.ID.String(), "not-a-uuid", 20, "")
+	assert.EqualError(t, err, "variation not found")
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+)
+
+type CookLogService struct {
+	cookLogRepo   *repository.CookLogRepository
+	recipeRepo    *repository.RecipeRepository
+	variationRepo *repository.VariationRepository
+	storage       *StorageService
+}
+
+func NewCookLogService(cookLogRepo *repository.CookLogRepository, recipeRepo *repository.RecipeRepository, variationRepo *repository.VariationRepository, storage *StorageService) *CookLogService {
+	return &CookLogService{
+		cookLogRepo:   cookLogRepo,
+		recipeRepo:    recipeRepo,
+		variationRepo: variationRepo,
+		storage:       storage,
+	}
+}
+
+// CreateCookLog records that userID made the recipe, storing the uploaded
+// photos alongside. Photos already saved are removed again when the log
+// can't be stored.
+func (s *CookLogService) CreateCookLog(recipeID string, req *models.CreateCookLogRequest, photos []*multipart.FileHeader, userID string) (*models.CookLog, error) {
+	if _, err := uuid.Parse(recipeID); err != nil {
+		return nil, errors.New("recipe not found")
+	}
+	if _, err := s.recipeRepo.GetByID(recipeID); err != nil {
+		return nil, errors.New("recipe not found")
+	}
+
+	cookedOn := time.Now().Format(models.CookLogDateFormat)
+	if req.CookedOn != "" {
+		date, err := time.Parse(models.CookLogDateFormat, req.CookedOn)
+		if err != nil {
+			return nil, errors.New("cooked_on must be a date such as 2024-03-01")
+		}
+		// A day of slack lets cooks ahead of the server's time zone log
+		// today's meal.
+		if date.After(time.Now().AddDate(0, 0, 1)) {
+			return nil, errors.New("cooked_on can't be in the future")
+		}
+		cookedOn = req.CookedOn
+	}
+	if req.Rating != nil && (*req.Rating < 1 || *req.Rating > 5) {
+		return nil, errors.New("rating must be between 1 and 5")
+	}
+	if len(photos) > models.MaxCookLogPhotos {
+		return nil, fmt.Errorf("at most %d photos can be added to a cook log", models.MaxCookLogPhotos)
+	}
+
+	var variationID *uuid.UUID
+	if req.VariationID != nil && *req.VariationID != "" {
+		id, err := uuid.Parse(*req.VariationID)
+		if err != nil {
+			return nil, errors.New("variation not found")
+		}
+		variation, err := s.variationRepo.GetByID(id.String())
+		if err != nil || variation.RecipeID.String() != recipeID {
+			return nil, errors.New("variation not found")
+		}
+		variationID = &id
+	}
+
+	var notes *string
+	if req.Notes != nil && strings.TrimSpace(*req.Notes) != "" {
+		trimmed := strings.TrimSpace(*req.Notes)
+		notes = &trimmed
+	}
+
+	cookLog := &models.CookLog{
+		RecipeID:    uuid.MustParse(recipeID),
+		VariationID: variationID,
+		UserID:      uuid.MustParse(userID),
+		CookedOn:    cookedOn,
+		Rating:      req.Rating,
+		Notes:       notes,
+	}
+	for _, photo := range photos {
+		filename, err := s.storage.SaveImage(photo, "cooklog")
+		if err != nil {
+			s.deletePhotos(cookLog.Photos)
+			return nil, fmt.Errorf("invalid photo %s: %w", photo.Filename, err)
+		}
+		cookLog.Photos = append(cookLog.Photos, "/uploads/"+filename)
+	}
+
+	created, err := s.cookLogRepo.Create(cookLog)
+	if err != nil {
+		s.deletePhotos(cookLog.Photos)
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *CookLogService) GetCookLog(id string) (*models.CookLog, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("cook log not found")
+	}
+	return s.cookLogRepo.GetByID(id)
+}
+
+// DeleteCookLog removes one of the user's cook logs together with its
+// photo files.
+func (s *CookLogService) DeleteCookLog(id string, userID string) error {
+	cookLog, err := s.GetCookLog(id)
+	if err != nil {
+		return errors.New("cook log not found")
+	}
+	if cookLog.UserID.String() != userID {
+		return errors.New("unauthorized: you can only delete your own cook logs")
+	}
+
+	if err := s.cookLogRepo.Delete(id); err != nil {
+		return err
+	}
+	s.deletePhotos(cookLog.Photos)
+	return nil
+}
+
+// ListRecipeCookLogs pages through a recipe's cook logs, most recently
+// cooked first.
+func (s *CookLogService) ListRecipeCookLogs(recipeID string, limit int, cursor string) (*models.Page[*models.CookLog], error) {
+	if _, err := uuid.Parse(recipeID); err != nil {
+		return nil, errors.New("recipe not found")
+	}
+	if _, err := s.recipeRepo.GetByID(recipeID); err != nil {
+		return nil, errors.New("recipe not found")
+	}
+	return s.cookLogRepo.ListByRecipe(recipeID, pageSize(limit), cursor)
+}
+
+// ListUserCookLogs pages through everything a user has cooked, most
+// recently cooked first.
+func (s *CookLogService) ListUserCookLogs(userID string, limit int, cursor string) (*models.Page[*models.CookLog], error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+	return s.cookLogRepo.ListByUser(userID, pageSize(limit), cursor)
+}
+
+// deletePhotos removes stored photo files. A file that can't be removed is
+// left behind; it is no longer referenced.
+func (s *CookLogService) deletePhotos(photos []string) {
+	for _, photo := range photos {
+		s.storage.DeleteImage(strings.TrimPrefix(photo, "/uploads/"))
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCookLogService(t *testing.T, db *sql.DB, q *sqlc.Queries) (*CookLogService, string) {
+	uploads := t.TempDir()
+	service := NewCookLogService(
+		repository.NewCookLogRepository(db, q),
+		repository.NewRecipeRepository(db, q),
+		repository.NewVariationRepository(db, q),
+		NewStorageService(uploads, 10*1024*1024),
+	)
+	return service, uploads
+}
+
+// testPhotos builds uploaded file headers the way a multipart request
+// would carry them.
+func testPhotos(t *testing.T, files map[string][]byte) []*multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, data := range files {
+		part, err := writer.CreateFormFile("photos", name)
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	return form.File["photos"]
+}
+
+func TestCookLogService_CreateCookLog(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service, uploads := newTestCookLogService(t, db, q)
+	recipeService := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "author@example.com")
+	cookID := createTestUser(db, q, "cook@example.com")
+
+	recipe, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Tomato Soup",
+		MarkdownContent: "## Ingredients\n\n- 4 tomatoes",
+		IsPublished:     true,
+	}, authorID)
+	require.NoError(t, err)
+
+	notes := "  Needed more salt.  "
+	cookLog, err := service.CreateCookLog(recipe.ID.String(), &models.CreateCookLogRequest{
+		CookedOn: "2024-03-01",
+		Rating:   intPtr(4),
+		Notes:    &notes,
+	}, testPhotos(t, map[string][]byte{"soup.png": testPNG(t)}), cookID)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-03-01", cookLog.CookedOn)
+	assert.Equal(t, 4, *cookLog.Rating)
+	assert.Equal(t, "Needed more salt.", *cookLog.Notes)
+	require.Len(t, cookLog.Photos, 1)
+	assert.FileExists(t, filepath.Join(uploads, filepath.Base(cookLog.Photos[0])))
+
+	loaded, err := service.GetCookLog(cookLog.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, cookLog.Photos, loaded.Photos)
+	assert.Equal(t, cookID, loaded.UserID.String())
+
+	// The cooked_on date defaults to today.
+	today, err := service.CreateCookLog(recipe.ID.String(), &models.CreateCookLogRequest{}, nil, cookID)
+	require.NoError(t, err)
+	assert.Nil(t, today.Rating)
+	assert.Empty(t, today.Photos)
+
+	_, err = service.CreateCookLog(recipe.ID.String(), &models.CreateCookLogRequest{Rating: intPtr(6)}, nil, cookID)
+	assert.EqualError(t, err, "rating must be between 1 and 5")
+	_, err = service.CreateCookLog(recipe.ID.String(), &models.CreateCookLogRequest{CookedOn: "01/03/2024"}, nil, cookID)
+	assert.EqualError(t, err, "cooked_on must be a date such as 2024-03-01")
+	_, err = service.CreateCookLog(recipe.ID.String(), &models.CreateCookLogRequest{CookedOn: "2999-01-01"}, nil, cookID)
+	assert.EqualError(t, err, "cooked_on can't be in the future")
+	_, err = service.CreateCookLog(recipe.ID.String(), &models.CreateCookLogRequest{VariationID: stringPtr(uuid.New().String())}, nil, cookID)
+	assert.EqualError(t, err, "variation not found")
+	_, err = service.CreateCookLog(uuid.New().String(), &models.CreateCookLogRequest{}, nil, cookID)
+	assert.EqualError(t, err, "recipe not found")
+
+	// A photo that isn't an image is refused and nothing is stored.
+	_, err = service.CreateCookLog(recipe.ID.String(), &models.CreateCookLogRequest{}, testPhotos(t, map[string][]byte{"notes.txt": []byte("x")}), cookID)
+	assert.ErrorContains(t, err, "invalid photo notes.txt")
+	entries, err := os.ReadDir(uploads)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	err = service.DeleteCookLog(cookLog.ID.String(), authorID)
+	assert.EqualError(t, err, "unauthorized: you can only delete your own cook logs")
+	require.NoError(t, service.DeleteCookLog(cookLog.ID.String(), cookID))
+	assert.NoFileExists(t, filepath.Join(uploads, filepath.Base(cookLog.Photos[0])))
+	_, err = service.GetCookLog(cookLog.ID.String())
+	assert.Error(t, err)
+}
+
+func TestCookLogService_HistoryAndStats(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service, _ := newTestCookLogService(t, db, q)
+	recipeService := newTestRecipeService(db, q)
+	cookID := createTestUser(db, q, "cook@example.com")
+	otherID := createTestUser(db, q, "other@example.com")
+
+	soup, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{Title: "Soup", MarkdownContent: "Simmer."}, cookID)
+	require.NoError(t, err)
+	bread, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{Title: "Bread", MarkdownContent: "Bake."}, cookID)
+	require.NoError(t, err)
+
+	loaded, err := recipeService.GetRecipe(soup.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 0, loaded.CookStats.TimesCooked)
+	assert.Nil(t, loaded.CookStats.AverageRating)
+	assert.Nil(t, loaded.CookStats.LastCookedOn)
+
+	spicyID := uuid.New()
+	_, err = db.Exec(`INSERT INTO recipe_variations (id, recipe_id, author_id, markdown_content) VALUES (?, ?, ?, ?)`, spicyID.String(), soup.ID.String(), otherID, "Simmer with chili.")
+	require.NoError(t, err)
+
+	for _, entry := range []struct {
+		recipeID, userID, cookedOn string
+		rating                     *int
+	}{
+		{soup.ID.String(), cookID, "2024-01-05", intPtr(3)},
+		{soup.ID.String(), otherID, "2024-02-10", intPtr(5)},
+		{soup.ID.String(), cookID, "2024-03-15", nil},
+		{bread.ID.String(), cookID, "2024-02-01", intPtr(4)},
+	} {
+		_, err := service.CreateCookLog(entry.recipeID, &models.CreateCookLogRequest{CookedOn: entry.cookedOn, Rating: entry.rating}, nil, entry.userID)
+		require.NoError(t, err)
+	}
+	_, err = service.cookLogRepo.Create(&models.CookLog{
+		RecipeID:    soup.ID,
+		VariationID: &spicyID,
+		UserID:      uuid.MustParse(otherID),
+		CookedOn:    "2024-02-20",
+		Rating:      intPtr(5),
+	})
+	require.NoError(t, err)
+
+	loaded, err = recipeService.GetRecipe(soup.ID.String())
+	require.NoError(t, err)
+	stats := loaded.CookStats
+	assert.Equal(t, 4, stats.TimesCooked)
+	assert.Equal(t, 4.3, *stats.AverageRating)
+	assert.Equal(t, "2024-03-15", *stats.LastCookedOn)
+	require.Len(t, stats.Variations, 1)
+	assert.Equal(t, spicyID, stats.Variations[0].VariationID)
+	assert.Equal(t, 1, stats.Variations[0].TimesCooked)
+	assert.Equal(t, 5.0, *stats.Variations[0].AverageRating)
+
+	page, err := service.ListUserCookLogs(cookID, 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "2024-03-15", page.Items[0].CookedOn)
+	assert.Equal(t, "Soup", page.Items[0].RecipeTitle)
+	assert.Equal(t, "2024-02-01", page.Items[1].CookedOn)
+	assert.Equal(t, "bread", page.Items[1].RecipeSlug)
+	require.NotNil(t, page.NextCursor)
+
+	page, err = service.ListUserCookLogs(cookID, 2, *page.NextCursor)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "2024-01-05", page.Items[0].CookedOn)
+	assert.Nil(t, page.NextCursor)
+
+	page, err = service.ListRecipeCookLogs(soup.ID.String(), 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, 4, page.Total)
+	assert.Equal(t, "2024-03-15", page.Items[0].CookedOn)
+	assert.Equal(t, spicyID, *page.Items[1].VariationID)
+	assert.Empty(t, page.Items[0].RecipeTitle)
+
+	_, err = service.ListRecipeCookLogs(soup.ID.String(), 0, "bogus")
+	assert.EqualError(t, err, "invalid cursor")
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+// durationPattern matches durations in a step such as "20 minutes",
+// "1-2 hours", "an hour", "half an hour" and "1 hour 30 minutes".
+var durationPattern = regexp.MustCompile(`(?i)\b(\d+(?:\.\d+)?|half an?|an?|one)(?:\s*(?:-|–|to|or)\s*(\d+(?:\.\d+)?))?\s*(hours?|hrs?|minutes?|mins?|seconds?|secs?)\b(?:,?\s*(?:and\s+)?(\d+)\s*(minutes?|mins?|seconds?|secs?)\b)?`)
+
+// GetSteps splits the recipe's method into numbered steps, each with the
+// timers its text mentions and the ingredients it uses.
+func (s *RecipeService) GetSteps(id string) (*models.RecipeSteps, error) {
+	recipe, err := s.GetRecipe(id)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := &models.RecipeSteps{
+		RecipeID: recipe.ID,
+		Title:    recipe.Title,
+		Steps:    []models.RecipeStep{},
+	}
+	sections, _ := instructionSections(recipe.MarkdownContent)
+	for _, section := range sections {
+		for _, text := range section.steps {
+			steps.Steps = append(steps.Steps, models.RecipeStep{
+				Number:      len(steps.Steps) + 1,
+				Section:     section.name,
+				Text:        text,
+				Timers:      StepTimers(text),
+				Ingredients: stepIngredients(text, recipe.Ingredients),
+			})
+		}
+	}
+	return steps, nil
+}
+
+// StepTimers returns the durations mentioned in text, in order.
+func StepTimers(text string) []models.StepTimer {
+	timers := []models.StepTimer{}
+	for _, m := range durationPattern.FindAllStringSubmatch(text, -1) {
+		unit := durationUnitSeconds(m[3])
+		low, ok := durationAmount(m[1])
+		if !ok {
+			continue
+		}
+		high := low
+		if m[2] != "" {
+			high, _ = strconv.ParseFloat(m[2], 64)
+		}
+		extra := 0.0
+		if m[4] != "" {
+			n, _ := strconv.ParseFloat(m[4], 64)
+			extra = n * durationUnitSeconds(m[5])
+		}
+		if high < low {
+			low, high = high, low
+		}
+		timers = append(timers, models.StepTimer{
+			Text:       m[0],
+			MinSeconds: int(math.Round(low*unit + extra)),
+			MaxSeconds: int(math.Round(high*unit + extra)),
+		})
+	}
+	return timers
+}
+
+func durationAmount(s string) (float64, bool) {
+	switch strings.ToLower(s) {
+	case "a", "an", "one":
+		return 1, true
+	case "half a", "half an":
+		return 0.5, true
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+func durationUnitSeconds(unit string) float64 {
+	switch unit = strings.ToLower(unit); {
+	case strings.HasPrefix(unit, "h"):
+		return 3600
+	case strings.HasPrefix(unit, "m"):
+		return 60
+	}
+	return 1
+}
+
+// stepIngredients returns the ingredients a step mentions. A step mentions
+// an ingredient when it has the last word of its name, so "the oil" counts
+// for "olive oil". Plurals match their singular.
+func stepIngredients(text string, ingredients []models.RecipeIngredient) []models.RecipeIngredient {
+	words := map[string]bool{}
+	for _, word := range stemmedWords(text) {
+		words[word] = true
+	}
+
+	used := []models.RecipeIngredient{}
+	for _, ingredient := range ingredients {
+		name := stemmedWords(plainMarkdown(ingredient.Name))
+		if len(name) > 0 && words[name[len(name)-1]] {
+			used = append(used, ingredient)
+		}
+	}
+	return used
+}
+
+// stemmedWords splits s into lower-case words with plural endings removed.
+func stemmedWords(s string) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for i, word := range words {
+		switch {
+		case strings.HasSuffix(word, "ies") && len(word) > 4:
+			words[i] = strings.TrimSuffix(word, "ies") + "y"
+		case strings.HasSuffix(word, "oes"):
+			words[i] = strings.TrimSuffix(word, "es")
+		case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && len(word) > 3:
+			words[i] = strings.TrimSuffix(word, "s")
+		}
+	}
+	return words
+}
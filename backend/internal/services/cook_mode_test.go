@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/homecooking/backend/internal/models"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepTimers(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []models.StepTimer
+	}{
+		{"Simmer for 20 minutes.", []models.StepTimer{{Text: "20 minutes", MinSeconds: 1200, MaxSeconds: 1200}}},
+		{"Bake 1-2 hours until dark.", []models.StepTimer{{Text: "1-2 hours", MinSeconds: 3600, MaxSeconds: 7200}}},
+		{"Rest 10 to 15 mins, then fry 30 seconds.", []models.StepTimer{
+			{Text: "10 to 15 mins", MinSeconds: 600, MaxSeconds: 900},
+			{Text: "30 seconds", MinSeconds: 30, MaxSeconds: 30},
+		}},
+		{"Roast for 1 hour 30 minutes.", []models.StepTimer{{Text: "1 hour 30 minutes", MinSeconds: 5400, MaxSeconds: 5400}}},
+		{"Chill for half an hour or an hour.", []models.StepTimer{
+			{Text: "half an hour", MinSeconds: 1800, MaxSeconds: 1800},
+			{Text: "an hour", MinSeconds: 3600, MaxSeconds: 3600},
+		}},
+		{"Proof 1.5 hrs.", []models.StepTimer{{Text: "1.5 hrs", MinSeconds: 5400, MaxSeconds: 5400}}},
+		{"Cook a few minutes at 200 degrees.", []models.StepTimer{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StepTimers(tt.text))
+		})
+	}
+}
+
+func TestRecipeService_GetSteps(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "test@example.com")
+
+	recipe, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title: "Tomato Soup",
+		MarkdownContent: `## Ingredients
+
+- 4 tomatoes
+- 1 onion, diced
+- 2 tbsp olive oil
+
+## Instructions
+
+1. Warm the oil and soften the onion for 5 minutes.
+2. Add the tomatoes and simmer 20-25 minutes.
+
+### To serve
+
+Season and ladle into bowls.`,
+	}, authorID)
+	require.NoError(t, err)
+
+	steps, err := service.GetSteps(recipe.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Tomato Soup", steps.Title)
+	require.Len(t, steps.Steps, 3)
+
+	first := steps.Steps[0]
+	assert.Equal(t, 1, first.Number)
+	assert.Equal(t, "Warm the oil and soften the onion for 5 minutes.", first.Text)
+	assert.Equal(t, []models.StepTimer{{Text: "5 minutes", MinSeconds: 300, MaxSeconds: 300}}, first.Timers)
+	require.Len(t, first.Ingredients, 2)
+	assert.Equal(t, "onion", first.Ingredients[0].Name)
+	assert.Equal(t, "olive oil", first.Ingredients[1].Name)
+
+	second := steps.Steps[1]
+	assert.Equal(t, 1200, second.Timers[0].MinSeconds)
+	assert.Equal(t, 1500, second.Timers[0].MaxSeconds)
+	require.Len(t, second.Ingredients, 1)
+	assert.Equal(t, "tomatoes", second.Ingredients[0].Name)
+
+	third := steps.Steps[2]
+	assert.Equal(t, 3, third.Number)
+	assert.Equal(t, "To serve", third.Section)
+	assert.Empty(t, third.Timers)
+	assert.Empty(t, third.Ingredients)
+}
@@ -0,0 +1,505 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+// Traits tracked next to the allergens that only matter for the diets.
+const (
+	traitMeat   = "meat"   // meat and fish
+	traitAnimal = "animal" // other animal products, such as honey
+)
+
+// dietaryContains maps ingredient phrases to the allergens and traits they
+// indicate. Phrases are matched on whole words, longest first, so "peanut
+// butter" is not dairy and "coconut milk" (listed with nothing) is not
+// either. Flours made from nuts, seeds and grains other than wheat, rye and
+// barley are listed with only their own allergens, so they aren't gluten. Shellfish doesn't need traitMeat; it rules out vegetarian on its
+// own.
+var dietaryContains = map[string][]string{
+	// Gluten
+	"flour":           {models.AllergenGluten},
+	"wheat":           {models.AllergenGluten},
+	"bread":           {models.AllergenGluten},
+	"breadcrumbs":     {models.AllergenGluten},
+	"bread crumbs":    {models.AllergenGluten},
+	"breadstick":      {models.AllergenGluten},
+	"panko":           {models.AllergenGluten},
+	"crouton":         {models.AllergenGluten},
+	"stuffing":        {models.AllergenGluten},
+	"bun":             {models.AllergenGluten},
+	"hamburger buns":  {models.AllergenGluten},
+	"hot dog buns":    {models.AllergenGluten},
+	"roll":            {models.AllergenGluten},
+	"bagel":           {models.AllergenGluten},
+	"baguette":        {models.AllergenGluten},
+	"ciabatta":        {models.AllergenGluten},
+	"sourdough":       {models.AllergenGluten},
+	"cornbread":       {models.AllergenGluten},
+	"pita":            {models.AllergenGluten},
+	"naan":            {models.AllergenGluten},
+	"tortilla":        {models.AllergenGluten},
+	"matzo":           {models.AllergenGluten},
+	"pretzel":         {models.AllergenGluten},
+	"cracker":         {models.AllergenGluten},
+	"cookie":          {models.AllergenGluten},
+	"biscuit":         {models.AllergenGluten},
+	"cake":            {models.AllergenGluten},
+	"dough":           {models.AllergenGluten},
+	"pastry":          {models.AllergenGluten},
+	"pie crust":       {models.AllergenGluten},
+	"filo":            {models.AllergenGluten},
+	"phyllo":          {models.AllergenGluten},
+	"roux":            {models.AllergenGluten},
+	"pasta":           {models.AllergenGluten},
+	"spaghetti":       {models.AllergenGluten},
+	"macaroni":        {models.AllergenGluten},
+	"penne":           {models.AllergenGluten},
+	"linguine":        {models.AllergenGluten},
+	"fettuccine":      {models.AllergenGluten},
+	"tagliatelle":     {models.AllergenGluten},
+	"rigatoni":        {models.AllergenGluten},
+	"fusilli":         {models.AllergenGluten},
+	"farfalle":        {models.AllergenGluten},
+	"lasagna":         {models.AllergenGluten},
+	"lasagne":         {models.AllergenGluten},
+	"ravioli":         {models.AllergenGluten},
+	"tortellini":      {models.AllergenGluten},
+	"gnocchi":         {models.AllergenGluten},
+	"orzo":            {models.AllergenGluten},
+	"vermicelli":      {models.AllergenGluten},
+	"noodles":         {models.AllergenGluten},
+	"ramen":           {models.AllergenGluten},
+	"udon":            {models.AllergenGluten},
+	"soba":            {models.AllergenGluten},
+	"dumpling":        {models.AllergenGluten},
+	"wonton":          {models.AllergenGluten},
+	"couscous":        {models.AllergenGluten},
+	"bulgur":          {models.AllergenGluten},
+	"barley":          {models.AllergenGluten},
+	"rye":             {models.AllergenGluten},
+	"semolina":        {models.AllergenGluten},
+	"spelt":           {models.AllergenGluten},
+	"farro":           {models.AllergenGluten},
+	"freekeh":         {models.AllergenGluten},
+	"seitan":          {models.AllergenGluten},
+	"malt":            {models.AllergenGluten},
+	"beer":            {models.AllergenGluten},
+	"croissant":       {models.AllergenGluten, models.AllergenDairy},
+	"puff pastry":     {models.AllergenGluten, models.AllergenDairy},
+	"bechamel":        {models.AllergenGluten, models.AllergenDairy},
+	"béchamel":        {models.AllergenGluten, models.AllergenDairy},
+	"brioche":         {models.AllergenGluten, models.AllergenDairy, models.AllergenEgg},
+	"egg noodles":     {models.AllergenGluten, models.AllergenEgg},
+	"soy sauce":       {models.AllergenGluten, models.AllergenSoy},
+	"teriyaki":        {models.AllergenGluten, models.AllergenSoy},
+	"hoisin":          {models.AllergenGluten, models.AllergenSoy},
+	"rice flour":      {},
+	"corn flour":      {},
+	"coconut flour":   {},
+	"chickpea flour":  {},
+	"gram flour":      {},
+	"buckwheat flour": {},
+	"potato flour":    {},
+	"tapioca flour":   {},
+	"cassava flour":   {},
+	"sorghum flour":   {},
+	"oat flour":       {},
+	"millet flour":    {},
+	"quinoa flour":    {},
+	"amaranth flour":  {},
+	"teff flour":      {},
+	"arrowroot flour": {},
+	"lentil flour":    {},
+	"pea flour":       {},
+	"banana flour":    {},
+	"hemp flour":      {},
+	"flaxseed flour":  {},
+	"flaxseed meal":   {},
+	"sunflower flour": {},
+	"pepita flour":    {},
+	"almond flour":    {models.AllergenNuts},
+	"almond meal":     {models.AllergenNuts},
+	"hazelnut flour":  {models.AllergenNuts},
+	"cashew flour":    {models.AllergenNuts},
+	"walnut flour":    {models.AllergenNuts},
+	"pecan flour":     {models.AllergenNuts},
+	"pistachio flour": {models.AllergenNuts},
+	"chestnut flour":  {models.AllergenNuts},
+	"peanut flour":    {models.AllergenPeanuts},
+	"soy flour":       {models.AllergenSoy},
+	"sesame flour":    {models.AllergenSesame},
+	"rice noodles":    {},
+	"glass noodles":   {},
+	"rice vermicelli": {},
+	"rice cake":       {},
+	"corn tortilla":   {},
+
+	// Dairy
+	"milk":             {models.AllergenDairy},
+	"buttermilk":       {models.AllergenDairy},
+	"milk powder":      {models.AllergenDairy},
+	"half and half":    {models.AllergenDairy},
+	"butter":           {models.AllergenDairy},
+	"butterscotch":     {models.AllergenDairy},
+	"ghee":             {models.AllergenDairy},
+	"cream":            {models.AllergenDairy},
+	"creamed corn":     {models.AllergenDairy},
+	"crème fraîche":    {models.AllergenDairy},
+	"creme fraiche":    {models.AllergenDairy},
+	"ice cream":        {models.AllergenDairy},
+	"gelato":           {models.AllergenDairy},
+	"cheese":           {models.AllergenDairy},
+	"cheddar":          {models.AllergenDairy},
+	"parmesan":         {models.AllergenDairy},
+	"parmigiano":       {models.AllergenDairy},
+	"pecorino":         {models.AllergenDairy},
+	"mozzarella":       {models.AllergenDairy},
+	"burrata":          {models.AllergenDairy},
+	"feta":             {models.AllergenDairy},
+	"ricotta":          {models.AllergenDairy},
+	"mascarpone":       {models.AllergenDairy},
+	"brie":             {models.AllergenDairy},
+	"camembert":        {models.AllergenDairy},
+	"gouda":            {models.AllergenDairy},
+	"gruyere":          {models.AllergenDairy},
+	"gruyère":          {models.AllergenDairy},
+	"emmental":         {models.AllergenDairy},
+	"provolone":        {models.AllergenDairy},
+	"halloumi":         {models.AllergenDairy},
+	"paneer":           {models.AllergenDairy},
+	"yogurt":           {models.AllergenDairy},
+	"yoghurt":          {models.AllergenDairy},
+	"kefir":            {models.AllergenDairy},
+	"labneh":           {models.AllergenDairy},
+	"skyr":             {models.AllergenDairy},
+	"quark":            {models.AllergenDairy},
+	"curd":             {models.AllergenDairy},
+	"whey":             {models.AllergenDairy},
+	"casein":           {models.AllergenDairy},
+	"lactose":          {models.AllergenDairy},
+	"alfredo":          {models.AllergenDairy},
+	"dulce de leche":   {models.AllergenDairy},
+	"milk chocolate":   {models.AllergenDairy},
+	"white chocolate":  {models.AllergenDairy},
+	"chocolate chips":  {models.AllergenDairy},
+	"coconut milk":     {},
+	"coconut cream":    {},
+	"coconut yogurt":   {},
+	"oat milk":         {},
+	"rice milk":        {},
+	"cocoa butter":     {},
+	"apple butter":     {},
+	"sunflower butter": {},
+	"butter beans":     {},
+	"butter lettuce":   {},
+	"cream of tartar":  {},
+
+	// Egg
+	"egg":         {models.AllergenEgg},
+	"yolk":        {models.AllergenEgg},
+	"egg white":   {models.AllergenEgg},
+	"mayonnaise":  {models.AllergenEgg},
+	"mayo":        {models.AllergenEgg},
+	"aioli":       {models.AllergenEgg},
+	"meringue":    {models.AllergenEgg},
+	"duck egg":    {models.AllergenEgg},
+	"hollandaise": {models.AllergenEgg, models.AllergenDairy},
+	"custard":     {models.AllergenEgg, models.AllergenDairy},
+	"lemon curd":  {models.AllergenEgg, models.AllergenDairy},
+	"flax egg":    {},
+	"chia egg":    {},
+
+	// Tree nuts
+	"nuts":           {models.AllergenNuts},
+	"almond":         {models.AllergenNuts},
+	"almond milk":    {models.AllergenNuts},
+	"almond butter":  {models.AllergenNuts},
+	"walnut":         {models.AllergenNuts},
+	"pecan":          {models.AllergenNuts},
+	"cashew":         {models.AllergenNuts},
+	"cashew milk":    {models.AllergenNuts},
+	"cashew butter":  {models.AllergenNuts},
+	"pistachio":      {models.AllergenNuts},
+	"hazelnut":       {models.AllergenNuts},
+	"macadamia":      {models.AllergenNuts},
+	"brazil nuts":    {models.AllergenNuts},
+	"pine nuts":      {models.AllergenNuts},
+	"chestnut":       {models.AllergenNuts},
+	"nut butter":     {models.AllergenNuts},
+	"nut milk":       {models.AllergenNuts},
+	"praline":        {models.AllergenNuts},
+	"marzipan":       {models.AllergenNuts},
+	"frangipane":     {models.AllergenNuts},
+	"nougat":         {models.AllergenNuts, models.AllergenEgg},
+	"nutella":        {models.AllergenNuts, models.AllergenDairy},
+	"pesto":          {models.AllergenNuts, models.AllergenDairy},
+	"water chestnut": {},
+
+	// Peanuts
+	"peanut":        {models.AllergenPeanuts},
+	"peanut butter": {models.AllergenPeanuts},
+	"groundnut":     {models.AllergenPeanuts},
+	"satay":         {models.AllergenPeanuts},
+
+	// Shellfish
+	"shellfish":        {models.AllergenShellfish},
+	"seafood":          {models.AllergenShellfish, traitMeat},
+	"shrimp":           {models.AllergenShellfish},
+	"shrimp paste":     {models.AllergenShellfish},
+	"prawn":            {models.AllergenShellfish},
+	"crab":             {models.AllergenShellfish},
+	"lobster":          {models.AllergenShellfish},
+	"crayfish":         {models.AllergenShellfish},
+	"crawfish":         {models.AllergenShellfish},
+	"langoustine":      {models.AllergenShellfish},
+	"scallop":          {models.AllergenShellfish},
+	"mussel":           {models.AllergenShellfish},
+	"clam":             {models.AllergenShellfish},
+	"oyster":           {models.AllergenShellfish},
+	"oyster sauce":     {models.AllergenShellfish},
+	"squid":            {models.AllergenShellfish},
+	"calamari":         {models.AllergenShellfish},
+	"octopus":          {models.AllergenShellfish},
+	"oyster mushrooms": {},
+
+	// Soy
+	"soy":       {models.AllergenSoy},
+	"soya":      {models.AllergenSoy},
+	"soybean":   {models.AllergenSoy},
+	"soy milk":  {models.AllergenSoy},
+	"tofu":      {models.AllergenSoy},
+	"bean curd": {models.AllergenSoy},
+	"tempeh":    {models.AllergenSoy},
+	"edamame":   {models.AllergenSoy},
+	"miso":      {models.AllergenSoy},
+	"tamari":    {models.AllergenSoy},
+
+	// Sesame
+	"sesame":   {models.AllergenSesame},
+	"tahini":   {models.AllergenSesame},
+	"hummus":   {models.AllergenSesame},
+	"halva":    {models.AllergenSesame},
+	"halvah":   {models.AllergenSesame},
+	"za'atar":  {models.AllergenSesame},
+	"gomasio":  {models.AllergenSesame},
+	"furikake": {models.AllergenSesame, traitMeat},
+
+	// Meat and fish
+	"meat":            {traitMeat},
+	"chicken":         {traitMeat},
+	"beef":            {traitMeat},
+	"pork":            {traitMeat},
+	"lamb":            {traitMeat},
+	"mutton":          {traitMeat},
+	"veal":            {traitMeat},
+	"venison":         {traitMeat},
+	"rabbit":          {traitMeat},
+	"turkey":          {traitMeat},
+	"duck":            {traitMeat},
+	"goose":           {traitMeat},
+	"steak":           {traitMeat},
+	"brisket":         {traitMeat},
+	"mince":           {traitMeat},
+	"meatballs":       {traitMeat},
+	"bacon":           {traitMeat},
+	"ham":             {traitMeat},
+	"prosciutto":      {traitMeat},
+	"pancetta":        {traitMeat},
+	"guanciale":       {traitMeat},
+	"chorizo":         {traitMeat},
+	"salami":          {traitMeat},
+	"pepperoni":       {traitMeat},
+	"sausage":         {traitMeat},
+	"bratwurst":       {traitMeat},
+	"hot dogs":        {traitMeat},
+	"hamburger":       {traitMeat},
+	"lard":            {traitMeat},
+	"suet":            {traitMeat},
+	"dripping":        {traitMeat},
+	"schmaltz":        {traitMeat},
+	"gelatin":         {traitMeat},
+	"gelatine":        {traitMeat},
+	"stock":           {traitMeat},
+	"broth":           {traitMeat},
+	"bouillon":        {traitMeat},
+	"dashi":           {traitMeat},
+	"bonito":          {traitMeat},
+	"fish":            {traitMeat},
+	"fish sauce":      {traitMeat},
+	"anchovy":         {traitMeat},
+	"salmon":          {traitMeat},
+	"tuna":            {traitMeat},
+	"cod":             {traitMeat},
+	"haddock":         {traitMeat},
+	"halibut":         {traitMeat},
+	"trout":           {traitMeat},
+	"sardine":         {traitMeat},
+	"mackerel":        {traitMeat},
+	"tilapia":         {traitMeat},
+	"snapper":         {traitMeat},
+	"sea bass":        {traitMeat},
+	"swordfish":       {traitMeat},
+	"catfish":         {traitMeat},
+	"worcestershire":  {traitMeat},
+	"caesar dressing": {traitMeat, models.AllergenEgg, models.AllergenDairy},
+	"vegetable stock": {},
+	"vegetable broth": {},
+	"mushroom stock":  {},
+	"mushroom broth":  {},
+
+	// Other animal products
+	"honey":     {traitAnimal},
+	"honeycomb": {traitAnimal},
+}
+
+// dietaryClears maps phrases such as "gluten-free" to what they rule out
+// for the whole ingredient line they appear in, so "gluten-free pasta" and
+// "vegan butter" aren't reported.
+var dietaryClears = map[string][]string{
+	"gluten free": {models.AllergenGluten},
+	"dairy free":  {models.AllergenDairy},
+	"non dairy":   {models.AllergenDairy},
+	"egg free":    {models.AllergenEgg},
+	"eggless":     {models.AllergenEgg},
+	"nut free":    {models.AllergenNuts, models.AllergenPeanuts},
+	"soy free":    {models.AllergenSoy},
+	"vegetarian":  {traitMeat},
+	"meatless":    {traitMeat},
+	"meat free":   {traitMeat},
+	"vegan":       {models.AllergenDairy, models.AllergenEgg, models.AllergenShellfish, traitMeat, traitAnimal},
+	"plant based": {models.AllergenDairy, models.AllergenEgg, models.AllergenShellfish, traitMeat, traitAnimal},
+}
+
+type dietaryTerm struct {
+	contains []string
+	clears   []string
+}
+
+// dietaryTable finds dietary terms by their normalised phrase.
+type dietaryTable struct {
+	byPhrase map[string]dietaryTerm
+	maxWords int
+}
+
+var dietaryTerms = mustBuildDietaryTable(dietaryContains, dietaryClears)
+
+// ClassifyDietary detects the allergens in a recipe's ingredient lines and
+// derives the diets it suits. Markdown without an ingredient section is
+// classified line by line in full, so that a recipe written as free text
+// isn't labelled allergen-free for lack of a list.
+func ClassifyDietary(markdown string) *models.RecipeDietary {
+	var lines []string
+	if ingredients := ParseIngredients(markdown); len(ingredients) > 0 {
+		for _, ingredient := range ingredients {
+			lines = append(lines, ingredient.RawText)
+		}
+	} else {
+		lines = strings.Split(markdown, "\n")
+	}
+
+	found := map[string]bool{}
+	for _, line := range lines {
+		for trait := range dietaryTerms.scan(line) {
+			found[trait] = true
+		}
+	}
+
+	dietary := &models.RecipeDietary{Allergens: []string{}, Diets: []string{}}
+	for _, allergen := range models.Allergens {
+		if found[allergen] {
+			dietary.Allergens = append(dietary.Allergens, allergen)
+		}
+	}
+
+	vegetarian := !found[traitMeat] && !found[models.AllergenShellfish]
+	suits := map[string]bool{
+		models.DietVegetarian: vegetarian,
+		models.DietVegan:      vegetarian && !found[models.AllergenDairy] && !found[models.AllergenEgg] && !found[traitAnimal],
+		models.DietGlutenFree: !found[models.AllergenGluten],
+		models.DietDairyFree:  !found[models.AllergenDairy],
+		models.DietNutFree:    !found[models.AllergenNuts] && !found[models.AllergenPeanuts],
+	}
+	for _, diet := range models.Diets {
+		if suits[diet] {
+			dietary.Diets = append(dietary.Diets, diet)
+		}
+	}
+	return dietary
+}
+
+// classifyVariations sets the dietary classification of each variation
+// from its own markdown.
+func classifyVariations(variations ...*models.RecipeVariation) {
+	for _, variation := range variations {
+		variation.Dietary = ClassifyDietary(variation.MarkdownContent)
+	}
+}
+
+// scan returns the allergens and traits of one ingredient line. At every
+// word the longest known phrase starting there is taken and its words are
+// skipped, then whatever the line's clearing phrases rule out is removed.
+func (t *dietaryTable) scan(line string) map[string]bool {
+	words := strings.Fields(normalizeFoodName(line))
+	found := map[string]bool{}
+	var cleared []string
+	for i := 0; i < len(words); {
+		n := min(t.maxWords, len(words)-i)
+		for ; n > 0; n-- {
+			term, ok := t.byPhrase[strings.Join(words[i:i+n], " ")]
+			if !ok {
+				continue
+			}
+			for _, trait := range term.contains {
+				found[trait] = true
+			}
+			cleared = append(cleared, term.clears...)
+			break
+		}
+		i += max(n, 1)
+	}
+	for _, trait := range cleared {
+		delete(found, trait)
+	}
+	return found
+}
+
+func mustBuildDietaryTable(contains, clears map[string][]string) *dietaryTable {
+	table, err := buildDietaryTable(contains, clears)
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// buildDietaryTable normalises the phrases of both maps the same way
+// ingredient lines are. A phrase may only appear once after normalising.
+func buildDietaryTable(contains, clears map[string][]string) (*dietaryTable, error) {
+	table := &dietaryTable{byPhrase: map[string]dietaryTerm{}}
+	add := func(phrase string, term dietaryTerm) error {
+		key := normalizeFoodName(phrase)
+		if key == "" {
+			return fmt.Errorf("dietary terms: %q has no words", phrase)
+		}
+		if _, ok := table.byPhrase[key]; ok {
+			return fmt.Errorf("dietary terms: %q is listed twice", phrase)
+		}
+		table.byPhrase[key] = term
+		table.maxWords = max(table.maxWords, len(strings.Fields(key)))
+		return nil
+	}
+	for phrase, traits := range contains {
+		if err := add(phrase, dietaryTerm{contains: traits}); err != nil {
+			return nil, err
+		}
+	}
+	for phrase, traits := range clears {
+		if err := add(phrase, dietaryTerm{clears: traits}); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
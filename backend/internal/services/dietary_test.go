@@ -0,0 +1,109 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDietaryTable_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected []string
+	}{
+		{"allergen", "2 cups all-purpose flour", []string{models.AllergenGluten}},
+		{"plural", "3 large eggs", []string{models.AllergenEgg}},
+		{"several", "1 tbsp soy sauce", []string{models.AllergenGluten, models.AllergenSoy}},
+		{"longest phrase", "2 tbsp peanut butter", []string{models.AllergenPeanuts}},
+		{"gluten-free flour", "1 cup oat flour", nil},
+		{"nut flour", "2 cups almond flour", []string{models.AllergenNuts}},
+		{"safe phrase", "1 can coconut milk", nil},
+		{"whole word", "1 eggplant, diced", nil},
+		{"nutmeg is not a nut", "1 pinch nutmeg", nil},
+		{"tree nuts", "1/2 cup chopped walnuts", []string{models.AllergenNuts}},
+		{"shellfish", "1 lb shrimp, peeled", []string{models.AllergenShellfish}},
+		{"mushroom", "200g oyster mushrooms", nil},
+		{"sesame", "2 tbsp tahini", []string{models.AllergenSesame}},
+		{"meat", "4 slices bacon", []string{traitMeat}},
+		{"vegetable stock", "2 cups low-sodium vegetable stock", nil},
+		{"free from", "8 oz gluten-free spaghetti", nil},
+		{"free from keeps others", "2 tbsp gluten-free soy sauce", []string{models.AllergenSoy}},
+		{"vegan", "2 tbsp vegan butter", nil},
+		{"honey", "1 tbsp honey", []string{traitAnimal}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var found []string
+			for trait := range dietaryTerms.scan(tt.line) {
+				found = append(found, trait)
+			}
+			assert.ElementsMatch(t, tt.expected, found)
+		})
+	}
+}
+
+func TestBuildDietaryTable_Duplicate(t *testing.T) {
+	_, err := buildDietaryTable(map[string][]string{"egg": {models.AllergenEgg}}, map[string][]string{"eggs": {models.AllergenEgg}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "listed twice")
+}
+
+func TestClassifyDietary(t *testing.T) {
+	tests := []struct {
+		name      string
+		markdown  string
+		allergens []string
+		diets     []string
+	}{
+		{
+			name: "pancakes",
+			markdown: `## Ingredients
+- 2 cups flour
+- 2 eggs
+- 1 1/2 cups milk
+- 2 tbsp butter, melted`,
+			allergens: []string{models.AllergenGluten, models.AllergenDairy, models.AllergenEgg},
+			diets:     []string{models.DietVegetarian, models.DietNutFree},
+		},
+		{
+			name: "vegan",
+			markdown: `## Ingredients
+- 1 cup rice
+- 1 can chickpeas
+- 1 can coconut milk
+- 2 tbsp curry paste`,
+			allergens: []string{},
+			diets:     []string{models.DietVegetarian, models.DietVegan, models.DietGlutenFree, models.DietDairyFree, models.DietNutFree},
+		},
+		{
+			name: "pad thai",
+			markdown: `## Ingredients
+- 8 oz rice noodles
+- 1/2 lb shrimp
+- 2 tbsp fish sauce
+- 1/4 cup crushed peanuts
+
+## Instructions
+1. Serve with bread.`,
+			allergens: []string{models.AllergenShellfish, models.AllergenPeanuts},
+			diets:     []string{models.DietGlutenFree, models.DietDairyFree},
+		},
+		{
+			name:      "no ingredient section",
+			markdown:  "Toast the almonds, then fold them into the cream.",
+			allergens: []string{models.AllergenDairy, models.AllergenNuts},
+			diets:     []string{models.DietVegetarian, models.DietGlutenFree},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dietary := ClassifyDietary(tt.markdown)
+			assert.ElementsMatch(t, tt.allergens, dietary.Allergens)
+			assert.ElementsMatch(t, tt.diets, dietary.Diets)
+		})
+	}
+}
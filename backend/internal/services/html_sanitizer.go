@@ -0,0 +1,172 @@
+package services
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// sanitizedElements are the elements SanitizeHTML keeps, with the attributes
+// each may carry. Other elements are replaced by their content.
+var sanitizedElements = map[atom.Atom][]string{
+	atom.H1: {"id"}, atom.H2: {"id"}, atom.H3: {"id"},
+	atom.H4: {"id"}, atom.H5: {"id"}, atom.H6: {"id"},
+	atom.P: nil, atom.Br: nil, atom.Hr: nil, atom.Blockquote: nil,
+	atom.Pre: nil, atom.Code: {"class"},
+	atom.Ul: {"class"}, atom.Ol: {"class", "start"}, atom.Li: {"id"},
+	atom.Label: nil, atom.Input: {"type", "checked", "disabled"},
+	atom.A: {"href", "title", "class"}, atom.Img: {"src", "alt", "title", "width", "height"},
+	atom.Strong: nil, atom.B: nil, atom.Em: nil, atom.I: nil, atom.U: nil,
+	atom.Del: nil, atom.S: nil, atom.Sub: nil, atom.Sup: nil, atom.Small: nil, atom.Mark: nil,
+	atom.Table: nil, atom.Thead: nil, atom.Tbody: nil, atom.Tr: nil,
+	atom.Th: {"align"}, atom.Td: {"align"},
+	atom.Span: nil, atom.Div: nil,
+}
+
+// droppedElements are removed together with their content: they run code,
+// style the page, embed other documents or hold text no one should see.
+var droppedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Frame: true,
+	atom.Frameset: true, atom.Object: true, atom.Embed: true, atom.Applet: true,
+	atom.Template: true, atom.Noscript: true, atom.Noembed: true, atom.Noframes: true,
+	atom.Svg: true, atom.Math: true, atom.Form: true, atom.Textarea: true,
+	atom.Select: true, atom.Button: true, atom.Head: true, atom.Title: true,
+	atom.Meta: true, atom.Link: true, atom.Base: true,
+}
+
+var (
+	safeNamePattern  = regexp.MustCompile(`^[A-Za-z0-9_-]+(?: [A-Za-z0-9_-]+)*$`)
+	safeSizePattern  = regexp.MustCompile(`^\d{1,4}$`)
+	safeAlignPattern = regexp.MustCompile(`^(?:left|right|center)$`)
+)
+
+// SanitizeHTML keeps the markup of fragment that can safely be shown inside
+// a page: formatting, lists, tables, links and images. Scripts, styles,
+// event handlers, form controls other than checkboxes and links to
+// anything but web, mail and relative addresses are removed.
+func SanitizeHTML(fragment string) string {
+	context := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), context)
+	if err != nil {
+		return html.EscapeString(fragment)
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		writeSanitized(&b, node)
+	}
+	return b.String()
+}
+
+func writeSanitized(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if droppedElements[n.DataAtom] {
+		return
+	}
+	allowed, ok := sanitizedElements[n.DataAtom]
+	if !ok || n.DataAtom == atom.Input && !isCheckbox(n) {
+		writeSanitizedChildren(b, n)
+		return
+	}
+
+	b.WriteString("<" + n.Data)
+	for _, attr := range n.Attr {
+		if attr.Namespace != "" || !slices.Contains(allowed, attr.Key) {
+			continue
+		}
+		value, ok := sanitizeAttr(attr.Key, attr.Val)
+		if !ok {
+			continue
+		}
+		if value == "" && (attr.Key == "checked" || attr.Key == "disabled") {
+			b.WriteString(" " + attr.Key)
+			continue
+		}
+		b.WriteString(" " + attr.Key + `="` + html.EscapeString(value) + `"`)
+	}
+	if n.DataAtom == atom.A && isExternalURL(htmlAttr(n, "href")) {
+		b.WriteString(` rel="nofollow noopener noreferrer"`)
+	}
+	b.WriteString(">")
+
+	switch n.DataAtom {
+	case atom.Br, atom.Hr, atom.Img, atom.Input:
+		return
+	case atom.Pre:
+		// The parser drops a newline right after <pre>; put it back so it
+		// isn't lost from the content.
+		if first := n.FirstChild; first != nil && first.Type == html.TextNode && strings.HasPrefix(first.Data, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	writeSanitizedChildren(b, n)
+	b.WriteString("</" + n.Data + ">")
+}
+
+func writeSanitizedChildren(b *strings.Builder, n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		writeSanitized(b, child)
+	}
+}
+
+// sanitizeAttr checks an allowed attribute's value, reporting false for
+// values that have to go.
+func sanitizeAttr(key, value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	switch key {
+	case "href":
+		return value, isSafeURL(value, false)
+	case "src":
+		return value, isSafeURL(value, true)
+	case "id", "class":
+		return value, safeNamePattern.MatchString(value)
+	case "start", "width", "height":
+		return value, safeSizePattern.MatchString(value)
+	case "align":
+		return value, safeAlignPattern.MatchString(value)
+	case "type":
+		return "checkbox", true
+	case "checked", "disabled":
+		return "", true
+	}
+	return value, true
+}
+
+// isSafeURL accepts relative addresses and absolute http and https ones,
+// and for links also mailto.
+func isSafeURL(raw string, image bool) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "":
+		return u.Opaque == ""
+	case "http", "https":
+		return true
+	case "mailto":
+		return !image
+	}
+	return false
+}
+
+func isExternalURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+func isCheckbox(n *html.Node) bool {
+	return strings.EqualFold(strings.TrimSpace(htmlAttr(n, "type")), "checkbox")
+}
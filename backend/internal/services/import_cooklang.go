@@ -0,0 +1,244 @@
+package services
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+var (
+	cooklangIngredientPattern = regexp.MustCompile(`@(?:([^@#~{}\n]+?)\{([^}]*)\}|([\p{L}\p{N}_]+))(?:\(([^)]*)\))?`)
+	cooklangCookwarePattern   = regexp.MustCompile(`#(?:([^@#~{}\n]+?)\{([^}]*)\}|([\p{L}\p{N}_]+))`)
+	cooklangTimerPattern      = regexp.MustCompile(`~([^@#~{}\n]*?)\{([^}]*)\}`)
+	cooklangBlockComment      = regexp.MustCompile(`(?s)\[-.*?-\]`)
+	cooklangLineComment       = regexp.MustCompile(`--.*$`)
+	cooklangSectionPattern    = regexp.MustCompile(`^=+\s*(.*?)\s*=*$`)
+	cooklangMetadataPattern   = regexp.MustCompile(`^>>\s*([^:]+?)\s*:\s*(.*)$`)
+)
+
+// cooklangImporter reads Cooklang .cook files, or a zip of them with each
+// recipe's photo stored under the same name. Ingredients, cookware and
+// timers are marked up inside the steps; the ingredient list is collected
+// from them.
+type cooklangImporter struct{}
+
+func (cooklangImporter) Format() string {
+	return "cooklang"
+}
+
+func (c cooklangImporter) Import(name string, data []byte) ([]*models.ImportedRecipe, error) {
+	if !isZip(data) {
+		return []*models.ImportedRecipe{parseCooklang(name, string(data))}, nil
+	}
+
+	entries, err := readZip(data)
+	if err != nil {
+		return nil, err
+	}
+	images := map[string][]byte{}
+	for _, entry := range entries {
+		ext := strings.ToLower(path.Ext(entry.name))
+		if ext == ".jpg" || ext == ".jpeg" || ext == ".png" || ext == ".webp" || ext == ".gif" {
+			images[strings.TrimSuffix(entry.name, path.Ext(entry.name))] = entry.data
+		}
+	}
+
+	var recipes []*models.ImportedRecipe
+	for _, entry := range entries {
+		if !strings.EqualFold(path.Ext(entry.name), ".cook") {
+			continue
+		}
+		recipe := parseCooklang(entry.name, string(entry.data))
+		addImage(recipe, images[strings.TrimSuffix(entry.name, path.Ext(entry.name))])
+		recipes = append(recipes, recipe)
+	}
+	return recipes, nil
+}
+
+// parseCooklang converts one recipe. Recipes without a title in their
+// metadata are named after the file.
+func parseCooklang(name, text string) *models.ImportedRecipe {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	metadata, text := cooklangFrontMatter(text)
+	text = cooklangBlockComment.ReplaceAllString(text, "")
+
+	ingredients := []contentSection{{}}
+	steps := []contentSection{{}}
+	var notes, paragraph []string
+	endStep := func() {
+		if step := strings.Join(paragraph, " "); step != "" {
+			steps[len(steps)-1].items = append(steps[len(steps)-1].items, step)
+		}
+		paragraph = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if match := cooklangMetadataPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			metadata[strings.ToLower(match[1])] = []string{match[2]}
+			continue
+		}
+		line = strings.TrimSpace(cooklangLineComment.ReplaceAllString(line, ""))
+		switch {
+		case line == "":
+			endStep()
+		case strings.HasPrefix(line, "="):
+			endStep()
+			section := cooklangSectionPattern.FindStringSubmatch(line)[1]
+			steps = append(steps, contentSection{name: section})
+			ingredients = append(ingredients, contentSection{name: section})
+		case strings.HasPrefix(line, ">"):
+			endStep()
+			if note := strings.TrimSpace(strings.TrimPrefix(line, ">")); note != "" {
+				notes = append(notes, note)
+			}
+		default:
+			for _, match := range cooklangIngredientPattern.FindAllStringSubmatch(line, -1) {
+				ingredients[len(ingredients)-1].items = append(ingredients[len(ingredients)-1].items, cooklangIngredient(match))
+			}
+			paragraph = append(paragraph, cooklangStepText(line))
+		}
+	}
+	endStep()
+
+	content := importedContent{ingredients: ingredients, instructions: steps, notes: notes}
+	content.source = cooklangMeta(metadata, "source", "source.url")
+
+	title := cooklangMeta(metadata, "title")
+	if title == "" {
+		title = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	}
+	recipe := &models.ImportedRecipe{
+		Recipe: models.CreateRecipeRequest{
+			Title:           title,
+			MarkdownContent: content.markdown(),
+			PrepTimeMinutes: parseDurationText(cooklangMeta(metadata, "prep time", "prep_time", "time.prep")),
+			CookTimeMinutes: parseDurationText(cooklangMeta(metadata, "cook time", "cook_time", "time.cook")),
+			Servings:        schemaYield(cooklangMeta(metadata, "servings", "serves", "yield")),
+		},
+	}
+	if recipe.Recipe.CookTimeMinutes == nil {
+		total := parseDurationText(cooklangMeta(metadata, "time", "total time", "duration"))
+		recipe.Recipe.CookTimeMinutes = remainingTime(total, recipe.Recipe.PrepTimeMinutes)
+	}
+	if description := cooklangMeta(metadata, "description", "introduction"); description != "" {
+		recipe.Recipe.Description = &description
+	}
+	if difficulty := importedDifficulty(cooklangMeta(metadata, "difficulty")); difficulty != "" {
+		recipe.Recipe.Difficulty = &difficulty
+	}
+	setTaxonomy(recipe, cooklangList(metadata, "category", "course"), cooklangList(metadata, "tags", "cuisine"))
+	return recipe
+}
+
+// cooklangIngredient formats a matched ingredient as a list item such as
+// "2 cups flour, sifted".
+func cooklangIngredient(match []string) string {
+	name := strings.TrimSpace(match[1] + match[3])
+	quantity, unit, _ := strings.Cut(match[2], "%")
+	var parts []string
+	for _, part := range []string{strings.TrimSuffix(strings.TrimSpace(quantity), "*"), strings.TrimSpace(unit), name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	item := strings.Join(parts, " ")
+	if note := strings.TrimSpace(match[4]); note != "" {
+		item += ", " + note
+	}
+	return item
+}
+
+// cooklangStepText strips the markup from a step, leaving ingredient and
+// cookware names and timer durations.
+func cooklangStepText(line string) string {
+	line = cooklangIngredientPattern.ReplaceAllStringFunc(line, func(s string) string {
+		match := cooklangIngredientPattern.FindStringSubmatch(s)
+		return strings.TrimSpace(match[1] + match[3])
+	})
+	line = cooklangCookwarePattern.ReplaceAllStringFunc(line, func(s string) string {
+		match := cooklangCookwarePattern.FindStringSubmatch(s)
+		return strings.TrimSpace(match[1] + match[3])
+	})
+	line = cooklangTimerPattern.ReplaceAllStringFunc(line, func(s string) string {
+		match := cooklangTimerPattern.FindStringSubmatch(s)
+		quantity, unit, _ := strings.Cut(match[2], "%")
+		return strings.TrimSpace(strings.TrimSpace(quantity) + " " + strings.TrimSpace(unit))
+	})
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// cooklangFrontMatter splits YAML front matter off the recipe. Only the
+// subset recipes use is read: "key: value" pairs and lists given inline or
+// as "- item" lines.
+func cooklangFrontMatter(text string) (map[string][]string, string) {
+	metadata := map[string][]string{}
+	if !strings.HasPrefix(text, "---\n") {
+		return metadata, text
+	}
+	header, body, ok := strings.Cut(text[len("---\n"):], "\n---")
+	if !ok {
+		return metadata, text
+	}
+	_, body, _ = strings.Cut(body, "\n")
+
+	key := ""
+	for _, line := range strings.Split(header, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok && key != "" {
+			metadata[key] = append(metadata[key], yamlScalar(item))
+			continue
+		}
+		name, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		switch {
+		case value == "":
+			metadata[key] = nil
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				metadata[key] = append(metadata[key], yamlScalar(item))
+			}
+		default:
+			metadata[key] = []string{yamlScalar(value)}
+		}
+	}
+	return metadata, body
+}
+
+func yamlScalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		s = s[1 : len(s)-1]
+	}
+	return s
+}
+
+// cooklangMeta returns the first of keys the metadata has a value for.
+func cooklangMeta(metadata map[string][]string, keys ...string) string {
+	for _, key := range keys {
+		if values := metadata[key]; len(values) > 0 && strings.TrimSpace(values[0]) != "" {
+			return strings.TrimSpace(values[0])
+		}
+	}
+	return ""
+}
+
+// cooklangList returns the values of keys, splitting comma-separated ones.
+func cooklangList(metadata map[string][]string, keys ...string) []string {
+	var list []string
+	for _, key := range keys {
+		for _, value := range metadata[key] {
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+		}
+	}
+	return list
+}
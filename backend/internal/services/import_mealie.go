@@ -0,0 +1,217 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+// mealieImporter reads Mealie recipes: the JSON of one recipe or a list of
+// them, or an export zip with a folder per recipe holding its JSON and an
+// images folder.
+type mealieImporter struct{}
+
+type mealieRecipe struct {
+	Name               string              `json:"name"`
+	Description        string              `json:"description"`
+	RecipeYield        interface{}         `json:"recipeYield"`
+	RecipeServings     interface{}         `json:"recipeServings"`
+	PrepTime           string              `json:"prepTime"`
+	PerformTime        string              `json:"performTime"`
+	CookTime           string              `json:"cookTime"`
+	TotalTime          string              `json:"totalTime"`
+	RecipeCategory     interface{}         `json:"recipeCategory"`
+	Tags               interface{}         `json:"tags"`
+	RecipeIngredient   []mealieIngredient  `json:"recipeIngredient"`
+	RecipeInstructions []mealieInstruction `json:"recipeInstructions"`
+	Notes              []mealieNote        `json:"notes"`
+	OrgURL             string              `json:"orgURL"`
+}
+
+type mealieIngredient struct {
+	Title        string      `json:"title"`
+	Display      string      `json:"display"`
+	OriginalText string      `json:"originalText"`
+	Note         string      `json:"note"`
+	Quantity     float64     `json:"quantity"`
+	Unit         interface{} `json:"unit"`
+	Food         interface{} `json:"food"`
+}
+
+type mealieInstruction struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+type mealieNote struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+func (mealieImporter) Format() string {
+	return "mealie"
+}
+
+func (m mealieImporter) Import(name string, data []byte) ([]*models.ImportedRecipe, error) {
+	if !isZip(data) {
+		return m.importJSON(data)
+	}
+
+	entries, err := readZip(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Images sit in an images folder next to the recipe's JSON.
+	images := map[string][]byte{}
+	for _, entry := range entries {
+		dir, file := path.Split(entry.name)
+		if path.Base(dir) == "images" && strings.HasPrefix(file, "original.") {
+			images[path.Dir(path.Dir(entry.name))] = entry.data
+		}
+	}
+
+	var recipes []*models.ImportedRecipe
+	for _, entry := range entries {
+		if !strings.EqualFold(path.Ext(entry.name), ".json") {
+			continue
+		}
+		found, err := m.importJSON(entry.data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.name, err)
+		}
+		for _, recipe := range found {
+			addImage(recipe, images[path.Dir(entry.name)])
+		}
+		recipes = append(recipes, found...)
+	}
+	return recipes, nil
+}
+
+// importJSON reads one recipe, a list of recipes or an object with a
+// "recipes" list.
+func (mealieImporter) importJSON(data []byte) ([]*models.ImportedRecipe, error) {
+	var probe interface{}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("invalid Mealie recipe: %w", err)
+	}
+	if obj, ok := probe.(map[string]interface{}); ok {
+		if list, ok := obj["recipes"]; ok {
+			if data, err := json.Marshal(list); err == nil {
+				return mealieImporter{}.importJSON(data)
+			}
+		}
+	}
+
+	var sources []mealieRecipe
+	if _, ok := probe.([]interface{}); ok {
+		if err := json.Unmarshal(data, &sources); err != nil {
+			return nil, fmt.Errorf("invalid Mealie recipe: %w", err)
+		}
+	} else {
+		var source mealieRecipe
+		if err := json.Unmarshal(data, &source); err != nil {
+			return nil, fmt.Errorf("invalid Mealie recipe: %w", err)
+		}
+		sources = append(sources, source)
+	}
+
+	var recipes []*models.ImportedRecipe
+	for _, source := range sources {
+		if strings.TrimSpace(source.Name) == "" {
+			return nil, errors.New("invalid Mealie recipe: no name")
+		}
+		recipes = append(recipes, source.recipe())
+	}
+	return recipes, nil
+}
+
+func (source mealieRecipe) recipe() *models.ImportedRecipe {
+	content := importedContent{source: source.OrgURL}
+
+	ingredients := []contentSection{{}}
+	for _, ingredient := range source.RecipeIngredient {
+		if title := strings.TrimSpace(ingredient.Title); title != "" {
+			ingredients = append(ingredients, contentSection{name: title})
+		}
+		if text := ingredient.text(); text != "" {
+			ingredients[len(ingredients)-1].items = append(ingredients[len(ingredients)-1].items, text)
+		}
+	}
+	content.ingredients = ingredients
+
+	instructions := []contentSection{{}}
+	for _, step := range source.RecipeInstructions {
+		if title := strings.TrimSpace(step.Title); title != "" {
+			instructions = append(instructions, contentSection{name: title})
+		}
+		if text := strings.Join(textParagraphs(step.Text), " "); text != "" {
+			instructions[len(instructions)-1].items = append(instructions[len(instructions)-1].items, text)
+		}
+	}
+	content.instructions = instructions
+
+	for _, note := range source.Notes {
+		text := strings.Join(textParagraphs(note.Text), " ")
+		if title := strings.TrimSpace(note.Title); title != "" {
+			text = "**" + title + "**: " + text
+		}
+		if text != "" {
+			content.notes = append(content.notes, text)
+		}
+	}
+
+	cookTime := source.PerformTime
+	if cookTime == "" {
+		cookTime = source.CookTime
+	}
+	recipe := &models.ImportedRecipe{
+		Recipe: models.CreateRecipeRequest{
+			Title:           strings.TrimSpace(source.Name),
+			MarkdownContent: content.markdown(),
+			PrepTimeMinutes: parseDurationText(source.PrepTime),
+			CookTimeMinutes: parseDurationText(cookTime),
+			Servings:        schemaYield(source.RecipeServings),
+		},
+	}
+	if recipe.Recipe.Servings == nil {
+		recipe.Recipe.Servings = schemaYield(source.RecipeYield)
+	}
+	if recipe.Recipe.CookTimeMinutes == nil {
+		recipe.Recipe.CookTimeMinutes = remainingTime(parseDurationText(source.TotalTime), recipe.Recipe.PrepTimeMinutes)
+	}
+	if description := strings.TrimSpace(source.Description); description != "" {
+		recipe.Recipe.Description = &description
+	}
+	setTaxonomy(recipe, schemaTexts(source.RecipeCategory), schemaTexts(source.Tags))
+	return recipe
+}
+
+// text is the ingredient line as Mealie displays it, or as it was typed,
+// falling back to the parsed quantity, unit, food and note.
+func (i mealieIngredient) text() string {
+	for _, text := range []string{i.Display, i.OriginalText} {
+		if text = strings.Join(strings.Fields(text), " "); text != "" {
+			return text
+		}
+	}
+
+	var parts []string
+	if i.Quantity > 0 {
+		parts = append(parts, FormatQuantity(i.Quantity))
+	}
+	parts = append(parts, schemaTexts(i.Unit)...)
+	parts = append(parts, schemaTexts(i.Food)...)
+	text := strings.Join(parts, " ")
+	if note := strings.TrimSpace(i.Note); note != "" {
+		if text != "" {
+			return text + ", " + note
+		}
+		return note
+	}
+	return text
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+var (
+	mealMasterHeaderPattern    = regexp.MustCompile(`(?i)^(?:MMMMM|-----)-*\s*recipe via meal-?master`)
+	mealMasterEndPattern       = regexp.MustCompile(`^(?:MMMMM|-----)\s*$`)
+	mealMasterSeparatorPattern = regexp.MustCompile(`^(?:MMMMM|-----)-*\s*(.*?)\s*-*\s*$`)
+	mealMasterFieldPattern     = regexp.MustCompile(`(?i)^\s*(title|categories|yield|servings)\s*:\s*(.*)$`)
+	mealMasterQuantityPattern  = regexp.MustCompile(`^[\d\s/.-]*$`)
+)
+
+// mealMasterUnits spells out MealMaster's two-letter unit codes. Codes that
+// add nothing to the ingredient, such as "ea", map to nothing.
+var mealMasterUnits = map[string]string{
+	"x": "", "ea": "",
+	"sm": "small", "md": "medium", "lg": "large",
+	"cn": "can", "pk": "package", "pn": "pinch", "dr": "drop", "ds": "dash",
+	"ct": "carton", "bn": "bunch", "sl": "slice",
+	"t": "tsp", "ts": "tsp", "T": "tbsp", "tb": "tbsp",
+	"fl": "fl oz", "c": "cup", "pt": "pint", "qt": "quart", "ga": "gallon",
+	"oz": "oz", "lb": "lb",
+	"ml": "ml", "cb": "cc", "cl": "cl", "dl": "dl", "l": "l",
+	"mg": "mg", "cg": "cg", "dg": "dg", "g": "g", "kg": "kg",
+}
+
+// mealMasterImporter reads MealMaster text files, which hold any number of
+// recipes between "MMMMM" or "-----" header and footer lines. Ingredients
+// are laid out in columns: a quantity in the first seven, a unit code in
+// the two after and the ingredient from the twelfth on, sometimes in two
+// columns side by side.
+type mealMasterImporter struct{}
+
+func (mealMasterImporter) Format() string {
+	return "mealmaster"
+}
+
+func (mealMasterImporter) Import(name string, data []byte) ([]*models.ImportedRecipe, error) {
+	var recipes []*models.ImportedRecipe
+	var current *mealMasterRecipe
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " \t\r\x1a")
+		switch {
+		case mealMasterHeaderPattern.MatchString(line):
+			if current != nil {
+				recipes = append(recipes, current.recipe())
+			}
+			current = &mealMasterRecipe{ingredients: []contentSection{{}}}
+		case current == nil:
+		case mealMasterEndPattern.MatchString(line):
+			recipes = append(recipes, current.recipe())
+			current = nil
+		default:
+			current.addLine(line)
+		}
+	}
+	if current != nil {
+		recipes = append(recipes, current.recipe())
+	}
+	return recipes, nil
+}
+
+// mealMasterRecipe collects a recipe's lines as they are read.
+type mealMasterRecipe struct {
+	title       string
+	categories  []string
+	yield       string
+	ingredients []contentSection
+	directions  []string
+	inDirection bool
+}
+
+func (m *mealMasterRecipe) addLine(line string) {
+	if m.inDirection {
+		if match := mealMasterSeparatorPattern.FindStringSubmatch(line); match != nil && match[1] != "" {
+			line = match[1] + ":"
+		}
+		m.directions = append(m.directions, line)
+		return
+	}
+
+	if match := mealMasterFieldPattern.FindStringSubmatch(line); match != nil && len(m.ingredients) == 1 && len(m.ingredients[0].items) == 0 {
+		value := strings.TrimSpace(match[2])
+		switch strings.ToLower(match[1]) {
+		case "title":
+			m.title = value
+		case "categories":
+			m.categories = append(m.categories, strings.Split(value, ",")...)
+		default:
+			m.yield = value
+		}
+		return
+	}
+	if match := mealMasterSeparatorPattern.FindStringSubmatch(line); match != nil {
+		m.ingredients = append(m.ingredients, contentSection{name: titleCase(match[1])})
+		return
+	}
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	left, right := line, ""
+	if len(line) > 41 && line[40] == ' ' && isMealMasterIngredient(line[41:]) {
+		left, right = line[:40], line[41:]
+	}
+	if !isMealMasterIngredient(left) {
+		m.inDirection = true
+		m.directions = append(m.directions, line)
+		return
+	}
+	m.addIngredient(left)
+	if right != "" {
+		m.addIngredient(right)
+	}
+}
+
+func (m *mealMasterRecipe) addIngredient(line string) {
+	line += strings.Repeat(" ", 11)
+	quantity := strings.TrimSpace(line[:7])
+	unit := strings.TrimSpace(line[8:10])
+	text := strings.ReplaceAll(strings.Join(strings.Fields(line[11:]), " "), "; ", ", ")
+	if text == "" && quantity == "" {
+		return
+	}
+
+	section := &m.ingredients[len(m.ingredients)-1]
+	if quantity == "" && unit == "" && strings.HasPrefix(text, "-") && len(section.items) > 0 {
+		section.items[len(section.items)-1] += " " + strings.TrimSpace(strings.TrimPrefix(text, "-"))
+		return
+	}
+
+	var parts []string
+	for _, part := range []string{quantity, mealMasterUnits[unit], text} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	section.items = append(section.items, strings.Join(parts, " "))
+}
+
+// isMealMasterIngredient reports whether line fits the ingredient columns:
+// a numeric quantity, a known unit code and blanks between them.
+func isMealMasterIngredient(line string) bool {
+	if len(line) < 12 {
+		return false
+	}
+	if line[7] != ' ' || line[10] != ' ' || !mealMasterQuantityPattern.MatchString(line[:7]) {
+		return false
+	}
+	_, known := mealMasterUnits[strings.TrimSpace(line[8:10])]
+	return known || strings.TrimSpace(line[8:10]) == ""
+}
+
+func (m *mealMasterRecipe) recipe() *models.ImportedRecipe {
+	content := importedContent{ingredients: m.ingredients}
+	steps := contentSection{}
+	for _, paragraph := range textParagraphs(strings.Join(m.directions, "\n")) {
+		if step := stepNumberPattern.ReplaceAllString(paragraph, ""); step != "" {
+			steps.items = append(steps.items, step)
+		}
+	}
+	content.instructions = []contentSection{steps}
+
+	recipe := &models.ImportedRecipe{
+		Recipe: models.CreateRecipeRequest{
+			Title:           m.title,
+			MarkdownContent: content.markdown(),
+			Servings:        schemaYield(m.yield),
+		},
+	}
+	var categories []string
+	for _, category := range m.categories {
+		if category = strings.TrimSpace(category); category != "" && !strings.EqualFold(category, "none") {
+			categories = append(categories, category)
+		}
+	}
+	setTaxonomy(recipe, categories, nil)
+	return recipe
+}
+
+// titleCase turns the upper-case section names MealMaster files use into
+// "Title Case".
+func titleCase(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(first)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
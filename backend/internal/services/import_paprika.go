@@ -0,0 +1,193 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+// paprikaImporter reads Paprika exports: a .paprikarecipes zip holding one
+// gzipped JSON .paprikarecipe file per recipe, or a single such file.
+type paprikaImporter struct{}
+
+type paprikaRecipe struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Ingredients string         `json:"ingredients"`
+	Directions  string         `json:"directions"`
+	Notes       string         `json:"notes"`
+	Servings    string         `json:"servings"`
+	PrepTime    string         `json:"prep_time"`
+	CookTime    string         `json:"cook_time"`
+	TotalTime   string         `json:"total_time"`
+	Difficulty  string         `json:"difficulty"`
+	Source      string         `json:"source"`
+	SourceURL   string         `json:"source_url"`
+	Categories  []string       `json:"categories"`
+	PhotoData   string         `json:"photo_data"`
+	Photos      []paprikaPhoto `json:"photos"`
+}
+
+type paprikaPhoto struct {
+	Data string `json:"data"`
+}
+
+func (paprikaImporter) Format() string {
+	return "paprika"
+}
+
+func (p paprikaImporter) Import(name string, data []byte) ([]*models.ImportedRecipe, error) {
+	remaining := int64(maxArchiveSize)
+	if !isZip(data) {
+		recipe, err := p.importRecipe(data, &remaining)
+		if err != nil {
+			return nil, err
+		}
+		return []*models.ImportedRecipe{recipe}, nil
+	}
+
+	entries, err := readZip(data)
+	if err != nil {
+		return nil, err
+	}
+	var recipes []*models.ImportedRecipe
+	for _, entry := range entries {
+		if !strings.HasSuffix(strings.ToLower(entry.name), ".paprikarecipe") {
+			continue
+		}
+		recipe, err := p.importRecipe(entry.data, &remaining)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.name, err)
+		}
+		recipes = append(recipes, recipe)
+	}
+	return recipes, nil
+}
+
+// importRecipe reads a single recipe. Unzipping it counts against the
+// bytes remaining for the whole upload.
+func (paprikaImporter) importRecipe(data []byte, remaining *int64) (*models.ImportedRecipe, error) {
+	// Recipes are gzipped inside archives, but accept plain JSON too.
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Paprika recipe: %w", err)
+		}
+		data, err = readLimited(func() (io.ReadCloser, error) { return gz, nil }, min(maxArchiveEntrySize, *remaining))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Paprika recipe: %w", err)
+		}
+		*remaining -= int64(len(data))
+	}
+
+	var source paprikaRecipe
+	if err := json.Unmarshal(data, &source); err != nil {
+		return nil, fmt.Errorf("invalid Paprika recipe: %w", err)
+	}
+	if strings.TrimSpace(source.Name) == "" {
+		return nil, errors.New("invalid Paprika recipe: no name")
+	}
+
+	content := importedContent{
+		ingredients:  textSections(source.Ingredients),
+		instructions: textSections(source.Directions),
+		notes:        textParagraphs(source.Notes),
+		source:       source.SourceURL,
+	}
+	if content.source == "" {
+		content.source = source.Source
+	}
+
+	recipe := &models.ImportedRecipe{
+		Recipe: models.CreateRecipeRequest{
+			Title:           strings.TrimSpace(source.Name),
+			MarkdownContent: content.markdown(),
+			PrepTimeMinutes: parseDurationText(source.PrepTime),
+			CookTimeMinutes: parseDurationText(source.CookTime),
+			Servings:        schemaYield(source.Servings),
+		},
+	}
+	if description := strings.TrimSpace(source.Description); description != "" {
+		recipe.Recipe.Description = &description
+	}
+	if recipe.Recipe.CookTimeMinutes == nil {
+		recipe.Recipe.CookTimeMinutes = remainingTime(parseDurationText(source.TotalTime), recipe.Recipe.PrepTimeMinutes)
+	}
+	if difficulty := importedDifficulty(source.Difficulty); difficulty != "" {
+		recipe.Recipe.Difficulty = &difficulty
+	}
+	setTaxonomy(recipe, source.Categories, nil)
+
+	photos := []string{source.PhotoData}
+	for _, photo := range source.Photos {
+		photos = append(photos, photo.Data)
+	}
+	for _, photo := range photos {
+		if photo == "" {
+			continue
+		}
+		image, err := base64.StdEncoding.DecodeString(photo)
+		if err != nil {
+			recipe.Warnings = append(recipe.Warnings, "skipped an image that could not be decoded")
+			continue
+		}
+		addImage(recipe, image)
+	}
+	return recipe, nil
+}
+
+// textSections splits a block of ingredient or direction lines into
+// sections. A short line ending in a colon names the section after it;
+// step numbers and list markers are dropped.
+func textSections(text string) []contentSection {
+	sections := []contentSection{{}}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		line = stepNumberPattern.ReplaceAllString(listMarkerPattern.ReplaceAllString(line, ""), "")
+		if line == "" {
+			continue
+		}
+		if name, ok := strings.CutSuffix(line, ":"); ok && len(strings.Fields(name)) <= 5 {
+			sections = append(sections, contentSection{name: name})
+			continue
+		}
+		sections[len(sections)-1].items = append(sections[len(sections)-1].items, line)
+	}
+	return sections
+}
+
+// remainingTime is the cook time left when an export only gives the total
+// time: the total minus the prep time.
+func remainingTime(total, prep *int32) *int32 {
+	if total == nil {
+		return nil
+	}
+	cook := *total
+	if prep != nil {
+		cook -= *prep
+	}
+	if cook <= 0 {
+		return nil
+	}
+	return &cook
+}
+
+// importedDifficulty maps a source's difficulty onto easy, medium or hard.
+func importedDifficulty(difficulty string) string {
+	switch strings.ToLower(strings.TrimSpace(difficulty)) {
+	case "easy", "simple", "beginner":
+		return "easy"
+	case "medium", "moderate", "intermediate":
+		return "medium"
+	case "hard", "difficult", "advanced":
+		return "hard"
+	}
+	return ""
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/homecooking/backend/internal/models"
+	"golang.org/x/net/html"
+)
+
+// Limits on what an import fetches.
+const (
+	importTimeout      = 20 * time.Second
+	maxImportPageBytes = 5 << 20
+)
+
+const importUserAgent = "HomeCooking/1.0 (+recipe import)"
+
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type ImportService struct {
+	recipeService   *RecipeService
+	categoryService *CategoryService
+	storageService  *StorageService
+	client          *http.Client
+}
+
+func NewImportService(recipeService *RecipeService, categoryService *CategoryService, storageService *StorageService) *ImportService {
+	return &ImportService{
+		recipeService:   recipeService,
+		categoryService: categoryService,
+		storageService:  storageService,
+		client:          newImportClient(),
+	}
+}
+
+// newImportClient returns an HTTP client that only connects to public
+// addresses, so a submitted URL can't be used to reach the server's own
+// network.
+func newImportClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+				return fmt.Errorf("refusing to connect to %s", host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   importTimeout,
+		Transport: transport,
+	}
+}
+
+// ImportURL fetches a recipe page and saves the schema.org Recipe it
+// describes as an unpublished recipe of the author, so it can be reviewed
+// before it is shared. The recipe's image becomes the featured image when it
+// can be downloaded; an image that can't be is left out.
+func (s *ImportService) ImportURL(rawURL string, authorID string) (*models.Recipe, error) {
+	pageURL, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		return nil, errors.New("invalid URL")
+	}
+
+	doc, err := s.fetchPage(pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, recipe := range findSchemaRecipes(doc) {
+		req, ok := schemaRecipeRequest(recipe, pageURL)
+		if !ok {
+			continue
+		}
+		if req.Title == "" {
+			req.Title = pageTitle(doc)
+		}
+		if imageURL := schemaImageURL(recipe, pageURL); imageURL != "" {
+			if filename, err := s.downloadImage(imageURL); err == nil {
+				imagePath := "/uploads/" + filename
+				req.FeaturedImagePath = &imagePath
+			}
+		}
+		return s.recipeService.CreateRecipe(req, authorID)
+	}
+	return nil, errors.New("no recipe found on page")
+}
+
+// ImportFile reads an export of another recipe manager. A dry run returns
+// the recipes as they would be saved; otherwise each is saved for the
+// author, unpublished unless req.Publish is set. Categories are matched by
+// slug and created when missing. A recipe that can't be saved is reported
+// in the result without stopping the others.
+func (s *ImportService) ImportFile(req *models.ImportFileRequest, data []byte, authorID string) (*models.ImportResult, error) {
+	importer, err := recipeImporter(req.Format, req.Filename)
+	if err != nil {
+		return nil, err
+	}
+	recipes, err := importer.Import(req.Filename, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(recipes) == 0 {
+		return nil, errors.New("no recipes found in file")
+	}
+
+	result := &models.ImportResult{Format: importer.Format(), DryRun: req.DryRun, Recipes: []models.ImportItem{}}
+	categories := map[string]string{}
+	for _, recipe := range recipes {
+		recipe.Recipe.IsPublished = req.Publish
+		item := models.ImportItem{
+			Recipe:     recipe.Recipe,
+			Category:   recipe.Category,
+			ImageCount: len(recipe.Images),
+			Warnings:   recipe.Warnings,
+		}
+		if !req.DryRun {
+			created, err := s.saveImported(recipe, categories, authorID)
+			if err != nil {
+				item.Error = err.Error()
+				result.Failed++
+			} else {
+				item.Created = created
+				result.Imported++
+			}
+		}
+		result.Recipes = append(result.Recipes, item)
+	}
+	return result, nil
+}
+
+// saveImported stores the recipe's images, files it in its category and
+// creates it. The first image becomes the featured image; the rest are
+// added to the end of the recipe. categories caches category IDs by slug.
+func (s *ImportService) saveImported(recipe *models.ImportedRecipe, categories map[string]string, authorID string) (*models.Recipe, error) {
+	req := recipe.Recipe
+	if recipe.Category != "" {
+		categoryID, err := s.importCategory(recipe.Category, categories)
+		if err != nil {
+			return nil, err
+		}
+		req.CategoryID = &categoryID
+	}
+
+	var photos []string
+	for _, image := range recipe.Images {
+		filename, err := s.storageService.SaveImageFrom(bytes.NewReader(image.Data), image.Ext, "recipe")
+		if err != nil {
+			continue
+		}
+		imagePath := "/uploads/" + filename
+		if req.FeaturedImagePath == nil {
+			req.FeaturedImagePath = &imagePath
+		} else {
+			photos = append(photos, "![]("+imagePath+")")
+		}
+	}
+	if len(photos) > 0 {
+		req.MarkdownContent = strings.TrimRight(req.MarkdownContent, "\n") + "\n\n## Photos\n\n" + strings.Join(photos, "\n\n") + "\n"
+	}
+
+	return s.recipeService.CreateRecipe(&req, authorID)
+}
+
+// importCategory returns the ID of the category named name, creating it
+// when no category has its slug.
+func (s *ImportService) importCategory(name string, categories map[string]string) (string, error) {
+	slug := generateSlug(name)
+	if id, ok := categories[slug]; ok {
+		return id, nil
+	}
+
+	category, err := s.categoryService.GetBySlug(slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		category, err = s.categoryService.CreateCategory(&models.Category{Name: name})
+	}
+	if err != nil {
+		return "", err
+	}
+	categories[slug] = category.ID.String()
+	return categories[slug], nil
+}
+
+func (s *ImportService) fetchPage(pageURL *url.URL) (*html.Node, error) {
+	resp, err := s.get(pageURL.String(), "text/html,application/xhtml+xml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, maxImportPageBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	return doc, nil
+}
+
+// downloadImage stores the image at imageURL and returns its filename.
+func (s *ImportService) downloadImage(imageURL string) (string, error) {
+	resp, err := s.get(imageURL, "image/*")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	ext, ok := imageExtensions[mediaType]
+	if !ok {
+		ext = strings.ToLower(path.Ext(resp.Request.URL.Path))
+	}
+	return s.storageService.SaveImageFrom(resp.Body, ext, "recipe")
+}
+
+func (s *ImportService) get(rawURL, accept string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", importUserAgent)
+	req.Header.Set("Accept", accept)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+)
+
+const jsonLDRecipePage = `<!DOCTYPE html>
+<html><head><title>Grandma's Pancakes | Some Blog</title>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@graph": [
+    {"@type": "WebPage", "name": "Grandma's Pancakes"},
+    {
+      "@type": ["Recipe"],
+      "name": "Grandma&#39;s Pancakes",
+      "description": "<p>Fluffy &amp; light.</p>",
+      "image": [{"@type": "ImageObject", "url": "/images/pancakes.png"}],
+      "recipeYield": ["4", "4 servings"],
+      "prepTime": "PT10M",
+      "totalTime": "PT25M",
+      "recipeCategory": "Breakfast",
+      "recipeCuisine": ["American", "breakfast"],
+      "recipeIngredient": ["2 cups flour", "2 eggs", "1 1/2 cups milk"],
+      "recipeInstructions": [
+        {
+          "@type": "HowToSection",
+          "name": "Batter",
+          "itemListElement": [
+            {"@type": "HowToStep", "text": "Whisk the flour and eggs."},
+            {"@type": "HowToStep", "text": "Stir in the milk."}
+          ]
+        },
+        {
+          "@type": "HowToSection",
+          "name": "Cooking",
+          "itemListElement": [{"@type": "HowToStep", "text": "Fry in a hot pan."}]
+        }
+      ]
+    }
+  ]
+}
+</script></head><body><h1>Grandma's Pancakes</h1></body></html>`
+
+func TestSchemaRecipeRequest_JSONLD(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(jsonLDRecipePage))
+	require.NoError(t, err)
+	pageURL, _ := url.Parse("https://example.com/recipes/pancakes")
+
+	recipes := findSchemaRecipes(doc)
+	require.Len(t, recipes, 1)
+
+	req, ok := schemaRecipeRequest(recipes[0], pageURL)
+	require.True(t, ok)
+	assert.Equal(t, "Grandma's Pancakes", req.Title)
+	require.NotNil(t, req.Description)
+	assert.Equal(t, "Fluffy & light.", *req.Description)
+	assert.Equal(t, int32(4), *req.Servings)
+	assert.Equal(t, int32(10), *req.PrepTimeMinutes)
+	assert.Equal(t, int32(15), *req.CookTimeMinutes)
+	assert.Equal(t, []string{"Breakfast", "American"}, req.Tags)
+	assert.False(t, req.IsPublished)
+	assert.Equal(t, `## Ingredients
+
+- 2 cups flour
+- 2 eggs
+- 1 1/2 cups milk
+
+## Instructions
+
+### Batter
+
+1. Whisk the flour and eggs.
+2. Stir in the milk.
+
+### Cooking
+
+1. Fry in a hot pan.
+
+Source: <https://example.com/recipes/pancakes>
+`, req.MarkdownContent)
+
+	assert.Equal(t, "https://example.com/images/pancakes.png", schemaImageURL(recipes[0], pageURL))
+}
+
+func TestSchemaRecipeRequest_Microdata(t *testing.T) {
+	page := `<html><body>
+<div itemscope itemtype="http://schema.org/Recipe">
+  <h1 itemprop="name">Tomato Soup</h1>
+  <img itemprop="image" src="https://cdn.example.com/soup.jpg">
+  <meta itemprop="cookTime" content="PT1H">
+  <span itemprop="recipeYield">Serves 6-8</span>
+  <ul>
+    <li itemprop="recipeIngredient">1 kg tomatoes</li>
+    <li itemprop="recipeIngredient">1 onion</li>
+  </ul>
+  <div itemprop="author" itemscope itemtype="http://schema.org/Person"><span itemprop="name">Ann</span></div>
+  <ol itemprop="recipeInstructions">
+    <li>1. Roast the tomatoes.</li>
+    <li>Step 2: Blend with the onion.</li>
+  </ol>
+</div>
+</body></html>`
+	doc, err := html.Parse(strings.NewReader(page))
+	require.NoError(t, err)
+	pageURL, _ := url.Parse("https://example.com/soup")
+
+	recipes := findSchemaRecipes(doc)
+	require.Len(t, recipes, 1)
+	assert.Equal(t, "Tomato Soup", recipes[0]["name"])
+
+	req, ok := schemaRecipeRequest(recipes[0], pageURL)
+	require.True(t, ok)
+	assert.Equal(t, "Tomato Soup", req.Title)
+	assert.Equal(t, int32(6), *req.Servings)
+	assert.Equal(t, int32(60), *req.CookTimeMinutes)
+	assert.Nil(t, req.PrepTimeMinutes)
+	assert.Contains(t, req.MarkdownContent, "- 1 kg tomatoes\n- 1 onion\n")
+	assert.Contains(t, req.MarkdownContent, "1. Roast the tomatoes.\n2. Blend with the onion.\n")
+	assert.Equal(t, "https://cdn.example.com/soup.jpg", schemaImageURL(recipes[0], pageURL))
+}
+
+func TestSchemaInstructions_Text(t *testing.T) {
+	sections := schemaInstructions("<ol><li>Boil water.</li><li>Add pasta.</li></ol>")
+	require.Len(t, sections, 1)
+	assert.Equal(t, []string{"Boil water.", "Add pasta."}, sections[0].items)
+
+	sections = schemaInstructions([]interface{}{"Mix.\nBake.", map[string]interface{}{"@type": "HowToStep", "name": "Cool."}})
+	require.Len(t, sections, 1)
+	assert.Equal(t, []string{"Mix.", "Bake.", "Cool."}, sections[0].items)
+}
+
+func TestIsoDurationMinutes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected *int32
+	}{
+		{"PT15M", int32Ptr(15)},
+		{"PT1H30M", int32Ptr(90)},
+		{"P0DT2H", int32Ptr(120)},
+		{"pt45s", int32Ptr(1)},
+		{"PT0M", nil},
+		{"PT", nil},
+		{"15 minutes", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isoDurationMinutes(tt.input))
+		})
+	}
+}
+
+func TestImportService_ImportURL(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	var pngData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/recipes/pancakes":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(jsonLDRecipePage))
+		case "/images/pancakes.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(pngData.Bytes())
+		case "/blog":
+			w.Write([]byte("<html><body><p>Just a story.</p></body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	uploads := t.TempDir()
+	service := NewImportService(newTestRecipeService(db, q), nil, NewStorageService(uploads, 10*1024*1024))
+	service.client = server.Client()
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	recipe, err := service.ImportURL(server.URL+"/recipes/pancakes", authorID)
+	require.NoError(t, err)
+	assert.Equal(t, "Grandma's Pancakes", recipe.Title)
+	assert.Equal(t, "grandmas-pancakes", recipe.Slug)
+	assert.False(t, recipe.IsPublished)
+	assert.Len(t, recipe.Ingredients, 3)
+
+	require.NotNil(t, recipe.FeaturedImagePath)
+	assert.True(t, strings.HasPrefix(*recipe.FeaturedImagePath, "/uploads/recipe_"))
+	_, err = os.Stat(filepath.Join(uploads, strings.TrimPrefix(*recipe.FeaturedImagePath, "/uploads/")))
+	assert.NoError(t, err)
+
+	tags, err := repository.NewTagRepository(db, q).GetRecipeTags(recipe.ID.String())
+	require.NoError(t, err)
+	assert.Len(t, tags, 2)
+
+	_, err = service.ImportURL(server.URL+"/blog", authorID)
+	assert.EqualError(t, err, "no recipe found on page")
+
+	_, err = service.ImportURL(server.URL+"/missing", authorID)
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "failed to fetch page"))
+
+	_, err = service.ImportURL("file:///etc/passwd", authorID)
+	assert.EqualError(t, err, "invalid URL")
+}
+
+func TestImportService_RefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(jsonLDRecipePage))
+	}))
+	defer server.Close()
+
+	service := NewImportService(nil, nil, nil)
+	_, err := service.ImportURL(server.URL, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refusing to connect")
+}
+
+func TestImportService_ImportFile(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	recipeService := newTestRecipeService(db, q)
+	categoryService := NewCategoryService(repository.NewCategoryRepository(db, q), repository.NewRecipeRepository(db, q))
+	service := NewImportService(recipeService, categoryService, NewStorageService(t.TempDir(), 10*1024*1024))
+
+	authorID := createTestUser(db, q, "test@example.com")
+	desserts, err := categoryService.CreateCategory(&models.Category{Name: "Desserts"})
+	require.NoError(t, err)
+
+	untitled := "MMMMM----- Recipe via Meal-Master (tm) v8.05\n\n      1    Mystery\n\n  Stir.\n\nMMMMM\n"
+	data := []byte(mealMasterFile + untitled)
+
+	preview, err := service.ImportFile(&models.ImportFileRequest{Filename: "recipes.mmf", DryRun: true}, data, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, "mealmaster", preview.Format)
+	assert.True(t, preview.DryRun)
+	require.Len(t, preview.Recipes, 3)
+	assert.Equal(t, "Apple Crumble", preview.Recipes[0].Recipe.Title)
+	assert.Equal(t, "Desserts", preview.Recipes[0].Category)
+	assert.Nil(t, preview.Recipes[0].Created)
+	assert.Zero(t, preview.Imported)
+
+	recipes, err := recipeService.ListRecipes(10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, recipes)
+
+	result, err := service.ImportFile(&models.ImportFileRequest{Filename: "recipes.mmf", Publish: true}, data, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, "title is required", result.Recipes[2].Error)
+
+	crumble := result.Recipes[0].Created
+	require.NotNil(t, crumble)
+	assert.True(t, crumble.IsPublished)
+	require.NotNil(t, crumble.CategoryID)
+	assert.Equal(t, desserts.ID, *crumble.CategoryID)
+
+	photo := base64.StdEncoding.EncodeToString(testPNG(t))
+	paprika := []byte(`{"name": "Scones", "directions": "Bake.", "categories": ["Baking"],
+		"photo_data": "` + photo + `", "photos": [{"data": "` + photo + `"}]}`)
+	result, err = service.ImportFile(&models.ImportFileRequest{Format: "paprika", Filename: "Scones.paprikarecipe"}, paprika, authorID)
+	require.NoError(t, err)
+	scones := result.Recipes[0].Created
+	require.NotNil(t, scones)
+	assert.False(t, scones.IsPublished)
+	require.NotNil(t, scones.FeaturedImagePath)
+	assert.Contains(t, scones.MarkdownContent, "## Photos\n\n![](/uploads/recipe_")
+
+	baking, err := categoryService.GetBySlug("baking")
+	require.NoError(t, err)
+	assert.Equal(t, baking.ID, *scones.CategoryID)
+
+	_, err = service.ImportFile(&models.ImportFileRequest{Filename: "notes.txt"}, []byte("Just notes."), authorID)
+	assert.EqualError(t, err, "no recipes found in file")
+
+	_, err = service.ImportFile(&models.ImportFileRequest{Filename: "recipes.pdf"}, data, authorID)
+	assert.EqualError(t, err, "unsupported import format")
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+const vulgarFractionChars = "¼½¾⅓⅔⅕⅖⅗⅘⅙⅚⅛⅜⅝⅞"
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	listMarkerPattern  = regexp.MustCompile(`^(?:[-*+]|\d+[.)])(?:\s+|$)(?:\[[ xX]\]\s*)?`)
+	numberPattern      = `(\d+\s*[` + vulgarFractionChars + `]|[` + vulgarFractionChars + `]|\d+\s+\d+[/⁄]\d+|\d+[/⁄]\d+|\d*\.\d+|\d+)`
+	rangePattern       = regexp.MustCompile(`^` + numberPattern + `\s*(?:-|–|—|to)\s*` + numberPattern)
+	singleQtyPattern   = regexp.MustCompile(`^` + numberPattern)
+	leadingParenthesis = regexp.MustCompile(`^\(([^)]*)\)\s*`)
+	innerParenthesis   = regexp.MustCompile(`\s*\(([^)]*)\)`)
+)
+
+var vulgarFractions = map[rune]string{
+	'¼': "1/4", '½': "1/2", '¾': "3/4",
+	'⅓': "1/3", '⅔': "2/3",
+	'⅕': "1/5", '⅖': "2/5", '⅗': "3/5", '⅘': "4/5",
+	'⅙': "1/6", '⅚': "5/6",
+	'⅛': "1/8", '⅜': "3/8", '⅝': "5/8", '⅞': "7/8",
+}
+
+// unitAliases maps every spelling we accept to its canonical unit name.
+var unitAliases = map[string]string{
+	"tsp": "tsp", "tsps": "tsp", "teaspoon": "tsp", "teaspoons": "tsp",
+	"tbsp": "tbsp", "tbsps": "tbsp", "tbs": "tbsp", "tbl": "tbsp", "tablespoon": "tbsp", "tablespoons": "tbsp",
+	"cup": "cup", "cups": "cup", "c": "cup",
+	"fl oz": "fl oz", "fluid ounce": "fl oz", "fluid ounces": "fl oz",
+	"pt": "pint", "pint": "pint", "pints": "pint",
+	"qt": "quart", "quart": "quart", "quarts": "quart",
+	"gal": "gallon", "gallon": "gallon", "gallons": "gallon",
+	"ml": "ml", "milliliter": "ml", "milliliters": "ml", "millilitre": "ml", "millilitres": "ml",
+	"l": "l", "liter": "l", "liters": "l", "litre": "l", "litres": "l",
+	"g": "g", "gram": "g", "grams": "g", "gramme": "g", "grammes": "g",
+	"kg": "kg", "kilogram": "kg", "kilograms": "kg",
+	"oz": "oz", "ounce": "oz", "ounces": "oz",
+	"lb": "lb", "lbs": "lb", "pound": "lb", "pounds": "lb",
+	"pinch": "pinch", "pinches": "pinch",
+	"dash": "dash", "dashes": "dash",
+	"clove": "clove", "cloves": "clove",
+	"can": "can", "cans": "can",
+	"package": "package", "packages": "package", "pkg": "package",
+	"stick": "stick", "sticks": "stick",
+	"slice": "slice", "slices": "slice",
+	"bunch": "bunch", "bunches": "bunch",
+	"sprig": "sprig", "sprigs": "sprig",
+	"handful": "handful", "handfuls": "handful",
+	"head": "head", "heads": "head",
+}
+
+// ParseIngredients extracts the structured ingredient list from the
+// "## Ingredients" section of a recipe's markdown content. Lines outside that
+// section are ignored; sub-headings inside it (e.g. "### For the crust") are
+// skipped.
+func ParseIngredients(markdown string) []models.RecipeIngredient {
+	lines := strings.Split(markdown, "\n")
+	start, end := ingredientSection(lines)
+
+	var ingredients []models.RecipeIngredient
+	for _, line := range lines[start:end] {
+		if headingPattern.MatchString(strings.TrimSpace(line)) {
+			continue
+		}
+		ingredient, ok := parseIngredientLine(line)
+		if !ok {
+			continue
+		}
+		ingredient.OrderIndex = len(ingredients)
+		ingredients = append(ingredients, ingredient)
+	}
+	return ingredients
+}
+
+// ingredientSection returns the half-open range of line indexes between the
+// ingredients heading and the next heading of the same or a higher level.
+// Both bounds are zero when there is no ingredients section.
+func ingredientSection(lines []string) (int, int) {
+	return markdownSection(lines, isIngredientsHeading)
+}
+
+// markdownSection returns the range of lines below the first heading whose
+// title satisfies isHeading, up to the next heading of the same or a higher
+// level. Both bounds are zero when no heading matches.
+func markdownSection(lines []string, isHeading func(string) bool) (int, int) {
+	start, sectionLevel := 0, 0
+
+	for i, line := range lines {
+		m := headingPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		level := len(m[1])
+		if sectionLevel > 0 && level <= sectionLevel {
+			return start, i
+		}
+		if sectionLevel == 0 && isHeading(m[2]) {
+			start, sectionLevel = i+1, level
+		}
+	}
+
+	if sectionLevel == 0 {
+		return 0, 0
+	}
+	return start, len(lines)
+}
+
+func isIngredientsHeading(title string) bool {
+	title = strings.ToLower(strings.TrimSpace(title))
+	return strings.HasPrefix(title, "ingredient")
+}
+
+// parseIngredientLine splits one ingredient line into quantity, unit, name and
+// preparation note. It reports false for blank lines.
+func parseIngredientLine(line string) (models.RecipeIngredient, bool) {
+	text := strings.TrimSpace(listMarkerPattern.ReplaceAllString(strings.TrimSpace(line), ""))
+	if text == "" {
+		return models.RecipeIngredient{}, false
+	}
+
+	quantity, quantityMax, rest := splitQuantity(text)
+	rest = strings.TrimSpace(rest)
+
+	ingredient := models.RecipeIngredient{
+		RawText:     text,
+		Quantity:    quantity,
+		QuantityMax: quantityMax,
+	}
+	var notes []string
+
+	if ingredient.Quantity != nil {
+		// A package size directly after the quantity, as in "1 (14 oz) can".
+		if m := leadingParenthesis.FindStringSubmatch(rest); m != nil {
+			notes = append(notes, strings.TrimSpace(m[1]))
+			rest = rest[len(m[0]):]
+		}
+		if unit, consumed := matchUnit(rest); unit != "" {
+			ingredient.Unit = &unit
+			rest = strings.TrimSpace(rest[consumed:])
+		}
+	}
+
+	rest = strings.TrimPrefix(rest, "of ")
+
+	name := rest
+	if idx := strings.Index(rest, ","); idx >= 0 {
+		name = rest[:idx]
+		if note := strings.TrimSpace(rest[idx+1:]); note != "" {
+			notes = append(notes, note)
+		}
+	}
+	for _, m := range innerParenthesis.FindAllStringSubmatch(name, -1) {
+		if note := strings.TrimSpace(m[1]); note != "" {
+			notes = append(notes, note)
+		}
+	}
+	name = strings.TrimSpace(innerParenthesis.ReplaceAllString(name, ""))
+
+	if name == "" {
+		name = text
+	}
+	ingredient.Name = name
+	if len(notes) > 0 {
+		note := strings.Join(notes, ", ")
+		ingredient.Note = &note
+	}
+
+	return ingredient, true
+}
+
+// splitQuantity separates a leading quantity or quantity range from the rest
+// of an ingredient line. The remainder is returned untrimmed so callers can
+// rebuild the line around a different quantity.
+func splitQuantity(text string) (*float64, *float64, string) {
+	if m := rangePattern.FindStringSubmatch(text); m != nil {
+		low, okLow := parseQuantity(m[1])
+		high, okHigh := parseQuantity(m[2])
+		if okLow && okHigh {
+			return &low, &high, text[len(m[0]):]
+		}
+	}
+	if m := singleQtyPattern.FindStringSubmatch(text); m != nil {
+		if qty, ok := parseQuantity(m[1]); ok {
+			return &qty, nil, text[len(m[0]):]
+		}
+	}
+	return nil, nil, text
+}
+
+// matchUnit checks whether s starts with a known unit (one or two words) and
+// returns the canonical unit and the number of bytes consumed.
+func matchUnit(s string) (string, int) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return "", 0
+	}
+
+	if len(fields) >= 2 {
+		twoWords := normalizeUnitToken(fields[0]) + " " + normalizeUnitToken(fields[1])
+		if unit, ok := unitAliases[twoWords]; ok {
+			return unit, consumedLength(s, fields[1])
+		}
+	}
+
+	// A lone capital "T" is the traditional abbreviation for tablespoon.
+	if strings.TrimSuffix(fields[0], ".") == "T" {
+		return "tbsp", consumedLength(s, fields[0])
+	}
+	if unit, ok := unitAliases[normalizeUnitToken(fields[0])]; ok {
+		return unit, consumedLength(s, fields[0])
+	}
+	return "", 0
+}
+
+func normalizeUnitToken(token string) string {
+	return strings.TrimSuffix(strings.ToLower(token), ".")
+}
+
+// consumedLength returns the byte offset just past the first occurrence of
+// field in s.
+func consumedLength(s, field string) int {
+	return strings.Index(s, field) + len(field)
+}
+
+// normalizeFractions rewrites unicode vulgar fractions ("1½") into ASCII
+// ("1 1/2") so parseQuantity only has to deal with one notation.
+func normalizeFractions(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if frac, ok := vulgarFractions[r]; ok {
+			b.WriteString(" " + frac)
+			continue
+		}
+		if r == '⁄' {
+			b.WriteRune('/')
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// parseQuantity converts "2", "1.5", "1/2", "1 1/2" and "1½" into a float.
+func parseQuantity(s string) (float64, bool) {
+	parts := strings.Fields(normalizeFractions(s))
+	total := 0.0
+	for _, part := range parts {
+		if num, den, found := strings.Cut(part, "/"); found {
+			n, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, false
+			}
+			d, err := strconv.ParseFloat(den, 64)
+			if err != nil || d == 0 {
+				return 0, false
+			}
+			total += n / d
+			continue
+		}
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, false
+		}
+		total += v
+	}
+	return total, len(parts) > 0
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func TestParseIngredientLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		quantity    *float64
+		quantityMax *float64
+		unit        *string
+		ingredient  string
+		note        *string
+	}{
+		{"simple", "- 2 cups flour", float64Ptr(2), nil, stringPtr("cup"), "flour", nil},
+		{"mixed fraction", "- 1 1/2 cups sugar", float64Ptr(1.5), nil, stringPtr("cup"), "sugar", nil},
+		{"fraction", "* 1/2 cup shredded cheese", float64Ptr(0.5), nil, stringPtr("cup"), "shredded cheese", nil},
+		{"unicode fraction", "- 1½ tsp salt", float64Ptr(1.5), nil, stringPtr("tsp"), "salt", nil},
+		{"decimal", "- 1.5 kg potatoes", float64Ptr(1.5), nil, stringPtr("kg"), "potatoes", nil},
+		{"attached unit", "- 200g butter", float64Ptr(200), nil, stringPtr("g"), "butter", nil},
+		{"range", "- 2-3 cloves garlic, minced", float64Ptr(2), float64Ptr(3), stringPtr("clove"), "garlic", stringPtr("minced")},
+		{"range with to", "- 1 to 2 tbsp olive oil", float64Ptr(1), float64Ptr(2), stringPtr("tbsp"), "olive oil", nil},
+		{"no unit", "- 3 eggs", float64Ptr(3), nil, nil, "eggs", nil},
+		{"no quantity", "- Salt and pepper", nil, nil, nil, "Salt and pepper", nil},
+		{"note after comma", "- Salt, to taste", nil, nil, nil, "Salt", stringPtr("to taste")},
+		{"package size", "- 1 (14 oz) can diced tomatoes", float64Ptr(1), nil, stringPtr("can"), "diced tomatoes", stringPtr("14 oz")},
+		{"parenthetical note", "- 1 cup walnuts (optional)", float64Ptr(1), nil, stringPtr("cup"), "walnuts", stringPtr("optional")},
+		{"two word unit", "- 4 fl oz cream", float64Ptr(4), nil, stringPtr("fl oz"), "cream", nil},
+		{"capital T", "- 2 T butter", float64Ptr(2), nil, stringPtr("tbsp"), "butter", nil},
+		{"abbreviation with dot", "- 1 Tbsp. honey", float64Ptr(1), nil, stringPtr("tbsp"), "honey", nil},
+		{"of prefix", "- 1 pinch of nutmeg", float64Ptr(1), nil, stringPtr("pinch"), "nutmeg", nil},
+		{"checkbox", "- [ ] 2 cups milk", float64Ptr(2), nil, stringPtr("cup"), "milk", nil},
+		{"numbered", "1. 8 oz pasta", float64Ptr(8), nil, stringPtr("oz"), "pasta", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ingredient, ok := parseIngredientLine(tt.line)
+			require.True(t, ok)
+			assert.Equal(t, tt.quantity, ingredient.Quantity)
+			assert.Equal(t, tt.quantityMax, ingredient.QuantityMax)
+			assert.Equal(t, tt.unit, ingredient.Unit)
+			assert.Equal(t, tt.ingredient, ingredient.Name)
+			assert.Equal(t, tt.note, ingredient.Note)
+		})
+	}
+}
+
+func TestParseIngredientLine_Blank(t *testing.T) {
+	_, ok := parseIngredientLine("   ")
+	assert.False(t, ok)
+
+	_, ok = parseIngredientLine("- ")
+	assert.False(t, ok)
+}
+
+func TestParseIngredients(t *testing.T) {
+	markdown := `# Pie
+
+Grandma's favourite.
+
+## Ingredients
+
+### For the crust
+- 2 cups flour
+- 1/2 cup butter, cold
+
+### For the filling
+- 6 apples, sliced
+
+## Instructions
+
+1. Mix the flour and butter.
+2. Bake for 45 minutes.`
+
+	ingredients := ParseIngredients(markdown)
+	require.Len(t, ingredients, 3)
+
+	assert.Equal(t, "flour", ingredients[0].Name)
+	assert.Equal(t, 0, ingredients[0].OrderIndex)
+	assert.Equal(t, "butter", ingredients[1].Name)
+	assert.Equal(t, "cold", *ingredients[1].Note)
+	assert.Equal(t, "apples", ingredients[2].Name)
+	assert.Equal(t, 2, ingredients[2].OrderIndex)
+	assert.Equal(t, "6 apples, sliced", ingredients[2].RawText)
+}
+
+func TestParseIngredients_NoSection(t *testing.T) {
+	assert.Empty(t, ParseIngredients("Ingredients: Flour, Eggs. Instructions: Mix and cook."))
+}
@@ -0,0 +1,891 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	horizontalRulePattern   = regexp.MustCompile(`^(?:-{3,}|\*{3,}|_{3,})$`)
+	fencePattern            = regexp.MustCompile("^(`{3,}|~{3,})\\s*([^`\\s]*)")
+	bulletMarkerPattern     = regexp.MustCompile(`^( {0,3})([-*+])(?:[ \t]+|$)`)
+	orderedMarkerPattern    = regexp.MustCompile(`^( {0,3})(\d{1,9})([.)])(?:[ \t]+|$)`)
+	taskItemPattern         = regexp.MustCompile(`^\[([ xX])\][ \t]+`)
+	htmlBlockPattern        = regexp.MustCompile(`^ {0,3}<(?:[A-Za-z][A-Za-z0-9-]*[\s/>]|[A-Za-z][A-Za-z0-9-]*$|/[A-Za-z]|!--)`)
+	closingHashesPattern    = regexp.MustCompile(`\s+#+\s*$`)
+	tableDelimiterPattern   = regexp.MustCompile(`^\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?$`)
+	entityPattern           = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+	inlineAutolinkPattern   = regexp.MustCompile(`^<((?:https?://|mailto:)[^\s<>]+)>`)
+	inlineRecipeLinkPattern = regexp.MustCompile(`^` + recipeLinkPattern.String())
+	inlineTagPattern        = regexp.MustCompile(`^(?:<[A-Za-z][A-Za-z0-9-]*(?:\s[^<>]*)?/?>|</[A-Za-z][A-Za-z0-9-]*\s*>|<!--[\s\S]*?-->)`)
+)
+
+// RenderMarkdown renders recipe markdown as sanitized HTML. Headings get
+// ids to link to, the list in the ingredients section becomes a checklist
+// and the numbered lists in the instructions section get step ids.
+// [[recipe:slug]] references become links to the recipe page. Raw HTML
+// in the markdown is kept only as far as SanitizeHTML allows.
+func RenderMarkdown(markdown string) string {
+	r := &markdownRenderer{ids: map[string]int{}}
+	markdown = strings.ReplaceAll(markdown, "\r\n", "\n")
+	markdown = strings.ReplaceAll(markdown, "\t", "    ")
+	return SanitizeHTML(r.blocks(strings.Split(markdown, "\n")))
+}
+
+// markdownRenderer keeps what rendering needs to know across blocks: the
+// heading ids handed out so far, the number of steps and the section the
+// current block is in.
+type markdownRenderer struct {
+	ids          map[string]int
+	steps        int
+	section      string
+	sectionLevel int
+	depth        int
+}
+
+const (
+	ingredientsSection  = "ingredients"
+	instructionsSection = "instructions"
+)
+
+// maxBlockDepth caps how deeply lists and quotes nest. Markers beyond it
+// are left as text, which keeps deeply nested input from taking time to
+// render in proportion to its depth.
+const maxBlockDepth = 16
+
+func (r *markdownRenderer) blocks(lines []string) string {
+	var b strings.Builder
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case trimmed == "":
+			i++
+		case fencePattern.MatchString(trimmed):
+			i = r.fence(&b, lines, i)
+		case headingPattern.MatchString(trimmed):
+			r.heading(&b, trimmed)
+			i++
+		case isHorizontalRule(trimmed):
+			b.WriteString("<hr>\n")
+			i++
+		case r.depth < maxBlockDepth && strings.HasPrefix(trimmed, ">"):
+			i = r.blockquote(&b, lines, i)
+		case r.depth < maxBlockDepth && isListItem(lines[i]):
+			i = r.list(&b, lines, i)
+		case htmlBlockPattern.MatchString(lines[i]):
+			start := i
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+				i++
+			}
+			b.WriteString(strings.Join(lines[start:i], "\n") + "\n")
+		case i+1 < len(lines) && strings.Contains(trimmed, "|") && tableDelimiterPattern.MatchString(strings.TrimSpace(lines[i+1])):
+			i = r.table(&b, lines, i)
+		default:
+			i = r.paragraph(&b, lines, i)
+		}
+	}
+	return b.String()
+}
+
+func isHorizontalRule(trimmed string) bool {
+	return horizontalRulePattern.MatchString(strings.ReplaceAll(trimmed, " ", ""))
+}
+
+// interruptsParagraph reports whether line starts a block that ends the
+// paragraph before it.
+func interruptsParagraph(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" ||
+		fencePattern.MatchString(trimmed) ||
+		headingPattern.MatchString(trimmed) ||
+		isHorizontalRule(trimmed) ||
+		strings.HasPrefix(trimmed, ">") ||
+		isListItem(line) ||
+		htmlBlockPattern.MatchString(line)
+}
+
+func (r *markdownRenderer) paragraph(b *strings.Builder, lines []string, i int) int {
+	var text []string
+	for ; i < len(lines); i++ {
+		if len(text) > 0 && interruptsParagraph(lines[i]) {
+			break
+		}
+		text = append(text, strings.TrimLeft(lines[i], " "))
+	}
+	b.WriteString("<p>" + r.inline(strings.TrimRight(strings.Join(text, "\n"), " ")) + "</p>\n")
+	return i
+}
+
+// heading writes an ATX heading with an id made from its text. Headings
+// outside lists and quotes also decide which section the blocks after them
+// are in, the way ParseIngredients reads sections.
+func (r *markdownRenderer) heading(b *strings.Builder, trimmed string) {
+	m := headingPattern.FindStringSubmatch(trimmed)
+	level := len(m[1])
+	text := strings.TrimSpace(closingHashesPattern.ReplaceAllString(m[2], ""))
+	plain := plainMarkdown(text)
+
+	if r.depth == 0 && (r.sectionLevel == 0 || level <= r.sectionLevel) {
+		r.section, r.sectionLevel = "", 0
+		switch {
+		case isIngredientsHeading(plain):
+			r.section, r.sectionLevel = ingredientsSection, level
+		case isInstructionsHeading(plain):
+			r.section, r.sectionLevel = instructionsSection, level
+		}
+	}
+
+	fmt.Fprintf(b, "<h%d id=\"%s\">%s</h%d>\n", level, r.headingID(plain), r.inline(text), level)
+}
+
+// headingID returns a slug of the heading text that no earlier heading
+// has, numbering repeats.
+func (r *markdownRenderer) headingID(text string) string {
+	id := generateSlug(text)
+	if id == "" {
+		id = "section"
+	}
+	r.ids[id]++
+	if n := r.ids[id]; n > 1 {
+		return fmt.Sprintf("%s-%d", id, n)
+	}
+	return id
+}
+
+func (r *markdownRenderer) fence(b *strings.Builder, lines []string, i int) int {
+	m := fencePattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+	marker := m[1]
+
+	var code []string
+	for i++; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, marker) && strings.Trim(trimmed, marker[:1]) == "" {
+			i++
+			break
+		}
+		code = append(code, lines[i])
+	}
+
+	b.WriteString("<pre><code")
+	if m[2] != "" {
+		b.WriteString(` class="language-` + html.EscapeString(m[2]) + `"`)
+	}
+	b.WriteString(">")
+	for _, line := range code {
+		b.WriteString(html.EscapeString(line) + "\n")
+	}
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+func (r *markdownRenderer) blockquote(b *strings.Builder, lines []string, i int) int {
+	var quoted []string
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			break
+		}
+		if rest, ok := strings.CutPrefix(trimmed, ">"); ok {
+			quoted = append(quoted, strings.TrimPrefix(rest, " "))
+			continue
+		}
+		// A line without the marker continues the quoted paragraph.
+		if len(quoted) == 0 || interruptsParagraph(lines[i]) {
+			break
+		}
+		quoted = append(quoted, trimmed)
+	}
+
+	r.depth++
+	b.WriteString("<blockquote>\n" + r.blocks(quoted) + "</blockquote>\n")
+	r.depth--
+	return i
+}
+
+// listMarker is the bullet or number that starts a list item.
+type listMarker struct {
+	ordered bool
+	char    string
+	start   int
+	indent  int
+	content int
+}
+
+func parseListMarker(line string) (listMarker, string, bool) {
+	if isHorizontalRule(strings.TrimSpace(line)) {
+		return listMarker{}, "", false
+	}
+	if m := bulletMarkerPattern.FindStringSubmatch(line); m != nil {
+		return listMarker{char: m[2], indent: len(m[1]), content: markerContent(line, len(m[0]))}, line[len(m[0]):], true
+	}
+	if m := orderedMarkerPattern.FindStringSubmatch(line); m != nil {
+		start, _ := strconv.Atoi(m[2])
+		return listMarker{ordered: true, char: m[3], start: start, indent: len(m[1]), content: markerContent(line, len(m[0]))}, line[len(m[0]):], true
+	}
+	return listMarker{}, "", false
+}
+
+// markerContent is the column an item's text starts at. Items that start
+// with a gap of five or more spaces keep all but one as part of the text.
+func markerContent(line string, end int) int {
+	if end == len(line) {
+		return end + 1
+	}
+	marker := strings.TrimRight(line[:end], " ")
+	if end-len(marker) > 4 {
+		return len(marker) + 1
+	}
+	return end
+}
+
+func isListItem(line string) bool {
+	_, _, ok := parseListMarker(line)
+	return ok
+}
+
+func (m listMarker) sameList(other listMarker) bool {
+	return m.ordered == other.ordered && m.char == other.char
+}
+
+// listItem is the lines of one item with the marker and its indentation
+// taken off.
+type listItem struct {
+	marker listMarker
+	lines  []string
+}
+
+func (r *markdownRenderer) list(b *strings.Builder, lines []string, i int) int {
+	first, rest, _ := parseListMarker(lines[i])
+	items := []*listItem{{marker: first, lines: []string{rest}}}
+	loose := false
+
+	for i++; i < len(lines); i++ {
+		item := items[len(items)-1]
+		line := lines[i]
+		indent := leadingSpaces(line)
+
+		if strings.TrimSpace(line) == "" {
+			next := i + 1
+			for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+				next++
+			}
+			if next == len(lines) {
+				break
+			}
+			if leadingSpaces(lines[next]) >= item.marker.content {
+				item.lines = append(item.lines, "")
+				continue
+			}
+			if marker, _, ok := parseListMarker(lines[next]); ok && marker.sameList(first) && marker.indent <= first.indent {
+				loose = true
+				continue
+			}
+			break
+		}
+
+		if marker, rest, ok := parseListMarker(line); ok && indent <= first.indent {
+			if !marker.sameList(first) {
+				break
+			}
+			items = append(items, &listItem{marker: marker, lines: []string{rest}})
+			continue
+		}
+		if indent > item.marker.indent {
+			item.lines = append(item.lines, line[min(indent, item.marker.content):])
+			continue
+		}
+		// Unindented text continues the item's paragraph.
+		if previous := item.lines[len(item.lines)-1]; strings.TrimSpace(previous) != "" && !interruptsParagraph(line) {
+			item.lines = append(item.lines, line)
+			continue
+		}
+		break
+	}
+
+	tag, class := "ul", ""
+	checklist, steps := false, false
+	if r.depth == 0 {
+		checklist = r.section == ingredientsSection && !first.ordered
+		steps = r.section == instructionsSection && first.ordered
+	}
+	switch {
+	case first.ordered && steps:
+		tag, class = "ol", "steps"
+	case first.ordered:
+		tag = "ol"
+	case checklist:
+		class = "ingredients"
+	}
+
+	b.WriteString("<" + tag)
+	if class != "" {
+		b.WriteString(` class="` + class + `"`)
+	}
+	if first.ordered && first.start != 1 {
+		fmt.Fprintf(b, ` start="%d"`, first.start)
+	}
+	b.WriteString(">\n")
+
+	r.depth++
+	for _, item := range items {
+		r.listItem(b, item, loose, checklist, steps)
+	}
+	r.depth--
+
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+func (r *markdownRenderer) listItem(b *strings.Builder, item *listItem, loose, checklist, steps bool) {
+	task := ""
+	if m := taskItemPattern.FindStringSubmatch(item.lines[0]); m != nil {
+		task = "<input type=\"checkbox\" disabled>"
+		if m[1] != " " {
+			task = "<input type=\"checkbox\" disabled checked>"
+		}
+		item.lines[0] = item.lines[0][len(m[0]):]
+	}
+
+	body := strings.TrimSuffix(r.blocks(item.lines), "\n")
+	tight := !loose && strings.HasPrefix(body, "<p>") && strings.Count(body, "<p>") == 1
+	if tight {
+		end := strings.Index(body, "</p>")
+		body = body[len("<p>"):end] + body[end+len("</p>"):]
+	}
+
+	if steps {
+		r.steps++
+		fmt.Fprintf(b, "<li id=\"step-%d\">", r.steps)
+	} else {
+		b.WriteString("<li>")
+	}
+	switch {
+	case task != "":
+		b.WriteString(task + " " + body)
+	case checklist && tight && !strings.Contains(body, "\n<"):
+		b.WriteString("<label><input type=\"checkbox\"> " + body + "</label>")
+	case checklist:
+		b.WriteString("<input type=\"checkbox\"> " + body)
+	default:
+		b.WriteString(body)
+	}
+	b.WriteString("</li>\n")
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func (r *markdownRenderer) table(b *strings.Builder, lines []string, i int) int {
+	header := tableCells(lines[i])
+	var aligns []string
+	for _, cell := range tableCells(lines[i+1]) {
+		switch {
+		case strings.HasPrefix(cell, ":") && strings.HasSuffix(cell, ":"):
+			aligns = append(aligns, "center")
+		case strings.HasSuffix(cell, ":"):
+			aligns = append(aligns, "right")
+		case strings.HasPrefix(cell, ":"):
+			aligns = append(aligns, "left")
+		default:
+			aligns = append(aligns, "")
+		}
+	}
+
+	row := func(cells []string, tag string) {
+		b.WriteString("<tr>")
+		for n := range header {
+			b.WriteString("<" + tag)
+			if n < len(aligns) && aligns[n] != "" {
+				b.WriteString(` align="` + aligns[n] + `"`)
+			}
+			b.WriteString(">")
+			if n < len(cells) {
+				b.WriteString(r.inline(cells[n]))
+			}
+			b.WriteString("</" + tag + ">")
+		}
+		b.WriteString("</tr>\n")
+	}
+
+	b.WriteString("<table>\n<thead>\n")
+	row(header, "th")
+	b.WriteString("</thead>\n<tbody>\n")
+	for i += 2; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || !strings.Contains(trimmed, "|") {
+			break
+		}
+		row(tableCells(lines[i]), "td")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return i
+}
+
+// tableCells splits a table row at the pipes that aren't escaped.
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// inline renders the spans of a block: escapes, code, links, images,
+// emphasis, hard line breaks and inline HTML. Everything but emphasis is
+// rendered in one pass that leaves the runs of emphasis delimiters in
+// between; emphasis is then matched over those runs.
+func (r *markdownRenderer) inline(s string) string {
+	var b strings.Builder
+	var runs []*delimiterNode
+	var nodes []inlineNode
+	links := &linkBrackets{s: s}
+	flush := func() {
+		if b.Len() > 0 {
+			nodes = append(nodes, inlineNode{text: b.String()})
+			b.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch c {
+		case '\\':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				b.WriteString("<br>\n")
+				i += 2
+				continue
+			}
+			if i+1 < len(s) && s[i+1] < utf8.RuneSelf && (unicode.IsPunct(rune(s[i+1])) || unicode.IsSymbol(rune(s[i+1]))) {
+				b.WriteString(html.EscapeString(s[i+1 : i+2]))
+				i += 2
+				continue
+			}
+		case '`':
+			if code, end, ok := codeSpan(s, i); ok {
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i = end
+				continue
+			}
+			run := delimiterRun(s, i)
+			b.WriteString(s[i : i+run])
+			i += run
+			continue
+		case '!':
+			if i+1 < len(s) && s[i+1] == '[' {
+				if text, dest, title, end, ok := links.parseInlineLink(i + 1); ok {
+					fmt.Fprintf(&b, `<img src="%s" alt="%s"`, html.EscapeString(dest), html.EscapeString(plainMarkdown(text)))
+					if title != "" {
+						fmt.Fprintf(&b, ` title="%s"`, html.EscapeString(title))
+					}
+					b.WriteString(">")
+					i = end
+					continue
+				}
+			}
+		case '[':
+			if m := inlineRecipeLinkPattern.FindStringSubmatch(s[i:]); m != nil {
+				slug := strings.ToLower(m[1])
+				fmt.Fprintf(&b, `<a href="/recipes/view?slug=%s" class="recipe-link">%s</a>`, slug, html.EscapeString(recipeLinkText(slug)))
+				i += len(m[0])
+				continue
+			}
+			if text, dest, title, end, ok := links.parseInlineLink(i); ok {
+				fmt.Fprintf(&b, `<a href="%s"`, html.EscapeString(dest))
+				if title != "" {
+					fmt.Fprintf(&b, ` title="%s"`, html.EscapeString(title))
+				}
+				b.WriteString(">" + r.inline(text) + "</a>")
+				i = end
+				continue
+			}
+		case '<':
+			if m := inlineAutolinkPattern.FindStringSubmatch(s[i:]); m != nil {
+				fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(m[1]), html.EscapeString(strings.TrimPrefix(m[1], "mailto:")))
+				i += len(m[0])
+				continue
+			}
+			if tag := inlineTagPattern.FindString(s[i:]); tag != "" {
+				b.WriteString(tag)
+				i += len(tag)
+				continue
+			}
+		case '&':
+			if entity := entityPattern.FindString(s[i:]); entity != "" {
+				b.WriteString(entity)
+				i += len(entity)
+				continue
+			}
+		case '*', '_', '~':
+			run := newDelimiterNode(s, i)
+			flush()
+			runs = append(runs, run)
+			nodes = append(nodes, inlineNode{run: run})
+			i += run.length
+			continue
+		case ' ':
+			run := delimiterRun(s, i)
+			if run >= 2 && i+run < len(s) && s[i+run] == '\n' {
+				b.WriteString("<br>\n")
+				i += run + 1
+				continue
+			}
+			b.WriteString(s[i : i+run])
+			i += run
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i:])
+		b.WriteString(html.EscapeString(s[i : i+size]))
+		i += size
+	}
+	if len(runs) == 0 {
+		return b.String()
+	}
+	flush()
+
+	matchEmphasis(runs)
+	for _, node := range nodes {
+		if node.run == nil {
+			b.WriteString(node.text)
+			continue
+		}
+		b.WriteString(node.run.closeTags)
+		b.WriteString(strings.Repeat(string(node.run.char), node.run.count))
+		b.WriteString(node.run.openTags)
+	}
+	return b.String()
+}
+
+// delimiterRun counts the repeats of the byte at i.
+func delimiterRun(s string, i int) int {
+	n := 1
+	for i+n < len(s) && s[i+n] == s[i] {
+		n++
+	}
+	return n
+}
+
+// codeSpan finds the code span opened by the backticks at i, returning its
+// text and the index after it.
+func codeSpan(s string, i int) (string, int, bool) {
+	run := delimiterRun(s, i)
+	for j := i + run; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		closing := delimiterRun(s, j)
+		if closing == run {
+			code := strings.ReplaceAll(s[i+run:j], "\n", " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+				code = code[1 : len(code)-1]
+			}
+			return code, j + closing, true
+		}
+		j += closing
+	}
+	return "", 0, false
+}
+
+// inlineNode is a piece of rendered inline text or a run of emphasis
+// delimiters.
+type inlineNode struct {
+	text string
+	run  *delimiterNode
+}
+
+// delimiterNode is a run of *, _ or ~ that may open or close emphasis. The
+// runs form a doubly linked list while emphasis is matched; count is how
+// many of the delimiters are still left as text. Delimiters used to close
+// spans are taken from the left of a run and those used to open spans from
+// the right, so the run renders as closeTags, the rest, then openTags.
+type delimiterNode struct {
+	char       byte
+	length     int
+	count      int
+	pos        int
+	canOpen    bool
+	canClose   bool
+	prev, next *delimiterNode
+	openTags   string
+	closeTags  string
+}
+
+// newDelimiterNode reads the run of delimiters at i. A run opens when text
+// follows it and closes when text precedes it. Underscores only count at
+// word boundaries so that snake_case stays as it is.
+func newDelimiterNode(s string, i int) *delimiterNode {
+	run := delimiterRun(s, i)
+	node := &delimiterNode{
+		char:     s[i],
+		length:   run,
+		count:    run,
+		pos:      i,
+		canOpen:  i+run < len(s) && !isSpace(s[i+run]),
+		canClose: i > 0 && !isSpace(s[i-1]),
+	}
+	if node.char == '_' {
+		node.canOpen = node.canOpen && !(i > 0 && isWordByte(s[i-1]))
+		node.canClose = node.canClose && !(i+run < len(s) && isWordByte(s[i+run]))
+	}
+	return node
+}
+
+// emphasisBottom is what openersBottom is kept per in matchEmphasis, as in
+// CommonMark.
+type emphasisBottom struct {
+	char    byte
+	canOpen bool
+	mod     int
+}
+
+// matchEmphasis pairs the runs of delimiters into strong, emphasized and
+// struck-through spans with the delimiter stack algorithm of CommonMark:
+// each closer looks back for the nearest matching opener, and a lower
+// bound is kept for the search of each kind of closer, so that unmatched
+// delimiters are only looked at once.
+func matchEmphasis(runs []*delimiterNode) {
+	for i, run := range runs {
+		if i > 0 {
+			run.prev = runs[i-1]
+		}
+		if i+1 < len(runs) {
+			run.next = runs[i+1]
+		}
+	}
+	remove := func(run *delimiterNode) {
+		if run.prev != nil {
+			run.prev.next = run.next
+		}
+		if run.next != nil {
+			run.next.prev = run.prev
+		}
+	}
+
+	openersBottom := map[emphasisBottom]int{}
+	for closer := runs[0]; closer != nil; {
+		if !closer.canClose {
+			closer = closer.next
+			continue
+		}
+		key := emphasisBottom{char: closer.char, canOpen: closer.canOpen, mod: closer.length % 3}
+		bottom, ok := openersBottom[key]
+		if !ok {
+			bottom = -1
+		}
+
+		opener := closer.prev
+		for opener != nil && opener.pos > bottom && !opensFor(opener, closer) {
+			opener = opener.prev
+		}
+		if opener == nil || opener.pos <= bottom {
+			openersBottom[key] = closer.pos - 1
+			next := closer.next
+			if !closer.canOpen {
+				remove(closer)
+			}
+			closer = next
+			continue
+		}
+
+		n, tag := 1, "em"
+		switch {
+		case closer.char == '~':
+			n, tag = 2, "del"
+		case opener.count >= 2 && closer.count >= 2:
+			n, tag = 2, "strong"
+		}
+		opener.openTags = "<" + tag + ">" + opener.openTags
+		closer.closeTags += "</" + tag + ">"
+		opener.count -= n
+		closer.count -= n
+
+		// The runs in between can no longer match anything.
+		opener.next, closer.prev = closer, opener
+		if opener.count == 0 {
+			remove(opener)
+		}
+		if closer.count == 0 {
+			next := closer.next
+			remove(closer)
+			closer = next
+		}
+	}
+}
+
+// opensFor reports whether opener can start the span closer ends. Tildes
+// only pair up two and two. A run that can both open and close doesn't
+// pair with another whose combined length is a multiple of three, unless
+// both are, so that "*a**b*" is not read as "*a*" and "*b*".
+func opensFor(opener, closer *delimiterNode) bool {
+	if opener.char != closer.char || !opener.canOpen {
+		return false
+	}
+	if opener.char == '~' {
+		return opener.count >= 2 && closer.count >= 2
+	}
+	if (opener.canClose || closer.canOpen) && (opener.length+closer.length)%3 == 0 {
+		return opener.length%3 == 0 && closer.length%3 == 0
+	}
+	return true
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\t'
+}
+
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+// maxInlineLink bounds how far parseInlineLink looks past the text of a
+// link for its destination and title.
+const maxInlineLink = 2048
+
+// linkBrackets finds the bracket that closes each opening bracket of a
+// block. Brackets are paired in a single pass over the block, skipping
+// escapes and code spans, so unclosed brackets don't each scan the rest of
+// it.
+type linkBrackets struct {
+	s       string
+	closing []int
+}
+
+// closingBracket returns the index of the bracket that closes the one at
+// i, or -1 if none does.
+func (l *linkBrackets) closingBracket(i int) int {
+	if l.closing == nil {
+		l.closing = make([]int, len(l.s))
+		for j := range l.closing {
+			l.closing[j] = -2
+		}
+		var open []int
+		for j := 0; j < len(l.s); j++ {
+			switch l.s[j] {
+			case '\\':
+				j++
+			case '`':
+				if _, codeEnd, found := codeSpan(l.s, j); found {
+					j = codeEnd - 1
+				}
+			case '[':
+				l.closing[j] = -1
+				open = append(open, j)
+			case ']':
+				if len(open) > 0 {
+					l.closing[open[len(open)-1]] = j
+					open = open[:len(open)-1]
+				}
+			}
+		}
+	}
+	if bracket := l.closing[i]; bracket != -2 {
+		return bracket
+	}
+	// The bracket is one the pass above took for part of a code span, but
+	// the inline pass reached it by skipping over an autolink or tag.
+	return scanClosingBracket(l.s[:min(len(l.s), i+maxInlineLink)], i)
+}
+
+// scanClosingBracket looks for the bracket that closes the one at i.
+func scanClosingBracket(s string, i int) int {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '`':
+			if _, codeEnd, found := codeSpan(s, j); found {
+				j = codeEnd - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// parseInlineLink reads "[text](destination "title")" starting at the
+// bracket at i.
+func (l *linkBrackets) parseInlineLink(i int) (text, dest, title string, end int, ok bool) {
+	bracket := l.closingBracket(i)
+	if bracket < 0 {
+		return "", "", "", 0, false
+	}
+	s := l.s[:min(len(l.s), bracket+maxInlineLink)]
+	if bracket < 0 || bracket+1 >= len(s) || s[bracket+1] != '(' {
+		return "", "", "", 0, false
+	}
+
+	j := skipSpaces(s, bracket+2)
+	if j < len(s) && s[j] == '<' {
+		end := strings.IndexAny(s[j:], ">\n")
+		if end < 0 || s[j+end] != '>' {
+			return "", "", "", 0, false
+		}
+		dest, j = s[j+1:j+end], j+end+1
+	} else {
+		start, parens := j, 0
+		for ; j < len(s) && !isSpace(s[j]); j++ {
+			if s[j] == '(' {
+				parens++
+			} else if s[j] == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		dest = s[start:j]
+	}
+
+	j = skipSpaces(s, j)
+	if j < len(s) && (s[j] == '"' || s[j] == '\'' || s[j] == '(') {
+		closing := s[j]
+		if closing == '(' {
+			closing = ')'
+		}
+		end := strings.IndexByte(s[j+1:], closing)
+		if end < 0 {
+			return "", "", "", 0, false
+		}
+		title, j = s[j+1:j+1+end], skipSpaces(s, j+end+2)
+	}
+	if j >= len(s) || s[j] != ')' {
+		return "", "", "", 0, false
+	}
+	return s[i+1 : bracket], dest, title, j + 1, true
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return i
+}
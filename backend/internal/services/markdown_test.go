@@ -0,0 +1,162 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	markdown := `# Tomato Soup
+
+A *warm* soup with **lots** of ` + "`basil`" + `.
+
+## Ingredients
+
+- 4 tomatoes
+- 1 onion, diced
+
+## Instructions
+
+1. Chop the [onion](https://example.com/onion).
+2. Simmer for 20 minutes.
+
+## Instructions
+
+1. Serve.`
+
+	expected := `<h1 id="tomato-soup">Tomato Soup</h1>
+<p>A <em>warm</em> soup with <strong>lots</strong> of <code>basil</code>.</p>
+<h2 id="ingredients">Ingredients</h2>
+<ul class="ingredients">
+<li><label><input type="checkbox"> 4 tomatoes</label></li>
+<li><label><input type="checkbox"> 1 onion, diced</label></li>
+</ul>
+<h2 id="instructions">Instructions</h2>
+<ol class="steps">
+<li id="step-1">Chop the <a href="https://example.com/onion" rel="nofollow noopener noreferrer">onion</a>.</li>
+<li id="step-2">Simmer for 20 minutes.</li>
+</ol>
+<h2 id="instructions-2">Instructions</h2>
+<ol class="steps">
+<li id="step-3">Serve.</li>
+</ol>
+`
+	assert.Equal(t, expected, RenderMarkdown(markdown))
+}
+
+func TestRenderMarkdown_Blocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		expected string
+	}{
+		{
+			"code block",
+			"```go\nx := 1 < 2\n```",
+			"<pre><code class=\"language-go\">x := 1 &lt; 2\n</code></pre>\n",
+		},
+		{
+			"table",
+			"| Nutrient | Amount |\n|---|--:|\n| Fat | 3g |",
+			"<table>\n<thead>\n<tr><th>Nutrient</th><th align=\"right\">Amount</th></tr>\n</thead>\n<tbody>\n<tr><td>Fat</td><td align=\"right\">3g</td></tr>\n</tbody>\n</table>\n",
+		},
+		{
+			"blockquote",
+			"> Best served ~~cold~~ warm.",
+			"<blockquote>\n<p>Best served <del>cold</del> warm.</p>\n</blockquote>\n",
+		},
+		{
+			"ordered list start",
+			"3. Third\n4. Fourth",
+			"<ol start=\"3\">\n<li>Third</li>\n<li>Fourth</li>\n</ol>\n",
+		},
+		{
+			"task list",
+			"- [x] Shopping done",
+			"<ul>\n<li><input type=\"checkbox\" disabled checked> Shopping done</li>\n</ul>\n",
+		},
+		{
+			"recipe link",
+			"Roll out the [[recipe:Pie-Crust]].",
+			"<p>Roll out the <a href=\"/recipes/view?slug=pie-crust\" class=\"recipe-link\">pie crust</a>.</p>\n",
+		},
+		{
+			"intraword underscores",
+			"snake_case_name and 2*3*4",
+			"<p>snake_case_name and 2<em>3</em>4</p>\n",
+		},
+		{
+			"nested emphasis",
+			"***both*** and *a **b** c* and **a *b***",
+			"<p><em><strong>both</strong></em> and <em>a <strong>b</strong> c</em> and <strong>a <em>b</em></strong></p>\n",
+		},
+		{
+			"unmatched delimiters",
+			"**a *b and `*code*` _c",
+			"<p>**a *b and <code>*code*</code> _c</p>\n",
+		},
+		{
+			"link text",
+			"[*Pie* [crust]](/pie) and [unclosed",
+			"<p><a href=\"/pie\"><em>Pie</em> [crust]</a> and [unclosed</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RenderMarkdown(tt.markdown))
+		})
+	}
+}
+
+func TestRenderMarkdown_StripsDangerousHTML(t *testing.T) {
+	markdown := `Simmer <script>alert(1)</script>gently.
+
+<div onclick="steal()"><a href="javascript:alert(1)">Click</a><img src="x.png" onerror="steal()"><iframe src="https://example.com"></iframe></div>
+
+[Link](javascript:alert(1)) and ![photo](data:image/png;base64,AAAA)`
+
+	rendered := RenderMarkdown(markdown)
+	assert.NotContains(t, rendered, "script")
+	assert.NotContains(t, rendered, "alert")
+	assert.NotContains(t, rendered, "onclick")
+	assert.NotContains(t, rendered, "onerror")
+	assert.NotContains(t, rendered, "iframe")
+	assert.NotContains(t, rendered, "data:")
+	assert.Contains(t, rendered, "<p>Simmer gently.</p>")
+	assert.Contains(t, rendered, `<div><a>Click</a><img src="x.png"></div>`)
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`<p style="color:red">Hi</p>`, `<p>Hi</p>`},
+		{`<a href="/recipes/soup" target="_blank">Soup</a>`, `<a href="/recipes/soup">Soup</a>`},
+		{`<a href="mailto:cook@example.com">Mail</a>`, `<a href="mailto:cook@example.com">Mail</a>`},
+		{`<a href=" JavaScript:alert(1)">x</a>`, `<a>x</a>`},
+		{`<input type="text" value="x"><input type="checkbox" checked>`, `<input type="checkbox" checked>`},
+		{`<font color="red">Red</font>`, `Red`},
+		{`<style>p{}</style><noscript>x</noscript>Text`, `Text`},
+		{`<h2 id="a&quot; onclick=&quot;x">Title</h2>`, `<h2>Title</h2>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SanitizeHTML(tt.input))
+		})
+	}
+}
+
+func TestRenderMarkdown_NestingDepth(t *testing.T) {
+	var markdown strings.Builder
+	for i := 0; i < 100; i++ {
+		markdown.WriteString(strings.Repeat("  ", i) + "- item\n")
+	}
+	rendered := RenderMarkdown(markdown.String() + "\n" + strings.Repeat(">", 100) + " quoted")
+	assert.Equal(t, maxBlockDepth, strings.Count(rendered, "<ul>"))
+	assert.Equal(t, maxBlockDepth, strings.Count(rendered, "<blockquote>"))
+}
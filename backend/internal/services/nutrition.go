@@ -0,0 +1,295 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+//go:embed data/nutrients.csv
+var nutrientsCSV string
+
+// food is one row of the bundled nutrient table.
+type food struct {
+	name       string
+	per100g    models.NutrientAmounts
+	gramsPerML float64
+	gramsEach  float64
+}
+
+// foodTable finds foods by their normalised name or any of their aliases.
+type foodTable struct {
+	byPhrase map[string]*food
+	maxWords int
+}
+
+var foods = mustLoadFoods(nutrientsCSV)
+
+// countUnits are counted in whole pieces and weighed with the food's
+// grams_each; the empty unit covers lines like "3 eggs".
+var countUnits = map[string]bool{
+	"":        true,
+	"clove":   true,
+	"stick":   true,
+	"slice":   true,
+	"can":     true,
+	"package": true,
+	"head":    true,
+}
+
+// fixedUnitGrams are rough weights for measures that don't depend much on
+// what is being measured.
+var fixedUnitGrams = map[string]float64{
+	"pinch":   0.4,
+	"dash":    0.6,
+	"sprig":   1,
+	"handful": 30,
+	"bunch":   50,
+}
+
+// EstimateNutrition adds up the nutrients of the parsed ingredients and
+// divides them over the recipe's servings, counting a recipe without
+// servings as a single one. It returns nil when there are no ingredients.
+func EstimateNutrition(ingredients []models.RecipeIngredient, servings *int32) *models.Nutrition {
+	if len(ingredients) == 0 {
+		return nil
+	}
+
+	var total models.NutrientAmounts
+	unmatched := []string{}
+	for _, ingredient := range ingredients {
+		f := foods.match(ingredient.Name)
+		if f == nil {
+			unmatched = append(unmatched, ingredient.RawText)
+			continue
+		}
+		grams, ok := ingredientGrams(ingredient, f)
+		if !ok {
+			unmatched = append(unmatched, ingredient.RawText)
+			continue
+		}
+		total = addNutrients(total, f.per100g, grams/100)
+	}
+
+	count := 1
+	if servings != nil && *servings > 0 {
+		count = int(*servings)
+	}
+
+	return &models.Nutrition{
+		Servings:   count,
+		PerServing: roundNutrients(addNutrients(models.NutrientAmounts{}, total, 1/float64(count))),
+		Total:      roundNutrients(total),
+		Confidence: nutritionConfidence(len(ingredients), len(unmatched)),
+		Unmatched:  unmatched,
+	}
+}
+
+// nutritionConfidence is high when every ingredient was counted and medium
+// when at most a quarter of them were missed.
+func nutritionConfidence(ingredients, unmatched int) string {
+	switch {
+	case unmatched == 0:
+		return models.NutritionConfidenceHigh
+	case unmatched*4 <= ingredients:
+		return models.NutritionConfidenceMedium
+	default:
+		return models.NutritionConfidenceLow
+	}
+}
+
+// ingredientGrams turns an ingredient's quantity and unit into grams of f.
+// Ranges count as their midpoint.
+func ingredientGrams(ingredient models.RecipeIngredient, f *food) (float64, bool) {
+	if ingredient.Quantity == nil {
+		return 0, false
+	}
+	quantity := *ingredient.Quantity
+	if ingredient.QuantityMax != nil {
+		quantity = (quantity + *ingredient.QuantityMax) / 2
+	}
+
+	unit := ""
+	if ingredient.Unit != nil {
+		unit = *ingredient.Unit
+	}
+
+	if size, ok := unitTable[unit]; ok {
+		if size.kind == weightUnit {
+			return quantity * size.factor, true
+		}
+		if f.gramsPerML == 0 {
+			return 0, false
+		}
+		return quantity * size.factor * f.gramsPerML, true
+	}
+	if grams, ok := fixedUnitGrams[unit]; ok {
+		return quantity * grams, true
+	}
+	if !countUnits[unit] {
+		return 0, false
+	}
+	if unit == "can" || unit == "package" {
+		if each, ok := packageGrams(ingredient.Note, f); ok {
+			return quantity * each, true
+		}
+	}
+	if f.gramsEach == 0 {
+		return 0, false
+	}
+	return quantity * f.gramsEach, true
+}
+
+// packageGrams reads a package size such as "14 oz" from the start of the
+// note, where the parser leaves it for "1 (14 oz) can diced tomatoes".
+func packageGrams(note *string, f *food) (float64, bool) {
+	if note == nil {
+		return 0, false
+	}
+	size, _, _ := strings.Cut(*note, ",")
+	pkg, ok := parseIngredientLine(size)
+	if !ok || pkg.Unit == nil {
+		return 0, false
+	}
+	if _, ok := unitTable[*pkg.Unit]; !ok {
+		return 0, false
+	}
+	return ingredientGrams(pkg, f)
+}
+
+// match finds the food named by the longest run of words in name, so that
+// "peanut butter" wins over "butter" and "chicken broth" over "chicken".
+func (t *foodTable) match(name string) *food {
+	words := strings.Fields(normalizeFoodName(name))
+	for n := min(t.maxWords, len(words)); n > 0; n-- {
+		for i := 0; i+n <= len(words); i++ {
+			if f, ok := t.byPhrase[strings.Join(words[i:i+n], " ")]; ok {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeFoodName lowercases name, keeps only letters and reduces every
+// word to a rough singular so that "Tomatoes" and "tomato" compare equal.
+func normalizeFoodName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	normalized := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ReplaceAll(word, "'", "")
+		if word != "" {
+			normalized = append(normalized, singularize(word))
+		}
+	}
+	return strings.Join(normalized, " ")
+}
+
+func singularize(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return strings.TrimSuffix(word, "ies") + "y"
+	case len(word) > 4 && strings.HasSuffix(word, "oes"):
+		return strings.TrimSuffix(word, "es")
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+func addNutrients(sum, amounts models.NutrientAmounts, factor float64) models.NutrientAmounts {
+	return models.NutrientAmounts{
+		Calories:      sum.Calories + amounts.Calories*factor,
+		ProteinG:      sum.ProteinG + amounts.ProteinG*factor,
+		FatG:          sum.FatG + amounts.FatG*factor,
+		CarbohydrateG: sum.CarbohydrateG + amounts.CarbohydrateG*factor,
+		FiberG:        sum.FiberG + amounts.FiberG*factor,
+		SodiumMg:      sum.SodiumMg + amounts.SodiumMg*factor,
+	}
+}
+
+func roundNutrients(amounts models.NutrientAmounts) models.NutrientAmounts {
+	return models.NutrientAmounts{
+		Calories:      roundTo(amounts.Calories, 0),
+		ProteinG:      roundTo(amounts.ProteinG, 1),
+		FatG:          roundTo(amounts.FatG, 1),
+		CarbohydrateG: roundTo(amounts.CarbohydrateG, 1),
+		FiberG:        roundTo(amounts.FiberG, 1),
+		SodiumMg:      roundTo(amounts.SodiumMg, 0),
+	}
+}
+
+func mustLoadFoods(data string) *foodTable {
+	table, err := loadFoods(data)
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// loadFoods parses the nutrient table. Every name and alias must point at a
+// single food.
+func loadFoods(data string) (*foodTable, error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("nutrient table: %w", err)
+	}
+	if len(records) < 2 || len(records[0]) != 10 {
+		return nil, fmt.Errorf("nutrient table: unexpected header %v", records[0])
+	}
+
+	table := &foodTable{byPhrase: map[string]*food{}}
+	for _, record := range records[1:] {
+		var values [8]float64
+		for i, field := range record[2:] {
+			if field == "" {
+				continue
+			}
+			value, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, fmt.Errorf("nutrient table: %s: %w", record[0], err)
+			}
+			values[i] = value
+		}
+
+		f := &food{
+			name: record[0],
+			per100g: models.NutrientAmounts{
+				Calories:      values[0],
+				ProteinG:      values[1],
+				FatG:          values[2],
+				CarbohydrateG: values[3],
+				FiberG:        values[4],
+				SodiumMg:      values[5],
+			},
+			gramsPerML: values[6],
+			gramsEach:  values[7],
+		}
+
+		phrases := []string{record[0]}
+		if record[1] != "" {
+			phrases = append(phrases, strings.Split(record[1], ";")...)
+		}
+		for _, phrase := range phrases {
+			key := normalizeFoodName(phrase)
+			if key == "" {
+				continue
+			}
+			if other, ok := table.byPhrase[key]; ok && other != f {
+				return nil, fmt.Errorf("nutrient table: %q names both %s and %s", phrase, other.name, f.name)
+			}
+			table.byPhrase[key] = f
+			table.maxWords = max(table.maxWords, len(strings.Fields(key)))
+		}
+	}
+	return table, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoodTable_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"exact", "flour", "all-purpose flour"},
+		{"alias", "Unsalted Butter", "butter"},
+		{"plural", "Tomatoes", "tomato"},
+		{"berries", "strawberries", "strawberries"},
+		{"longest phrase", "peanut butter", "peanut butter"},
+		{"longest phrase inside", "low sodium chicken broth", "chicken broth"},
+		{"canned", "diced tomatoes", "canned tomatoes"},
+		{"three words", "red pepper flakes", "red pepper flakes"},
+		{"adjectives", "freshly ground black pepper", "black pepper"},
+		{"whole word", "eggplant", "eggplant"},
+		{"punctuation", "confectioners' sugar", "powdered sugar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := foods.match(tt.input)
+			require.NotNil(t, f)
+			assert.Equal(t, tt.expected, f.name)
+		})
+	}
+
+	assert.Nil(t, foods.match("unicorn tears"))
+	assert.Nil(t, foods.match(""))
+}
+
+func TestLoadFoods_DuplicateAlias(t *testing.T) {
+	_, err := loadFoods(`name,aliases,calories,protein_g,fat_g,carbohydrate_g,fiber_g,sodium_mg,grams_per_ml,grams_each
+butter,,717,0.9,81.1,0.1,0,11,0.96,113
+salted butter,butter,717,0.9,81.1,0.1,0,643,0.96,113
+`)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "names both")
+}
+
+func TestEstimateNutrition(t *testing.T) {
+	markdown := `## Ingredients
+- 2 cups flour
+- 2 eggs
+- 1 tsp salt
+- 1 cup unicorn tears`
+
+	nutrition := EstimateNutrition(ParseIngredients(markdown), int32Ptr(4))
+	require.NotNil(t, nutrition)
+
+	// 250.8 g flour, 100 g egg and 6 g salt.
+	assert.Equal(t, 4, nutrition.Servings)
+	assert.Equal(t, 1056.0, nutrition.Total.Calories)
+	assert.Equal(t, 264.0, nutrition.PerServing.Calories)
+	assert.Equal(t, 38.4, nutrition.Total.ProteinG)
+	assert.Equal(t, 2478.0, nutrition.Total.SodiumMg)
+	assert.Equal(t, 1.7, nutrition.PerServing.FiberG)
+	assert.Equal(t, models.NutritionConfidenceMedium, nutrition.Confidence)
+	assert.Equal(t, []string{"1 cup unicorn tears"}, nutrition.Unmatched)
+}
+
+func TestEstimateNutrition_Units(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		calories float64
+	}{
+		{"weight", "- 200g butter", 1434},
+		{"range midpoint", "- 1-3 tbsp olive oil", 238},
+		{"pieces", "- 2 cloves garlic, minced", 9},
+		{"stick", "- 1 stick butter", 810},
+		{"package size", "- 1 (14 oz) can diced tomatoes", 127},
+		{"can without size", "- 1 can coconut milk", 788},
+		{"pinch", "- 1 pinch nutmeg", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nutrition := EstimateNutrition(ParseIngredients("## Ingredients\n"+tt.line), nil)
+			require.NotNil(t, nutrition)
+			assert.Empty(t, nutrition.Unmatched)
+			assert.Equal(t, models.NutritionConfidenceHigh, nutrition.Confidence)
+			assert.Equal(t, tt.calories, nutrition.Total.Calories)
+			assert.Equal(t, 1, nutrition.Servings)
+		})
+	}
+}
+
+func TestEstimateNutrition_Unmatched(t *testing.T) {
+	markdown := `## Ingredients
+- Salt, to taste
+- 1 cup spinach
+- 1 tbsp lemon zest
+- 2 dragon scales`
+
+	nutrition := EstimateNutrition(ParseIngredients(markdown), int32Ptr(2))
+	require.NotNil(t, nutrition)
+	assert.Equal(t, []string{"Salt, to taste", "1 tbsp lemon zest", "2 dragon scales"}, nutrition.Unmatched)
+	assert.Equal(t, models.NutritionConfidenceLow, nutrition.Confidence)
+	assert.Equal(t, 7.0, nutrition.Total.Calories)
+
+	assert.Nil(t, EstimateNutrition(nil, int32Ptr(2)))
+}
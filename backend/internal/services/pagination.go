@@ -0,0 +1,15 @@
+package services
+
+// Page sizes for list endpoints. A missing or non-positive limit falls back
+// to the default; larger requests are capped.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	return min(limit, maxPageSize)
+}
@@ -0,0 +1,484 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"strconv"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	pdfFont       = "Helvetica"
+	pdfLineHeight = 5.5
+)
+
+// cp1252Fractions are the vulgar fractions the standard PDF fonts can show;
+// the others are written out as "1/3" and so on.
+const cp1252Fractions = "¼½¾"
+
+// pdfWriter lays recipes out on PDF pages. It sticks to the standard
+// Helvetica fonts so documents need no font files or external tools.
+type pdfWriter struct {
+	pdf     *gofpdf.Fpdf
+	tr      func(string) string
+	storage *StorageService
+	images  map[string]*gofpdf.ImageInfoType
+}
+
+func newPDFWriter(pageSize string, storage *StorageService) *pdfWriter {
+	pdf := gofpdf.New("P", "mm", pageSize, "")
+	margin := 18.0
+	if pageSize == "A5" {
+		margin = 12
+	}
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetCreator("HomeCooking", true)
+
+	return &pdfWriter{
+		pdf:     pdf,
+		tr:      pdf.UnicodeTranslatorFromDescriptor(""),
+		storage: storage,
+		images:  map[string]*gofpdf.ImageInfoType{},
+	}
+}
+
+// text converts UTF-8 to the fonts' code page.
+func (w *pdfWriter) text(s string) string {
+	for r, fraction := range vulgarFractions {
+		if !strings.ContainsRune(cp1252Fractions, r) {
+			s = strings.ReplaceAll(s, string(r), fraction)
+		}
+	}
+	return w.tr(s)
+}
+
+func (w *pdfWriter) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := w.pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (w *pdfWriter) contentWidth() float64 {
+	pageWidth, _ := w.pdf.GetPageSize()
+	left, _, right, _ := w.pdf.GetMargins()
+	return pageWidth - left - right
+}
+
+// pageNumbers prints the page number at the foot of every page after the
+// first skip pages.
+func (w *pdfWriter) pageNumbers(skip int) {
+	w.pdf.SetFooterFunc(func() {
+		if w.pdf.PageNo() <= skip {
+			return
+		}
+		w.pdf.SetY(-12)
+		w.pdf.SetFont(pdfFont, "", 8)
+		w.pdf.SetTextColor(120, 120, 120)
+		w.pdf.CellFormat(0, 5, fmt.Sprintf("%d", w.pdf.PageNo()), "", 0, "C", false, 0, "")
+		w.pdf.SetTextColor(0, 0, 0)
+	})
+}
+
+// recipe writes the recipe from the current position: its title, facts,
+// featured image and the markdown body.
+func (w *pdfWriter) recipe(recipe *models.Recipe, category string) {
+	if category != "" {
+		w.pdf.SetFont(pdfFont, "", 9)
+		w.pdf.SetTextColor(194, 65, 12)
+		w.pdf.MultiCell(0, 5, w.text(strings.ToUpper(category)), "", "L", false)
+		w.pdf.SetTextColor(0, 0, 0)
+	}
+
+	w.pdf.SetFont(pdfFont, "B", 20)
+	w.pdf.MultiCell(0, 9, w.text(recipe.Title), "", "L", false)
+
+	if recipe.Description != nil && *recipe.Description != "" {
+		w.pdf.Ln(1)
+		w.pdf.SetFont(pdfFont, "I", 11)
+		w.pdf.MultiCell(0, pdfLineHeight, w.text(plainMarkdown(*recipe.Description)), "", "L", false)
+	}
+
+	if facts := recipeFacts(recipe); len(facts) > 0 {
+		w.pdf.Ln(2)
+		w.pdf.SetFont(pdfFont, "", 9)
+		w.pdf.SetTextColor(90, 90, 90)
+		w.pdf.MultiCell(0, 5, w.text(strings.Join(facts, "  ·  ")), "", "L", false)
+		w.pdf.SetTextColor(0, 0, 0)
+	}
+	w.pdf.Ln(3)
+
+	if recipe.FeaturedImagePath != nil {
+		_, pageHeight := w.pdf.GetPageSize()
+		w.image(*recipe.FeaturedImagePath, pageHeight/4)
+	}
+
+	w.markdown(recipe.MarkdownContent, recipe.Title)
+}
+
+// recipeFacts lists the servings, times and difficulty that are known.
+func recipeFacts(recipe *models.Recipe) []string {
+	var facts []string
+	if recipe.Servings != nil && *recipe.Servings > 0 {
+		facts = append(facts, fmt.Sprintf("Serves %d", *recipe.Servings))
+	}
+	if recipe.PrepTimeMinutes != nil && *recipe.PrepTimeMinutes > 0 {
+		facts = append(facts, "Prep "+formatMinutes(*recipe.PrepTimeMinutes))
+	}
+	if recipe.CookTimeMinutes != nil && *recipe.CookTimeMinutes > 0 {
+		facts = append(facts, "Cook "+formatMinutes(*recipe.CookTimeMinutes))
+	}
+	if recipe.Difficulty != nil && *recipe.Difficulty != "" {
+		facts = append(facts, strings.ToUpper((*recipe.Difficulty)[:1])+(*recipe.Difficulty)[1:])
+	}
+	return facts
+}
+
+func formatMinutes(minutes int32) string {
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%d min", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%d h", minutes/60)
+	default:
+		return fmt.Sprintf("%d h %d min", minutes/60, minutes%60)
+	}
+}
+
+// markdown writes recipe markdown as headings, lists and paragraphs, laid
+// out from the same sanitized HTML the API renders. Inline formatting and
+// embedded images are dropped, and a leading "# Title" that repeats the
+// recipe title is skipped.
+func (w *pdfWriter) markdown(markdown string, title string) {
+	context := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(RenderMarkdown(markdown)), context)
+	if err != nil {
+		w.paragraph(plainMarkdown(markdown))
+		return
+	}
+	for _, n := range nodes {
+		w.htmlBlock(n, title)
+	}
+}
+
+func (w *pdfWriter) htmlBlock(n *html.Node, title string) {
+	if n.Type == html.TextNode {
+		if text := pdfText(n); text != "" {
+			w.paragraph(text)
+		}
+		return
+	}
+	if n.Type != html.ElementNode {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		text := pdfText(n)
+		if text == "" || level == 1 && strings.EqualFold(text, title) {
+			return
+		}
+		w.subheading(text, level)
+	case atom.Hr:
+		w.rule()
+	case atom.Ul, atom.Ol:
+		w.htmlList(n, title)
+	case atom.Tr:
+		var cells []string
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode {
+				cells = append(cells, pdfText(c))
+			}
+		}
+		w.paragraph(strings.Join(cells, "   "))
+	case atom.Blockquote, atom.Div, atom.Table, atom.Thead, atom.Tbody:
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			w.htmlBlock(c, title)
+		}
+	default:
+		if text := pdfText(n); text != "" {
+			w.paragraph(text)
+		}
+	}
+}
+
+// htmlList writes the items of a list with bullets, or numbers counting on
+// from the list's start. Nested lists follow their item.
+func (w *pdfWriter) htmlList(list *html.Node, title string) {
+	number := 1
+	if start, err := strconv.Atoi(htmlAttr(list, "start")); err == nil {
+		number = start
+	}
+	for item := list.FirstChild; item != nil; item = item.NextSibling {
+		if item.DataAtom != atom.Li {
+			continue
+		}
+		marker := "•"
+		if list.DataAtom == atom.Ol {
+			marker = strconv.Itoa(number) + "."
+			number++
+		}
+		if text := pdfText(item); text != "" {
+			w.listItem(marker, text)
+		}
+		for c := item.FirstChild; c != nil; c = c.NextSibling {
+			if c.DataAtom == atom.Ul || c.DataAtom == atom.Ol {
+				w.htmlList(c, title)
+			}
+		}
+	}
+}
+
+// pdfText returns the text of n with its whitespace collapsed, leaving out
+// nested lists.
+func pdfText(n *html.Node) string {
+	var b strings.Builder
+	walkHTML(n, func(c *html.Node) bool {
+		if c != n && (c.DataAtom == atom.Ul || c.DataAtom == atom.Ol) {
+			return false
+		}
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+		return true
+	})
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func (w *pdfWriter) subheading(text string, level int) {
+	size := 12.0
+	if level <= 2 {
+		size = 14
+	}
+	w.pdf.Ln(3)
+	w.pdf.SetFont(pdfFont, "B", size)
+	w.pdf.MultiCell(0, 7, w.text(text), "", "L", false)
+	w.pdf.Ln(1)
+}
+
+func (w *pdfWriter) paragraph(text string) {
+	w.pdf.SetFont(pdfFont, "", 10.5)
+	w.pdf.MultiCell(0, pdfLineHeight, w.text(text), "", "L", false)
+	w.pdf.Ln(2)
+}
+
+// listItem writes text with a hanging indent after its bullet or number.
+func (w *pdfWriter) listItem(marker, text string) {
+	left, _, _, _ := w.pdf.GetMargins()
+	w.pdf.SetFont(pdfFont, "", 10.5)
+	w.pdf.SetX(left + 2)
+	w.pdf.CellFormat(7, pdfLineHeight, w.text(marker), "", 0, "L", false, 0, "")
+	w.pdf.MultiCell(0, pdfLineHeight, w.text(text), "", "L", false)
+	w.pdf.Ln(0.8)
+}
+
+func (w *pdfWriter) rule() {
+	left, _, right, _ := w.pdf.GetMargins()
+	pageWidth, _ := w.pdf.GetPageSize()
+	y := w.pdf.GetY() + 2
+	w.pdf.SetDrawColor(200, 200, 200)
+	w.pdf.Line(left, y, pageWidth-right, y)
+	w.pdf.SetDrawColor(0, 0, 0)
+	w.pdf.SetY(y + 3)
+}
+
+// image draws a stored image centred at the current position, as wide as the
+// page allows but no taller than maxHeight. Images that can't be read are
+// left out.
+func (w *pdfWriter) image(imagePath string, maxHeight float64) {
+	info := w.loadImage(imagePath)
+	if info == nil {
+		return
+	}
+
+	width := w.contentWidth()
+	height := width * info.Height() / info.Width()
+	if height > maxHeight {
+		height = maxHeight
+		width = height * info.Width() / info.Height()
+	}
+
+	_, pageHeight := w.pdf.GetPageSize()
+	_, _, _, bottom := w.pdf.GetMargins()
+	if w.pdf.GetY()+height > pageHeight-bottom {
+		w.pdf.AddPage()
+	}
+
+	left, _, _, _ := w.pdf.GetMargins()
+	x := left + (w.contentWidth()-width)/2
+	y := w.pdf.GetY()
+	w.pdf.ImageOptions(imagePath, x, y, width, height, false, gofpdf.ImageOptions{ImageType: "JPG"}, 0, "")
+	w.pdf.SetY(y + height + 5)
+}
+
+// loadImage registers the stored image with the document, flattened onto
+// white and re-encoded as JPEG so any format the uploader accepts prints.
+func (w *pdfWriter) loadImage(imagePath string) *gofpdf.ImageInfoType {
+	if info, ok := w.images[imagePath]; ok {
+		return info
+	}
+	w.images[imagePath] = nil
+	if w.storage == nil || imagePath == "" {
+		return nil
+	}
+
+	file, err := w.storage.OpenImage(imagePath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil
+	}
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: 90}); err != nil {
+		return nil
+	}
+	info := w.pdf.RegisterImageOptionsReader(imagePath, gofpdf.ImageOptions{ImageType: "JPG"}, &buf)
+	if w.pdf.Err() {
+		return nil
+	}
+	w.images[imagePath] = info
+	return info
+}
+
+// cookbook writes the cover, the table of contents and every chapter, each
+// recipe starting on a new page. pages holds the page numbers to print in
+// the contents, keyed as in the map it returns; it is nil on a first pass.
+func (w *pdfWriter) cookbook(book *cookbook, pages map[string]int) map[string]int {
+	w.pdf.SetTitle(book.title, true)
+	w.pageNumbers(1)
+	w.cover(book)
+
+	links := map[string]int{}
+	w.pdf.AddPage()
+	w.pdf.SetFont(pdfFont, "B", 22)
+	w.pdf.MultiCell(0, 10, w.text("Contents"), "", "L", false)
+	w.pdf.Ln(4)
+	for i, chapter := range book.chapters {
+		key := chapterKey(i)
+		links[key] = w.pdf.AddLink()
+		w.pdf.Ln(2)
+		w.contentsEntry(chapter.name, pages[key], links[key], "B", 0)
+		for _, recipe := range chapter.recipes {
+			key := recipe.ID.String()
+			links[key] = w.pdf.AddLink()
+			w.contentsEntry(recipe.Title, pages[key], links[key], "", 5)
+		}
+	}
+
+	found := map[string]int{}
+	_, pageHeight := w.pdf.GetPageSize()
+	for i, chapter := range book.chapters {
+		key := chapterKey(i)
+		w.pdf.AddPage()
+		w.pdf.SetLink(links[key], 0, -1)
+		w.pdf.Bookmark(w.text(chapter.name), 0, 0)
+		found[key] = w.pdf.PageNo()
+
+		w.pdf.SetY(pageHeight / 3)
+		w.pdf.SetFont(pdfFont, "B", 28)
+		w.pdf.MultiCell(0, 12, w.text(chapter.name), "", "C", false)
+		if chapter.description != "" {
+			w.pdf.Ln(4)
+			w.pdf.SetFont(pdfFont, "I", 12)
+			w.pdf.MultiCell(0, 6, w.text(plainMarkdown(chapter.description)), "", "C", false)
+		}
+
+		for _, recipe := range chapter.recipes {
+			key := recipe.ID.String()
+			w.pdf.AddPage()
+			w.pdf.SetLink(links[key], 0, -1)
+			w.pdf.Bookmark(w.text(recipe.Title), 1, 0)
+			found[key] = w.pdf.PageNo()
+			w.recipe(recipe, "")
+		}
+	}
+	return found
+}
+
+func chapterKey(i int) string {
+	return fmt.Sprintf("chapter:%d", i)
+}
+
+// cover writes the title page, illustrated with the first recipe image that
+// can be printed.
+func (w *pdfWriter) cover(book *cookbook) {
+	w.pdf.AddPage()
+	_, pageHeight := w.pdf.GetPageSize()
+
+	w.pdf.SetY(pageHeight / 5)
+	w.pdf.SetFont(pdfFont, "B", 32)
+	w.pdf.MultiCell(0, 14, w.text(book.title), "", "C", false)
+	if book.subtitle != "" {
+		w.pdf.Ln(4)
+		w.pdf.SetFont(pdfFont, "I", 14)
+		w.pdf.MultiCell(0, 7, w.text(plainMarkdown(book.subtitle)), "", "C", false)
+	}
+	w.pdf.Ln(10)
+
+	if imagePath := w.coverImage(book); imagePath != "" {
+		w.image(imagePath, pageHeight/3)
+	}
+
+	_, _, _, bottom := w.pdf.GetMargins()
+	w.pdf.SetY(pageHeight - bottom - 10)
+	w.pdf.SetFont(pdfFont, "", 10)
+	w.pdf.SetTextColor(120, 120, 120)
+	w.pdf.CellFormat(0, 5, w.text(book.printed.Format("January 2006")), "", 1, "C", false, 0, "")
+	w.pdf.SetTextColor(0, 0, 0)
+}
+
+func (w *pdfWriter) coverImage(book *cookbook) string {
+	for _, chapter := range book.chapters {
+		for _, recipe := range chapter.recipes {
+			if recipe.FeaturedImagePath != nil && w.loadImage(*recipe.FeaturedImagePath) != nil {
+				return *recipe.FeaturedImagePath
+			}
+		}
+	}
+	return ""
+}
+
+// contentsEntry writes one line of the table of contents, linked to the
+// page it names.
+func (w *pdfWriter) contentsEntry(title string, page int, link int, style string, indent float64) {
+	const numberWidth = 12.0
+
+	left, _, _, _ := w.pdf.GetMargins()
+	w.pdf.SetFont(pdfFont, style, 11)
+	titleWidth := w.contentWidth() - indent - numberWidth
+	text := w.text(title)
+	if w.pdf.GetStringWidth(text) > titleWidth {
+		for len(text) > 0 && w.pdf.GetStringWidth(text+"...") > titleWidth {
+			text = text[:len(text)-1]
+		}
+		text += "..."
+	}
+
+	number := ""
+	if page > 0 {
+		number = fmt.Sprintf("%d", page)
+	}
+	w.pdf.SetX(left + indent)
+	w.pdf.CellFormat(titleWidth, 6.5, text, "", 0, "L", false, link, "")
+	w.pdf.CellFormat(numberWidth, 6.5, number, "", 1, "R", false, link, "")
+}
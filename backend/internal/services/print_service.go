@@ -0,0 +1,260 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+// pageSizes maps the page sizes documents can be printed on to gofpdf's
+// names for them.
+var pageSizes = map[string]string{
+	"a4":     "A4",
+	"a5":     "A5",
+	"letter": "Letter",
+}
+
+// uncategorizedChapter collects cookbook recipes without a category.
+const uncategorizedChapter = "More Recipes"
+
+type PrintService struct {
+	recipeService   *RecipeService
+	categoryService *CategoryService
+	groupService    *RecipeGroupService
+	storageService  *StorageService
+}
+
+func NewPrintService(recipeService *RecipeService, categoryService *CategoryService, groupService *RecipeGroupService, storageService *StorageService) *PrintService {
+	return &PrintService{
+		recipeService:   recipeService,
+		categoryService: categoryService,
+		groupService:    groupService,
+		storageService:  storageService,
+	}
+}
+
+// cookbook is the content of a printed cookbook: a cover and one chapter
+// per category.
+type cookbook struct {
+	title    string
+	subtitle string
+	printed  time.Time
+	chapters []cookbookChapter
+}
+
+type cookbookChapter struct {
+	name        string
+	description string
+	recipes     []*models.Recipe
+}
+
+// RecipeCard renders one recipe as a printable card, on A5 unless another
+// page size is asked for.
+func (s *PrintService) RecipeCard(id string, pageSize string) (*models.PDFDocument, error) {
+	size, err := parsePageSize(pageSize, "A5")
+	if err != nil {
+		return nil, err
+	}
+
+	if parseUUID(&id) == nil {
+		return nil, errors.New("recipe not found")
+	}
+	recipe, err := s.recipeService.GetRecipe(id)
+	if err != nil {
+		return nil, errors.New("recipe not found")
+	}
+
+	w := newPDFWriter(size, s.storageService)
+	w.pdf.SetTitle(recipe.Title, true)
+	w.pageNumbers(1)
+	w.pdf.AddPage()
+	w.recipe(recipe, s.categoryName(recipe))
+
+	content, err := w.bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &models.PDFDocument{Filename: recipe.Slug + ".pdf", Content: content}, nil
+}
+
+// GroupCookbook renders the group's published recipes as a cookbook with a
+// chapter per category.
+func (s *PrintService) GroupCookbook(groupID string, pageSize string) (*models.PDFDocument, error) {
+	size, err := parsePageSize(pageSize, "A4")
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := s.groupService.GetByID(groupID)
+	if err != nil {
+		return nil, errors.New("group not found")
+	}
+
+	members, err := s.groupService.GetRecipesInGroup(group.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	recipes := make([]*models.Recipe, 0, len(members))
+	for _, member := range members {
+		recipe, err := s.recipeService.GetRecipe(member.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	chapters, err := s.categoryChapters(recipes)
+	if err != nil {
+		return nil, err
+	}
+
+	book := &cookbook{title: group.Name, printed: time.Now(), chapters: chapters}
+	if group.Description != nil {
+		book.subtitle = *group.Description
+	}
+	return s.renderCookbook(book, group.Slug, size)
+}
+
+// CategoryCookbook renders the published recipes of a category as a
+// cookbook of one chapter.
+func (s *PrintService) CategoryCookbook(categoryID string, pageSize string) (*models.PDFDocument, error) {
+	size, err := parsePageSize(pageSize, "A4")
+	if err != nil {
+		return nil, err
+	}
+
+	if parseUUID(&categoryID) == nil {
+		return nil, errors.New("category not found")
+	}
+	category, err := s.categoryService.GetCategory(categoryID)
+	if err != nil {
+		return nil, errors.New("category not found")
+	}
+
+	var recipes []*models.Recipe
+	cursor := ""
+	for {
+		filter := &models.RecipeFilter{Category: category.ID.String(), Sort: models.RecipeSortTitle}
+		list, err := s.recipeService.FilterRecipes(filter, maxPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			recipe, err := s.recipeService.GetRecipe(item.ID.String())
+			if err != nil {
+				return nil, err
+			}
+			recipes = append(recipes, recipe)
+		}
+		if list.NextCursor == nil {
+			break
+		}
+		cursor = *list.NextCursor
+	}
+
+	chapter := cookbookChapter{name: category.Name, recipes: recipes}
+	book := &cookbook{title: category.Name, printed: time.Now(), chapters: []cookbookChapter{chapter}}
+	if category.Description != nil {
+		book.subtitle = *category.Description
+	}
+	return s.renderCookbook(book, category.Slug, size)
+}
+
+// categoryChapters sorts recipes into chapters in the categories' display
+// order, with uncategorized recipes last. Recipes within a chapter are
+// sorted by title.
+func (s *PrintService) categoryChapters(recipes []*models.Recipe) ([]cookbookChapter, error) {
+	categories, err := s.categoryService.ListCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	var chapters []cookbookChapter
+	placed := map[*models.Recipe]bool{}
+	for _, category := range categories {
+		chapter := cookbookChapter{name: category.Name}
+		if category.Description != nil {
+			chapter.description = *category.Description
+		}
+		for _, recipe := range recipes {
+			if recipe.CategoryID != nil && *recipe.CategoryID == category.ID {
+				chapter.recipes = append(chapter.recipes, recipe)
+				placed[recipe] = true
+			}
+		}
+		if len(chapter.recipes) > 0 {
+			chapters = append(chapters, chapter)
+		}
+	}
+
+	rest := cookbookChapter{name: uncategorizedChapter}
+	for _, recipe := range recipes {
+		if !placed[recipe] {
+			rest.recipes = append(rest.recipes, recipe)
+		}
+	}
+	if len(rest.recipes) > 0 {
+		chapters = append(chapters, rest)
+	}
+
+	for _, chapter := range chapters {
+		sort.SliceStable(chapter.recipes, func(i, j int) bool {
+			return strings.ToLower(chapter.recipes[i].Title) < strings.ToLower(chapter.recipes[j].Title)
+		})
+	}
+	return chapters, nil
+}
+
+// renderCookbook lays the book out twice: the first pass finds the page
+// every chapter and recipe starts on, the second prints those pages in the
+// table of contents.
+func (s *PrintService) renderCookbook(book *cookbook, slug string, size string) (*models.PDFDocument, error) {
+	empty := true
+	for _, chapter := range book.chapters {
+		if len(chapter.recipes) > 0 {
+			empty = false
+		}
+	}
+	if empty {
+		return nil, errors.New("no recipes to print")
+	}
+
+	draft := newPDFWriter(size, s.storageService)
+	pages := draft.cookbook(book, nil)
+	if err := draft.pdf.Error(); err != nil {
+		return nil, err
+	}
+
+	w := newPDFWriter(size, s.storageService)
+	w.cookbook(book, pages)
+	content, err := w.bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &models.PDFDocument{Filename: slug + "-cookbook.pdf", Content: content}, nil
+}
+
+func (s *PrintService) categoryName(recipe *models.Recipe) string {
+	if recipe.CategoryID == nil {
+		return ""
+	}
+	category, err := s.categoryService.GetCategory(recipe.CategoryID.String())
+	if err != nil {
+		return ""
+	}
+	return category.Name
+}
+
+func parsePageSize(pageSize string, fallback string) (string, error) {
+	if pageSize == "" {
+		return fallback, nil
+	}
+	size, ok := pageSizes[strings.ToLower(pageSize)]
+	if !ok {
+		return "", errors.New("invalid page size")
+	}
+	return size, nil
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const printTestMarkdown = `# Apple Pie
+
+## Ingredients
+
+- 6 apples, sliced
+- ⅓ cup sugar
+- 1 tsp cinnamon
+
+## Instructions
+
+1. Heat the oven to 200°C.
+2. Fill the crust and bake for 45 minutes.
+
+---
+
+Best served *warm*.`
+
+func TestPrintService_RecipeCard(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	storage := NewStorageService(t.TempDir(), 10*1024*1024)
+	var pngData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, image.NewNRGBA(image.Rect(0, 0, 40, 30))))
+	filename, err := storage.SaveImageFrom(&pngData, ".png", "recipe")
+	require.NoError(t, err)
+
+	recipeService := newTestRecipeService(db, q)
+	categoryService := NewCategoryService(repository.NewCategoryRepository(db, q), repository.NewRecipeRepository(db, q))
+	service := NewPrintService(recipeService, categoryService, NewRecipeGroupService(repository.NewRecipeGroupRepository(db, q)), storage)
+
+	authorID := createTestUser(db, q, "test@example.com")
+	recipe, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:             "Apple Pie",
+		MarkdownContent:   printTestMarkdown,
+		Servings:          int32Ptr(8),
+		PrepTimeMinutes:   int32Ptr(30),
+		CookTimeMinutes:   int32Ptr(75),
+		FeaturedImagePath: stringPtr("/uploads/" + filename),
+	}, authorID)
+	require.NoError(t, err)
+
+	doc, err := service.RecipeCard(recipe.ID.String(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "apple-pie.pdf", doc.Filename)
+	assert.True(t, bytes.HasPrefix(doc.Content, []byte("%PDF-")))
+	assert.Contains(t, string(doc.Content), "/Subtype /Image")
+
+	_, err = service.RecipeCard(recipe.ID.String(), "letter")
+	assert.NoError(t, err)
+
+	_, err = service.RecipeCard(recipe.ID.String(), "tabloid")
+	assert.EqualError(t, err, "invalid page size")
+
+	_, err = service.RecipeCard(uuid.New().String(), "")
+	assert.EqualError(t, err, "recipe not found")
+}
+
+func TestPrintService_GroupCookbook(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	recipeService := newTestRecipeService(db, q)
+	categoryService := NewCategoryService(repository.NewCategoryRepository(db, q), repository.NewRecipeRepository(db, q))
+	groupService := NewRecipeGroupService(repository.NewRecipeGroupRepository(db, q))
+	service := NewPrintService(recipeService, categoryService, groupService, NewStorageService(t.TempDir(), 10*1024*1024))
+
+	authorID := createTestUser(db, q, "test@example.com")
+	desserts, err := categoryService.CreateCategory(&models.Category{Name: "Desserts", OrderIndex: 2})
+	require.NoError(t, err)
+	mains, err := categoryService.CreateCategory(&models.Category{Name: "Mains", OrderIndex: 1})
+	require.NoError(t, err)
+
+	group, err := groupService.Create(&models.RecipeGroup{Name: "Holiday Favourites", Description: stringPtr("Grandma's best")})
+	require.NoError(t, err)
+
+	_, err = service.GroupCookbook(group.ID.String(), "")
+	assert.EqualError(t, err, "no recipes to print")
+
+	for _, r := range []struct {
+		title    string
+		category *string
+	}{
+		{"Roast Turkey", stringPtr(mains.ID.String())},
+		{"Apple Pie", stringPtr(desserts.ID.String())},
+		{"Banana Bread", stringPtr(desserts.ID.String())},
+		{"Eggnog", nil},
+	} {
+		recipe, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+			Title:           r.title,
+			MarkdownContent: printTestMarkdown,
+			CategoryID:      r.category,
+			IsPublished:     true,
+		}, authorID)
+		require.NoError(t, err)
+		require.NoError(t, groupService.AddRecipeToGroup(group.ID.String(), recipe.ID.String()))
+	}
+
+	doc, err := service.GroupCookbook(group.ID.String(), "a5")
+	require.NoError(t, err)
+	assert.Equal(t, "holiday-favourites-cookbook.pdf", doc.Filename)
+	assert.True(t, bytes.HasPrefix(doc.Content, []byte("%PDF-")))
+	assert.Contains(t, string(doc.Content), "/Outlines")
+
+	_, err = service.GroupCookbook(uuid.New().String(), "")
+	assert.EqualError(t, err, "group not found")
+
+	recipes, err := groupService.GetRecipesInGroup(group.ID.String())
+	require.NoError(t, err)
+	chapters, err := service.categoryChapters(recipes)
+	require.NoError(t, err)
+
+	var names []string
+	for _, chapter := range chapters {
+		var titles []string
+		for _, recipe := range chapter.recipes {
+			titles = append(titles, recipe.Title)
+		}
+		names = append(names, chapter.name+": "+strings.Join(titles, ", "))
+	}
+	assert.Equal(t, []string{
+		"Mains: Roast Turkey",
+		"Desserts: Apple Pie, Banana Bread",
+		"More Recipes: Eggnog",
+	}, names)
+}
+
+func TestPrintService_CategoryCookbook(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	recipeService := newTestRecipeService(db, q)
+	categoryService := NewCategoryService(repository.NewCategoryRepository(db, q), repository.NewRecipeRepository(db, q))
+	service := NewPrintService(recipeService, categoryService, NewRecipeGroupService(repository.NewRecipeGroupRepository(db, q)), nil)
+
+	authorID := createTestUser(db, q, "test@example.com")
+	category, err := categoryService.CreateCategory(&models.Category{Name: "Soups"})
+	require.NoError(t, err)
+
+	_, err = service.CategoryCookbook(category.ID.String(), "")
+	assert.EqualError(t, err, "no recipes to print")
+
+	_, err = recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Tomato Soup",
+		MarkdownContent: "## Ingredients\n\n- 1 kg tomatoes",
+		CategoryID:      stringPtr(category.ID.String()),
+		IsPublished:     true,
+	}, authorID)
+	require.NoError(t, err)
+
+	doc, err := service.CategoryCookbook(category.ID.String(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "soups-cookbook.pdf", doc.Filename)
+
+	_, err = service.CategoryCookbook(uuid.New().String(), "")
+	assert.EqualError(t, err, "category not found")
+
+	_, err = service.CategoryCookbook("soups", "")
+	assert.EqualError(t, err, "category not found")
+}
+
+func TestPDFWriter_CookbookPages(t *testing.T) {
+	recipe := func(title string) *models.Recipe {
+		return &models.Recipe{ID: uuid.New(), Title: title, MarkdownContent: printTestMarkdown}
+	}
+	book := &cookbook{
+		title:   "Family Cookbook",
+		printed: time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+		chapters: []cookbookChapter{
+			{name: "Mains", recipes: []*models.Recipe{recipe("Roast Turkey"), recipe("Ham")}},
+			{name: "Desserts", recipes: []*models.Recipe{recipe("Apple Pie")}},
+		},
+	}
+
+	w := newPDFWriter("A4", nil)
+	pages := w.cookbook(book, nil)
+	require.NoError(t, w.pdf.Error())
+
+	// Cover, contents, then each chapter's title page before its recipes.
+	assert.Equal(t, map[string]int{
+		"chapter:0":                             3,
+		book.chapters[0].recipes[0].ID.String(): 4,
+		book.chapters[0].recipes[1].ID.String(): 5,
+		"chapter:1":                             6,
+		book.chapters[1].recipes[0].ID.String(): 7,
+	}, pages)
+
+	w = newPDFWriter("A4", nil)
+	w.pdf.SetCompression(false)
+	assert.Equal(t, pages, w.cookbook(book, pages))
+	content, err := w.bytes()
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "(Contents)")
+	assert.Contains(t, string(content), "(December 2026)")
+	assert.Contains(t, string(content), "(1/3 cup sugar)")
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/models"
+)
+
+// ForkRecipe copies the recipe with the given ID into a new, unpublished
+// recipe owned by userID. The fork keeps the original's content, tags,
+// category, groups and images but is otherwise independent of it. Only
+// published recipes can be forked, apart from the caller's own drafts.
+func (s *RecipeService) ForkRecipe(id string, req *models.ForkRecipeRequest, userID string) (*models.Recipe, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, sql.ErrNoRows
+	}
+	original, err := s.recipeRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	authorUUID := uuid.MustParse(userID)
+	isAuthor := original.AuthorID != nil && *original.AuthorID == authorUUID
+	if !original.IsPublished && !isAuthor {
+		return nil, errors.New("can only fork published recipes")
+	}
+
+	title := original.Title
+	if req.Title != nil {
+		title = strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, errors.New("title is required")
+		}
+	}
+
+	slug, err := uniqueSlug(title, "recipe", slugOwner("", s.recipeIDBySlug))
+	if err != nil {
+		return nil, err
+	}
+
+	fork, err := s.recipeRepo.Fork(id, &models.Recipe{
+		Title:             title,
+		Slug:              slug,
+		MarkdownContent:   original.MarkdownContent,
+		AuthorID:          &authorUUID,
+		CategoryID:        original.CategoryID,
+		Description:       original.Description,
+		PrepTimeMinutes:   original.PrepTimeMinutes,
+		CookTimeMinutes:   original.CookTimeMinutes,
+		Servings:          original.Servings,
+		Difficulty:        original.Difficulty,
+		FeaturedImagePath: original.FeaturedImagePath,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recipeRepo.ReleaseOldSlug(fork.Slug); err != nil {
+		return nil, err
+	}
+	if err := s.syncIngredients(fork); err != nil {
+		return nil, err
+	}
+	if err := s.syncLinks(fork); err != nil {
+		return nil, err
+	}
+	if err := s.recordRevision(fork, &authorUUID, nil); err != nil {
+		return nil, err
+	}
+	if err := s.recipeRepo.IndexForSearch(fork.ID.String()); err != nil {
+		return nil, err
+	}
+	fork.ForkedFromID = &original.ID
+	return fork, nil
+}
+
+// loadForks fills in the recipe the recipe was forked from and the
+// published recipes forked from it.
+func (s *RecipeService) loadForks(recipe *models.Recipe) error {
+	forkedFromID, err := s.recipeRepo.GetForkedFrom(recipe.ID.String())
+	if err != nil {
+		return err
+	}
+	forks, err := s.recipeRepo.GetForks(recipe.ID.String())
+	if err != nil {
+		return err
+	}
+	recipe.ForkedFromID = forkedFromID
+	recipe.Forks = forks
+	return nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeService_ForkRecipe(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	categoryService := NewCategoryService(repository.NewCategoryRepository(db, q), repository.NewRecipeRepository(db, q))
+	authorID := createTestUser(db, q, "author@example.com")
+	forkerID := createTestUser(db, q, "forker@example.com")
+
+	desserts, err := categoryService.CreateCategory(&models.Category{Name: "Desserts"})
+	require.NoError(t, err)
+	categoryID := desserts.ID.String()
+	original, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Apple Pie",
+		MarkdownContent: "## Ingredients\n\n- 6 apples\n\n## Instructions\n\n1. Bake.",
+		CategoryID:      &categoryID,
+		Servings:        int32Ptr(8),
+		IsPublished:     true,
+		Tags:            []string{"Baking"},
+	}, authorID)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO recipe_groups (id, name, slug) VALUES (?, ?, ?)`, uuid.New().String(), "Autumn", "autumn")
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO recipe_groupings (group_id, recipe_id, order_index) SELECT id, ?, 0 FROM recipe_groups`, original.ID.String())
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO recipe_images (id, recipe_id, file_path, order_index) VALUES (?, ?, ?, 0)`, uuid.New().String(), original.ID.String(), "/uploads/pie.png")
+	require.NoError(t, err)
+
+	fork, err := service.ForkRecipe(original.ID.String(), &models.ForkRecipeRequest{}, forkerID)
+	require.NoError(t, err)
+	assert.NotEqual(t, original.ID, fork.ID)
+	assert.Equal(t, "Apple Pie", fork.Title)
+	assert.Equal(t, "apple-pie-2", fork.Slug)
+	assert.Equal(t, forkerID, fork.AuthorID.String())
+	assert.Equal(t, desserts.ID, *fork.CategoryID)
+	assert.Equal(t, int32(8), *fork.Servings)
+	assert.False(t, fork.IsPublished)
+
+	var tags, groups int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM recipe_tags WHERE recipe_id = ?`, fork.ID.String()).Scan(&tags))
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM recipe_groupings WHERE recipe_id = ?`, fork.ID.String()).Scan(&groups))
+	assert.Equal(t, 1, tags)
+	assert.Equal(t, 1, groups)
+	var imagePath string
+	require.NoError(t, db.QueryRow(`SELECT file_path FROM recipe_images WHERE recipe_id = ?`, fork.ID.String()).Scan(&imagePath))
+	assert.Equal(t, "/uploads/pie.png", imagePath)
+
+	loaded, err := service.GetRecipe(fork.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, original.ID, *loaded.ForkedFromID)
+	require.Len(t, loaded.Ingredients, 1)
+
+	// Unpublished forks aren't listed on the original.
+	loaded, err = service.GetRecipe(original.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, loaded.ForkedFromID)
+	assert.Empty(t, loaded.Forks)
+
+	_, err = db.Exec(`UPDATE recipes SET is_published = 1 WHERE id = ?`, fork.ID.String())
+	require.NoError(t, err)
+	loaded, err = service.GetRecipeBySlug("apple-pie")
+	require.NoError(t, err)
+	require.Len(t, loaded.Forks, 1)
+	assert.Equal(t, fork.ID, *loaded.Forks[0].RecipeID)
+	assert.Equal(t, "apple-pie-2", loaded.Forks[0].Slug)
+
+	// Editing the fork leaves the original alone.
+	_, err = db.Exec(`DELETE FROM recipe_tags WHERE recipe_id = ?`, fork.ID.String())
+	require.NoError(t, err)
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM recipe_tags WHERE recipe_id = ?`, original.ID.String()).Scan(&tags))
+	assert.Equal(t, 1, tags)
+
+	renamed, err := service.ForkRecipe(original.ID.String(), &models.ForkRecipeRequest{Title: stringPtr("Pear Pie")}, forkerID)
+	require.NoError(t, err)
+	assert.Equal(t, "pear-pie", renamed.Slug)
+
+	_, err = service.ForkRecipe(original.ID.String(), &models.ForkRecipeRequest{Title: stringPtr("  ")}, forkerID)
+	assert.EqualError(t, err, "title is required")
+}
+
+func TestRecipeService_ForkRecipe_Draft(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "author@example.com")
+	otherID := createTestUser(db, q, "other@example.com")
+
+	draft, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Secret Stew",
+		MarkdownContent: "Simmer.",
+	}, authorID)
+	require.NoError(t, err)
+
+	_, err = service.ForkRecipe(draft.ID.String(), &models.ForkRecipeRequest{}, otherID)
+	assert.EqualError(t, err, "can only fork published recipes")
+
+	fork, err := service.ForkRecipe(draft.ID.String(), &models.ForkRecipeRequest{}, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-stew-2", fork.Slug)
+}
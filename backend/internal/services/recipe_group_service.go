@@ -1,9 +1,6 @@
 package services
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/google/uuid"
 	"github.com/homecooking/backend/internal/models"
 	"github.com/homecooking/backend/internal/repository"
@@ -20,9 +17,11 @@ func NewRecipeGroupService(repo *repository.RecipeGroupRepository) *RecipeGroupS
 }
 
 func (s *RecipeGroupService) Create(group *models.RecipeGroup) (*models.RecipeGroup, error) {
-	if group.Slug == "" {
-		group.Slug = s.GenerateSlug(group.Name)
+	slug, err := uniqueSlug(slugSource(group.Slug, group.Name), "group", slugOwner("", s.groupIDBySlug))
+	if err != nil {
+		return nil, err
 	}
+	group.Slug = slug
 	return s.repo.Create(group)
 }
 
@@ -34,8 +33,8 @@ func (s *RecipeGroupService) GetBySlug(slug string) (*models.RecipeGroup, error)
 	return s.repo.GetBySlug(slug)
 }
 
-func (s *RecipeGroupService) List() ([]*models.RecipeGroup, error) {
-	return s.repo.List()
+func (s *RecipeGroupService) List(limit int, cursor string) (*models.Page[*models.RecipeGroup], error) {
+	return s.repo.List(pageSize(limit), cursor)
 }
 
 func (s *RecipeGroupService) Update(id string, group *models.RecipeGroup) (*models.RecipeGroup, error) {
@@ -46,9 +45,11 @@ func (s *RecipeGroupService) Update(id string, group *models.RecipeGroup) (*mode
 		}
 	}
 
-	if group.Slug == "" {
-		group.Slug = s.GenerateSlug(group.Name)
+	slug, err := uniqueSlug(slugSource(group.Slug, group.Name), "group", slugOwner(id, s.groupIDBySlug))
+	if err != nil {
+		return nil, err
 	}
+	group.Slug = slug
 	return s.repo.Update(id, group)
 }
 
@@ -68,18 +69,10 @@ func (s *RecipeGroupService) GetRecipesInGroup(groupID string) ([]*models.Recipe
 	return s.repo.GetRecipesInGroup(groupID)
 }
 
-func (s *RecipeGroupService) GenerateSlug(name string) string {
-	slug := strings.ToLower(strings.ReplaceAll(name, " ", "-"))
-	slug = strings.Map(func(r rune) rune {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
-			return r
-		}
-		return '-'
-	}, slug)
-
-	slug = strings.Trim(slug, "-")
-	if slug == "" {
-		slug = fmt.Sprintf("group-%s", uuid.New().String()[:8])
+func (s *RecipeGroupService) groupIDBySlug(slug string) (uuid.UUID, error) {
+	group, err := s.repo.GetBySlug(slug)
+	if err != nil {
+		return uuid.Nil, err
 	}
-	return slug
+	return group.ID, nil
 }
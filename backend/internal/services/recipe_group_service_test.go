@@ -139,9 +139,10 @@ func TestRecipeGroup_List(t *testing.T) {
 	_, err = groupRepo.Create(group2)
 	require.NoError(t, err)
 
-	groups, err := service.List()
+	groups, err := service.List(20, "")
 	require.NoError(t, err)
-	assert.Len(t, groups, 2)
+	assert.Len(t, groups.Items, 2)
+	assert.Equal(t, 2, groups.Total)
 }
 
 func TestRecipeGroup_List_Empty(t *testing.T) {
@@ -152,9 +153,10 @@ func TestRecipeGroup_List_Empty(t *testing.T) {
 	groupRepo := repository.NewRecipeGroupRepository(db, q)
 	service := NewRecipeGroupService(groupRepo)
 
-	groups, err := service.List()
+	groups, err := service.List(20, "")
 	require.NoError(t, err)
-	assert.Len(t, groups, 0)
+	assert.Len(t, groups.Items, 0)
+	assert.Nil(t, groups.NextCursor)
 }
 
 func TestRecipeGroup_Update(t *testing.T) {
@@ -370,14 +372,15 @@ func TestRecipeGroup_GenerateSlug(t *testing.T) {
 	}{
 		{"simple", "Breakfast", "breakfast"},
 		{"with spaces", "Comfort Food", "comfort-food"},
-		{"with special chars", "Sunday's Best!", "sunday-s-best"},
+		{"with special chars", "Sunday's Best!", "sundays-best"},
 		{"already lowercase", "dinner", "dinner"},
-		{"with multiple spaces", "  Quick  Meals  ", "quick--meals"},
+		{"with multiple spaces", "  Quick  Meals  ", "quick-meals"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			slug := service.GenerateSlug(tt.input)
+			slug, err := uniqueSlug(tt.input, "group", slugOwner("", service.groupIDBySlug))
+			require.NoError(t, err)
 			assert.Equal(t, tt.expected, slug)
 		})
 	}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+// Limits on what an uploaded export may unpack to.
+const (
+	maxArchiveEntries   = 5000
+	maxArchiveEntrySize = 20 << 20
+	maxArchiveSize      = 256 << 20
+)
+
+var (
+	durationUnitPattern  = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(days?|d|hours?|hrs?|h|minutes?|mins?|m)\b`)
+	durationClockPattern = regexp.MustCompile(`^(\d+):(\d{1,2})(?::\d{1,2})?$`)
+	bareNumberPattern    = regexp.MustCompile(`^\d+(?:[.,]\d+)?$`)
+)
+
+// RecipeImporter reads the recipes out of an export of another recipe
+// manager. Import gets the uploaded file's name and content and returns the
+// recipes it holds, or an error when the file isn't in the importer's format.
+type RecipeImporter interface {
+	Format() string
+	Import(name string, data []byte) ([]*models.ImportedRecipe, error)
+}
+
+// recipeImporters are the formats files can be imported from.
+var recipeImporters = []RecipeImporter{
+	paprikaImporter{},
+	mealieImporter{},
+	mealMasterImporter{},
+	cooklangImporter{},
+}
+
+// importFormatExtensions picks an importer from the file's extension when
+// the upload doesn't name one.
+var importFormatExtensions = map[string]string{
+	".paprikarecipes": "paprika",
+	".paprikarecipe":  "paprika",
+	".json":           "mealie",
+	".zip":            "mealie",
+	".mmf":            "mealmaster",
+	".mm":             "mealmaster",
+	".txt":            "mealmaster",
+	".cook":           "cooklang",
+}
+
+// recipeImporter returns the importer for format, detecting it from the
+// filename when format is empty.
+func recipeImporter(format, filename string) (RecipeImporter, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = importFormatExtensions[strings.ToLower(path.Ext(filename))]
+	}
+	for _, importer := range recipeImporters {
+		if importer.Format() == format {
+			return importer, nil
+		}
+	}
+	return nil, errors.New("unsupported import format")
+}
+
+// contentSection is a named group of ingredients or steps. Recipes without
+// sub-headings have a single section without a name.
+type contentSection struct {
+	name  string
+	items []string
+}
+
+// importedContent is the text of an imported recipe, laid out as markdown
+// the way recipes written here are.
+type importedContent struct {
+	ingredients  []contentSection
+	instructions []contentSection
+	notes        []string
+	source       string
+}
+
+// markdown writes the ingredients as a list and the instructions as
+// numbered steps, with sub-headings for named sections, followed by the
+// notes and where the recipe came from.
+func (c importedContent) markdown() string {
+	var b strings.Builder
+	writeSections := func(heading string, sections []contentSection, item func(i int, text string) string) {
+		if !hasContent(sections) {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("## " + heading + "\n")
+		for _, section := range sections {
+			if len(section.items) == 0 {
+				continue
+			}
+			b.WriteString("\n")
+			if section.name != "" {
+				b.WriteString("### " + section.name + "\n\n")
+			}
+			for i, text := range section.items {
+				b.WriteString(item(i, text) + "\n")
+			}
+		}
+	}
+	writeSections("Ingredients", c.ingredients, func(_ int, text string) string {
+		return "- " + text
+	})
+	writeSections("Instructions", c.instructions, func(i int, text string) string {
+		return fmt.Sprintf("%d. %s", i+1, text)
+	})
+
+	if len(c.notes) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("## Notes\n\n" + strings.Join(c.notes, "\n\n") + "\n")
+	}
+
+	if source := strings.TrimSpace(c.source); source != "" {
+		if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+			source = "<" + source + ">"
+		}
+		fmt.Fprintf(&b, "\nSource: %s\n", source)
+	}
+	return b.String()
+}
+
+func hasContent(sections []contentSection) bool {
+	for _, section := range sections {
+		if len(section.items) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueTags drops empty names and names that resolve to the same tag.
+func uniqueTags(names []string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if slug := generateSlug(name); slug != "" && !seen[slug] {
+			seen[slug] = true
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+// setTaxonomy files the recipe under the first of the source's categories
+// and keeps the rest, with its tags, as tags.
+func setTaxonomy(recipe *models.ImportedRecipe, categories []string, tags []string) {
+	var rest []string
+	for _, category := range categories {
+		category = strings.TrimSpace(category)
+		if category == "" {
+			continue
+		}
+		if recipe.Category == "" {
+			recipe.Category = category
+			continue
+		}
+		rest = append(rest, category)
+	}
+	recipe.Recipe.Tags = uniqueTags(append(rest, tags...))
+}
+
+// addImage attaches data as an image of the recipe when it is one in a
+// format uploads accept.
+func addImage(recipe *models.ImportedRecipe, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	ext, ok := imageExtensions[http.DetectContentType(data)]
+	if !ok {
+		recipe.Warnings = append(recipe.Warnings, "skipped an image in an unsupported format")
+		return
+	}
+	recipe.Images = append(recipe.Images, models.ImportedImage{Data: data, Ext: ext})
+}
+
+// parseDurationText reads the free-form times exports carry: ISO 8601
+// durations, "1 hr 30 mins", "1:30" and bare minutes.
+func parseDurationText(text string) *int32 {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if minutes := isoDurationMinutes(text); minutes != nil {
+		return minutes
+	}
+
+	var minutes float64
+	switch {
+	case bareNumberPattern.MatchString(text):
+		minutes = parseDecimal(text)
+	case durationClockPattern.MatchString(text):
+		match := durationClockPattern.FindStringSubmatch(text)
+		hours, _ := strconv.Atoi(match[1])
+		mins, _ := strconv.Atoi(match[2])
+		minutes = float64(hours*60 + mins)
+	default:
+		for _, match := range durationUnitPattern.FindAllStringSubmatch(text, -1) {
+			value := parseDecimal(match[1])
+			switch unit := strings.ToLower(match[2]); {
+			case strings.HasPrefix(unit, "d"):
+				minutes += value * 24 * 60
+			case strings.HasPrefix(unit, "h"):
+				minutes += value * 60
+			default:
+				minutes += value
+			}
+		}
+	}
+	if minutes <= 0 || minutes > math.MaxInt32 {
+		return nil
+	}
+	rounded := int32(math.Ceil(minutes))
+	return &rounded
+}
+
+func parseDecimal(s string) float64 {
+	value, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// isZip reports whether data starts like a zip archive.
+func isZip(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04"))
+}
+
+// zipEntry is a file read out of an archive.
+type zipEntry struct {
+	name string
+	data []byte
+}
+
+// readZip returns the files in a zip archive, refusing archives with more
+// entries, bigger files or more data in total than an export plausibly has.
+func readZip(data []byte) ([]zipEntry, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	if len(archive.File) > maxArchiveEntries {
+		return nil, errors.New("invalid archive: too many files")
+	}
+
+	var entries []zipEntry
+	remaining := int64(maxArchiveSize)
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		content, err := readZipFile(file, maxArchiveEntrySize, &remaining)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive: %s: %w", file.Name, err)
+		}
+		entries = append(entries, zipEntry{name: file.Name, data: content})
+	}
+	return entries, nil
+}
+
+// readZipFile reads an archive entry of at most limit bytes, counting it
+// against the bytes remaining for the whole archive. The sizes the archive
+// claims are checked before anything is unpacked, and the actual ones while
+// reading.
+func readZipFile(file *zip.File, limit int64, remaining *int64) ([]byte, error) {
+	if file.UncompressedSize64 > uint64(limit) {
+		return nil, errors.New("file too large")
+	}
+	if file.UncompressedSize64 > uint64(*remaining) {
+		return nil, errors.New("archive too large")
+	}
+	data, err := readLimited(file.Open, min(limit, *remaining))
+	if err != nil {
+		return nil, err
+	}
+	*remaining -= int64(len(data))
+	return data, nil
+}
+
+// readLimited reads what open returns, failing once it passes limit bytes.
+func readLimited(open func() (io.ReadCloser, error), limit int64) ([]byte, error) {
+	r, err := open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, errors.New("file too large")
+	}
+	return data, nil
+}
+
+// textParagraphs splits text into paragraphs at blank lines, joining the
+// lines within each.
+func textParagraphs(text string) []string {
+	var paragraphs, lines []string
+	flush := func() {
+		if len(lines) > 0 {
+			paragraphs = append(paragraphs, strings.Join(lines, " "))
+			lines = nil
+		}
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line == "" {
+			flush()
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return paragraphs
+}
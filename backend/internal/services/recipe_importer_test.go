@@ -0,0 +1,429 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testZip(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func testPNG(t *testing.T) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 4, 4))))
+	return buf.Bytes()
+}
+
+func TestRecipeImporter_DetectsFormat(t *testing.T) {
+	tests := []struct {
+		format, filename, expected string
+	}{
+		{"", "Export.paprikarecipes", "paprika"},
+		{"", "recipes.JSON", "mealie"},
+		{"", "cookbook.mmf", "mealmaster"},
+		{"", "Pancakes.cook", "cooklang"},
+		{"Cooklang", "recipes.zip", "cooklang"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			importer, err := recipeImporter(tt.format, tt.filename)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, importer.Format())
+		})
+	}
+
+	_, err := recipeImporter("", "recipes.docx")
+	assert.EqualError(t, err, "unsupported import format")
+	_, err = recipeImporter("evernote", "recipes.json")
+	assert.EqualError(t, err, "unsupported import format")
+}
+
+func TestParseDurationText(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected *int32
+	}{
+		{"PT1H15M", int32Ptr(75)},
+		{"1 hr 30 mins", int32Ptr(90)},
+		{"2 hours", int32Ptr(120)},
+		{"45 minutes", int32Ptr(45)},
+		{"1:20", int32Ptr(80)},
+		{"25", int32Ptr(25)},
+		{"1.5 hours", int32Ptr(90)},
+		{"overnight", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseDurationText(tt.text))
+		})
+	}
+}
+
+func TestImportedContent_Markdown(t *testing.T) {
+	content := importedContent{
+		ingredients: []contentSection{
+			{items: []string{"2 cups flour"}},
+			{name: "Filling", items: []string{"6 apples"}},
+		},
+		instructions: []contentSection{{items: []string{"Mix.", "Bake."}}},
+		notes:        []string{"Keeps for a week."},
+		source:       "Grandma",
+	}
+	assert.Equal(t, `## Ingredients
+
+- 2 cups flour
+
+### Filling
+
+- 6 apples
+
+## Instructions
+
+1. Mix.
+2. Bake.
+
+## Notes
+
+Keeps for a week.
+
+Source: Grandma
+`, content.markdown())
+
+	instructions, source := markdownInstructions(content.markdown())
+	assert.Empty(t, source)
+	assert.Len(t, instructions, 2)
+}
+
+func TestPaprikaImporter(t *testing.T) {
+	recipe := func(json string) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(json))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		return buf.Bytes()
+	}
+	photo := base64.StdEncoding.EncodeToString(testPNG(t))
+
+	data := testZip(t, map[string][]byte{
+		"Apple Pie.paprikarecipe": recipe(`{
+			"name": "Apple Pie",
+			"description": "A classic.",
+			"ingredients": "Crust:\n2 cups flour\n1 cup butter\n\nFilling:\n6 apples, sliced",
+			"directions": "1. Make the crust.\n\n2. Fill and bake.",
+			"notes": "Serve warm.",
+			"servings": "8 slices",
+			"prep_time": "30 mins",
+			"total_time": "1 hr 45 mins",
+			"difficulty": "Easy",
+			"source": "grandma.example.com",
+			"source_url": "https://grandma.example.com/pie",
+			"categories": ["Desserts", "Holiday"],
+			"photo_data": "` + photo + `"
+		}`),
+		"Toast.paprikarecipe": recipe(`{"name": "Toast", "directions": "Toast the bread."}`),
+		"ignored.txt":         []byte("not a recipe"),
+	})
+
+	recipes, err := paprikaImporter{}.Import("Export.paprikarecipes", data)
+	require.NoError(t, err)
+	require.Len(t, recipes, 2)
+
+	pie := recipes[0]
+	if pie.Recipe.Title != "Apple Pie" {
+		pie = recipes[1]
+	}
+	assert.Equal(t, "Apple Pie", pie.Recipe.Title)
+	assert.Equal(t, "A classic.", *pie.Recipe.Description)
+	assert.Equal(t, int32Ptr(8), pie.Recipe.Servings)
+	assert.Equal(t, int32Ptr(30), pie.Recipe.PrepTimeMinutes)
+	assert.Equal(t, int32Ptr(75), pie.Recipe.CookTimeMinutes)
+	assert.Equal(t, "easy", *pie.Recipe.Difficulty)
+	assert.Equal(t, "Desserts", pie.Category)
+	assert.Equal(t, []string{"Holiday"}, pie.Recipe.Tags)
+	require.Len(t, pie.Images, 1)
+	assert.Equal(t, ".png", pie.Images[0].Ext)
+	assert.Equal(t, `## Ingredients
+
+### Crust
+
+- 2 cups flour
+- 1 cup butter
+
+### Filling
+
+- 6 apples, sliced
+
+## Instructions
+
+1. Make the crust.
+2. Fill and bake.
+
+## Notes
+
+Serve warm.
+
+Source: <https://grandma.example.com/pie>
+`, pie.Recipe.MarkdownContent)
+
+	_, err = paprikaImporter{}.Import("Broken.paprikarecipe", []byte("{"))
+	assert.Error(t, err)
+}
+
+func TestMealieImporter(t *testing.T) {
+	recipeJSON := []byte(`{
+		"name": "Pancakes",
+		"description": "Fluffy.",
+		"recipeServings": 4,
+		"recipeYield": "12 pancakes",
+		"prepTime": "10 minutes",
+		"performTime": "PT20M",
+		"recipeCategory": [{"name": "Breakfast", "slug": "breakfast"}],
+		"tags": [{"name": "Quick"}, {"name": "quick"}],
+		"recipeIngredient": [
+			{"display": "2 cups flour", "title": "Batter"},
+			{"display": "", "originalText": "", "quantity": 1.5, "unit": {"name": "cup"}, "food": {"name": "milk"}, "note": "warm"},
+			{"display": "butter", "title": "To serve"}
+		],
+		"recipeInstructions": [
+			{"text": "Whisk everything.\nRest for 5 minutes."},
+			{"title": "Cooking", "text": "Fry in butter."}
+		],
+		"notes": [{"title": "Tip", "text": "Use buttermilk."}],
+		"orgURL": "https://example.com/pancakes"
+	}`)
+
+	recipes, err := mealieImporter{}.Import("pancakes.json", recipeJSON)
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+
+	recipe := recipes[0]
+	assert.Equal(t, "Pancakes", recipe.Recipe.Title)
+	assert.Equal(t, int32Ptr(4), recipe.Recipe.Servings)
+	assert.Equal(t, int32Ptr(10), recipe.Recipe.PrepTimeMinutes)
+	assert.Equal(t, int32Ptr(20), recipe.Recipe.CookTimeMinutes)
+	assert.Equal(t, "Breakfast", recipe.Category)
+	assert.Equal(t, []string{"Quick"}, recipe.Recipe.Tags)
+	assert.Equal(t, `## Ingredients
+
+### Batter
+
+- 2 cups flour
+- 1 1/2 cup milk, warm
+
+### To serve
+
+- butter
+
+## Instructions
+
+1. Whisk everything. Rest for 5 minutes.
+
+### Cooking
+
+1. Fry in butter.
+
+## Notes
+
+**Tip**: Use buttermilk.
+
+Source: <https://example.com/pancakes>
+`, recipe.Recipe.MarkdownContent)
+
+	recipes, err = mealieImporter{}.Import("recipes.json", []byte(`{"recipes": [{"name": "A"}, {"name": "B"}]}`))
+	require.NoError(t, err)
+	assert.Len(t, recipes, 2)
+
+	archive := testZip(t, map[string][]byte{
+		"recipes/pancakes/pancakes.json":         recipeJSON,
+		"recipes/pancakes/images/original.png":   testPNG(t),
+		"recipes/pancakes/images/min-original.x": []byte("thumbnail"),
+	})
+	recipes, err = mealieImporter{}.Import("mealie.zip", archive)
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	assert.Len(t, recipes[0].Images, 1)
+
+	_, err = mealieImporter{}.Import("pancakes.json", []byte(`{"description": "no name"}`))
+	assert.EqualError(t, err, "invalid Mealie recipe: no name")
+}
+
+const mealMasterFile = `Some header text from the BBS.
+
+MMMMM----- Recipe via Meal-Master (tm) v8.05
+
+      Title: Apple Crumble
+ Categories: Desserts, Fruit
+      Yield: 6 servings
+
+      6 md Apples; peeled                    1/2 c  Sugar
+      1 ts Cinnamon
+           -ground
+
+MMMMM--------------------------TOPPING-------------------------------
+      1 c  Flour
+    1/2 c  Butter
+
+  Heat the oven to 190C. Slice the apples into a dish
+  and toss with the sugar and cinnamon.
+
+  Rub the butter into the flour, scatter over and bake for 40 minutes.
+
+MMMMM
+
+---------- Recipe via Meal-Master (tm) v8.02
+
+      Title: Iced Tea
+ Categories: None
+   Servings: 2
+
+      2    Tea bags
+
+  Brew and chill.
+
+-----
+`
+
+func TestMealMasterImporter(t *testing.T) {
+	recipes, err := mealMasterImporter{}.Import("recipes.mmf", []byte(mealMasterFile))
+	require.NoError(t, err)
+	require.Len(t, recipes, 2)
+
+	crumble := recipes[0]
+	assert.Equal(t, "Apple Crumble", crumble.Recipe.Title)
+	assert.Equal(t, int32Ptr(6), crumble.Recipe.Servings)
+	assert.Equal(t, "Desserts", crumble.Category)
+	assert.Equal(t, []string{"Fruit"}, crumble.Recipe.Tags)
+	assert.Equal(t, `## Ingredients
+
+- 6 medium Apples, peeled
+- 1/2 cup Sugar
+- 1 tsp Cinnamon ground
+
+### Topping
+
+- 1 cup Flour
+- 1/2 cup Butter
+
+## Instructions
+
+1. Heat the oven to 190C. Slice the apples into a dish and toss with the sugar and cinnamon.
+2. Rub the butter into the flour, scatter over and bake for 40 minutes.
+`, crumble.Recipe.MarkdownContent)
+
+	tea := recipes[1]
+	assert.Equal(t, "Iced Tea", tea.Recipe.Title)
+	assert.Equal(t, int32Ptr(2), tea.Recipe.Servings)
+	assert.Empty(t, tea.Category)
+	assert.Contains(t, tea.Recipe.MarkdownContent, "- 2 Tea bags\n")
+
+	recipes, err = mealMasterImporter{}.Import("notes.txt", []byte("Just some notes."))
+	require.NoError(t, err)
+	assert.Empty(t, recipes)
+}
+
+const cooklangRecipe = `---
+title: Garlic Pasta
+servings: 2
+prep time: 5 minutes
+cook time: 15 minutes
+tags: [quick, vegetarian]
+course: Mains
+---
+
+-- Adapted from a weeknight favourite.
+Bring a #large pot{} of water to the boil and cook @spaghetti{200%g} for ~{10%minutes}.
+
+= Sauce
+
+Fry @garlic{3%cloves}(sliced) in @olive oil{2%tbsp} [- gently -] in a #pan.
+Toss with the pasta and @salt.
+
+> Add chilli flakes for heat.
+`
+
+func TestCooklangImporter(t *testing.T) {
+	recipes, err := cooklangImporter{}.Import("Garlic Pasta.cook", []byte(cooklangRecipe))
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+
+	recipe := recipes[0]
+	assert.Equal(t, "Garlic Pasta", recipe.Recipe.Title)
+	assert.Equal(t, int32Ptr(2), recipe.Recipe.Servings)
+	assert.Equal(t, int32Ptr(5), recipe.Recipe.PrepTimeMinutes)
+	assert.Equal(t, int32Ptr(15), recipe.Recipe.CookTimeMinutes)
+	assert.Equal(t, "Mains", recipe.Category)
+	assert.Equal(t, []string{"quick", "vegetarian"}, recipe.Recipe.Tags)
+	assert.Equal(t, `## Ingredients
+
+- 200 g spaghetti
+
+### Sauce
+
+- 3 cloves garlic, sliced
+- 2 tbsp olive oil
+- salt
+
+## Instructions
+
+1. Bring a large pot of water to the boil and cook spaghetti for 10 minutes.
+
+### Sauce
+
+1. Fry garlic in olive oil in a pan. Toss with the pasta and salt.
+
+## Notes
+
+Add chilli flakes for heat.
+`, recipe.Recipe.MarkdownContent)
+
+	archive := testZip(t, map[string][]byte{
+		"Toast.cook": []byte(">> servings: 1\nToast @bread{2%slices}."),
+		"Toast.png":  testPNG(t),
+	})
+	recipes, err = cooklangImporter{}.Import("recipes.zip", archive)
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	assert.Equal(t, "Toast", recipes[0].Recipe.Title)
+	assert.Equal(t, int32Ptr(1), recipes[0].Recipe.Servings)
+	assert.Len(t, recipes[0].Images, 1)
+}
+
+func TestReadZipFile_TotalLimit(t *testing.T) {
+	data := testZip(t, map[string][]byte{"a.txt": bytes.Repeat([]byte("a"), 600)})
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	file := archive.File[0]
+
+	remaining := int64(1000)
+	content, err := readZipFile(file, 1<<20, &remaining)
+	require.NoError(t, err)
+	assert.Len(t, content, 600)
+	assert.Equal(t, int64(400), remaining)
+
+	_, err = readZipFile(file, 1<<20, &remaining)
+	assert.EqualError(t, err, "archive too large", "the second copy no longer fits")
+
+	remaining = 1000
+	_, err = readZipFile(file, 500, &remaining)
+	assert.EqualError(t, err, "file too large")
+}
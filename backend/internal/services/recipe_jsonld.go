@@ -0,0 +1,239 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+var (
+	markdownImagePattern    = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	markdownLinkPattern     = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	markdownAutolinkPattern = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	markdownEmphasisPattern = regexp.MustCompile("\\*\\*|__|[*`]")
+	sourceLinePattern       = regexp.MustCompile(`(?i)^source:\s*<?(https?://[^>\s]+)>?$`)
+	sourceTextPattern       = regexp.MustCompile(`(?i)^source:\s`)
+)
+
+// schemaDiets maps our diet labels onto schema.org RestrictedDiet values.
+// Diets schema.org has no value for are left out.
+var schemaDiets = map[string]string{
+	models.DietVegetarian: "https://schema.org/VegetarianDiet",
+	models.DietVegan:      "https://schema.org/VeganDiet",
+	models.DietGlutenFree: "https://schema.org/GlutenFreeDiet",
+}
+
+// RecipeJSONLD renders a recipe as a schema.org Recipe. Category and tags
+// are used when full carries them; baseURL turns uploaded image paths into
+// absolute URLs.
+func RecipeJSONLD(full *models.RecipeWithVariations, baseURL string) *models.SchemaRecipe {
+	recipe := &full.Recipe
+
+	schema := &models.SchemaRecipe{
+		Context:          "https://schema.org",
+		Type:             "Recipe",
+		Name:             recipe.Title,
+		DateModified:     recipe.UpdatedAt.UTC().Format(time.RFC3339),
+		PrepTime:         isoDuration(recipe.PrepTimeMinutes),
+		CookTime:         isoDuration(recipe.CookTimeMinutes),
+		RecipeIngredient: []string{},
+	}
+	if recipe.Description != nil {
+		schema.Description = *recipe.Description
+	}
+	if recipe.FeaturedImagePath != nil && *recipe.FeaturedImagePath != "" {
+		schema.Image = []string{absoluteURL(baseURL, *recipe.FeaturedImagePath)}
+	}
+	if recipe.PublishedAt != nil {
+		schema.DatePublished = recipe.PublishedAt.UTC().Format(time.RFC3339)
+	}
+	if recipe.PrepTimeMinutes != nil || recipe.CookTimeMinutes != nil {
+		total := int32(0)
+		for _, minutes := range []*int32{recipe.PrepTimeMinutes, recipe.CookTimeMinutes} {
+			if minutes != nil {
+				total += *minutes
+			}
+		}
+		schema.TotalTime = isoDuration(&total)
+	}
+	if recipe.Servings != nil && *recipe.Servings > 0 {
+		schema.RecipeYield = strconv.Itoa(int(*recipe.Servings))
+	}
+	if full.Category != nil {
+		schema.RecipeCategory = full.Category.Name
+	}
+	if len(full.Tags) > 0 {
+		names := make([]string, len(full.Tags))
+		for i, tag := range full.Tags {
+			names[i] = tag.Name
+		}
+		schema.Keywords = strings.Join(names, ", ")
+	}
+	if recipe.Dietary != nil {
+		for _, diet := range recipe.Dietary.Diets {
+			if value, ok := schemaDiets[diet]; ok {
+				schema.SuitableForDiet = append(schema.SuitableForDiet, value)
+			}
+		}
+	}
+	if n := recipe.Nutrition; n != nil && len(recipe.Ingredients) > len(n.Unmatched) {
+		schema.Nutrition = &models.SchemaNutrition{
+			Type:                "NutritionInformation",
+			Calories:            fmt.Sprintf("%.0f calories", n.PerServing.Calories),
+			ProteinContent:      fmt.Sprintf("%.1f g", n.PerServing.ProteinG),
+			FatContent:          fmt.Sprintf("%.1f g", n.PerServing.FatG),
+			CarbohydrateContent: fmt.Sprintf("%.1f g", n.PerServing.CarbohydrateG),
+			FiberContent:        fmt.Sprintf("%.1f g", n.PerServing.FiberG),
+			SodiumContent:       fmt.Sprintf("%.0f mg", n.PerServing.SodiumMg),
+		}
+	}
+
+	ingredients := recipe.Ingredients
+	if ingredients == nil {
+		ingredients = ParseIngredients(recipe.MarkdownContent)
+	}
+	for _, ingredient := range ingredients {
+		if text := plainMarkdown(ingredient.RawText); text != "" {
+			schema.RecipeIngredient = append(schema.RecipeIngredient, text)
+		}
+	}
+	schema.RecipeInstructions, schema.IsBasedOn = markdownInstructions(recipe.MarkdownContent)
+	return schema
+}
+
+// markdownInstructions turns the "## Instructions" section of a recipe into
+// HowToSteps, grouped into HowToSections under their sub-headings. A
+// "Source: <url>" line, as written by the URL importer, is returned
+// separately rather than as a step.
+func markdownInstructions(markdown string) ([]models.SchemaInstruction, string) {
+	sections, source := instructionSections(markdown)
+
+	instructions := []models.SchemaInstruction{}
+	for _, section := range sections {
+		steps := make([]models.SchemaInstruction, len(section.steps))
+		for i, text := range section.steps {
+			steps[i] = models.SchemaInstruction{Type: "HowToStep", Text: text}
+		}
+		if section.name != "" {
+			instructions = append(instructions, models.SchemaInstruction{Type: "HowToSection", Name: section.name, ItemListElement: steps})
+		} else {
+			instructions = append(instructions, steps...)
+		}
+	}
+	return instructions, source
+}
+
+// instructionSection is a run of steps under one sub-heading of a recipe's
+// method, or before the first one when name is empty.
+type instructionSection struct {
+	name  string
+	steps []string
+}
+
+// instructionSections splits the "## Instructions" section of a recipe into
+// plain-text steps, one per list item or paragraph, grouped by
+// sub-heading. Without such a section everything outside the ingredients is
+// read instead. Sections without steps are left out. The URL of a
+// "Source: <url>" line is returned separately; a source that isn't a URL
+// is dropped.
+func instructionSections(markdown string) ([]instructionSection, string) {
+	lines := strings.Split(markdown, "\n")
+	if start, end := markdownSection(lines, isInstructionsHeading); end > 0 {
+		lines = lines[start:end]
+	} else if start, end := ingredientSection(lines); end > 0 {
+		lines = append(lines[:start-1:start-1], lines[end:]...)
+	}
+
+	var sections []instructionSection
+	var source string
+	current := instructionSection{}
+	var paragraph []string
+
+	endStep := func() {
+		if text := plainMarkdown(strings.Join(paragraph, " ")); text != "" {
+			current.steps = append(current.steps, text)
+		}
+		paragraph = nil
+	}
+	endSection := func() {
+		endStep()
+		if len(current.steps) > 0 {
+			sections = append(sections, current)
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			endStep()
+		case headingPattern.MatchString(trimmed):
+			endSection()
+			current = instructionSection{name: plainMarkdown(headingPattern.FindStringSubmatch(trimmed)[2])}
+		case sourceLinePattern.MatchString(trimmed):
+			endStep()
+			source = sourceLinePattern.FindStringSubmatch(trimmed)[1]
+		case sourceTextPattern.MatchString(trimmed):
+			endStep()
+		case listMarkerPattern.MatchString(trimmed):
+			endStep()
+			paragraph = append(paragraph, listMarkerPattern.ReplaceAllString(trimmed, ""))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	endSection()
+	return sections, source
+}
+
+func isInstructionsHeading(title string) bool {
+	title = strings.ToLower(strings.TrimSpace(title))
+	for _, prefix := range []string{"instruction", "direction", "method", "step", "preparation"} {
+		if strings.HasPrefix(title, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// plainMarkdown strips inline markdown from s: images are dropped, links
+// keep their text, [[recipe:slug]] references read as the slug's words and
+// emphasis markers are removed.
+func plainMarkdown(s string) string {
+	s = markdownImagePattern.ReplaceAllString(s, "")
+	s = recipeLinkPattern.ReplaceAllStringFunc(s, func(link string) string {
+		return recipeLinkText(recipeLinkPattern.FindStringSubmatch(link)[1])
+	})
+	s = markdownLinkPattern.ReplaceAllString(s, "$1")
+	s = markdownAutolinkPattern.ReplaceAllString(s, "$1")
+	s = markdownEmphasisPattern.ReplaceAllString(s, "")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// isoDuration formats minutes as an ISO 8601 duration such as "PT1H30M".
+// Unknown and zero durations give an empty string.
+func isoDuration(minutes *int32) string {
+	if minutes == nil || *minutes <= 0 {
+		return ""
+	}
+	hours, mins := *minutes/60, *minutes%60
+	duration := "PT"
+	if hours > 0 {
+		duration += fmt.Sprintf("%dH", hours)
+	}
+	if mins > 0 {
+		duration += fmt.Sprintf("%dM", mins)
+	}
+	return duration
+}
+
+func absoluteURL(baseURL, path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
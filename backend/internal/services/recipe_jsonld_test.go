@@ -0,0 +1,154 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeJSONLD(t *testing.T) {
+	markdown := `# Pancakes
+
+## Ingredients
+
+- 2 cups flour
+- 2 **large** eggs
+- 1 1/2 cups milk
+
+## Instructions
+
+### Batter
+
+1. Whisk the [flour](https://example.com/flour) and eggs.
+2. Stir in the milk,
+   a little at a time.
+
+### Cooking
+
+Fry in a hot pan.
+
+![pancakes](/uploads/pan.jpg)
+
+Source: <https://example.com/recipes/pancakes>
+`
+	publishedAt := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	full := &models.RecipeWithVariations{
+		Recipe: models.Recipe{
+			Title:             "Pancakes",
+			MarkdownContent:   markdown,
+			Description:       stringPtr("Fluffy pancakes"),
+			PrepTimeMinutes:   int32Ptr(10),
+			CookTimeMinutes:   int32Ptr(80),
+			Servings:          int32Ptr(4),
+			FeaturedImagePath: stringPtr("/uploads/pancakes.jpg"),
+			PublishedAt:       &publishedAt,
+			UpdatedAt:         publishedAt,
+			Ingredients:       ParseIngredients(markdown),
+			Dietary:           &models.RecipeDietary{Diets: []string{models.DietVegetarian, models.DietNutFree}},
+		},
+		Category: &models.Category{Name: "Breakfast"},
+		Tags:     []models.Tag{{Name: "Quick"}, {Name: "Sweet"}},
+	}
+
+	schema := RecipeJSONLD(full, "https://cook.example.com/")
+
+	assert.Equal(t, "https://schema.org", schema.Context)
+	assert.Equal(t, "Recipe", schema.Type)
+	assert.Equal(t, "Pancakes", schema.Name)
+	assert.Equal(t, "Fluffy pancakes", schema.Description)
+	assert.Equal(t, []string{"https://cook.example.com/uploads/pancakes.jpg"}, schema.Image)
+	assert.Equal(t, "2025-03-01T09:30:00Z", schema.DatePublished)
+	assert.Equal(t, "PT10M", schema.PrepTime)
+	assert.Equal(t, "PT1H20M", schema.CookTime)
+	assert.Equal(t, "PT1H30M", schema.TotalTime)
+	assert.Equal(t, "4", schema.RecipeYield)
+	assert.Equal(t, "Breakfast", schema.RecipeCategory)
+	assert.Equal(t, "Quick, Sweet", schema.Keywords)
+	assert.Equal(t, []string{"https://schema.org/VegetarianDiet"}, schema.SuitableForDiet)
+	assert.Equal(t, "https://example.com/recipes/pancakes", schema.IsBasedOn)
+	assert.Equal(t, []string{"2 cups flour", "2 large eggs", "1 1/2 cups milk"}, schema.RecipeIngredient)
+	assert.Equal(t, []models.SchemaInstruction{
+		{Type: "HowToSection", Name: "Batter", ItemListElement: []models.SchemaInstruction{
+			{Type: "HowToStep", Text: "Whisk the flour and eggs."},
+			{Type: "HowToStep", Text: "Stir in the milk, a little at a time."},
+		}},
+		{Type: "HowToSection", Name: "Cooking", ItemListElement: []models.SchemaInstruction{
+			{Type: "HowToStep", Text: "Fry in a hot pan."},
+		}},
+	}, schema.RecipeInstructions)
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "Recipe", decoded["@type"])
+	assert.Equal(t, "PT1H20M", decoded["cookTime"])
+	assert.NotContains(t, decoded, "nutrition")
+}
+
+func TestRecipeJSONLD_Minimal(t *testing.T) {
+	full := &models.RecipeWithVariations{
+		Recipe: models.Recipe{Title: "Toast", MarkdownContent: "Toast the bread.\n\nButter it."},
+	}
+
+	schema := RecipeJSONLD(full, "http://localhost:8080")
+
+	assert.Empty(t, schema.Image)
+	assert.Empty(t, schema.PrepTime)
+	assert.Empty(t, schema.TotalTime)
+	assert.Empty(t, schema.RecipeYield)
+	assert.Equal(t, []string{}, schema.RecipeIngredient)
+	assert.Equal(t, []models.SchemaInstruction{
+		{Type: "HowToStep", Text: "Toast the bread."},
+		{Type: "HowToStep", Text: "Butter it."},
+	}, schema.RecipeInstructions)
+}
+
+func TestMarkdownInstructions_WithoutInstructionsHeading(t *testing.T) {
+	markdown := `Chop the onion.
+
+## Ingredients
+
+- 1 onion
+
+## Cooking
+
+- Fry it.
+- Season.`
+
+	instructions, source := markdownInstructions(markdown)
+	assert.Empty(t, source)
+	assert.Equal(t, []models.SchemaInstruction{
+		{Type: "HowToStep", Text: "Chop the onion."},
+		{Type: "HowToSection", Name: "Cooking", ItemListElement: []models.SchemaInstruction{
+			{Type: "HowToStep", Text: "Fry it."},
+			{Type: "HowToStep", Text: "Season."},
+		}},
+	}, instructions)
+}
+
+func TestIsoDuration(t *testing.T) {
+	tests := []struct {
+		minutes  *int32
+		expected string
+	}{
+		{int32Ptr(45), "PT45M"},
+		{int32Ptr(60), "PT1H"},
+		{int32Ptr(135), "PT2H15M"},
+		{int32Ptr(0), ""},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isoDuration(tt.minutes))
+			if tt.expected != "" {
+				assert.Equal(t, tt.minutes, isoDurationMinutes(tt.expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+)
+
+// recipeLinkPattern matches a reference to another recipe in markdown, as
+// in "[[recipe:pie-crust]]".
+var recipeLinkPattern = regexp.MustCompile(`\[\[recipe:([A-Za-z0-9-]+)\]\]`)
+
+// RecipeLinkSlugs returns the slugs the markdown links to, in the order they
+// first appear.
+func RecipeLinkSlugs(markdown string) []string {
+	var slugs []string
+	seen := map[string]bool{}
+	for _, m := range recipeLinkPattern.FindAllStringSubmatch(markdown, -1) {
+		slug := strings.ToLower(m[1])
+		if !seen[slug] {
+			seen[slug] = true
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs
+}
+
+// recipeLinkText is what a link to slug reads as where the linked recipe's
+// title isn't at hand.
+func recipeLinkText(slug string) string {
+	return strings.ReplaceAll(strings.ToLower(slug), "-", " ")
+}
+
+// linkedRecipe finds the recipe a link to slug leads to, following renames
+// the way GetRecipeBySlug does. It returns nil when no recipe has the slug.
+func linkedRecipe(recipeRepo *repository.RecipeRepository, slug string) (*models.Recipe, error) {
+	recipe, err := recipeRepo.GetBySlug(slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		recipe, err = recipeRepo.GetByOldSlug(slug)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return recipe, err
+}
+
+// syncLinks stores the sub-recipes the recipe's markdown links to and
+// points existing links to the recipe's slug at it. Links from a recipe to
+// itself are left out.
+func (s *RecipeService) syncLinks(recipe *models.Recipe) error {
+	id := recipe.ID.String()
+	if err := s.recipeRepo.ResolveLinks(recipe.Slug, id); err != nil {
+		return err
+	}
+
+	var links []models.RecipeLink
+	for _, slug := range RecipeLinkSlugs(recipe.MarkdownContent) {
+		linked, err := linkedRecipe(s.recipeRepo, slug)
+		if err != nil {
+			return err
+		}
+		link := models.RecipeLink{Slug: slug}
+		if linked != nil {
+			if linked.ID == recipe.ID {
+				continue
+			}
+			link.RecipeID = &linked.ID
+		}
+		links = append(links, link)
+	}
+	return s.recipeRepo.ReplaceLinks(id, links)
+}
+
+// loadLinks fills in the recipes the recipe uses and the published recipes
+// that use it.
+func (s *RecipeService) loadLinks(recipe *models.Recipe) error {
+	uses, err := s.recipeRepo.GetLinks(recipe.ID.String())
+	if err != nil {
+		return err
+	}
+	usedBy, err := s.recipeRepo.GetBacklinks(recipe.ID.String())
+	if err != nil {
+		return err
+	}
+	recipe.Uses = uses
+	recipe.UsedBy = usedBy
+	return nil
+}
+
+// Limits on how far sub-recipes are inlined, so that deep chains and
+// recipes that use the same sub-recipe many times over can't blow up a
+// single scaled view or shopping list.
+const (
+	maxSubRecipeDepth = 8
+	maxSubRecipes     = 200
+)
+
+// inlineSubRecipes replaces the ingredients of the recipe with the given ID
+// and markdown that link to another recipe with that recipe's ingredients. A
+// linked ingredient line with a bare quantity, as in
+// "- 2 [[recipe:pie-crust]]", takes that many batches; otherwise, and for
+// recipes only linked from the method, one batch is taken for every batch of
+// the recipe, which factor gives. Links back to a recipe that is already
+// being inlined are left as they are, as are links past maxSubRecipeDepth
+// levels or beyond the first maxSubRecipes sub-recipes.
+func inlineSubRecipes(recipeRepo *repository.RecipeRepository, recipeID uuid.UUID, markdown string, ingredients []models.RecipeIngredient, factor float64) ([]models.RecipeIngredient, error) {
+	inliner := &subRecipeInliner{
+		recipeRepo: recipeRepo,
+		path:       map[uuid.UUID]bool{recipeID: true},
+		recipes:    map[string]*models.Recipe{},
+		resolved:   map[subRecipeKey][]models.RecipeIngredient{},
+	}
+	inlined, err := inliner.inline(markdown, ingredients, factor)
+	if err != nil {
+		return nil, err
+	}
+	for i := range inlined {
+		inlined[i].OrderIndex = i
+	}
+	return inlined, nil
+}
+
+type subRecipeInliner struct {
+	recipeRepo *repository.RecipeRepository
+	path       map[uuid.UUID]bool
+
+	// recipes holds the recipe each slug led to, nil for none, and resolved
+	// the ingredients of each sub-recipe already inlined, so that a recipe
+	// used in many places is only looked up and expanded once.
+	recipes  map[string]*models.Recipe
+	resolved map[subRecipeKey][]models.RecipeIngredient
+
+	// inlined counts the sub-recipes taken so far and refused the links
+	// left as they were because of the path or the limits.
+	inlined int
+	refused int
+}
+
+type subRecipeKey struct {
+	recipeID uuid.UUID
+	batches  float64
+}
+
+func (in *subRecipeInliner) inline(markdown string, ingredients []models.RecipeIngredient, factor float64) ([]models.RecipeIngredient, error) {
+	var result []models.RecipeIngredient
+	inlined := map[string]bool{}
+	for _, ingredient := range ingredients {
+		slugs := RecipeLinkSlugs(ingredient.RawText)
+		if len(slugs) == 0 {
+			result = append(result, ingredient)
+			continue
+		}
+
+		batches := factor
+		if ingredient.Quantity != nil && ingredient.Unit == nil {
+			batches = *ingredient.Quantity
+		}
+		var expanded []models.RecipeIngredient
+		found := true
+		for _, slug := range slugs {
+			sub, ok, err := in.subRecipe(slug, batches)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				found = false
+				break
+			}
+			inlined[slug] = true
+			expanded = append(expanded, sub...)
+		}
+		if !found {
+			result = append(result, ingredient)
+			continue
+		}
+		result = append(result, expanded...)
+	}
+
+	for _, slug := range RecipeLinkSlugs(markdown) {
+		if inlined[slug] {
+			continue
+		}
+		sub, _, err := in.subRecipe(slug, factor)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sub...)
+	}
+	return result, nil
+}
+
+// subRecipe returns the ingredients of batches batches of the recipe linked
+// as slug, with its own sub-recipes inlined. It reports false for links
+// that lead nowhere, back up the path or past the limits.
+func (in *subRecipeInliner) subRecipe(slug string, batches float64) ([]models.RecipeIngredient, bool, error) {
+	recipe, err := in.linkedRecipe(slug)
+	if err != nil || recipe == nil {
+		return nil, false, err
+	}
+	if in.path[recipe.ID] || len(in.path) > maxSubRecipeDepth || in.inlined >= maxSubRecipes {
+		in.refused++
+		return nil, false, nil
+	}
+	in.inlined++
+
+	key := subRecipeKey{recipeID: recipe.ID, batches: batches}
+	if ingredients, ok := in.resolved[key]; ok {
+		return append([]models.RecipeIngredient(nil), ingredients...), true, nil
+	}
+
+	in.path[recipe.ID] = true
+	defer delete(in.path, recipe.ID)
+
+	_, ingredients := ScaleContent(recipe.MarkdownContent, batches)
+	source := &models.RecipeLink{RecipeID: &recipe.ID, Slug: recipe.Slug, Title: recipe.Title}
+	for i := range ingredients {
+		ingredients[i].RecipeID = recipe.ID
+		ingredients[i].FromRecipe = source
+	}
+	refused := in.refused
+	ingredients, err = in.inline(recipe.MarkdownContent, ingredients, batches)
+	if err != nil {
+		return nil, false, err
+	}
+	// What was left out depends on the path taken here, so only complete
+	// expansions are reused.
+	if in.refused == refused {
+		in.resolved[key] = ingredients
+	}
+	return append([]models.RecipeIngredient(nil), ingredients...), true, nil
+}
+
+// linkedRecipe looks up the recipe a link to slug leads to once per
+// inliner.
+func (in *subRecipeInliner) linkedRecipe(slug string) (*models.Recipe, error) {
+	if recipe, ok := in.recipes[slug]; ok {
+		return recipe, nil
+	}
+	recipe, err := linkedRecipe(in.recipeRepo, slug)
+	if err != nil {
+		return nil, err
+	}
+	in.recipes[slug] = recipe
+	return recipe, nil
+}
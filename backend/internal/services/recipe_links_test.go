@@ -0,0 +1,181 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/homecooking/backend/internal/models"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecipeLinkSlugs(t *testing.T) {
+	markdown := "Make [[recipe:Pie-Crust]] and [[recipe:custard]], then more [[recipe:pie-crust]]. Not [[recipe:]] or [recipe:x]."
+	assert.Equal(t, []string{"pie-crust", "custard"}, RecipeLinkSlugs(markdown))
+}
+
+func TestRecipeService_RecipeLinks(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "test@example.com")
+
+	crust, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Pie Crust",
+		MarkdownContent: "## Ingredients\n\n- 200 g flour\n- 100 g butter\n\n## Instructions\n\n1. Rub together.",
+		IsPublished:     true,
+	}, authorID)
+	require.NoError(t, err)
+
+	pie, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Apple Pie",
+		MarkdownContent: "## Ingredients\n\n- 2 [[recipe:pie-crust]]\n- 6 apples\n\n## Instructions\n\n1. Fill and serve with [[recipe:custard]].",
+		Servings:        int32Ptr(8),
+		IsPublished:     true,
+	}, authorID)
+	require.NoError(t, err)
+
+	loaded, err := service.GetRecipe(pie.ID.String())
+	require.NoError(t, err)
+	require.Len(t, loaded.Uses, 2)
+	assert.Equal(t, crust.ID, *loaded.Uses[0].RecipeID)
+	assert.Equal(t, "Pie Crust", loaded.Uses[0].Title)
+	assert.Nil(t, loaded.Uses[1].RecipeID)
+	assert.Equal(t, "custard", loaded.Uses[1].Slug)
+
+	loaded, err = service.GetRecipe(crust.ID.String())
+	require.NoError(t, err)
+	require.Len(t, loaded.UsedBy, 1)
+	assert.Equal(t, pie.ID, *loaded.UsedBy[0].RecipeID)
+
+	// A recipe created under a linked slug picks up the links already made.
+	custard, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Custard",
+		MarkdownContent: "## Ingredients\n\n- 500 ml milk\n\n## Instructions\n\nServe with [[recipe:apple-pie]].",
+	}, authorID)
+	require.NoError(t, err)
+
+	loaded, err = service.GetRecipe(pie.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, custard.ID, *loaded.Uses[1].RecipeID)
+
+	// Doubling the pie doubles the two crusts its ingredient line asks for
+	// and the custard its method mentions. The custard's link back to the
+	// pie is not followed.
+	scaled, err := service.ScaleRecipe(pie.ID.String(), 16)
+	require.NoError(t, err)
+	require.Len(t, scaled.Ingredients, 4)
+	assert.Equal(t, "flour", scaled.Ingredients[0].Name)
+	assert.Equal(t, 800.0, *scaled.Ingredients[0].Quantity)
+	assert.Equal(t, "Pie Crust", scaled.Ingredients[0].FromRecipe.Title)
+	assert.Equal(t, 400.0, *scaled.Ingredients[1].Quantity)
+	assert.Equal(t, 12.0, *scaled.Ingredients[2].Quantity)
+	assert.Nil(t, scaled.Ingredients[2].FromRecipe)
+	assert.Equal(t, "milk", scaled.Ingredients[3].Name)
+	assert.Equal(t, 1000.0, *scaled.Ingredients[3].Quantity)
+	for i, ingredient := range scaled.Ingredients {
+		assert.Equal(t, i, ingredient.OrderIndex)
+	}
+}
+
+func TestRecipeService_ScaleRecipe_SubRecipeLimits(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "test@example.com")
+
+	// Each level uses the next one, so only the first maxSubRecipeDepth
+	// levels below the top are inlined.
+	var top *models.Recipe
+	for level := maxSubRecipeDepth + 3; level >= 1; level-- {
+		recipe, err := service.CreateRecipe(&models.CreateRecipeRequest{
+			Title:           fmt.Sprintf("Level %d", level),
+			MarkdownContent: fmt.Sprintf("## Ingredients\n\n- 1 g salt\n- 1 [[recipe:level-%d]]", level+1),
+			Servings:        int32Ptr(1),
+		}, authorID)
+		require.NoError(t, err)
+		top = recipe
+	}
+	scaled, err := service.ScaleRecipe(top.ID.String(), 1)
+	require.NoError(t, err)
+	require.Len(t, scaled.Ingredients, maxSubRecipeDepth+2)
+	assert.Equal(t, fmt.Sprintf("1 [[recipe:level-%d]]", maxSubRecipeDepth+2), scaled.Ingredients[len(scaled.Ingredients)-1].RawText)
+
+	// A sub-recipe used over and over is inlined at most maxSubRecipes
+	// times; the lines past that stay links.
+	_, err = service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Stock",
+		MarkdownContent: "## Ingredients\n\n- 1 l water",
+	}, authorID)
+	require.NoError(t, err)
+	lines := strings.Repeat("- 1 [[recipe:stock]]\n", maxSubRecipes+5)
+	soup, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Big Soup",
+		MarkdownContent: "## Ingredients\n\n" + lines,
+		Servings:        int32Ptr(1),
+	}, authorID)
+	require.NoError(t, err)
+	scaled, err = service.ScaleRecipe(soup.ID.String(), 1)
+	require.NoError(t, err)
+	require.Len(t, scaled.Ingredients, maxSubRecipes+5)
+	assert.Equal(t, "water", scaled.Ingredients[maxSubRecipes-1].Name)
+	assert.Equal(t, "1 [[recipe:stock]]", scaled.Ingredients[maxSubRecipes].RawText)
+}
+
+func TestRecipeService_ShoppingList(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "test@example.com")
+
+	_, err = service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Pizza Dough",
+		MarkdownContent: "## Ingredients\n\n- 500 g flour\n- Salt",
+	}, authorID)
+	require.NoError(t, err)
+	pizza, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Pizza",
+		MarkdownContent: "## Ingredients\n\n- [[recipe:pizza-dough]]\n- 200 g mozzarella",
+		Servings:        int32Ptr(2),
+	}, authorID)
+	require.NoError(t, err)
+	bread, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Bread",
+		MarkdownContent: "## Ingredients\n\n- 250 g flour\n- salt",
+	}, authorID)
+	require.NoError(t, err)
+
+	list, err := service.ShoppingList(&models.ShoppingListRequest{Recipes: []models.ShoppingListRecipe{
+		{RecipeID: pizza.ID.String(), Servings: intPtr(4)},
+		{RecipeID: bread.ID.String()},
+	}}, UnitsOriginal)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 3)
+	assert.Equal(t, "flour", list.Items[0].Name)
+	assert.Equal(t, 1250.0, *list.Items[0].Quantity)
+	assert.Equal(t, "g", *list.Items[0].Unit)
+	assert.Len(t, list.Items[0].RecipeIDs, 2)
+	assert.Nil(t, list.Items[1].Quantity)
+	assert.Len(t, list.Items[1].RecipeIDs, 2)
+	assert.Equal(t, 400.0, *list.Items[2].Quantity)
+
+	_, err = service.ShoppingList(&models.ShoppingListRequest{}, UnitsOriginal)
+	assert.EqualError(t, err, "at least one recipe is required")
+
+	_, err = service.ShoppingList(&models.ShoppingListRequest{Recipes: []models.ShoppingListRecipe{
+		{RecipeID: bread.ID.String(), Servings: intPtr(2)},
+	}}, UnitsOriginal)
+	assert.EqualError(t, err, "recipe has no servings to scale from")
+}
+
+func intPtr(i int) *int {
+	return &i
+}
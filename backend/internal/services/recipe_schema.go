@@ -0,0 +1,473 @@
+package services
+
+import (
+	"encoding/json"
+	"maps"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// schemaObject is a schema.org item as decoded from JSON-LD. Microdata items
+// are converted into the same shape so one mapping covers both.
+type schemaObject = map[string]interface{}
+
+var (
+	isoDurationPattern = regexp.MustCompile(`(?i)^P(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	stepNumberPattern  = regexp.MustCompile(`(?i)^(?:step\s*)?\d+[.):]\s+`)
+	firstNumberPattern = regexp.MustCompile(`\d+`)
+)
+
+// blockElements start a new line when HTML text is flattened.
+var blockElements = map[atom.Atom]bool{
+	atom.Br: true, atom.P: true, atom.Div: true, atom.Li: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+}
+
+// findSchemaRecipes returns the schema.org Recipe items on the page. JSON-LD
+// blocks are preferred; microdata is only read when they hold no recipe.
+func findSchemaRecipes(doc *html.Node) []schemaObject {
+	var recipes []schemaObject
+	walkHTML(doc, func(n *html.Node) bool {
+		if n.DataAtom != atom.Script || !strings.EqualFold(strings.TrimSpace(htmlAttr(n, "type")), "application/ld+json") {
+			return true
+		}
+		var data interface{}
+		if err := json.Unmarshal([]byte(nodeText(n)), &data); err == nil {
+			recipes = append(recipes, collectSchemaRecipes(data)...)
+		}
+		return false
+	})
+	if len(recipes) > 0 {
+		return recipes
+	}
+
+	walkHTML(doc, func(n *html.Node) bool {
+		if !hasHTMLAttr(n, "itemscope") || schemaTypeName(htmlAttr(n, "itemtype")) != "Recipe" {
+			return true
+		}
+		recipes = append(recipes, microdataItem(n))
+		return false
+	})
+	return recipes
+}
+
+// collectSchemaRecipes finds Recipe objects anywhere in decoded JSON-LD,
+// including inside @graph and arrays.
+func collectSchemaRecipes(data interface{}) []schemaObject {
+	switch v := data.(type) {
+	case []interface{}:
+		var recipes []schemaObject
+		for _, item := range v {
+			recipes = append(recipes, collectSchemaRecipes(item)...)
+		}
+		return recipes
+	case schemaObject:
+		if isSchemaType(v, "Recipe") {
+			return []schemaObject{v}
+		}
+		var recipes []schemaObject
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			recipes = append(recipes, collectSchemaRecipes(v[key])...)
+		}
+		return recipes
+	}
+	return nil
+}
+
+func isSchemaType(obj schemaObject, name string) bool {
+	for _, t := range schemaStrings(obj["@type"]) {
+		if schemaTypeName(t) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaTypeName reduces "https://schema.org/Recipe" and "schema:Recipe" to
+// "Recipe".
+func schemaTypeName(t string) string {
+	t = strings.TrimSpace(t)
+	if i := strings.LastIndexAny(t, "/:"); i >= 0 {
+		t = t[i+1:]
+	}
+	return t
+}
+
+// microdataItem collects the itemprop values below an itemscope element.
+// Nested items become nested objects.
+func microdataItem(item *html.Node) schemaObject {
+	obj := schemaObject{"@type": schemaTypeName(htmlAttr(item, "itemtype"))}
+	for c := item.FirstChild; c != nil; c = c.NextSibling {
+		walkHTML(c, func(n *html.Node) bool {
+			props := strings.Fields(htmlAttr(n, "itemprop"))
+			scoped := hasHTMLAttr(n, "itemscope")
+			if len(props) == 0 {
+				return !scoped
+			}
+
+			var value interface{}
+			if scoped {
+				value = microdataItem(n)
+			} else {
+				value = microdataValue(n)
+			}
+			for _, prop := range props {
+				switch existing := obj[prop].(type) {
+				case nil:
+					obj[prop] = value
+				case []interface{}:
+					obj[prop] = append(existing, value)
+				default:
+					obj[prop] = []interface{}{existing, value}
+				}
+			}
+			return !scoped
+		})
+	}
+	return obj
+}
+
+func microdataValue(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.Meta:
+		return htmlAttr(n, "content")
+	case atom.Img, atom.Audio, atom.Video, atom.Source, atom.Embed, atom.Iframe, atom.Track:
+		return htmlAttr(n, "src")
+	case atom.A, atom.Area, atom.Link:
+		return htmlAttr(n, "href")
+	case atom.Object:
+		return htmlAttr(n, "data")
+	case atom.Data, atom.Meter:
+		return htmlAttr(n, "value")
+	case atom.Time:
+		if hasHTMLAttr(n, "datetime") {
+			return htmlAttr(n, "datetime")
+		}
+	}
+	if hasHTMLAttr(n, "content") {
+		return htmlAttr(n, "content")
+	}
+	return strings.Join(htmlLines(n), "\n")
+}
+
+// schemaRecipeRequest maps a schema.org Recipe onto a recipe draft. The
+// markdown gets an ingredient list, numbered instructions and a link back to
+// the page. It returns false when the recipe has neither ingredients nor
+// instructions.
+func schemaRecipeRequest(recipe schemaObject, pageURL *url.URL) (*models.CreateRecipeRequest, bool) {
+	ingredients := schemaTexts(recipe["recipeIngredient"])
+	if len(ingredients) == 0 {
+		ingredients = schemaTexts(recipe["ingredients"])
+	}
+	instructions := schemaInstructions(recipe["recipeInstructions"])
+	if len(ingredients) == 0 && len(instructions) == 0 {
+		return nil, false
+	}
+
+	content := importedContent{
+		ingredients:  []contentSection{{items: ingredients}},
+		instructions: instructions,
+		source:       pageURL.String(),
+	}
+
+	req := &models.CreateRecipeRequest{
+		Title:           schemaText(recipe["name"]),
+		MarkdownContent: content.markdown(),
+		PrepTimeMinutes: isoDurationMinutes(schemaText(recipe["prepTime"])),
+		CookTimeMinutes: isoDurationMinutes(schemaText(recipe["cookTime"])),
+		Servings:        schemaYield(recipe["recipeYield"]),
+	}
+	if description := schemaText(recipe["description"]); description != "" {
+		req.Description = &description
+	}
+
+	// Pages that only give the total time get it as the cook time, minus
+	// the prep time when that is known.
+	if req.CookTimeMinutes == nil {
+		if total := isoDurationMinutes(schemaText(recipe["totalTime"])); total != nil {
+			cook := *total
+			if req.PrepTimeMinutes != nil {
+				cook -= *req.PrepTimeMinutes
+			}
+			if cook > 0 {
+				req.CookTimeMinutes = &cook
+			}
+		}
+	}
+
+	req.Tags = uniqueTags(append(schemaTexts(recipe["recipeCategory"]), schemaTexts(recipe["recipeCuisine"])...))
+	return req, true
+}
+
+// schemaImageURL returns the absolute URL of the recipe's first image.
+func schemaImageURL(recipe schemaObject, pageURL *url.URL) string {
+	var find func(v interface{}) string
+	find = func(v interface{}) string {
+		switch image := v.(type) {
+		case string:
+			return strings.TrimSpace(image)
+		case []interface{}:
+			for _, item := range image {
+				if found := find(item); found != "" {
+					return found
+				}
+			}
+		case schemaObject:
+			if found := find(image["url"]); found != "" {
+				return found
+			}
+			return find(image["contentUrl"])
+		}
+		return ""
+	}
+
+	ref := find(recipe["image"])
+	if ref == "" {
+		return ""
+	}
+	resolved, err := pageURL.Parse(ref)
+	if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+		return ""
+	}
+	return resolved.String()
+}
+
+// schemaInstructions flattens recipeInstructions, which pages give as one
+// block of text, a list of strings, HowToSteps or HowToSections of steps.
+func schemaInstructions(v interface{}) []contentSection {
+	sections := []contentSection{{}}
+	var add func(v interface{})
+	add = func(v interface{}) {
+		switch item := v.(type) {
+		case string:
+			for _, line := range htmlTextLines(item) {
+				if step := stepNumberPattern.ReplaceAllString(line, ""); step != "" {
+					sections[len(sections)-1].items = append(sections[len(sections)-1].items, step)
+				}
+			}
+		case []interface{}:
+			for _, element := range item {
+				add(element)
+			}
+		case schemaObject:
+			if isSchemaType(item, "HowToSection") {
+				sections = append(sections, contentSection{name: schemaText(item["name"])})
+				add(item["itemListElement"])
+				sections = append(sections, contentSection{})
+				return
+			}
+			if text, ok := item["text"]; ok {
+				add(text)
+			} else if _, ok := item["itemListElement"]; ok {
+				add(item["itemListElement"])
+			} else {
+				add(item["name"])
+			}
+		}
+	}
+	add(v)
+
+	kept := []contentSection{}
+	for _, section := range sections {
+		if len(section.items) > 0 {
+			kept = append(kept, section)
+		}
+	}
+	return kept
+}
+
+// schemaText returns the first non-empty text in v, reading "text" or
+// "name" from objects.
+func schemaText(v interface{}) string {
+	texts := schemaTexts(v)
+	if len(texts) == 0 {
+		return ""
+	}
+	return texts[0]
+}
+
+// schemaTexts returns the non-empty texts in v with any HTML stripped and
+// whitespace collapsed.
+func schemaTexts(v interface{}) []string {
+	var texts []string
+	for _, s := range schemaStrings(v) {
+		if text := strings.Join(htmlTextLines(s), " "); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+func schemaStrings(v interface{}) []string {
+	switch value := v.(type) {
+	case string:
+		return []string{value}
+	case float64:
+		return []string{strconv.FormatFloat(value, 'f', -1, 64)}
+	case []interface{}:
+		var values []string
+		for _, item := range value {
+			values = append(values, schemaStrings(item)...)
+		}
+		return values
+	case schemaObject:
+		if text, ok := value["text"]; ok {
+			return schemaStrings(text)
+		}
+		return schemaStrings(value["name"])
+	}
+	return nil
+}
+
+// schemaYield reads the servings from recipeYield, which is a number or
+// text such as "Serves 4-6"; the first number wins.
+func schemaYield(v interface{}) *int32 {
+	for _, text := range schemaStrings(v) {
+		if match := firstNumberPattern.FindString(text); match != "" {
+			if n, err := strconv.Atoi(match); err == nil && n > 0 && n <= math.MaxInt32 {
+				servings := int32(n)
+				return &servings
+			}
+		}
+	}
+	return nil
+}
+
+// isoDurationMinutes parses an ISO 8601 duration such as "PT1H30M" into
+// whole minutes, rounding seconds up.
+func isoDurationMinutes(duration string) *int32 {
+	match := isoDurationPattern.FindStringSubmatch(strings.TrimSpace(duration))
+	if match == nil {
+		return nil
+	}
+	var minutes float64
+	for i, perUnit := range []float64{24 * 60, 60, 1, 1.0 / 60} {
+		if match[i+1] == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(match[i+1], 64)
+		if err != nil {
+			return nil
+		}
+		minutes += value * perUnit
+	}
+	if minutes <= 0 {
+		return nil
+	}
+	rounded := int32(math.Ceil(minutes))
+	return &rounded
+}
+
+// htmlTextLines flattens text that may contain HTML into trimmed, non-empty
+// lines, breaking at newlines and block elements.
+func htmlTextLines(s string) []string {
+	if !strings.ContainsAny(s, "<&") {
+		return splitLines(s)
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(s), &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div})
+	if err != nil {
+		return splitLines(s)
+	}
+	var lines []string
+	for _, n := range nodes {
+		lines = append(lines, htmlLines(n)...)
+	}
+	return lines
+}
+
+// htmlLines returns the text below n as trimmed, non-empty lines.
+func htmlLines(n *html.Node) []string {
+	var b strings.Builder
+	var write func(n *html.Node)
+	write = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+		case n.DataAtom == atom.Script || n.DataAtom == atom.Style:
+			return
+		case blockElements[n.DataAtom]:
+			b.WriteString("\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			write(c)
+		}
+		if blockElements[n.DataAtom] {
+			b.WriteString("\n")
+		}
+	}
+	write(n)
+	return splitLines(b.String())
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// walkHTML calls visit for n and its descendants in document order, skipping
+// the children of nodes for which visit returns false.
+func walkHTML(n *html.Node, visit func(*html.Node) bool) {
+	if !visit(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkHTML(c, visit)
+	}
+}
+
+func nodeText(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+	}
+	return b.String()
+}
+
+func htmlAttr(n *html.Node, name string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func hasHTMLAttr(n *html.Node, name string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// pageTitle returns the text of the document's <title>.
+func pageTitle(doc *html.Node) string {
+	var title string
+	walkHTML(doc, func(n *html.Node) bool {
+		if title != "" {
+			return false
+		}
+		if n.DataAtom == atom.Title {
+			title = strings.Join(htmlLines(n), " ")
+			return false
+		}
+		return true
+	})
+	return title
+}
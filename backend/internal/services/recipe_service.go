@@ -1,8 +1,9 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
-	"strings"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/homecooking/backend/internal/models"
@@ -10,12 +11,18 @@ import (
 )
 
 type RecipeService struct {
-	recipeRepo *repository.RecipeRepository
+	recipeRepo     *repository.RecipeRepository
+	ingredientRepo *repository.RecipeIngredientRepository
+	revisionRepo   *repository.RecipeRevisionRepository
+	tagRepo        *repository.TagRepository
 }
 
-func NewRecipeService(recipeRepo *repository.RecipeRepository) *RecipeService {
+func NewRecipeService(recipeRepo *repository.RecipeRepository, ingredientRepo *repository.RecipeIngredientRepository, revisionRepo *repository.RecipeRevisionRepository, tagRepo *repository.TagRepository) *RecipeService {
 	return &RecipeService{
-		recipeRepo: recipeRepo,
+		recipeRepo:     recipeRepo,
+		ingredientRepo: ingredientRepo,
+		revisionRepo:   revisionRepo,
+		tagRepo:        tagRepo,
 	}
 }
 
@@ -27,7 +34,15 @@ func (s *RecipeService) CreateRecipe(recipe *models.CreateRecipeRequest, authorI
 		return nil, errors.New("markdown content is required")
 	}
 
-	slug := generateSlug(recipe.Title)
+	tags, err := resolveTagNames(s.tagRepo, recipe.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	slug, err := uniqueSlug(recipe.Title, "recipe", slugOwner("", s.recipeIDBySlug))
+	if err != nil {
+		return nil, err
+	}
 	authorUUID := uuid.MustParse(authorID)
 
 	recipeModel := &models.Recipe{
@@ -45,33 +60,209 @@ func (s *RecipeService) CreateRecipe(recipe *models.CreateRecipeRequest, authorI
 		IsPublished:       recipe.IsPublished,
 	}
 
-	return s.recipeRepo.Create(recipeModel)
+	created, err := s.recipeRepo.CreateWithTags(recipeModel, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recipeRepo.ReleaseOldSlug(created.Slug); err != nil {
+		return nil, err
+	}
+	if err := s.syncIngredients(created); err != nil {
+		return nil, err
+	}
+	if err := s.syncLinks(created); err != nil {
+		return nil, err
+	}
+	if err := s.recordRevision(created, &authorUUID, nil); err != nil {
+		return nil, err
+	}
+	if err := s.recipeRepo.IndexForSearch(created.ID.String()); err != nil {
+		return nil, err
+	}
+	return created, nil
 }
 
 func (s *RecipeService) GetRecipe(id string) (*models.Recipe, error) {
-	return s.recipeRepo.GetByID(id)
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, sql.ErrNoRows
+	}
+	recipe, err := s.recipeRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadIngredients(recipe); err != nil {
+		return nil, err
+	}
+	if err := s.loadLinks(recipe); err != nil {
+		return nil, err
+	}
+	if err := s.loadForks(recipe); err != nil {
+		return nil, err
+	}
+	if recipe.CookStats, err = s.recipeRepo.GetCookStats(recipe.ID.String()); err != nil {
+		return nil, err
+	}
+	return recipe, nil
 }
 
+// GetRecipeBySlug looks the recipe up by its current slug, falling back to
+// slugs it used before a rename. Callers can tell the two apart because the
+// returned recipe's slug differs from the one asked for.
 func (s *RecipeService) GetRecipeBySlug(slug string) (*models.Recipe, error) {
-	return s.recipeRepo.GetBySlug(slug)
+	recipe, err := s.recipeRepo.GetBySlug(slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		recipe, err = s.recipeRepo.GetByOldSlug(slug)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadIngredients(recipe); err != nil {
+		return nil, err
+	}
+	if err := s.loadLinks(recipe); err != nil {
+		return nil, err
+	}
+	if err := s.loadForks(recipe); err != nil {
+		return nil, err
+	}
+	if recipe.CookStats, err = s.recipeRepo.GetCookStats(recipe.ID.String()); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
+
+// GetFullRecipe returns the recipe with the sub-resources named in include,
+// or with all of them when include is empty.
+func (s *RecipeService) GetFullRecipe(id string, include []string) (*models.RecipeWithVariations, error) {
+	if err := validateIncludes(include); err != nil {
+		return nil, err
+	}
+	recipe, err := s.GetRecipe(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadFull(recipe, include)
+}
+
+// GetFullRecipeBySlug is GetFullRecipe for a slug, with the same fallback to
+// old slugs as GetRecipeBySlug.
+func (s *RecipeService) GetFullRecipeBySlug(slug string, include []string) (*models.RecipeWithVariations, error) {
+	if err := validateIncludes(include); err != nil {
+		return nil, err
+	}
+	recipe, err := s.GetRecipeBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadFull(recipe, include)
+}
+
+func (s *RecipeService) loadFull(recipe *models.Recipe, include []string) (*models.RecipeWithVariations, error) {
+	if len(include) == 0 {
+		include = models.RecipeIncludes
+	}
+	full, err := s.recipeRepo.LoadFull(recipe, include)
+	if err != nil {
+		return nil, err
+	}
+	for i := range full.Variations {
+		classifyVariations(&full.Variations[i].RecipeVariation)
+	}
+	return full, nil
+}
+
+func validateIncludes(include []string) error {
+	for _, name := range include {
+		if !slices.Contains(models.RecipeIncludes, name) {
+			return errors.New("invalid include: " + name)
+		}
+	}
+	return nil
 }
 
 func (s *RecipeService) ListRecipes(limit, offset int) ([]*models.Recipe, error) {
 	return s.recipeRepo.List(limit, offset)
 }
 
-func (s *RecipeService) SearchRecipes(query string, limit, offset int) ([]*models.Recipe, error) {
-	return s.recipeRepo.Search(query, limit, offset)
-}
+// FilterRecipes returns a page of published recipes matching the filter
+// together with facet counts for the category, tag and difficulty pickers.
+func (s *RecipeService) FilterRecipes(filter *models.RecipeFilter, limit int, cursor string) (*models.RecipeList, error) {
+	switch filter.Sort {
+	case "":
+		filter.Sort = models.RecipeSortNewest
+	case models.RecipeSortNewest, models.RecipeSortTitle, models.RecipeSortQuickest, models.RecipeSortUpdated:
+	default:
+		return nil, errors.New("invalid sort option")
+	}
+	if filter.AuthorID != "" && parseUUID(&filter.AuthorID) == nil {
+		return nil, errors.New("invalid author ID")
+	}
+	if err := validateDietaryFilter(filter.DietaryFilter); err != nil {
+		return nil, err
+	}
 
-func (s *RecipeService) UpdateRecipe(id string, req *models.UpdateRecipeRequest, authorID string) (*models.Recipe, error) {
-	existing, err := s.recipeRepo.GetByID(id)
+	page, err := s.recipeRepo.ListFiltered(filter, pageSize(limit), cursor)
 	if err != nil {
 		return nil, err
 	}
 
-	if existing.AuthorID.String() != authorID {
-		return nil, errors.New("unauthorized: you can only edit your own recipes")
+	facets, err := s.recipeRepo.FacetCounts(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RecipeList{
+		Page:   *page,
+		Facets: facets,
+	}, nil
+}
+
+func (s *RecipeService) SearchRecipes(query string, dietary models.DietaryFilter, limit int, cursor string) (*models.Page[*models.RecipeSearchResult], error) {
+	if err := validateDietaryFilter(dietary); err != nil {
+		return nil, err
+	}
+	return s.recipeRepo.Search(query, dietary, pageSize(limit), cursor)
+}
+
+func validateDietaryFilter(filter models.DietaryFilter) error {
+	for _, allergen := range filter.FreeFrom {
+		if !slices.Contains(models.Allergens, allergen) {
+			return errors.New("invalid allergen: " + allergen)
+		}
+	}
+	for _, diet := range filter.Diets {
+		if !slices.Contains(models.Diets, diet) {
+			return errors.New("invalid diet: " + diet)
+		}
+	}
+	return nil
+}
+
+// ClassifyUnclassified stores the dietary classification of every recipe
+// that doesn't have one yet, such as recipes created before classification
+// existed, and returns how many it classified.
+func (s *RecipeService) ClassifyUnclassified() (int, error) {
+	recipes, err := s.recipeRepo.ListUnclassified()
+	if err != nil {
+		return 0, err
+	}
+	for _, recipe := range recipes {
+		if err := s.recipeRepo.SaveDietary(recipe.ID.String(), ClassifyDietary(recipe.MarkdownContent)); err != nil {
+			return 0, err
+		}
+	}
+	return len(recipes), nil
+}
+
+// UpdateRecipe applies req and records the resulting state as a new
+// revision. Fields req leaves out keep their values.
+func (s *RecipeService) UpdateRecipe(id string, req *models.UpdateRecipeRequest, authorID string) (*models.Recipe, error) {
+	existing, err := s.editableRecipe(id, authorID)
+	if err != nil {
+		return nil, err
 	}
 
 	recipeModel := &models.Recipe{
@@ -88,12 +279,116 @@ func (s *RecipeService) UpdateRecipe(id string, req *models.UpdateRecipeRequest,
 	}
 
 	if req.Title != nil {
-		recipeModel.Slug = generateSlug(*req.Title)
+		slug, err := uniqueSlug(*req.Title, "recipe", slugOwner(id, s.recipeIDBySlug))
+		if err != nil {
+			return nil, err
+		}
+		if slug != existing.Slug {
+			recipeModel.Slug = slug
+		}
+	}
+
+	updated, err := s.recipeRepo.Update(id, recipeModel)
+	if err != nil {
+		return nil, err
+	}
+	return s.finishUpdate(existing, updated, req.MarkdownContent != nil, authorID, nil)
+}
+
+// restoreRevision puts the recipe's editable fields back to what revision
+// recorded, clearing the ones that were empty then, and records the result
+// as a new revision. The published state is left as it is.
+func (s *RecipeService) restoreRevision(id string, revision *models.RecipeRevision, authorID string) (*models.Recipe, error) {
+	existing, err := s.editableRecipe(id, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	slug, err := uniqueSlug(revision.Title, "recipe", slugOwner(id, s.recipeIDBySlug))
+	if err != nil {
+		return nil, err
+	}
+	updated, err := s.recipeRepo.ReplaceContent(id, &models.Recipe{
+		Title:             revision.Title,
+		Slug:              slug,
+		MarkdownContent:   revision.MarkdownContent,
+		CategoryID:        revision.CategoryID,
+		Description:       revision.Description,
+		PrepTimeMinutes:   revision.PrepTimeMinutes,
+		CookTimeMinutes:   revision.CookTimeMinutes,
+		Servings:          revision.Servings,
+		Difficulty:        revision.Difficulty,
+		FeaturedImagePath: revision.FeaturedImagePath,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.finishUpdate(existing, updated, true, authorID, &revision.RevisionNumber)
+}
+
+// editableRecipe returns the recipe authorID is about to edit. Recipes
+// created before revisions existed get their current state saved first, so
+// the first edit never loses the original text.
+func (s *RecipeService) editableRecipe(id string, authorID string) (*models.Recipe, error) {
+	existing, err := findRecipe(s.recipeRepo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing.AuthorID.String() != authorID {
+		return nil, errors.New("unauthorized: you can only edit your own recipes")
+	}
+
+	count, err := s.revisionRepo.CountByRecipe(id)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		if err := s.recordRevision(existing, existing.AuthorID, nil); err != nil {
+			return nil, err
+		}
+	}
+	return existing, nil
+}
+
+// finishUpdate brings what is derived from a recipe up to date after an
+// edit and records the edit as a new revision. restoredFrom is set when the
+// edit restores an earlier revision.
+func (s *RecipeService) finishUpdate(existing, updated *models.Recipe, markdownChanged bool, authorID string, restoredFrom *int) (*models.Recipe, error) {
+	id := updated.ID.String()
+	if updated.Slug != existing.Slug {
+		if err := s.recipeRepo.RecordOldSlug(existing.Slug, id); err != nil {
+			return nil, err
+		}
+		if err := s.recipeRepo.ReleaseOldSlug(updated.Slug); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if markdownChanged {
+		err = s.syncIngredients(updated)
+	} else {
+		err = s.loadIngredients(updated)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.syncLinks(updated); err != nil {
+		return nil, err
 	}
 
-	return s.recipeRepo.Update(id, recipeModel)
+	if err := s.recordRevision(updated, parseUUID(&authorID), restoredFrom); err != nil {
+		return nil, err
+	}
+	if err := s.recipeRepo.IndexForSearch(id); err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
+// DeleteRecipe moves the recipe to the trash, where it stays restorable
+// until the trash is purged.
 func (s *RecipeService) DeleteRecipe(id string, authorID string) error {
 	existing, err := s.recipeRepo.GetByID(id)
 	if err != nil {
@@ -104,7 +399,10 @@ func (s *RecipeService) DeleteRecipe(id string, authorID string) error {
 		return errors.New("unauthorized: you can only delete your own recipes")
 	}
 
-	return s.recipeRepo.Delete(id)
+	if err := s.recipeRepo.RemoveFromSearch(id); err != nil {
+		return err
+	}
+	return s.recipeRepo.SoftDelete(id)
 }
 
 func (s *RecipeService) PublishRecipe(id string, authorID string, published bool) (*models.Recipe, error) {
@@ -120,12 +418,158 @@ func (s *RecipeService) PublishRecipe(id string, authorID string, published bool
 	return s.recipeRepo.UpdatePublishedStatus(id, published)
 }
 
-func generateSlug(title string) string {
-	slug := strings.ToLower(title)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	slug = strings.ReplaceAll(slug, "'", "")
-	slug = strings.ReplaceAll(slug, "\"", "")
-	return slug
+// ScaleRecipe rewrites the recipe's ingredient quantities for the requested
+// number of servings. The ingredients of linked sub-recipes are inlined at
+// the same scale.
+func (s *RecipeService) ScaleRecipe(id string, servings int) (*models.ScaledRecipe, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, sql.ErrNoRows
+	}
+	recipe, err := s.recipeRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	scaled, err := scaleRecipeContent(recipe.MarkdownContent, recipe.Servings, servings)
+	if err != nil {
+		return nil, err
+	}
+
+	scaled.RecipeID = recipe.ID
+	for i := range scaled.Ingredients {
+		scaled.Ingredients[i].RecipeID = recipe.ID
+	}
+	scaled.Ingredients, err = inlineSubRecipes(s.recipeRepo, recipe.ID, recipe.MarkdownContent, scaled.Ingredients, scaled.Factor)
+	if err != nil {
+		return nil, err
+	}
+	return scaled, nil
+}
+
+// RebuildRestored fills in what CreateRecipe derives from a recipe for one
+// that was written directly by a backup import: its ingredient rows and
+// dietary classification, its search entry and, when the archive carried no
+// revisions for it, its first revision.
+func (s *RecipeService) RebuildRestored(id string) error {
+	recipe, err := s.recipeRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.recipeRepo.ReleaseOldSlug(recipe.Slug); err != nil {
+		return err
+	}
+	if err := s.syncIngredients(recipe); err != nil {
+		return err
+	}
+	if err := s.syncLinks(recipe); err != nil {
+		return err
+	}
+	revisions, err := s.revisionRepo.CountByRecipe(id)
+	if err != nil {
+		return err
+	}
+	if revisions == 0 {
+		if err := s.recordRevision(recipe, recipe.AuthorID, nil); err != nil {
+			return err
+		}
+	}
+	return s.recipeRepo.IndexForSearch(recipe.ID.String())
+}
+
+// syncIngredients re-parses the recipe's markdown, replaces the stored
+// ingredient rows with the result and reclassifies the recipe's allergens
+// and diets.
+func (s *RecipeService) syncIngredients(recipe *models.Recipe) error {
+	ingredients, err := s.ingredientRepo.ReplaceForRecipe(recipe.ID.String(), ParseIngredients(recipe.MarkdownContent))
+	if err != nil {
+		return err
+	}
+	dietary := ClassifyDietary(recipe.MarkdownContent)
+	if err := s.recipeRepo.SaveDietary(recipe.ID.String(), dietary); err != nil {
+		return err
+	}
+	recipe.Ingredients = ingredients
+	recipe.Nutrition = EstimateNutrition(ingredients, recipe.Servings)
+	recipe.Dietary = dietary
+	return nil
+}
+
+// recordRevision snapshots the recipe's editable fields as its next revision.
+func (s *RecipeService) recordRevision(recipe *models.Recipe, authorID *uuid.UUID, restoredFrom *int) error {
+	_, err := s.revisionRepo.Create(&models.RecipeRevision{
+		RecipeID:          recipe.ID,
+		AuthorID:          authorID,
+		Title:             recipe.Title,
+		MarkdownContent:   recipe.MarkdownContent,
+		CategoryID:        recipe.CategoryID,
+		Description:       recipe.Description,
+		PrepTimeMinutes:   recipe.PrepTimeMinutes,
+		CookTimeMinutes:   recipe.CookTimeMinutes,
+		Servings:          recipe.Servings,
+		Difficulty:        recipe.Difficulty,
+		FeaturedImagePath: recipe.FeaturedImagePath,
+		IsPublished:       recipe.IsPublished,
+		RestoredFrom:      restoredFrom,
+	})
+	return err
+}
+
+// loadIngredients fills in the recipe's stored ingredients and dietary
+// classification. A recipe that hasn't been classified yet is classified
+// now.
+func (s *RecipeService) loadIngredients(recipe *models.Recipe) error {
+	ingredients, err := s.ingredientRepo.GetByRecipe(recipe.ID.String())
+	if err != nil {
+		return err
+	}
+	dietary, err := s.recipeRepo.GetDietary(recipe.ID.String())
+	if err != nil {
+		return err
+	}
+	if dietary == nil {
+		dietary = ClassifyDietary(recipe.MarkdownContent)
+		if err := s.recipeRepo.SaveDietary(recipe.ID.String(), dietary); err != nil {
+			return err
+		}
+	}
+	recipe.Ingredients = ingredients
+	recipe.Nutrition = EstimateNutrition(ingredients, recipe.Servings)
+	recipe.Dietary = dietary
+	return nil
+}
+
+// recipeIDBySlug finds the recipe holding a slug, including recipes in the
+// trash, which keep their slug until they are purged.
+func (s *RecipeService) recipeIDBySlug(slug string) (uuid.UUID, error) {
+	return s.recipeRepo.GetIDBySlug(slug)
+}
+
+// findRecipe looks a recipe up by ID for the records that hang off it, so
+// that those disappear along with a recipe moved to the trash. Malformed IDs
+// and missing recipes both fail with "recipe not found".
+func findRecipe(recipeRepo *repository.RecipeRepository, id string) (*models.Recipe, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("recipe not found")
+	}
+	recipe, err := recipeRepo.GetByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("recipe not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
+
+// reindexRecipes rewrites the search entries of the recipes, after a tag or
+// category they are indexed under was renamed or deleted.
+func reindexRecipes(recipeRepo *repository.RecipeRepository, ids []uuid.UUID) error {
+	for _, id := range ids {
+		if err := recipeRepo.IndexForSearch(id.String()); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func parseUUID(s *string) *uuid.UUID {
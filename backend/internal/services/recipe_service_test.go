@@ -2,6 +2,7 @@ package services
 
 import (
 	"database/sql"
+	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
@@ -33,13 +34,21 @@ func createTestUser(db *sql.DB, q *sqlc.Queries, email string) string {
 	return userID
 }
 
+func newTestRecipeService(db *sql.DB, q *sqlc.Queries) *RecipeService {
+	return NewRecipeService(
+		repository.NewRecipeRepository(db, q),
+		repository.NewRecipeIngredientRepository(db, q),
+		repository.NewRecipeRevisionRepository(db, q),
+		repository.NewTagRepository(db, q),
+	)
+}
+
 func TestRecipeService_CreateRecipe(t *testing.T) {
 	db, q, err := testutil.SetupTestDB()
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -62,13 +71,98 @@ func TestRecipeService_CreateRecipe(t *testing.T) {
 	assert.Equal(t, authorID, recipe.AuthorID.String())
 }
 
+func TestRecipeService_CreateRecipe_ParsesIngredients(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	req := &models.CreateRecipeRequest{
+		Title:           "Pancakes",
+		MarkdownContent: "## Ingredients\n\n- 2 cups flour\n- 2 eggs, beaten\n\n## Instructions\n\nMix and cook.",
+		IsPublished:     false,
+	}
+
+	created, err := service.CreateRecipe(req, authorID)
+	require.NoError(t, err)
+	require.Len(t, created.Ingredients, 2)
+	assert.Equal(t, "flour", created.Ingredients[0].Name)
+	assert.Equal(t, "cup", *created.Ingredients[0].Unit)
+
+	fetched, err := service.GetRecipe(created.ID.String())
+	require.NoError(t, err)
+	require.Len(t, fetched.Ingredients, 2)
+	assert.Equal(t, 2.0, *fetched.Ingredients[1].Quantity)
+	assert.Equal(t, "eggs", fetched.Ingredients[1].Name)
+	assert.Equal(t, "beaten", *fetched.Ingredients[1].Note)
+}
+
+func TestRecipeService_ScaleRecipe(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	req := &models.CreateRecipeRequest{
+		Title:           "Pancakes",
+		MarkdownContent: "## Ingredients\n\n- 1 1/2 cups flour\n- 2 eggs\n- Salt\n\n## Instructions\n\nMix and cook.",
+		Servings:        int32Ptr(4),
+	}
+
+	created, err := service.CreateRecipe(req, authorID)
+	require.NoError(t, err)
+
+	scaled, err := service.ScaleRecipe(created.ID.String(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, scaled.RecipeID)
+	assert.Equal(t, 4, scaled.OriginalServings)
+	assert.Equal(t, 0.5, scaled.Factor)
+	assert.Contains(t, scaled.MarkdownContent, "- 3/4 cups flour")
+	assert.Contains(t, scaled.MarkdownContent, "- 1 eggs")
+	require.Len(t, scaled.Ingredients, 3)
+	assert.Equal(t, 0.75, *scaled.Ingredients[0].Quantity)
+	assert.Nil(t, scaled.Ingredients[2].Quantity)
+
+	_, err = service.ScaleRecipe(created.ID.String(), 0)
+	assert.EqualError(t, err, "servings must be a positive number")
+
+	_, err = service.ScaleRecipe("not-a-uuid", 2)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRecipeService_ScaleRecipe_NoServings(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	req := &models.CreateRecipeRequest{
+		Title:           "Pancakes",
+		MarkdownContent: "## Ingredients\n\n- 2 cups flour",
+	}
+
+	created, err := service.CreateRecipe(req, authorID)
+	require.NoError(t, err)
+
+	_, err = service.ScaleRecipe(created.ID.String(), 2)
+	assert.EqualError(t, err, "recipe has no servings to scale from")
+}
+
 func TestRecipeService_CreateRecipe_EmptyTitle(t *testing.T) {
 	db, q, err := testutil.SetupTestDB()
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -88,8 +182,7 @@ func TestRecipeService_CreateRecipe_EmptyContent(t *testing.T) {
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -109,8 +202,7 @@ func TestRecipeService_GetRecipe(t *testing.T) {
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -129,13 +221,47 @@ func TestRecipeService_GetRecipe(t *testing.T) {
 	assert.Equal(t, "Pancakes", fetched.Title)
 }
 
+func TestRecipeService_GetRecipe_Nutrition(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	created, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Scrambled Eggs",
+		MarkdownContent: "## Ingredients\n- 4 eggs\n- 1 tbsp butter\n- 1 sprig of moonflower\n",
+		Servings:        int32Ptr(2),
+	}, authorID)
+	require.NoError(t, err)
+	require.NotNil(t, created.Nutrition)
+
+	fetched, err := service.GetRecipe(created.ID.String())
+	require.NoError(t, err)
+	require.NotNil(t, fetched.Nutrition)
+	assert.Equal(t, 2, fetched.Nutrition.Servings)
+	assert.Equal(t, 388.0, fetched.Nutrition.Total.Calories)
+	assert.Equal(t, 194.0, fetched.Nutrition.PerServing.Calories)
+	assert.Equal(t, models.NutritionConfidenceLow, fetched.Nutrition.Confidence)
+	assert.Equal(t, []string{"1 sprig of moonflower"}, fetched.Nutrition.Unmatched)
+
+	// Recipes without an ingredient list have nothing to estimate.
+	plain, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Toast",
+		MarkdownContent: "Toast the bread.",
+	}, authorID)
+	require.NoError(t, err)
+	assert.Nil(t, plain.Nutrition)
+}
+
 func TestRecipeService_GetRecipe_NotFound(t *testing.T) {
 	db, q, err := testutil.SetupTestDB()
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	_, err = service.GetRecipe("00000000-0000-0000-0000-000000000000")
 	assert.Error(t, err)
@@ -146,8 +272,7 @@ func TestRecipeService_GetRecipeBySlug(t *testing.T) {
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -171,8 +296,7 @@ func TestRecipeService_ListRecipes(t *testing.T) {
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -197,15 +321,377 @@ func TestRecipeService_ListRecipes(t *testing.T) {
 	assert.Len(t, recipes, 2)
 }
 
-func TestRecipeService_SearchRecipes(t *testing.T) {
-	t.Skip("Skip: SQLite ILIKE operator issue - needs PostgreSQL-specific query handling")
+func TestRecipeService_FilterRecipes(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	tagRepo := repository.NewTagRepository(db, q)
+	groupRepo := repository.NewRecipeGroupRepository(db, q)
+	categoryRepo := repository.NewCategoryRepository(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+	otherAuthorID := createTestUser(db, q, "other@example.com")
+
+	breakfast, err := categoryRepo.Create(&models.Category{Name: "Breakfast", Slug: "breakfast"})
+	require.NoError(t, err)
+	dinner, err := categoryRepo.Create(&models.Category{Name: "Dinner", Slug: "dinner"})
+	require.NoError(t, err)
+	vegetarian, err := tagRepo.Create(&models.Tag{Name: "Vegetarian", Slug: "vegetarian", Color: "#22c55e"})
+	require.NoError(t, err)
+	quick, err := tagRepo.Create(&models.Tag{Name: "Quick", Slug: "quick", Color: "#f97316"})
+	require.NoError(t, err)
+	weekday, err := groupRepo.Create(&models.RecipeGroup{Name: "Weekday", Slug: "weekday"})
+	require.NoError(t, err)
+
+	breakfastID := breakfast.ID.String()
+	dinnerID := dinner.ID.String()
+	maxTime := 20
+
+	create := func(title string, categoryID *string, difficulty string, prep, cook int32, author string, tags ...*models.Tag) *models.Recipe {
+		recipe, err := service.CreateRecipe(&models.CreateRecipeRequest{
+			Title:           title,
+			MarkdownContent: "Cook the " + title + ".",
+			CategoryID:      categoryID,
+			Difficulty:      stringPtr(difficulty),
+			PrepTimeMinutes: int32Ptr(prep),
+			CookTimeMinutes: int32Ptr(cook),
+			IsPublished:     true,
+		}, author)
+		require.NoError(t, err)
+		for _, tag := range tags {
+			require.NoError(t, tagRepo.AddToRecipe(recipe.ID.String(), tag.ID.String()))
+		}
+		return recipe
+	}
+
+	create("Pancakes", &breakfastID, "easy", 10, 10, authorID, vegetarian, quick)
+	omelette := create("Omelette", &breakfastID, "easy", 5, 5, authorID, quick)
+	create("Lasagna", &dinnerID, "hard", 30, 60, otherAuthorID, vegetarian)
+	stew := create("Beef Stew", &dinnerID, "medium", 20, 120, otherAuthorID)
+	require.NoError(t, groupRepo.AddRecipeToGroup(weekday.ID.String(), omelette.ID.String()))
+	require.NoError(t, groupRepo.AddRecipeToGroup(weekday.ID.String(), stew.ID.String()))
+
+	_, err = service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Secret Draft",
+		MarkdownContent: "Not ready.",
+		CategoryID:      &breakfastID,
+	}, authorID)
+	require.NoError(t, err)
+
+	titles := func(list *models.RecipeList) []string {
+		var result []string
+		for _, recipe := range list.Items {
+			result = append(result, recipe.Title)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		filter   models.RecipeFilter
+		expected []string
+	}{
+		{"category by slug", models.RecipeFilter{Category: "breakfast", Sort: models.RecipeSortTitle}, []string{"Omelette", "Pancakes"}},
+		{"category by id", models.RecipeFilter{Category: dinnerID, Sort: models.RecipeSortTitle}, []string{"Beef Stew", "Lasagna"}},
+		{"any tag", models.RecipeFilter{Tags: []string{"vegetarian", "quick"}, Sort: models.RecipeSortTitle}, []string{"Lasagna", "Omelette", "Pancakes"}},
+		{"all tags", models.RecipeFilter{Tags: []string{"vegetarian", quick.ID.String()}, MatchAllTags: true}, []string{"Pancakes"}},
+		{"all tags named twice", models.RecipeFilter{Tags: []string{"quick", quick.ID.String(), "quick"}, MatchAllTags: true, Sort: models.RecipeSortTitle}, []string{"Omelette", "Pancakes"}},
+		{"all tags with unknown tag", models.RecipeFilter{Tags: []string{"quick", "unknown"}, MatchAllTags: true}, nil},
+		{"any tag with unknown tag", models.RecipeFilter{Tags: []string{"quick", "unknown"}, Sort: models.RecipeSortTitle}, []string{"Omelette", "Pancakes"}},
+		{"unknown tag", models.RecipeFilter{Tags: []string{"unknown"}}, nil},
+		{"difficulty", models.RecipeFilter{Difficulty: "easy", Sort: models.RecipeSortTitle}, []string{"Omelette", "Pancakes"}},
+		{"max total time", models.RecipeFilter{MaxTotalTime: &maxTime, Sort: models.RecipeSortTitle}, []string{"Omelette", "Pancakes"}},
+		{"author", models.RecipeFilter{AuthorID: otherAuthorID, Sort: models.RecipeSortTitle}, []string{"Beef Stew", "Lasagna"}},
+		{"group", models.RecipeFilter{Group: "weekday", Sort: models.RecipeSortTitle}, []string{"Beef Stew", "Omelette"}},
+		{"quickest", models.RecipeFilter{Sort: models.RecipeSortQuickest}, []string{"Omelette", "Pancakes", "Lasagna", "Beef Stew"}},
+		{"combined", models.RecipeFilter{Category: "breakfast", Tags: []string{"vegetarian"}}, []string{"Pancakes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			list, err := service.FilterRecipes(&filter, 20, "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, titles(list))
+		})
+	}
+
+	list, err := service.FilterRecipes(&models.RecipeFilter{}, 20, "")
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 4)
+	assert.NotContains(t, titles(list), "Secret Draft")
+}
+
+func TestRecipeService_FilterRecipes_Facets(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	tagRepo := repository.NewTagRepository(db, q)
+	categoryRepo := repository.NewCategoryRepository(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	breakfast, err := categoryRepo.Create(&models.Category{Name: "Breakfast", Slug: "breakfast"})
+	require.NoError(t, err)
+	dinner, err := categoryRepo.Create(&models.Category{Name: "Dinner", Slug: "dinner"})
+	require.NoError(t, err)
+	vegetarian, err := tagRepo.Create(&models.Tag{Name: "Vegetarian", Slug: "vegetarian", Color: "#22c55e"})
+	require.NoError(t, err)
+
+	breakfastID := breakfast.ID.String()
+	dinnerID := dinner.ID.String()
+
+	for _, req := range []*models.CreateRecipeRequest{
+		{Title: "Pancakes", CategoryID: &breakfastID, Difficulty: stringPtr("easy")},
+		{Title: "Omelette", CategoryID: &breakfastID, Difficulty: stringPtr("easy")},
+		{Title: "Lasagna", CategoryID: &dinnerID, Difficulty: stringPtr("hard")},
+	} {
+		req.MarkdownContent = "Cook it."
+		req.IsPublished = true
+		recipe, err := service.CreateRecipe(req, authorID)
+		require.NoError(t, err)
+		if req.Title != "Omelette" {
+			require.NoError(t, tagRepo.AddToRecipe(recipe.ID.String(), vegetarian.ID.String()))
+		}
+	}
+
+	list, err := service.FilterRecipes(&models.RecipeFilter{Category: "breakfast"}, 20, "")
+	require.NoError(t, err)
+	require.NotNil(t, list.Facets)
+
+	// The category facet ignores the category filter so the other
+	// categories remain selectable.
+	assert.ElementsMatch(t, []models.FacetCount{
+		{Value: "breakfast", Label: "Breakfast", Count: 2},
+		{Value: "dinner", Label: "Dinner", Count: 1},
+	}, list.Facets.Categories)
+	assert.Equal(t, []models.FacetCount{
+		{Value: "vegetarian", Label: "Vegetarian", Count: 1},
+	}, list.Facets.Tags)
+	assert.Equal(t, []models.FacetCount{
+		{Value: "easy", Label: "easy", Count: 2},
+	}, list.Facets.Difficulties)
+}
+
+func TestRecipeService_FilterRecipes_Pagination(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	for _, title := range []string{"Apple Pie", "Banana Bread", "Carrot Cake", "Date Squares", "Eclairs"} {
+		_, err := service.CreateRecipe(&models.CreateRecipeRequest{
+			Title:           title,
+			MarkdownContent: "Bake the " + title + ".",
+			IsPublished:     true,
+		}, authorID)
+		require.NoError(t, err)
+	}
+
+	filter := &models.RecipeFilter{Sort: models.RecipeSortTitle}
+	first, err := service.FilterRecipes(filter, 2, "")
+	require.NoError(t, err)
+	assert.Equal(t, 5, first.Total)
+	require.Len(t, first.Items, 2)
+	assert.Equal(t, "Apple Pie", first.Items[0].Title)
+	assert.Equal(t, "Banana Bread", first.Items[1].Title)
+	require.NotNil(t, first.NextCursor)
+
+	// A recipe published mid-scroll that sorts before the cursor must not
+	// shift the following pages.
+	_, err = service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Almond Tart",
+		MarkdownContent: "Bake the tart.",
+		IsPublished:     true,
+	}, authorID)
+	require.NoError(t, err)
+
+	second, err := service.FilterRecipes(filter, 2, *first.NextCursor)
+	require.NoError(t, err)
+	require.Len(t, second.Items, 2)
+	assert.Equal(t, "Carrot Cake", second.Items[0].Title)
+	assert.Equal(t, "Date Squares", second.Items[1].Title)
+	require.NotNil(t, second.NextCursor)
+
+	last, err := service.FilterRecipes(filter, 2, *second.NextCursor)
+	require.NoError(t, err)
+	require.Len(t, last.Items, 1)
+	assert.Equal(t, "Eclairs", last.Items[0].Title)
+	assert.Nil(t, last.NextCursor)
+	assert.Equal(t, 6, last.Total)
+}
+
+func TestRecipeService_FilterRecipes_NewestPagination(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	for i := 0; i < 7; i++ {
+		_, err := service.CreateRecipe(&models.CreateRecipeRequest{
+			Title:           fmt.Sprintf("Recipe %d", i),
+			MarkdownContent: "Cook it.",
+			IsPublished:     true,
+		}, authorID)
+		require.NoError(t, err)
+	}
+
+	// Walking every page returns each recipe exactly once, even though the
+	// recipes share a creation timestamp.
+	seen := map[string]bool{}
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		list, err := service.FilterRecipes(&models.RecipeFilter{}, 3, cursor)
+		require.NoError(t, err)
+		for _, recipe := range list.Items {
+			assert.False(t, seen[recipe.Title], "duplicate %s", recipe.Title)
+			seen[recipe.Title] = true
+		}
+		if list.NextCursor == nil {
+			break
+		}
+		cursor = *list.NextCursor
+	}
+	assert.Len(t, seen, 7)
+}
+
+func TestRecipeService_FilterRecipes_CursorAfterDelete(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "test@example.com")
+
+	apple := createPublishedRecipe(t, service, "apple pie", authorID)
+	createPublishedRecipe(t, service, "Banana Bread", authorID)
+	createPublishedRecipe(t, service, "Carrot Cake", authorID)
+
+	filter := &models.RecipeFilter{Sort: models.RecipeSortTitle}
+	first, err := service.FilterRecipes(filter, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apple pie"}, recipeTitles(first.Items))
+	require.NotNil(t, first.NextCursor)
+
+	// The cursor holds the sort key, so it outlives the recipe it marks.
+	require.NoError(t, service.DeleteRecipe(apple.ID.String(), authorID))
+	next, err := service.FilterRecipes(filter, 1, *first.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Banana Bread"}, recipeTitles(next.Items))
+
+	_, err = service.FilterRecipes(&models.RecipeFilter{Sort: models.RecipeSortUpdated}, 1, *first.NextCursor)
+	assert.EqualError(t, err, "invalid cursor", "a title cursor can't resume a list sorted by date")
+}
+
+func TestRecipeService_FilterRecipes_InvalidCursor(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+
+	_, err = service.FilterRecipes(&models.RecipeFilter{}, 20, "not-a-cursor")
+	assert.Error(t, err)
+	assert.Equal(t, "invalid cursor", err.Error())
+}
 
+func TestRecipeService_FilterRecipes_InvalidSort(t *testing.T) {
 	db, q, err := testutil.SetupTestDB()
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
+	service := newTestRecipeService(db, q)
+
+	_, err = service.FilterRecipes(&models.RecipeFilter{Sort: "popular"}, 20, "")
+	assert.Error(t, err)
+	assert.Equal(t, "invalid sort option", err.Error())
+}
+
+func TestRecipeService_FilterRecipes_Dietary(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
 	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	create := func(title, ingredients string) *models.Recipe {
+		recipe, err := service.CreateRecipe(&models.CreateRecipeRequest{
+			Title:           title,
+			MarkdownContent: "## Ingredients\n" + ingredients,
+			IsPublished:     true,
+		}, authorID)
+		require.NoError(t, err)
+		return recipe
+	}
+
+	brownies := create("Walnut Brownies", "- 1 cup flour\n- 2 eggs\n- 1/2 cup butter\n- 1 cup walnuts")
+	assert.Equal(t, []string{models.AllergenGluten, models.AllergenDairy, models.AllergenEgg, models.AllergenNuts}, brownies.Dietary.Allergens)
+	create("Lentil Soup", "- 1 cup lentils\n- 4 cups vegetable broth\n- 1 onion")
+	create("Chicken Soup", "- 1 chicken\n- 2 carrots\n- 1 cup egg noodles")
+
+	// Recipes saved without going through the service have no
+	// classification and stay out of dietary filters until classified.
+	legacyAuthor := uuid.MustParse(authorID)
+	_, err = recipeRepo.Create(&models.Recipe{
+		Title:           "Old Fruit Salad",
+		Slug:            "old-fruit-salad",
+		MarkdownContent: "## Ingredients\n- 2 apples\n- 1 banana",
+		AuthorID:        &legacyAuthor,
+		IsPublished:     true,
+	})
+	require.NoError(t, err)
+
+	titles := func(filter models.DietaryFilter) []string {
+		list, err := service.FilterRecipes(&models.RecipeFilter{DietaryFilter: filter, Sort: models.RecipeSortTitle}, 20, "")
+		require.NoError(t, err)
+		var result []string
+		for _, recipe := range list.Items {
+			result = append(result, recipe.Title)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"Chicken Soup", "Lentil Soup"}, titles(models.DietaryFilter{FreeFrom: []string{models.AllergenNuts}}))
+	assert.Equal(t, []string{"Lentil Soup"}, titles(models.DietaryFilter{FreeFrom: []string{models.AllergenNuts, models.AllergenEgg}}))
+	assert.Equal(t, []string{"Lentil Soup", "Walnut Brownies"}, titles(models.DietaryFilter{Diets: []string{models.DietVegetarian}}))
+	assert.Equal(t, []string{"Lentil Soup"}, titles(models.DietaryFilter{Diets: []string{models.DietVegan, models.DietGlutenFree}}))
+
+	count, err := service.ClassifyUnclassified()
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"Lentil Soup", "Old Fruit Salad"}, titles(models.DietaryFilter{Diets: []string{models.DietVegan}}))
+
+	results, err := service.SearchRecipes("soup", models.DietaryFilter{Diets: []string{models.DietVegetarian}}, 10, "")
+	require.NoError(t, err)
+	require.Len(t, results.Items, 1)
+	assert.Equal(t, 1, results.Total)
+	assert.Equal(t, "Lentil Soup", results.Items[0].Title)
+
+	_, err = service.FilterRecipes(&models.RecipeFilter{DietaryFilter: models.DietaryFilter{FreeFrom: []string{"kale"}}}, 20, "")
+	assert.EqualError(t, err, "invalid allergen: kale")
+	_, err = service.SearchRecipes("soup", models.DietaryFilter{Diets: []string{"keto"}}, 10, "")
+	assert.EqualError(t, err, "invalid diet: keto")
+}
+
+func TestRecipeService_SearchRecipes(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -227,10 +713,337 @@ func TestRecipeService_SearchRecipes(t *testing.T) {
 	_, err = service.CreateRecipe(req2, authorID)
 	require.NoError(t, err)
 
-	recipes, err := service.SearchRecipes("pancake", 10, 0)
+	recipes, err := service.SearchRecipes("pancake", models.DietaryFilter{}, 10, "")
+	require.NoError(t, err)
+	assert.Len(t, recipes.Items, 1)
+	assert.Equal(t, 1, recipes.Total)
+	assert.Equal(t, "Fluffy Pancakes", recipes.Items[0].Title)
+}
+
+func TestRecipeService_SearchRecipes_Ranking(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	_, err = service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Lemon Tart",
+		MarkdownContent: "## Ingredients\n\n- 3 lemons\n- 1 cup sugar\n\n## Instructions\n\nBake the pastry blind.",
+		IsPublished:     true,
+	}, authorID)
+	require.NoError(t, err)
+
+	_, err = service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Roast Chicken",
+		Description:     stringPtr("Sunday dinner"),
+		MarkdownContent: "## Ingredients\n\n- 1 chicken\n- 1 lemon, halved\n\n## Instructions\n\nStuff the chicken & roast.",
+		IsPublished:     true,
+	}, authorID)
+	require.NoError(t, err)
+
+	_, err = service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Secret Lemonade",
+		MarkdownContent: "- lemons",
+		IsPublished:     false,
+	}, authorID)
+	require.NoError(t, err)
+
+	results, err := service.SearchRecipes("lemon", models.DietaryFilter{}, 10, "")
+	require.NoError(t, err)
+	require.Len(t, results.Items, 2)
+	// A title match ranks above a match in the body.
+	assert.Equal(t, "Lemon Tart", results.Items[0].Title)
+	assert.Equal(t, "Roast Chicken", results.Items[1].Title)
+	assert.Greater(t, results.Items[0].Rank, results.Items[1].Rank)
+	assert.Contains(t, results.Items[1].Snippet, "<mark>")
+	assert.Contains(t, results.Items[1].Snippet, "&amp;")
+
+	results, err = service.SearchRecipes("chicken dinner", models.DietaryFilter{}, 10, "")
+	require.NoError(t, err)
+	require.Len(t, results.Items, 1)
+	assert.Equal(t, "Roast Chicken", results.Items[0].Title)
+
+	results, err = service.SearchRecipes("pastry", models.DietaryFilter{}, 10, "")
+	require.NoError(t, err)
+	require.Len(t, results.Items, 1)
+
+	results, err = service.SearchRecipes("  !! ", models.DietaryFilter{}, 10, "")
+	require.NoError(t, err)
+	assert.Empty(t, results.Items)
+}
+
+func TestRecipeService_SearchRecipes_Pagination(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	for _, title := range []string{"Tomato Soup", "Tomato Salad", "Tomato Sauce", "Stuffed Tomatoes"} {
+		_, err := service.CreateRecipe(&models.CreateRecipeRequest{
+			Title:           title,
+			MarkdownContent: "- 4 tomatoes",
+			IsPublished:     true,
+		}, authorID)
+		require.NoError(t, err)
+	}
+
+	var titles []string
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		results, err := service.SearchRecipes("tomato", models.DietaryFilter{}, 3, cursor)
+		require.NoError(t, err)
+		assert.Equal(t, 4, results.Total)
+		for _, result := range results.Items {
+			titles = append(titles, result.Title)
+		}
+		if results.NextCursor == nil {
+			break
+		}
+		cursor = *results.NextCursor
+	}
+	assert.ElementsMatch(t, []string{"Tomato Soup", "Tomato Salad", "Tomato Sauce", "Stuffed Tomatoes"}, titles)
+}
+
+func TestRecipeService_DeleteRecipe_RemovesFromSearch(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	created, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Gazpacho",
+		MarkdownContent: "- tomatoes",
+		IsPublished:     true,
+	}, authorID)
+	require.NoError(t, err)
+
+	require.NoError(t, service.DeleteRecipe(created.ID.String(), authorID))
+
+	results, err := service.SearchRecipes("gazpacho", models.DietaryFilter{}, 10, "")
+	require.NoError(t, err)
+	assert.Empty(t, results.Items)
+}
+
+func TestRecipeService_CreateRecipe_UniqueSlug(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	var slugs []string
+	for i := 0; i < 3; i++ {
+		recipe, err := service.CreateRecipe(&models.CreateRecipeRequest{
+			Title:           "Banana Bread",
+			MarkdownContent: "Bake it.",
+		}, authorID)
+		require.NoError(t, err)
+		slugs = append(slugs, recipe.Slug)
+	}
+	assert.Equal(t, []string{"banana-bread", "banana-bread-2", "banana-bread-3"}, slugs)
+
+	recipe, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "🍞",
+		MarkdownContent: "Bake it.",
+	}, authorID)
+	require.NoError(t, err)
+	assert.Regexp(t, `^recipe-[0-9a-f]{8}$`, recipe.Slug)
+}
+
+func TestRecipeService_GetRecipeBySlug_OldSlug(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	recipeRepo := repository.NewRecipeRepository(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	created, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Banana Bread",
+		MarkdownContent: "Bake it.",
+	}, authorID)
+	require.NoError(t, err)
+
+	// UpdateRecipe cannot run on SQLite, so the rename is recorded directly.
+	_, err = db.Exec(`UPDATE recipes SET slug = 'best-banana-bread' WHERE id = ?`, created.ID.String())
+	require.NoError(t, err)
+	require.NoError(t, recipeRepo.RecordOldSlug("banana-bread", created.ID.String()))
+
+	recipe, err := service.GetRecipeBySlug("banana-bread")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, recipe.ID)
+	assert.Equal(t, "best-banana-bread", recipe.Slug)
+
+	// A new recipe may claim the old slug, after which it no longer redirects.
+	claimed, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Banana Bread",
+		MarkdownContent: "A different loaf.",
+	}, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, "banana-bread", claimed.Slug)
+
+	recipe, err = service.GetRecipeBySlug("banana-bread")
 	require.NoError(t, err)
-	assert.Len(t, recipes, 1)
-	assert.Equal(t, "Fluffy Pancakes", recipes[0].Title)
+	assert.Equal(t, claimed.ID, recipe.ID)
+
+	_, err = recipeRepo.GetByOldSlug("banana-bread")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = service.GetRecipeBySlug("no-such-recipe")
+	assert.Error(t, err)
+}
+
+func TestRecipeService_CreateRecipe_InlineTags(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	tagRepo := repository.NewTagRepository(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	quick, err := tagRepo.Create(&models.Tag{Name: "Quick", Slug: "quick", Color: "#f97316"})
+	require.NoError(t, err)
+
+	created, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Pancakes",
+		MarkdownContent: "Mix and cook.",
+		Tags:            []string{"Quick", "Breakfast"},
+	}, authorID)
+	require.NoError(t, err)
+
+	tags, err := tagRepo.GetRecipeTags(created.ID.String())
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	slugs := map[string]bool{}
+	for _, tag := range tags {
+		slugs[tag.Slug] = true
+		if tag.Slug == "quick" {
+			assert.Equal(t, quick.ID, tag.ID)
+		}
+	}
+	assert.True(t, slugs["breakfast"])
+
+	_, err = service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Waffles",
+		MarkdownContent: "Mix and bake.",
+		Tags:            []string{""},
+	}, authorID)
+	assert.Error(t, err)
+	_, err = service.GetRecipeBySlug("waffles")
+	assert.Error(t, err)
+}
+
+func TestRecipeRepository_CreateWithTags_RollsBack(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	recipeRepo := repository.NewRecipeRepository(db, q)
+	tagRepo := repository.NewTagRepository(db, q)
+
+	_, err = tagRepo.Create(&models.Tag{Name: "Quick", Slug: "quick", Color: "#f97316"})
+	require.NoError(t, err)
+
+	_, err = recipeRepo.CreateWithTags(&models.Recipe{
+		Title:           "Pancakes",
+		Slug:            "pancakes",
+		MarkdownContent: "Mix and cook.",
+	}, []*models.Tag{{Name: "Breakfast", Slug: "breakfast"}, {Name: "Quick", Slug: "speedy"}})
+	require.Error(t, err)
+
+	_, err = recipeRepo.GetBySlug("pancakes")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = tagRepo.GetBySlug("breakfast")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRecipeService_GetFullRecipe(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service := newTestRecipeService(db, q)
+	tagRepo := repository.NewTagRepository(db, q)
+	groupRepo := repository.NewRecipeGroupRepository(db, q)
+	categoryRepo := repository.NewCategoryRepository(db, q)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	breakfast, err := categoryRepo.Create(&models.Category{Name: "Breakfast", Slug: "breakfast"})
+	require.NoError(t, err)
+	quick, err := tagRepo.Create(&models.Tag{Name: "Quick", Slug: "quick", Color: "#f97316"})
+	require.NoError(t, err)
+	weekday, err := groupRepo.Create(&models.RecipeGroup{Name: "Weekday", Slug: "weekday"})
+	require.NoError(t, err)
+
+	breakfastID := breakfast.ID.String()
+	created, err := service.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Pancakes",
+		MarkdownContent: "## Ingredients\n- 2 cups flour\n",
+		CategoryID:      &breakfastID,
+		IsPublished:     true,
+	}, authorID)
+	require.NoError(t, err)
+	require.NoError(t, tagRepo.AddToRecipe(created.ID.String(), quick.ID.String()))
+	require.NoError(t, groupRepo.AddRecipeToGroup(weekday.ID.String(), created.ID.String()))
+
+	full, err := service.GetFullRecipe(created.ID.String(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, full.Recipe.ID)
+	assert.Len(t, full.Recipe.Ingredients, 1)
+	require.NotNil(t, full.Category)
+	assert.Equal(t, "Breakfast", full.Category.Name)
+	require.NotNil(t, full.Author)
+	assert.Equal(t, "test@example.com", full.Author.Email)
+	require.Len(t, full.Tags, 1)
+	assert.Equal(t, "quick", full.Tags[0].Slug)
+	require.Len(t, full.Groups, 1)
+	assert.Equal(t, "weekday", full.Groups[0].Slug)
+	assert.NotNil(t, full.BodyImages)
+	assert.Empty(t, full.BodyImages)
+	assert.NotNil(t, full.Variations)
+	assert.Empty(t, full.Variations)
+
+	full, err = service.GetFullRecipe(created.ID.String(), []string{models.RecipeIncludeTags})
+	require.NoError(t, err)
+	assert.Len(t, full.Tags, 1)
+	assert.Nil(t, full.Category)
+	assert.Nil(t, full.Author)
+	assert.Nil(t, full.Groups)
+	assert.Nil(t, full.BodyImages)
+	assert.Nil(t, full.Variations)
+
+	_, err = service.GetFullRecipe("not-a-uuid", nil)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	full, err = service.GetFullRecipeBySlug("pancakes", []string{models.RecipeIncludeCategory, models.RecipeIncludeGroups})
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, full.Recipe.ID)
+	assert.NotNil(t, full.Category)
+	assert.Len(t, full.Groups, 1)
+	assert.Nil(t, full.Tags)
+
+	_, err = service.GetFullRecipe(created.ID.String(), []string{"comments"})
+	require.Error(t, err)
+	assert.Equal(t, "invalid include: comments", err.Error())
+
+	_, err = service.GetFullRecipeBySlug("no-such-recipe", nil)
+	assert.Error(t, err)
 }
 
 func TestRecipeService_UpdateRecipe(t *testing.T) {
@@ -240,8 +1053,7 @@ func TestRecipeService_UpdateRecipe(t *testing.T) {
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -270,8 +1082,7 @@ func TestRecipeService_UpdateRecipe_NotOwner(t *testing.T) {
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -298,8 +1109,7 @@ func TestRecipeService_DeleteRecipe(t *testing.T) {
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -324,8 +1134,7 @@ func TestRecipeService_DeleteRecipe_NotOwner(t *testing.T) {
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -350,8 +1159,7 @@ func TestRecipeService_PublishRecipe(t *testing.T) {
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -375,8 +1183,7 @@ func TestRecipeService_PublishRecipe_NotOwner(t *testing.T) {
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
-	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewRecipeService(recipeRepo)
+	service := newTestRecipeService(db, q)
 
 	authorID := createTestUser(db, q, "test@example.com")
 
@@ -405,6 +1212,13 @@ func TestRecipeService_GenerateSlug(t *testing.T) {
 		{"with apostrophe", "Chef's Special", "chefs-special"},
 		{"with quotes", "\"Best\" Recipe", "best-recipe"},
 		{"already lowercase", "omelette", "omelette"},
+		{"ampersand", "Mac & Cheese", "mac-and-cheese"},
+		{"accents", "Crème Brûlée", "creme-brulee"},
+		{"ligatures and sharp s", "Œufs à la Straße", "oeufs-a-la-strasse"},
+		{"punctuation runs", "Pancakes!!! (Best -- Ever)", "pancakes-best-ever"},
+		{"curly apostrophe", "Grandma’s Pie", "grandmas-pie"},
+		{"digits", "5-Minute Fudge", "5-minute-fudge"},
+		{"nothing usable", "🍰", ""},
 	}
 
 	for _, tt := range tests {
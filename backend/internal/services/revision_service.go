@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+)
+
+type RevisionService struct {
+	revisionRepo  *repository.RecipeRevisionRepository
+	recipeService *RecipeService
+}
+
+func NewRevisionService(revisionRepo *repository.RecipeRevisionRepository, recipeService *RecipeService) *RevisionService {
+	return &RevisionService{
+		revisionRepo:  revisionRepo,
+		recipeService: recipeService,
+	}
+}
+
+func (s *RevisionService) ListRevisions(recipeID string) ([]*models.RecipeRevision, error) {
+	if _, err := findRecipe(s.recipeService.recipeRepo, recipeID); err != nil {
+		return nil, err
+	}
+	return s.revisionRepo.ListByRecipe(recipeID)
+}
+
+func (s *RevisionService) GetRevision(recipeID string, revisionNumber int) (*models.RecipeRevision, error) {
+	if _, err := findRecipe(s.recipeService.recipeRepo, recipeID); err != nil {
+		return nil, err
+	}
+	return s.revisionRepo.GetByNumber(recipeID, revisionNumber)
+}
+
+// DiffRevisions compares two revisions of a recipe: a line diff of the
+// markdown plus the names of the other fields that changed.
+func (s *RevisionService) DiffRevisions(recipeID string, from, to int) (*models.RevisionDiff, error) {
+	if _, err := findRecipe(s.recipeService.recipeRepo, recipeID); err != nil {
+		return nil, err
+	}
+	fromRevision, err := s.revisionRepo.GetByNumber(recipeID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.revisionRepo.GetByNumber(recipeID, to)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := diffLines(fromRevision.MarkdownContent, toRevision.MarkdownContent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RevisionDiff{
+		RecipeID:      fromRevision.RecipeID,
+		From:          from,
+		To:            to,
+		ChangedFields: changedFields(fromRevision, toRevision),
+		Lines:         lines,
+	}, nil
+}
+
+// RestoreRevision copies an old revision's content back onto the recipe,
+// including the fields that were empty then. The restore is itself recorded
+// as a new revision, so nothing is lost. The recipe's published state is
+// left as it is.
+func (s *RevisionService) RestoreRevision(recipeID string, revisionNumber int, authorID string) (*models.Recipe, error) {
+	if _, err := findRecipe(s.recipeService.recipeRepo, recipeID); err != nil {
+		return nil, err
+	}
+	revision, err := s.revisionRepo.GetByNumber(recipeID, revisionNumber)
+	if err != nil {
+		return nil, err
+	}
+	return s.recipeService.restoreRevision(recipeID, revision, authorID)
+}
+
+func changedFields(a, b *models.RecipeRevision) []string {
+	changed := []string{}
+	if a.Title != b.Title {
+		changed = append(changed, "title")
+	}
+	if a.MarkdownContent != b.MarkdownContent {
+		changed = append(changed, "markdown_content")
+	}
+	if !ptrEqual(a.CategoryID, b.CategoryID) {
+		changed = append(changed, "category_id")
+	}
+	if !ptrEqual(a.Description, b.Description) {
+		changed = append(changed, "description")
+	}
+	if !ptrEqual(a.PrepTimeMinutes, b.PrepTimeMinutes) {
+		changed = append(changed, "prep_time_minutes")
+	}
+	if !ptrEqual(a.CookTimeMinutes, b.CookTimeMinutes) {
+		changed = append(changed, "cook_time_minutes")
+	}
+	if !ptrEqual(a.Servings, b.Servings) {
+		changed = append(changed, "servings")
+	}
+	if !ptrEqual(a.Difficulty, b.Difficulty) {
+		changed = append(changed, "difficulty")
+	}
+	if !ptrEqual(a.FeaturedImagePath, b.FeaturedImagePath) {
+		changed = append(changed, "featured_image_path")
+	}
+	if a.IsPublished != b.IsPublished {
+		changed = append(changed, "is_published")
+	}
+	return changed
+}
+
+func ptrEqual[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// maxDiffCells bounds the table diffLines fills in for the lines that
+// differ between two texts, so comparing two large, very different
+// revisions can't take up unbounded memory and time.
+const maxDiffCells = 4 << 20
+
+// diffLines produces a line diff between two texts from their longest common
+// subsequence of lines. The lines the texts start and end with in common are
+// taken off first, so only the part that changed is compared.
+func diffLines(oldText, newText string) ([]models.DiffLine, error) {
+	oldLines := strings.Split(oldText, "\n")
+	newLines := strings.Split(newText, "\n")
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+	oldMiddle := oldLines[prefix : len(oldLines)-suffix]
+	newMiddle := newLines[prefix : len(newLines)-suffix]
+	n, m := len(oldMiddle), len(newMiddle)
+	if (n+1)*(m+1) > maxDiffCells {
+		return nil, errors.New("revisions are too large to compare")
+	}
+
+	// lcs[i*(m+1)+j] is the LCS length of oldMiddle[i:] and newMiddle[j:].
+	width := m + 1
+	lcs := make([]int32, (n+1)*width)
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldMiddle[i] == newMiddle[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	lines := make([]models.DiffLine, 0, prefix+suffix+n+m)
+	for k := 0; k < prefix; k++ {
+		lines = append(lines, models.DiffLine{Op: "equal", Text: oldLines[k], OldLine: lineNumber(k), NewLine: lineNumber(k)})
+	}
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && oldMiddle[i] == newMiddle[j]:
+			lines = append(lines, models.DiffLine{Op: "equal", Text: oldMiddle[i], OldLine: lineNumber(prefix + i), NewLine: lineNumber(prefix + j)})
+			i++
+			j++
+		case i < n && (j == m || lcs[(i+1)*width+j] >= lcs[i*width+j+1]):
+			lines = append(lines, models.DiffLine{Op: "remove", Text: oldMiddle[i], OldLine: lineNumber(prefix + i)})
+			i++
+		default:
+			lines = append(lines, models.DiffLine{Op: "add", Text: newMiddle[j], NewLine: lineNumber(prefix + j)})
+			j++
+		}
+	}
+	for k := 0; k < suffix; k++ {
+		oldIndex, newIndex := len(oldLines)-suffix+k, len(newLines)-suffix+k
+		lines = append(lines, models.DiffLine{Op: "equal", Text: oldLines[oldIndex], OldLine: lineNumber(oldIndex), NewLine: lineNumber(newIndex)})
+	}
+	return lines, nil
+}
+
+func lineNumber(index int) *int {
+	n := index + 1
+	return &n
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevisionService_CreateRecipeRecordsRevision(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	recipeService := newTestRecipeService(db, q)
+	service := NewRevisionService(repository.NewRecipeRevisionRepository(db, q), recipeService)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	created, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Grandma's Bread",
+		MarkdownContent: "## Ingredients\n\n- 3 cups flour",
+		Servings:        int32Ptr(8),
+	}, authorID)
+	require.NoError(t, err)
+
+	revisions, err := service.ListRevisions(created.ID.String())
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, 1, revisions[0].RevisionNumber)
+	assert.Equal(t, "Grandma's Bread", revisions[0].Title)
+	assert.Equal(t, authorID, revisions[0].AuthorID.String())
+	assert.Equal(t, int32(8), *revisions[0].Servings)
+	assert.Nil(t, revisions[0].RestoredFrom)
+}
+
+func TestRevisionService_ListAndDiff(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	revisionRepo := repository.NewRecipeRevisionRepository(db, q)
+	recipeService := newTestRecipeService(db, q)
+	service := NewRevisionService(revisionRepo, recipeService)
+
+	authorID := createTestUser(db, q, "test@example.com")
+
+	created, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Bread",
+		MarkdownContent: "## Ingredients\n\n- 3 cups flour\n- 1 tsp salt",
+	}, authorID)
+	require.NoError(t, err)
+
+	_, err = revisionRepo.Create(&models.RecipeRevision{
+		RecipeID:        created.ID,
+		AuthorID:        created.AuthorID,
+		Title:           "Better Bread",
+		MarkdownContent: "## Ingredients\n\n- 4 cups flour\n- 1 tsp salt",
+		Servings:        int32Ptr(2),
+	})
+	require.NoError(t, err)
+
+	revisions, err := service.ListRevisions(created.ID.String())
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].RevisionNumber)
+	assert.Equal(t, 1, revisions[1].RevisionNumber)
+
+	revision, err := service.GetRevision(created.ID.String(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Better Bread", revision.Title)
+
+	diff, err := service.DiffRevisions(created.ID.String(), 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"title", "markdown_content", "servings"}, diff.ChangedFields)
+
+	var ops []string
+	for _, line := range diff.Lines {
+		ops = append(ops, line.Op+" "+line.Text)
+	}
+	assert.Equal(t, []string{
+		"equal ## Ingredients",
+		"equal ",
+		"remove - 3 cups flour",
+		"add - 4 cups flour",
+		"equal - 1 tsp salt",
+	}, ops)
+
+	_, err = service.DiffRevisions(created.ID.String(), 1, 5)
+	assert.Error(t, err)
+}
+
+func TestRevisionService_RestoreRevision(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	revisionRepo := repository.NewRecipeRevisionRepository(db, q)
+	recipeService := newTestRecipeService(db, q)
+	service := NewRevisionService(revisionRepo, recipeService)
+
+	authorID := createTestUser(db, q, "test@example.com")
+	otherID := createTestUser(db, q, "other@example.com")
+
+	description := "A crusty loaf."
+	created, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Bread",
+		MarkdownContent: "## Ingredients\n\n- 3 cups flour",
+		Description:     &description,
+		Servings:        int32Ptr(8),
+	}, authorID)
+	require.NoError(t, err)
+	recipeID := created.ID.String()
+
+	// The second revision had no description or servings yet.
+	_, err = revisionRepo.Create(&models.RecipeRevision{
+		RecipeID:        created.ID,
+		AuthorID:        created.AuthorID,
+		Title:           "Flatbread",
+		MarkdownContent: "## Ingredients\n\n- 2 cups flour",
+	})
+	require.NoError(t, err)
+
+	restored, err := service.RestoreRevision(recipeID, 2, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, "Flatbread", restored.Title)
+	assert.Equal(t, "flatbread", restored.Slug)
+	assert.Nil(t, restored.Description, "fields that were empty are cleared")
+	assert.Nil(t, restored.Servings)
+
+	revisions, err := service.ListRevisions(recipeID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	require.NotNil(t, revisions[0].RestoredFrom)
+	assert.Equal(t, 2, *revisions[0].RestoredFrom)
+	assert.Nil(t, revisions[0].Description)
+
+	restored, err = service.RestoreRevision(recipeID, 1, authorID)
+	require.NoError(t, err)
+	assert.Equal(t, "Bread", restored.Title)
+	require.NotNil(t, restored.Description)
+	assert.Equal(t, description, *restored.Description)
+	assert.Equal(t, int32(8), *restored.Servings)
+
+	_, err = service.RestoreRevision(recipeID, 1, otherID)
+	assert.EqualError(t, err, "unauthorized: you can only edit your own recipes")
+	_, err = service.RestoreRevision("not-a-uuid", 1, authorID)
+	assert.EqualError(t, err, "recipe not found")
+	_, err = service.ListRevisions("not-a-uuid")
+	assert.EqualError(t, err, "recipe not found")
+}
+
+func TestDiffLines(t *testing.T) {
+	lines, err := diffLines("a\nb\nc", "a\nc\nd")
+	require.NoError(t, err)
+	require.Len(t, lines, 4)
+
+	assert.Equal(t, "equal", lines[0].Op)
+	assert.Equal(t, 1, *lines[0].OldLine)
+	assert.Equal(t, 1, *lines[0].NewLine)
+
+	assert.Equal(t, "remove", lines[1].Op)
+	assert.Equal(t, "b", lines[1].Text)
+	assert.Equal(t, 2, *lines[1].OldLine)
+	assert.Nil(t, lines[1].NewLine)
+
+	assert.Equal(t, "equal", lines[2].Op)
+	assert.Equal(t, 3, *lines[2].OldLine)
+	assert.Equal(t, 2, *lines[2].NewLine)
+
+	assert.Equal(t, "add", lines[3].Op)
+	assert.Equal(t, "d", lines[3].Text)
+	assert.Nil(t, lines[3].OldLine)
+	assert.Equal(t, 3, *lines[3].NewLine)
+}
+
+func TestDiffLines_CommonEnds(t *testing.T) {
+	lines, err := diffLines("a\nb\nc\nd", "a\nx\nc\nd")
+	require.NoError(t, err)
+	ops := make([]string, len(lines))
+	for i, line := range lines {
+		ops[i] = line.Op + " " + line.Text
+	}
+	assert.Equal(t, []string{"equal a", "remove b", "add x", "equal c", "equal d"}, ops)
+	assert.Equal(t, 4, *lines[4].OldLine)
+	assert.Equal(t, 4, *lines[4].NewLine)
+
+	var oldText, newText strings.Builder
+	for i := 0; i < 3000; i++ {
+		fmt.Fprintf(&oldText, "old %d\n", i)
+		fmt.Fprintf(&newText, "new %d\n", i)
+	}
+	_, err = diffLines(oldText.String(), newText.String())
+	assert.EqualError(t, err, "revisions are too large to compare")
+
+	large := strings.Repeat("same line\n", 20000)
+	lines, err = diffLines(large+"old", large+"new")
+	require.NoError(t, err, "long revisions with a small change can still be compared")
+	assert.Len(t, lines, 20002)
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+// cookingFractions are the fractions a home cook can actually measure. Scaled
+// quantities are snapped to the nearest one when close enough.
+var cookingFractions = []struct {
+	value float64
+	text  string
+}{
+	{1.0 / 8, "1/8"},
+	{1.0 / 4, "1/4"},
+	{1.0 / 3, "1/3"},
+	{3.0 / 8, "3/8"},
+	{1.0 / 2, "1/2"},
+	{5.0 / 8, "5/8"},
+	{2.0 / 3, "2/3"},
+	{3.0 / 4, "3/4"},
+	{7.0 / 8, "7/8"},
+}
+
+const fractionTolerance = 0.03
+
+// ScaleContent rewrites every ingredient quantity in the markdown's
+// "## Ingredients" section by factor and returns the new markdown together
+// with the structured ingredients at the scaled amounts.
+func ScaleContent(markdown string, factor float64) (string, []models.RecipeIngredient) {
+	lines := strings.Split(markdown, "\n")
+	start, end := ingredientSection(lines)
+
+	var ingredients []models.RecipeIngredient
+	for i := start; i < end; i++ {
+		if headingPattern.MatchString(strings.TrimSpace(lines[i])) {
+			continue
+		}
+		ingredient, ok := parseIngredientLine(lines[i])
+		if !ok {
+			continue
+		}
+
+		if ingredient.Quantity != nil {
+			lines[i] = scaleIngredientLine(lines[i], factor)
+			ingredient.RawText = strings.TrimSpace(listMarkerPattern.ReplaceAllString(strings.TrimSpace(lines[i]), ""))
+			ingredient.Quantity = scaleQuantity(ingredient.Quantity, factor)
+			ingredient.QuantityMax = scaleQuantity(ingredient.QuantityMax, factor)
+		}
+		ingredient.OrderIndex = len(ingredients)
+		ingredients = append(ingredients, ingredient)
+	}
+
+	return strings.Join(lines, "\n"), ingredients
+}
+
+// scaleRecipeContent scales markdown written for fromServings so it serves
+// toServings.
+func scaleRecipeContent(markdown string, fromServings *int32, toServings int) (*models.ScaledRecipe, error) {
+	if toServings <= 0 {
+		return nil, errors.New("servings must be a positive number")
+	}
+	if fromServings == nil || *fromServings <= 0 {
+		return nil, errors.New("recipe has no servings to scale from")
+	}
+
+	factor := float64(toServings) / float64(*fromServings)
+	content, ingredients := ScaleContent(markdown, factor)
+
+	return &models.ScaledRecipe{
+		OriginalServings: int(*fromServings),
+		Servings:         toServings,
+		Factor:           factor,
+		MarkdownContent:  content,
+		Ingredients:      ingredients,
+	}, nil
+}
+
+// scaleIngredientLine replaces the leading quantity of a single ingredient
+// line, keeping indentation, list marker and the rest of the text intact.
+func scaleIngredientLine(line string, factor float64) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(trimmed)]
+
+	marker := listMarkerPattern.FindString(trimmed)
+	text := trimmed[len(marker):]
+
+	quantity, quantityMax, rest := splitQuantity(text)
+	if quantity == nil {
+		return line
+	}
+
+	scaled := FormatQuantity(*quantity * factor)
+	if quantityMax != nil {
+		scaled += "-" + FormatQuantity(*quantityMax*factor)
+	}
+	return indent + marker + scaled + rest
+}
+
+func scaleQuantity(quantity *float64, factor float64) *float64 {
+	if quantity == nil {
+		return nil
+	}
+	scaled := *quantity * factor
+	return &scaled
+}
+
+// FormatQuantity renders a quantity the way a recipe would print it: whole
+// numbers plus a measurable fraction ("1 1/2") where possible, otherwise a
+// short decimal.
+func FormatQuantity(value float64) string {
+	if value <= 0 {
+		return "0"
+	}
+
+	whole := math.Floor(value)
+	remainder := value - whole
+
+	if remainder < fractionTolerance {
+		if whole == 0 {
+			// Too little for any fraction; keep two significant digits
+			// rather than rounding a pinch down to nothing.
+			precision := 1 - int(math.Floor(math.Log10(value)))
+			return strconv.FormatFloat(roundTo(value, precision), 'f', -1, 64)
+		}
+		return strconv.FormatFloat(whole, 'f', -1, 64)
+	}
+	if 1-remainder < fractionTolerance {
+		return strconv.FormatFloat(whole+1, 'f', -1, 64)
+	}
+
+	// Fractions only make sense for small, hand-measured amounts.
+	if value < 10 {
+		for _, fraction := range cookingFractions {
+			if math.Abs(remainder-fraction.value) < fractionTolerance {
+				if whole == 0 {
+					return fraction.text
+				}
+				return fmt.Sprintf("%.0f %s", whole, fraction.text)
+			}
+		}
+	}
+
+	precision := 2
+	if value >= 10 {
+		precision = 1
+	}
+	return strconv.FormatFloat(roundTo(value, precision), 'f', -1, 64)
+}
+
+func roundTo(value float64, precision int) float64 {
+	pow := math.Pow(10, float64(precision))
+	return math.Round(value*pow) / pow
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatQuantity(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{2, "2"},
+		{0.5, "1/2"},
+		{1.5, "1 1/2"},
+		{0.333, "1/3"},
+		{2.6667, "2 2/3"},
+		{0.125, "1/8"},
+		{1.99, "2"},
+		{0.2, "0.2"},
+		{12.5, "12.5"},
+		{13.333, "13.3"},
+		{0, "0"},
+		{0.02, "0.02"},
+		{0.004, "0.004"},
+		{0.0123, "0.012"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatQuantity(tt.value))
+		})
+	}
+}
+
+func TestScaleContent(t *testing.T) {
+	markdown := `# Cookies
+
+Makes 2 dozen.
+
+## Ingredients
+
+- 1 1/2 cups sugar
+- 2-3 cloves garlic, minced
+  * ½ tsp salt
+- Pepper, to taste
+
+## Instructions
+
+1. Bake for 12 minutes.`
+
+	content, ingredients := ScaleContent(markdown, 2)
+
+	assert.Contains(t, content, "Makes 2 dozen.")
+	assert.Contains(t, content, "- 3 cups sugar")
+	assert.Contains(t, content, "- 4-6 cloves garlic, minced")
+	assert.Contains(t, content, "  * 1 tsp salt")
+	assert.Contains(t, content, "- Pepper, to taste")
+	assert.Contains(t, content, "1. Bake for 12 minutes.")
+
+	require.Len(t, ingredients, 4)
+	assert.Equal(t, 3.0, *ingredients[0].Quantity)
+	assert.Equal(t, "3 cups sugar", ingredients[0].RawText)
+	assert.Equal(t, 4.0, *ingredients[1].Quantity)
+	assert.Equal(t, 6.0, *ingredients[1].QuantityMax)
+	assert.Equal(t, 1.0, *ingredients[2].Quantity)
+	assert.Nil(t, ingredients[3].Quantity)
+	assert.Equal(t, 3, ingredients[3].OrderIndex)
+}
+
+func TestScaleContent_NoSection(t *testing.T) {
+	markdown := "Mix 2 cups of flour with 3 eggs."
+	content, ingredients := ScaleContent(markdown, 3)
+	assert.Equal(t, markdown, content)
+	assert.Empty(t, ingredients)
+}
@@ -95,10 +95,32 @@ func (s *ShareCodeService) UseShareCode(code string) error {
 	return s.shareCodeRepo.IncrementUse(shareCode.ID.String())
 }
 
+// AccessRecipe counts a use of the share code and returns the recipe it
+// shares.
+func (s *ShareCodeService) AccessRecipe(code string) (*models.Recipe, error) {
+	shareCode, err := s.GetShareCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.shareCodeRepo.IncrementUse(shareCode.ID.String()); err != nil {
+		return nil, err
+	}
+
+	recipe, err := s.recipeRepo.GetByID(shareCode.RecipeID.String())
+	if err != nil {
+		return nil, errors.New("recipe not found")
+	}
+	return recipe, nil
+}
+
 func (s *ShareCodeService) GetShareCodesForRecipe(recipeID string) ([]*models.ShareCode, error) {
 	if recipeID == "" {
 		return nil, errors.New("recipe_id is required")
 	}
+	if _, err := findRecipe(s.recipeRepo, recipeID); err != nil {
+		return nil, err
+	}
 
 	return s.shareCodeRepo.GetForRecipe(recipeID)
 }
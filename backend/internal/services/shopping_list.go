@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/models"
+)
+
+// ShoppingList adds up the ingredients of the requested recipes, each scaled
+// to its servings and with linked sub-recipes inlined, in the given unit
+// system. Ingredients with the same name and unit are combined; ranges are
+// shopped for at their upper end.
+func (s *RecipeService) ShoppingList(req *models.ShoppingListRequest, units UnitSystem) (*models.ShoppingList, error) {
+	if len(req.Recipes) == 0 {
+		return nil, errors.New("at least one recipe is required")
+	}
+	if len(req.Recipes) > models.MaxShoppingListRecipes {
+		return nil, fmt.Errorf("a shopping list covers at most %d recipes", models.MaxShoppingListRecipes)
+	}
+
+	list := &models.ShoppingList{Items: []models.ShoppingListItem{}}
+	items := map[string]int{}
+	for _, entry := range req.Recipes {
+		if parseUUID(&entry.RecipeID) == nil {
+			return nil, errors.New("invalid recipe ID")
+		}
+		recipe, err := s.recipeRepo.GetByID(entry.RecipeID)
+		if err != nil {
+			return nil, err
+		}
+
+		factor := 1.0
+		if entry.Servings != nil {
+			if *entry.Servings <= 0 {
+				return nil, errors.New("servings must be a positive number")
+			}
+			if recipe.Servings == nil || *recipe.Servings <= 0 {
+				return nil, errors.New("recipe has no servings to scale from")
+			}
+			factor = float64(*entry.Servings) / float64(*recipe.Servings)
+		}
+
+		_, ingredients := ScaleContent(recipe.MarkdownContent, factor)
+		ingredients, err = inlineSubRecipes(s.recipeRepo, recipe.ID, recipe.MarkdownContent, ingredients, factor)
+		if err != nil {
+			return nil, err
+		}
+		for _, ingredient := range ConvertIngredients(ingredients, units) {
+			addShoppingListItem(list, items, ingredient, recipe.ID)
+		}
+	}
+	return list, nil
+}
+
+// addShoppingListItem adds ingredient to the list item for its name and
+// unit, which items indexes.
+func addShoppingListItem(list *models.ShoppingList, items map[string]int, ingredient models.RecipeIngredient, recipeID uuid.UUID) {
+	name := strings.TrimSpace(ingredient.Name)
+	if name == "" {
+		name = ingredient.RawText
+	}
+	unit := ""
+	if ingredient.Unit != nil {
+		unit = *ingredient.Unit
+	}
+	quantity := ingredient.Quantity
+	if ingredient.QuantityMax != nil {
+		quantity = ingredient.QuantityMax
+	}
+
+	key := fmt.Sprintf("%s\x00%s\x00%t", strings.ToLower(name), unit, quantity != nil)
+	i, ok := items[key]
+	if !ok {
+		i = len(list.Items)
+		items[key] = i
+		list.Items = append(list.Items, models.ShoppingListItem{Name: name, Unit: ingredient.Unit})
+	}
+
+	item := &list.Items[i]
+	if quantity != nil {
+		total := *quantity
+		if item.Quantity != nil {
+			total += *item.Quantity
+		}
+		item.Quantity = &total
+	}
+	if !slices.Contains(item.RecipeIDs, recipeID) {
+		item.RecipeIDs = append(item.RecipeIDs, recipeID)
+	}
+}
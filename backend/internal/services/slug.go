@@ -0,0 +1,135 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// slugWords spells out symbols that read as words in a title.
+var slugWords = map[rune]string{
+	'&': "and",
+	'@': "at",
+	'+': "plus",
+}
+
+// latinLetters maps accented and special Latin letters to the ASCII they
+// are transliterated to.
+var latinLetters = map[string]string{
+	"a":  "àáâãäåāăą",
+	"ae": "æ",
+	"c":  "çćĉċč",
+	"d":  "ďđð",
+	"e":  "èéêëēĕėęě",
+	"g":  "ĝğġģ",
+	"h":  "ĥħ",
+	"i":  "ìíîïĩīĭįı",
+	"j":  "ĵ",
+	"k":  "ķ",
+	"l":  "ĺļľŀł",
+	"n":  "ñńņňŉ",
+	"o":  "òóôõöōŏőø",
+	"oe": "œ",
+	"r":  "ŕŗř",
+	"s":  "śŝşšș",
+	"ss": "ß",
+	"t":  "ţťŧț",
+	"th": "þ",
+	"u":  "ùúûüũūŭůűų",
+	"w":  "ŵ",
+	"y":  "ýÿŷ",
+	"z":  "źżž",
+}
+
+var transliterations = func() map[rune]string {
+	table := make(map[rune]string)
+	for ascii, letters := range latinLetters {
+		for _, r := range letters {
+			table[r] = ascii
+		}
+	}
+	return table
+}()
+
+// generateSlug turns a title into a URL slug: lowercase ASCII letters and
+// digits separated by single hyphens. Accented letters are transliterated,
+// apostrophes and quotes are dropped, and everything else separates words.
+func generateSlug(title string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	write := func(s string) {
+		if pendingHyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingHyphen = false
+		b.WriteString(s)
+	}
+
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			write(string(r))
+		case r == '\'' || r == '"' || r == '’' || r == '‘' || r == '“' || r == '”':
+			// Dropped so that "Chef's" becomes "chefs", not "chef-s".
+		case slugWords[r] != "":
+			pendingHyphen = true
+			write(slugWords[r])
+			pendingHyphen = true
+		case transliterations[r] != "":
+			write(transliterations[r])
+		default:
+			pendingHyphen = true
+		}
+	}
+	return b.String()
+}
+
+// slugSource picks what a slug is generated from: an explicitly requested
+// slug, normalized the same way, or otherwise the name.
+func slugSource(slug, name string) string {
+	if slug != "" {
+		return slug
+	}
+	return name
+}
+
+// uniqueSlug generates a slug for title and appends -2, -3, ... until taken
+// reports it free. Titles without any usable characters get prefix plus a
+// short random suffix instead.
+func uniqueSlug(title, prefix string, taken func(slug string) (bool, error)) (string, error) {
+	base := generateSlug(title)
+	if base == "" {
+		base = fmt.Sprintf("%s-%s", prefix, uuid.New().String()[:8])
+	}
+
+	slug := base
+	for n := 2; ; n++ {
+		exists, err := taken(slug)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// slugOwner adapts a get-by-slug lookup into a uniqueSlug check. A slug
+// belonging to selfID counts as free so that updates keep their own slug.
+func slugOwner(selfID string, lookup func(slug string) (uuid.UUID, error)) func(string) (bool, error) {
+	return func(slug string) (bool, error) {
+		id, err := lookup(slug)
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return id.String() != selfID, nil
+	}
+}
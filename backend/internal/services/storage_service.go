@@ -1,12 +1,14 @@
 package services
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
 	"image"
 	"image/jpeg"
 	"image/png"
+	"io"
 	"mime/multipart"
 	"os"
 	"path/filepath"
@@ -41,6 +43,30 @@ func (s *StorageService) SaveImage(file *multipart.FileHeader, prefix string) (s
 		return "", fmt.Errorf("invalid file type: %s", ext)
 	}
 
+	return s.saveImage(src, ext, prefix)
+}
+
+// SaveImageFrom stores an image read from src, such as a download, the same
+// way SaveImage stores an upload. ext is the extension the file would have
+// had, including the dot.
+func (s *StorageService) SaveImageFrom(src io.Reader, ext string, prefix string) (string, error) {
+	ext = strings.ToLower(ext)
+	if !s.isValidImageExtension(ext) {
+		return "", fmt.Errorf("invalid file type: %s", ext)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(src, s.maxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return "", fmt.Errorf("file size exceeds maximum allowed size")
+	}
+
+	return s.saveImage(bytes.NewReader(data), ext, prefix)
+}
+
+func (s *StorageService) saveImage(src io.Reader, ext string, prefix string) (string, error) {
 	filename := s.generateFilename(prefix, ext)
 	dstPath := filepath.Join(s.localPath, filename)
 
@@ -110,6 +136,116 @@ func (s *StorageService) DeleteImage(filename string) error {
 	return nil
 }
 
+// OpenImage opens a stored image by the path it is served under, such as
+// "/uploads/recipe_ab12.jpg". Paths outside the upload directory are
+// rejected.
+func (s *StorageService) OpenImage(imagePath string) (*os.File, error) {
+	_, filename, ok := strings.Cut(imagePath, "/uploads/")
+	if !ok || filename == "" || filename != filepath.Base(filename) || filename == ".." {
+		return nil, fmt.Errorf("invalid image path: %s", imagePath)
+	}
+	return os.Open(filepath.Join(s.localPath, filename))
+}
+
+// CreateTempDir creates a directory inside the upload directory for
+// RestoreFile to stage files in, so they can be moved into place without
+// copying. The caller removes it when done.
+func (s *StorageService) CreateTempDir() (string, error) {
+	dir, err := os.MkdirTemp(s.localPath, ".restore-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	return dir, nil
+}
+
+// RestoreFile stores a file taken from a backup under its original name,
+// copying it from src into tempDir first. Only images are accepted, as with
+// SaveImage. When a file of that name already exists it is kept if it has
+// the same content, and the new file goes under a fresh name otherwise. It
+// returns the name the file ended up under and whether a new file was
+// written.
+func (s *StorageService) RestoreFile(tempDir, filename string, src io.Reader) (string, bool, error) {
+	if filename == "" || filename != filepath.Base(filename) || filename == ".." || strings.ContainsAny(filename, `/\`) {
+		return "", false, fmt.Errorf("invalid file name: %s", filename)
+	}
+	ext := filepath.Ext(filename)
+	if !s.isValidImageExtension(strings.ToLower(ext)) {
+		return "", false, fmt.Errorf("invalid file type: %s", ext)
+	}
+
+	temp, err := os.CreateTemp(tempDir, "upload-*")
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create file: %w", err)
+	}
+	tempPath := temp.Name()
+	defer os.Remove(tempPath)
+	_, err = io.Copy(temp, src)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	same, err := sameContent(filepath.Join(s.localPath, filename), tempPath)
+	switch {
+	case err == nil && same:
+		return filename, false, nil
+	case err == nil:
+		prefix := strings.TrimSuffix(filename, ext)
+		if base, _, ok := strings.Cut(prefix, "_"); ok {
+			prefix = base
+		}
+		filename = s.generateFilename(prefix, ext)
+	case !os.IsNotExist(err):
+		return "", false, fmt.Errorf("failed to read existing file: %w", err)
+	}
+
+	if err := os.Chmod(tempPath, 0644); err != nil {
+		return "", false, fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tempPath, filepath.Join(s.localPath, filename)); err != nil {
+		return "", false, fmt.Errorf("failed to write file: %w", err)
+	}
+	return filename, true, nil
+}
+
+// sameContent reports whether the files at a and b hold the same bytes,
+// reading them in chunks.
+func sameContent(a, b string) (bool, error) {
+	fa, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+
+	bufA := make([]byte, 64<<10)
+	bufB := make([]byte, 64<<10)
+	for {
+		na, errA := io.ReadFull(fa, bufA)
+		nb, errB := io.ReadFull(fb, bufB)
+		if !bytes.Equal(bufA[:na], bufB[:nb]) {
+			return false, nil
+		}
+		endA := errA == io.EOF || errA == io.ErrUnexpectedEOF
+		endB := errB == io.EOF || errB == io.ErrUnexpectedEOF
+		if errA != nil && !endA {
+			return false, errA
+		}
+		if errB != nil && !endB {
+			return false, errB
+		}
+		if endA || endB {
+			return endA && endB, nil
+		}
+	}
+}
+
 func (s *StorageService) isValidImageExtension(ext string) bool {
 	validExts := map[string]bool{
 		".jpg":  true,
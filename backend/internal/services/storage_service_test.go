@@ -1,9 +1,12 @@
 package services
 
 import (
+	"bytes"
 	"image"
+	"image/png"
 	"mime/multipart"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -159,3 +162,40 @@ func TestResizeImage_Ratio(t *testing.T) {
 	ratio := float64(bounds.Dx()) / float64(bounds.Dy())
 	assert.InDelta(t, 2.0, ratio, 0.1)
 }
+
+func TestSaveImageFrom(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStorageService(dir, 10*1024*1024)
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 10, 10))))
+
+	filename, err := s.SaveImageFrom(&buf, ".PNG", "recipe")
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(filename, ".png"))
+	_, err = os.Stat(filepath.Join(dir, filename))
+	assert.NoError(t, err)
+
+	_, err = s.SaveImageFrom(strings.NewReader("%PDF"), ".pdf", "recipe")
+	assert.Error(t, err)
+
+	small := NewStorageService(dir, 16)
+	_, err = small.SaveImageFrom(strings.NewReader(strings.Repeat("x", 32)), ".jpg", "recipe")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds maximum allowed size")
+}
+
+func TestOpenImage(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStorageService(dir, 10*1024*1024)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "recipe_1.jpg"), []byte("data"), 0644))
+
+	file, err := s.OpenImage("/uploads/recipe_1.jpg")
+	require.NoError(t, err)
+	file.Close()
+
+	for _, path := range []string{"/uploads/../secret.txt", "/uploads/", "/etc/passwd", "recipe_1.jpg"} {
+		_, err := s.OpenImage(path)
+		assert.Error(t, err, path)
+	}
+}
@@ -1,20 +1,26 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/homecooking/backend/internal/models"
 	"github.com/homecooking/backend/internal/repository"
 )
 
+const defaultTagColor = "#6366f1"
+
 type TagService struct {
-	tagRepo *repository.TagRepository
+	tagRepo    *repository.TagRepository
+	recipeRepo *repository.RecipeRepository
 }
 
-func NewTagService(tagRepo *repository.TagRepository) *TagService {
+func NewTagService(tagRepo *repository.TagRepository, recipeRepo *repository.RecipeRepository) *TagService {
 	return &TagService{
-		tagRepo: tagRepo,
+		tagRepo:    tagRepo,
+		recipeRepo: recipeRepo,
 	}
 }
 
@@ -22,11 +28,13 @@ func (s *TagService) CreateTag(tag *models.Tag) (*models.Tag, error) {
 	if tag.Name == "" {
 		return nil, errors.New("name is required")
 	}
-	if tag.Slug == "" {
-		tag.Slug = generateTagSlug(tag.Name)
+	slug, err := uniqueSlug(slugSource(tag.Slug, tag.Name), "tag", slugOwner("", tagIDBySlug(s.tagRepo)))
+	if err != nil {
+		return nil, err
 	}
+	tag.Slug = slug
 	if tag.Color == "" {
-		tag.Color = "#6366f1"
+		tag.Color = defaultTagColor
 	}
 
 	return s.tagRepo.Create(tag)
@@ -40,44 +48,198 @@ func (s *TagService) GetBySlug(slug string) (*models.Tag, error) {
 	return s.tagRepo.GetBySlug(slug)
 }
 
-func (s *TagService) ListTags() ([]*models.Tag, error) {
-	return s.tagRepo.List()
+func (s *TagService) ListTags(limit int, cursor string) (*models.Page[*models.Tag], error) {
+	return s.tagRepo.List(pageSize(limit), cursor)
 }
 
+// UpdateTag renames the tag and updates the search entries of the recipes
+// that carry it.
 func (s *TagService) UpdateTag(id string, tag *models.Tag) (*models.Tag, error) {
 	if tag.Name == "" {
 		return nil, errors.New("name is required")
 	}
-	if tag.Slug == "" && tag.Name != "" {
-		tag.Slug = generateTagSlug(tag.Name)
+	slug, err := uniqueSlug(slugSource(tag.Slug, tag.Name), "tag", slugOwner(id, tagIDBySlug(s.tagRepo)))
+	if err != nil {
+		return nil, err
 	}
+	tag.Slug = slug
 	if tag.Color == "" {
-		tag.Color = "#6366f1"
+		tag.Color = defaultTagColor
 	}
 
-	return s.tagRepo.Update(id, tag)
+	updated, err := s.tagRepo.Update(id, tag)
+	if err != nil {
+		return nil, err
+	}
+	recipeIDs, err := s.recipeRepo.IDsWithTag(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := reindexRecipes(s.recipeRepo, recipeIDs); err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
+// DeleteTag deletes the tag and takes it out of the search entries of the
+// recipes that carried it.
 func (s *TagService) DeleteTag(id string) error {
-	return s.tagRepo.Delete(id)
+	recipeIDs, err := s.recipeRepo.IDsWithTag(id)
+	if err != nil {
+		return err
+	}
+	if err := s.tagRepo.Delete(id); err != nil {
+		return err
+	}
+	return reindexRecipes(s.recipeRepo, recipeIDs)
 }
 
 func (s *TagService) GetRecipeTags(recipeID string) ([]*models.Tag, error) {
+	if _, err := findRecipe(s.recipeRepo, recipeID); err != nil {
+		return nil, err
+	}
 	return s.tagRepo.GetRecipeTags(recipeID)
 }
 
-func (s *TagService) AddTagToRecipe(recipeID string, tagID string) error {
-	return s.tagRepo.AddToRecipe(recipeID, tagID)
+func (s *TagService) AddTagToRecipe(recipeID string, tagID string, userID string) error {
+	if err := s.checkRecipeAuthor(recipeID, userID); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(tagID); err != nil {
+		return errors.New("tag not found")
+	}
+	if err := s.tagRepo.AddToRecipe(recipeID, tagID); err != nil {
+		return err
+	}
+	return s.recipeRepo.IndexForSearch(recipeID)
 }
 
-func (s *TagService) RemoveTagFromRecipe(recipeID string, tagID string) error {
-	return s.tagRepo.RemoveFromRecipe(recipeID, tagID)
+func (s *TagService) RemoveTagFromRecipe(recipeID string, tagID string, userID string) error {
+	if err := s.checkRecipeAuthor(recipeID, userID); err != nil {
+		return err
+	}
+	if _, err := uuid.Parse(tagID); err != nil {
+		return errors.New("tag not found")
+	}
+	if err := s.tagRepo.RemoveFromRecipe(recipeID, tagID); err != nil {
+		return err
+	}
+	return s.recipeRepo.IndexForSearch(recipeID)
 }
 
-func generateTagSlug(title string) string {
-	slug := strings.ToLower(title)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	slug = strings.ReplaceAll(slug, "'", "")
-	slug = strings.ReplaceAll(slug, "\"", "")
-	return slug
+// SetRecipeTags replaces the recipe's tags with the named ones, creating any
+// tag that doesn't exist yet.
+func (s *TagService) SetRecipeTags(recipeID string, names []string, userID string) ([]*models.Tag, error) {
+	if err := s.checkRecipeAuthor(recipeID, userID); err != nil {
+		return nil, err
+	}
+
+	tags, err := resolveTagNames(s.tagRepo, names)
+	if err != nil {
+		return nil, err
+	}
+
+	saved, err := s.tagRepo.ReplaceForRecipe(recipeID, tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recipeRepo.IndexForSearch(recipeID); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// checkRecipeAuthor makes sure the recipe exists and userID wrote it, since
+// only the author may change a recipe's tags.
+func (s *TagService) checkRecipeAuthor(recipeID string, userID string) error {
+	if _, err := uuid.Parse(recipeID); err != nil {
+		return errors.New("recipe not found")
+	}
+	recipe, err := s.recipeRepo.GetByID(recipeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("recipe not found")
+	}
+	if err != nil {
+		return err
+	}
+	if recipe.AuthorID == nil || recipe.AuthorID.String() != userID {
+		return errors.New("unauthorized: you can only edit your own recipes")
+	}
+	return nil
+}
+
+// resolveTagNames matches each name to the existing tag with the same slug,
+// or failing that the same name, and prepares an unsaved tag for names that
+// match neither. Names resolving to the same tag are kept once.
+func resolveTagNames(tagRepo *repository.TagRepository, names []string) ([]*models.Tag, error) {
+	tags := []*models.Tag{}
+	seen := map[string]bool{}
+	seenIDs := map[uuid.UUID]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, errors.New("tag name is required")
+		}
+
+		// Names made only of symbols have no slug and can only match by
+		// name.
+		slug := generateSlug(name)
+		key := slug
+		if key == "" {
+			key = name
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		existing, err := findTag(tagRepo, slug, name)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			if !seenIDs[existing.ID] {
+				seenIDs[existing.ID] = true
+				tags = append(tags, existing)
+			}
+			continue
+		}
+
+		newSlug, err := uniqueSlug(name, "tag", slugOwner("", tagIDBySlug(tagRepo)))
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, &models.Tag{
+			Name:  name,
+			Slug:  newSlug,
+			Color: defaultTagColor,
+		})
+	}
+	return tags, nil
+}
+
+// findTag returns the tag with the slug, or else the one with the name, and
+// nil when there is neither.
+func findTag(tagRepo *repository.TagRepository, slug, name string) (*models.Tag, error) {
+	if slug != "" {
+		tag, err := tagRepo.GetBySlug(slug)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return tag, err
+		}
+	}
+	tag, err := tagRepo.GetByName(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return tag, err
+}
+
+func tagIDBySlug(tagRepo *repository.TagRepository) func(string) (uuid.UUID, error) {
+	return func(slug string) (uuid.UUID, error) {
+		tag, err := tagRepo.GetBySlug(slug)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return tag.ID, nil
+	}
 }
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"database/sql"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/homecooking/backend/internal/models"
 	"github.com/homecooking/backend/internal/repository"
 	testutil "github.com/homecooking/backend/internal/testing"
@@ -17,7 +19,7 @@ func TestCreateTag(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	tagRepo := repository.NewTagRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
 	tag := &models.Tag{
 		Name:  "Vegetarian",
@@ -39,7 +41,7 @@ func TestCreateTag_EmptyName(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	tagRepo := repository.NewTagRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
 	tag := &models.Tag{
 		Name: "",
@@ -50,13 +52,34 @@ func TestCreateTag_EmptyName(t *testing.T) {
 	assert.Contains(t, err.Error(), "name is required")
 }
 
+func TestCreateTag_SlugCollision(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	tagRepo := repository.NewTagRepository(db, q)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
+
+	first, err := service.CreateTag(&models.Tag{Name: "Gluten-Free"})
+	require.NoError(t, err)
+	assert.Equal(t, "gluten-free", first.Slug)
+
+	second, err := service.CreateTag(&models.Tag{Name: "Gluten Free!"})
+	require.NoError(t, err)
+	assert.Equal(t, "gluten-free-2", second.Slug)
+
+	third, err := service.CreateTag(&models.Tag{Name: "GF", Slug: "Gluten Free"})
+	require.NoError(t, err)
+	assert.Equal(t, "gluten-free-3", third.Slug)
+}
+
 func TestCreateTag_AutoColor(t *testing.T) {
 	db, q, err := testutil.SetupTestDB()
 	require.NoError(t, err)
 	defer testutil.TeardownTestDB(db)
 
 	tagRepo := repository.NewTagRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
 	tag := &models.Tag{
 		Name:  "Quick",
@@ -75,7 +98,7 @@ func TestGetTag(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	tagRepo := repository.NewTagRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
 	tag := &models.Tag{
 		Name:  "Vegetarian",
@@ -97,7 +120,7 @@ func TestGetTag_NotFound(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	tagRepo := repository.NewTagRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
 	_, err = service.GetTag("00000000-0000-0000-0000-000000000000")
 	assert.Error(t, err)
@@ -109,7 +132,7 @@ func TestListTags(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	tagRepo := repository.NewTagRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
 	tag1 := &models.Tag{
 		Name:  "Vegetarian",
@@ -134,9 +157,48 @@ func TestListTags(t *testing.T) {
 	_, err = tagRepo.Create(tag3)
 	require.NoError(t, err)
 
-	tags, err := service.ListTags()
+	tags, err := service.ListTags(20, "")
 	require.NoError(t, err)
-	assert.Len(t, tags, 3)
+	assert.Len(t, tags.Items, 3)
+	assert.Equal(t, 3, tags.Total)
+}
+
+func TestListTags_Pagination(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	tagRepo := repository.NewTagRepository(db, q)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
+
+	for _, name := range []string{"Spicy", "Baking", "Quick"} {
+		_, err := service.CreateTag(&models.Tag{Name: name})
+		require.NoError(t, err)
+	}
+
+	first, err := service.ListTags(2, "")
+	require.NoError(t, err)
+	assert.Equal(t, 3, first.Total)
+	require.Len(t, first.Items, 2)
+	assert.Equal(t, "Baking", first.Items[0].Name)
+	assert.Equal(t, "Quick", first.Items[1].Name)
+	require.NotNil(t, first.NextCursor)
+
+	second, err := service.ListTags(2, *first.NextCursor)
+	require.NoError(t, err)
+	require.Len(t, second.Items, 1)
+	assert.Equal(t, "Spicy", second.Items[0].Name)
+	assert.Nil(t, second.NextCursor)
+
+	// The cursor still leads to the next page after its tag is deleted.
+	require.NoError(t, service.DeleteTag(first.Items[1].ID.String()))
+	second, err = service.ListTags(2, *first.NextCursor)
+	require.NoError(t, err)
+	require.Len(t, second.Items, 1)
+	assert.Equal(t, "Spicy", second.Items[0].Name)
+
+	_, err = service.ListTags(2, "bogus")
+	assert.EqualError(t, err, "invalid cursor")
 }
 
 func TestUpdateTag(t *testing.T) {
@@ -147,7 +209,7 @@ func TestUpdateTag(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	tagRepo := repository.NewTagRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
 	tag := &models.Tag{
 		Name:  "Vegetarian",
@@ -177,7 +239,7 @@ func TestUpdateTag_EmptyName(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	tagRepo := repository.NewTagRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
 	tag := &models.Tag{
 		Name: "Vegetarian",
@@ -202,7 +264,7 @@ func TestUpdateTag_AutoColor(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	tagRepo := repository.NewTagRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
 	tag := &models.Tag{
 		Name:  "Vegetarian",
@@ -227,7 +289,7 @@ func TestDeleteTag(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	tagRepo := repository.NewTagRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
 	tag := &models.Tag{
 		Name:  "Vegetarian",
@@ -250,7 +312,7 @@ func TestDeleteTag_NotFound(t *testing.T) {
 	defer testutil.TeardownTestDB(db)
 
 	tagRepo := repository.NewTagRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
 	err = service.DeleteTag("00000000-0000-0000-0000-000000000000")
 	assert.NoError(t, err)
@@ -263,9 +325,11 @@ func TestAddTagToRecipe(t *testing.T) {
 
 	tagRepo := repository.NewTagRepository(db, q)
 	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
+	authorID := createTestUser(db, q, "author@example.com")
 	recipe := &models.Recipe{
+		AuthorID:        parseUUID(&authorID),
 		Title:           "Pancakes",
 		Slug:            "pancakes",
 		MarkdownContent: "Ingredients: Flour, Eggs. Instructions: Mix and cook.",
@@ -283,7 +347,7 @@ func TestAddTagToRecipe(t *testing.T) {
 	createdTag, err := tagRepo.Create(tag)
 	require.NoError(t, err)
 
-	err = service.AddTagToRecipe(createdRecipe.ID.String(), createdTag.ID.String())
+	err = service.AddTagToRecipe(createdRecipe.ID.String(), createdTag.ID.String(), authorID)
 	require.NoError(t, err)
 
 	tags, err := service.GetRecipeTags(createdRecipe.ID.String())
@@ -299,9 +363,11 @@ func TestRemoveTagFromRecipe(t *testing.T) {
 
 	tagRepo := repository.NewTagRepository(db, q)
 	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
+	authorID := createTestUser(db, q, "author@example.com")
 	recipe := &models.Recipe{
+		AuthorID:        parseUUID(&authorID),
 		Title:           "Pancakes",
 		Slug:            "pancakes",
 		MarkdownContent: "Ingredients: Flour, Eggs. Instructions: Mix and cook.",
@@ -319,10 +385,10 @@ func TestRemoveTagFromRecipe(t *testing.T) {
 	createdTag, err := tagRepo.Create(tag)
 	require.NoError(t, err)
 
-	err = service.AddTagToRecipe(createdRecipe.ID.String(), createdTag.ID.String())
+	err = service.AddTagToRecipe(createdRecipe.ID.String(), createdTag.ID.String(), authorID)
 	require.NoError(t, err)
 
-	err = service.RemoveTagFromRecipe(createdRecipe.ID.String(), createdTag.ID.String())
+	err = service.RemoveTagFromRecipe(createdRecipe.ID.String(), createdTag.ID.String(), authorID)
 	require.NoError(t, err)
 
 	tags, err := service.GetRecipeTags(createdRecipe.ID.String())
@@ -337,9 +403,11 @@ func TestGetRecipeTags(t *testing.T) {
 
 	tagRepo := repository.NewTagRepository(db, q)
 	recipeRepo := repository.NewRecipeRepository(db, q)
-	service := NewTagService(tagRepo)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
 
+	authorID := createTestUser(db, q, "author@example.com")
 	recipe := &models.Recipe{
+		AuthorID:        parseUUID(&authorID),
 		Title:           "Pancakes",
 		Slug:            "pancakes",
 		MarkdownContent: "Ingredients: Flour, Eggs. Instructions: Mix and cook.",
@@ -365,14 +433,214 @@ func TestGetRecipeTags(t *testing.T) {
 	createdTag2, err := tagRepo.Create(tag2)
 	require.NoError(t, err)
 
-	err = service.AddTagToRecipe(createdRecipe.ID.String(), createdTag1.ID.String())
+	err = service.AddTagToRecipe(createdRecipe.ID.String(), createdTag1.ID.String(), authorID)
 	require.NoError(t, err)
-	err = service.AddTagToRecipe(createdRecipe.ID.String(), createdTag2.ID.String())
+	err = service.AddTagToRecipe(createdRecipe.ID.String(), createdTag2.ID.String(), authorID)
 	require.NoError(t, err)
 
 	tags, err := service.GetRecipeTags(createdRecipe.ID.String())
 	require.NoError(t, err)
 	assert.Len(t, tags, 2)
+
+	_, err = service.GetRecipeTags("not-a-uuid")
+	assert.EqualError(t, err, "recipe not found")
+	_, err = service.GetRecipeTags(uuid.New().String())
+	assert.EqualError(t, err, "recipe not found")
+}
+
+func TestSetRecipeTags(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	tagRepo := repository.NewTagRepository(db, q)
+	recipeRepo := repository.NewRecipeRepository(db, q)
+	service := NewTagService(tagRepo, recipeRepo)
+
+	authorID := createTestUser(db, q, "author@example.com")
+	createdRecipe, err := recipeRepo.Create(&models.Recipe{
+		AuthorID:        parseUUID(&authorID),
+		Title:           "Pancakes",
+		Slug:            "pancakes",
+		MarkdownContent: "Ingredients: Flour, Eggs. Instructions: Mix and cook.",
+	})
+	require.NoError(t, err)
+
+	vegetarian, err := tagRepo.Create(&models.Tag{Name: "Vegetarian", Slug: "vegetarian", Color: "#22c55e"})
+	require.NoError(t, err)
+	quick, err := tagRepo.Create(&models.Tag{Name: "Quick", Slug: "quick", Color: "#10b981"})
+	require.NoError(t, err)
+	require.NoError(t, service.AddTagToRecipe(createdRecipe.ID.String(), quick.ID.String(), authorID))
+
+	tags, err := service.SetRecipeTags(createdRecipe.ID.String(), []string{"vegetarian", " Sunday Brunch ", "Vegetarian"}, authorID)
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, vegetarian.ID, tags[0].ID)
+	assert.Equal(t, "Sunday Brunch", tags[1].Name)
+	assert.Equal(t, "sunday-brunch", tags[1].Slug)
+	assert.Equal(t, "#6366f1", tags[1].Color)
+
+	recipeTags, err := service.GetRecipeTags(createdRecipe.ID.String())
+	require.NoError(t, err)
+	var slugs []string
+	for _, tag := range recipeTags {
+		slugs = append(slugs, tag.Slug)
+	}
+	assert.ElementsMatch(t, []string{"vegetarian", "sunday-brunch"}, slugs)
+
+	created, err := service.GetBySlug("sunday-brunch")
+	require.NoError(t, err)
+	assert.Equal(t, tags[1].ID, created.ID)
+
+	tags, err = service.SetRecipeTags(createdRecipe.ID.String(), []string{}, authorID)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+
+	recipeTags, err = service.GetRecipeTags(createdRecipe.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, recipeTags)
+}
+
+func TestSetRecipeTags_MatchesByName(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	tagRepo := repository.NewTagRepository(db, q)
+	recipeRepo := repository.NewRecipeRepository(db, q)
+	service := NewTagService(tagRepo, recipeRepo)
+
+	authorID := createTestUser(db, q, "author@example.com")
+	createdRecipe, err := recipeRepo.Create(&models.Recipe{
+		AuthorID:        parseUUID(&authorID),
+		Title:           "Pancakes",
+		Slug:            "pancakes",
+		MarkdownContent: "Mix and cook.",
+	})
+	require.NoError(t, err)
+
+	quick, err := tagRepo.Create(&models.Tag{Name: "Quick", Slug: "speedy", Color: "#10b981"})
+	require.NoError(t, err)
+
+	tags, err := service.SetRecipeTags(createdRecipe.ID.String(), []string{"★★★", "Quick", "Speedy"}, authorID)
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, "★★★", tags[0].Name)
+	assert.Equal(t, quick.ID, tags[1].ID)
+
+	again, err := service.SetRecipeTags(createdRecipe.ID.String(), []string{"★★★"}, authorID)
+	require.NoError(t, err)
+	require.Len(t, again, 1)
+	assert.Equal(t, tags[0].ID, again[0].ID)
+}
+
+func TestDeleteTag_Reindexes(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	tagRepo := repository.NewTagRepository(db, q)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
+	recipeService := newTestRecipeService(db, q)
+
+	authorID := createTestUser(db, q, "author@example.com")
+	created, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:           "Pancakes",
+		MarkdownContent: "Mix and cook.",
+		IsPublished:     true,
+		Tags:            []string{"Brunch"},
+	}, authorID)
+	require.NoError(t, err)
+	brunch, err := service.GetBySlug("brunch")
+	require.NoError(t, err)
+
+	results, err := recipeService.SearchRecipes("brunch", models.DietaryFilter{}, 10, "")
+	require.NoError(t, err)
+	assert.Len(t, results.Items, 1)
+
+	require.NoError(t, service.DeleteTag(brunch.ID.String()))
+	results, err = recipeService.SearchRecipes("brunch", models.DietaryFilter{}, 10, "")
+	require.NoError(t, err)
+	assert.Empty(t, results.Items)
+	recipeTags, err := service.GetRecipeTags(created.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, recipeTags)
+}
+
+func TestSetRecipeTags_EmptyName(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	tagRepo := repository.NewTagRepository(db, q)
+	recipeRepo := repository.NewRecipeRepository(db, q)
+	service := NewTagService(tagRepo, recipeRepo)
+
+	authorID := createTestUser(db, q, "author@example.com")
+	createdRecipe, err := recipeRepo.Create(&models.Recipe{
+		AuthorID:        parseUUID(&authorID),
+		Title:           "Pancakes",
+		Slug:            "pancakes",
+		MarkdownContent: "Mix and cook.",
+	})
+	require.NoError(t, err)
+
+	_, err = service.SetRecipeTags(createdRecipe.ID.String(), []string{"Brunch", "  "}, authorID)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "tag name is required")
+
+	// Nothing is created when the request is rejected.
+	_, err = service.GetBySlug("brunch")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestSetRecipeTags_RecipeNotFound(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	tagRepo := repository.NewTagRepository(db, q)
+	service := NewTagService(tagRepo, repository.NewRecipeRepository(db, q))
+
+	_, err = service.SetRecipeTags("00000000-0000-0000-0000-000000000000", []string{"Brunch"}, createTestUser(db, q, "author@example.com"))
+	assert.EqualError(t, err, "recipe not found")
+	_, err = service.SetRecipeTags("not-a-uuid", []string{"Brunch"}, createTestUser(db, q, "other@example.com"))
+	assert.EqualError(t, err, "recipe not found")
+}
+
+func TestRecipeTags_NotAuthor(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	tagRepo := repository.NewTagRepository(db, q)
+	recipeRepo := repository.NewRecipeRepository(db, q)
+	service := NewTagService(tagRepo, recipeRepo)
+
+	authorID := createTestUser(db, q, "author@example.com")
+	otherID := createTestUser(db, q, "other@example.com")
+	createdRecipe, err := recipeRepo.Create(&models.Recipe{
+		AuthorID:        parseUUID(&authorID),
+		Title:           "Pancakes",
+		Slug:            "pancakes",
+		MarkdownContent: "Mix and cook.",
+	})
+	require.NoError(t, err)
+	quick, err := tagRepo.Create(&models.Tag{Name: "Quick", Slug: "quick", Color: "#10b981"})
+	require.NoError(t, err)
+	require.NoError(t, service.AddTagToRecipe(createdRecipe.ID.String(), quick.ID.String(), authorID))
+
+	_, err = service.SetRecipeTags(createdRecipe.ID.String(), []string{"Brunch"}, otherID)
+	assert.EqualError(t, err, "unauthorized: you can only edit your own recipes")
+	err = service.AddTagToRecipe(createdRecipe.ID.String(), quick.ID.String(), otherID)
+	assert.EqualError(t, err, "unauthorized: you can only edit your own recipes")
+	err = service.RemoveTagFromRecipe(createdRecipe.ID.String(), quick.ID.String(), otherID)
+	assert.EqualError(t, err, "unauthorized: you can only edit your own recipes")
+
+	tags, err := service.GetRecipeTags(createdRecipe.ID.String())
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+	assert.Equal(t, quick.ID, tags[0].ID)
 }
 
 func TestGenerateTagSlug(t *testing.T) {
@@ -390,7 +658,7 @@ func TestGenerateTagSlug(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			slug := generateTagSlug(tt.input)
+			slug := generateSlug(tt.input)
 			assert.Equal(t, tt.expected, slug)
 		})
 	}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+)
+
+type TrashService struct {
+	recipeRepo *repository.RecipeRepository
+	storage    *StorageService
+}
+
+func NewTrashService(recipeRepo *repository.RecipeRepository, storage *StorageService) *TrashService {
+	return &TrashService{
+		recipeRepo: recipeRepo,
+		storage:    storage,
+	}
+}
+
+// ListTrash pages through the recipes userID deleted, most recently
+// deleted first.
+func (s *TrashService) ListTrash(userID string, limit int, cursor string) (*models.Page[*models.Recipe], error) {
+	return s.recipeRepo.ListTrashed(userID, pageSize(limit), cursor)
+}
+
+// RestoreRecipe takes one of userID's recipes out of the trash and adds it
+// back to the search index.
+func (s *TrashService) RestoreRecipe(id string, userID string) (*models.Recipe, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("recipe not found")
+	}
+	recipe, err := s.recipeRepo.GetTrashedByID(id)
+	if err != nil {
+		return nil, errors.New("recipe not found")
+	}
+	if recipe.AuthorID == nil || recipe.AuthorID.String() != userID {
+		return nil, errors.New("unauthorized: you can only restore your own recipes")
+	}
+
+	if err := s.recipeRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	if err := s.recipeRepo.IndexForSearch(id); err != nil {
+		return nil, err
+	}
+	return s.recipeRepo.GetByID(id)
+}
+
+// PurgeExpired permanently deletes the recipes that have been in the trash
+// for longer than retention, along with the uploads only they referred to.
+// Uploads still used elsewhere, such as images a fork shares with its
+// original, are kept. It returns how many recipes were purged.
+func (s *TrashService) PurgeExpired(retention time.Duration) (int, error) {
+	if retention < 0 {
+		return 0, errors.New("retention must not be negative")
+	}
+	cutoff := time.Now().Add(-retention)
+	recipes, err := s.recipeRepo.ListTrashedBefore(cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, recipe := range recipes {
+		id := recipe.ID.String()
+		references, err := s.recipeRepo.UploadReferences(id)
+		if err != nil {
+			return purged, err
+		}
+		deleted, err := s.recipeRepo.PurgeTrashed(id, cutoff)
+		if err != nil {
+			return purged, err
+		}
+		if !deleted {
+			continue
+		}
+		purged++
+
+		for _, filename := range uploadFilenames(references) {
+			count, err := s.recipeRepo.CountUploadReferences(filename)
+			if err != nil {
+				return purged, err
+			}
+			if count == 0 {
+				s.storage.DeleteImage(filename)
+			}
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/homecooking/backend/internal/db/sqlc"
+	"github.com/homecooking/backend/internal/models"
+	"github.com/homecooking/backend/internal/repository"
+	testutil "github.com/homecooking/backend/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTrashService(t *testing.T, db *sql.DB, q *sqlc.Queries) (*TrashService, string) {
+	uploads := t.TempDir()
+	service := NewTrashService(
+		repository.NewRecipeRepository(db, q),
+		NewStorageService(uploads, 10*1024*1024),
+	)
+	return service, uploads
+}
+
+func TestTrashService_DeleteHidesRecipe(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	recipeService := newTestRecipeService(db, q)
+	collectionService := newTestCollectionService(db, q)
+	authorID := createTestUser(db, q, "author@example.com")
+
+	soup := createPublishedRecipe(t, recipeService, "Soup", authorID)
+	stew := createPublishedRecipe(t, recipeService, "Stew", authorID)
+	require.NoError(t, collectionService.AddFavorite(soup.ID.String(), authorID))
+
+	require.NoError(t, recipeService.DeleteRecipe(soup.ID.String(), authorID))
+
+	_, err = recipeService.GetRecipe(soup.ID.String())
+	assert.Error(t, err)
+	_, err = recipeService.GetRecipeBySlug(soup.Slug)
+	assert.Error(t, err)
+
+	recipes, err := recipeService.ListRecipes(10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Stew"}, recipeTitles(recipes))
+
+	list, err := recipeService.FilterRecipes(&models.RecipeFilter{}, 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Stew"}, recipeTitles(list.Items))
+	assert.Equal(t, 1, list.Total)
+
+	favorites, err := collectionService.ListFavorites(authorID, 10, "")
+	require.NoError(t, err)
+	assert.Empty(t, favorites.Items)
+
+	err = recipeService.DeleteRecipe(soup.ID.String(), authorID)
+	assert.Error(t, err, "a trashed recipe can't be deleted again")
+
+	// The slug stays taken while the recipe can still be restored.
+	again := createPublishedRecipe(t, recipeService, "Soup", authorID)
+	assert.NotEqual(t, soup.Slug, again.Slug)
+	assert.NotEqual(t, stew.Slug, again.Slug)
+}
+
+func TestTrashService_DeleteHidesRelatedRecords(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	recipeService := newTestRecipeService(db, q)
+	revisionService := NewRevisionService(repository.NewRecipeRevisionRepository(db, q), recipeService)
+	shareCodeService := NewShareCodeService(repository.NewShareCodeRepository(db, q), repository.NewRecipeRepository(db, q))
+	authorID := createTestUser(db, q, "author@example.com")
+
+	soup := createPublishedRecipe(t, recipeService, "Soup", authorID)
+	_, err = shareCodeService.CreateShareCode(soup.ID.String(), nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, recipeService.DeleteRecipe(soup.ID.String(), authorID))
+
+	_, err = revisionService.ListRevisions(soup.ID.String())
+	assert.EqualError(t, err, "recipe not found")
+	_, err = revisionService.GetRevision(soup.ID.String(), 1)
+	assert.EqualError(t, err, "recipe not found")
+	_, err = revisionService.DiffRevisions(soup.ID.String(), 1, 1)
+	assert.EqualError(t, err, "recipe not found")
+	_, err = shareCodeService.GetShareCodesForRecipe(soup.ID.String())
+	assert.EqualError(t, err, "recipe not found")
+}
+
+func TestTrashService_ListAndRestore(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service, _ := newTestTrashService(t, db, q)
+	recipeService := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "author@example.com")
+	otherID := createTestUser(db, q, "other@example.com")
+
+	soup := createPublishedRecipe(t, recipeService, "Tomato Soup", authorID)
+	stew := createPublishedRecipe(t, recipeService, "Stew", authorID)
+	bread := createPublishedRecipe(t, recipeService, "Bread", otherID)
+	require.NoError(t, recipeService.DeleteRecipe(soup.ID.String(), authorID))
+	require.NoError(t, recipeService.DeleteRecipe(stew.ID.String(), authorID))
+	require.NoError(t, recipeService.DeleteRecipe(bread.ID.String(), otherID))
+
+	page, err := service.ListTrash(authorID, 1, "")
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, 2, page.Total)
+	assert.NotNil(t, page.Items[0].DeletedAt)
+	require.NotNil(t, page.NextCursor)
+
+	next, err := service.ListTrash(authorID, 1, *page.NextCursor)
+	require.NoError(t, err)
+	require.Len(t, next.Items, 1)
+	assert.ElementsMatch(t, []string{"Tomato Soup", "Stew"}, recipeTitles(append(page.Items, next.Items...)))
+
+	other, err := service.ListTrash(otherID, 10, *page.NextCursor)
+	require.NoError(t, err)
+	assert.NotContains(t, recipeTitles(other.Items), "Stew", "a cursor can't reach into another user's trash")
+
+	_, err = service.RestoreRecipe(soup.ID.String(), otherID)
+	assert.EqualError(t, err, "unauthorized: you can only restore your own recipes")
+	_, err = service.RestoreRecipe(createPublishedRecipe(t, recipeService, "Live", authorID).ID.String(), authorID)
+	assert.EqualError(t, err, "recipe not found", "only trashed recipes can be restored")
+	_, err = service.RestoreRecipe("not-a-uuid", authorID)
+	assert.EqualError(t, err, "recipe not found")
+
+	restored, err := service.RestoreRecipe(soup.ID.String(), authorID)
+	require.NoError(t, err)
+	assert.Equal(t, soup.Slug, restored.Slug)
+	assert.Nil(t, restored.DeletedAt)
+
+	fetched, err := recipeService.GetRecipe(soup.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Tomato Soup", fetched.Title)
+
+	results, err := recipeService.SearchRecipes("tomato", models.DietaryFilter{}, 10, "")
+	require.NoError(t, err)
+	require.Len(t, results.Items, 1, "a restored recipe is searchable again")
+
+	page, err = service.ListTrash(authorID, 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Stew"}, recipeTitles(page.Items))
+}
+
+func TestTrashService_ListAfterRestoredCursor(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service, _ := newTestTrashService(t, db, q)
+	recipeService := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "author@example.com")
+
+	soup := createPublishedRecipe(t, recipeService, "Soup", authorID)
+	stew := createPublishedRecipe(t, recipeService, "Stew", authorID)
+	for day, recipe := range []*models.Recipe{soup, stew} {
+		require.NoError(t, recipeService.DeleteRecipe(recipe.ID.String(), authorID))
+		_, err = db.Exec("UPDATE recipes SET deleted_at = $1 WHERE id = $2", fmt.Sprintf("2024-01-0%d 10:00:00", day+1), recipe.ID)
+		require.NoError(t, err)
+	}
+
+	page, err := service.ListTrash(authorID, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Stew"}, recipeTitles(page.Items))
+	require.NotNil(t, page.NextCursor)
+
+	// The cursor still leads on once the recipe it marks is restored.
+	_, err = service.RestoreRecipe(stew.ID.String(), authorID)
+	require.NoError(t, err)
+	next, err := service.ListTrash(authorID, 1, *page.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Soup"}, recipeTitles(next.Items))
+	assert.Nil(t, next.NextCursor)
+}
+
+func TestTrashService_PurgeExpired(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service, uploads := newTestTrashService(t, db, q)
+	recipeService := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "author@example.com")
+
+	for _, name := range []string{"own.png", "inline.png", "shared.png", "recent.png"} {
+		require.NoError(t, os.WriteFile(filepath.Join(uploads, name), testPNG(t), 0644))
+	}
+
+	create := func(title, featured, markdown string) *models.Recipe {
+		recipe, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+			Title:             title,
+			MarkdownContent:   markdown,
+			FeaturedImagePath: &featured,
+			IsPublished:       true,
+		}, authorID)
+		require.NoError(t, err)
+		return recipe
+	}
+	old := create("Old Pie", "/uploads/own.png", "![crust](/uploads/inline.png)\n\n![plate](/uploads/shared.png)")
+	keeper := create("Keeper", "/uploads/shared.png", "Still here")
+	recent := create("Recent", "/uploads/recent.png", "Deleted today")
+
+	require.NoError(t, recipeService.DeleteRecipe(old.ID.String(), authorID))
+	require.NoError(t, recipeService.DeleteRecipe(recent.ID.String(), authorID))
+	_, err = db.Exec("UPDATE recipes SET deleted_at = $1 WHERE id = $2", time.Now().AddDate(0, 0, -40).UTC(), old.ID)
+	require.NoError(t, err)
+
+	count, err := service.PurgeExpired(30 * 24 * time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	page, err := service.ListTrash(authorID, 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Recent"}, recipeTitles(page.Items))
+	_, err = service.RestoreRecipe(old.ID.String(), authorID)
+	assert.EqualError(t, err, "recipe not found", "purged recipes are gone for good")
+
+	assert.NoFileExists(t, filepath.Join(uploads, "own.png"))
+	assert.NoFileExists(t, filepath.Join(uploads, "inline.png"))
+	assert.FileExists(t, filepath.Join(uploads, "shared.png"), "files other recipes use are kept")
+	assert.FileExists(t, filepath.Join(uploads, "recent.png"))
+
+	_, err = recipeService.GetRecipe(keeper.ID.String())
+	require.NoError(t, err)
+
+	count, err = service.PurgeExpired(30 * 24 * time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestTrashService_PurgeKeepsForkImages(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service, uploads := newTestTrashService(t, db, q)
+	recipeService := newTestRecipeService(db, q)
+	authorID := createTestUser(db, q, "author@example.com")
+	forkerID := createTestUser(db, q, "forker@example.com")
+
+	require.NoError(t, os.WriteFile(filepath.Join(uploads, "pie.png"), testPNG(t), 0644))
+	featured := "/uploads/pie.png"
+	original, err := recipeService.CreateRecipe(&models.CreateRecipeRequest{
+		Title:             "Apple Pie",
+		MarkdownContent:   "- apples",
+		FeaturedImagePath: &featured,
+		IsPublished:       true,
+	}, authorID)
+	require.NoError(t, err)
+	_, err = recipeService.ForkRecipe(original.ID.String(), &models.ForkRecipeRequest{}, forkerID)
+	require.NoError(t, err)
+
+	require.NoError(t, recipeService.DeleteRecipe(original.ID.String(), authorID))
+	count, err := service.PurgeExpired(0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	assert.FileExists(t, filepath.Join(uploads, "pie.png"), "the fork still shows the image")
+}
+
+func TestTrashService_PurgeSkipsRestoredRecipes(t *testing.T) {
+	db, q, err := testutil.SetupTestDB()
+	require.NoError(t, err)
+	defer testutil.TeardownTestDB(db)
+
+	service, _ := newTestTrashService(t, db, q)
+	recipeService := newTestRecipeService(db, q)
+	recipeRepo := repository.NewRecipeRepository(db, q)
+	authorID := createTestUser(db, q, "author@example.com")
+
+	soup := createPublishedRecipe(t, recipeService, "Soup", authorID)
+	deleted, err := recipeRepo.PurgeTrashed(soup.ID.String(), time.Now())
+	require.NoError(t, err)
+	assert.False(t, deleted, "live recipes are never purged")
+
+	// A recipe restored after the purge listed it must survive the delete.
+	require.NoError(t, recipeService.DeleteRecipe(soup.ID.String(), authorID))
+	_, err = service.RestoreRecipe(soup.ID.String(), authorID)
+	require.NoError(t, err)
+	deleted, err = recipeRepo.PurgeTrashed(soup.ID.String(), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	_, err = recipeService.GetRecipe(soup.ID.String())
+	require.NoError(t, err)
+
+	_, err = service.PurgeExpired(-time.Hour)
+	assert.Error(t, err)
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/homecooking/backend/internal/models"
+)
+
+type UnitSystem string
+
+const (
+	UnitsOriginal UnitSystem = "original"
+	UnitsMetric   UnitSystem = "metric"
+	UnitsUS       UnitSystem = "us"
+)
+
+type unitKind int
+
+const (
+	volumeUnit unitKind = iota + 1
+	weightUnit
+)
+
+// unitTable holds every convertible canonical unit with its size in the base
+// unit of its kind: millilitres for volume, grams for weight. Units missing
+// here (pinch, clove, can, ...) are never converted.
+var unitTable = map[string]struct {
+	kind   unitKind
+	factor float64
+}{
+	"tsp":    {volumeUnit, 4.92892},
+	"tbsp":   {volumeUnit, 14.7868},
+	"fl oz":  {volumeUnit, 29.5735},
+	"cup":    {volumeUnit, 236.588},
+	"pint":   {volumeUnit, 473.176},
+	"quart":  {volumeUnit, 946.353},
+	"gallon": {volumeUnit, 3785.41},
+	"ml":     {volumeUnit, 1},
+	"l":      {volumeUnit, 1000},
+	"g":      {weightUnit, 1},
+	"kg":     {weightUnit, 1000},
+	"oz":     {weightUnit, 28.3495},
+	"lb":     {weightUnit, 453.592},
+}
+
+// pluralUnits are the canonical units written with an "s" for quantities
+// above one; abbreviations stay as they are.
+var pluralUnits = map[string]bool{
+	"cup":    true,
+	"pint":   true,
+	"quart":  true,
+	"gallon": true,
+}
+
+var temperaturePattern = regexp.MustCompile(`(?i)(-?\d+(?:\.\d+)?)\s*(?:°|º|degrees?\s+)\s*(fahrenheit|celsius|f|c)\b`)
+
+// ParseUnitSystem validates the units query parameter. An empty value means
+// the recipe is returned as written.
+func ParseUnitSystem(value string) (UnitSystem, error) {
+	switch UnitSystem(strings.ToLower(value)) {
+	case "", UnitsOriginal:
+		return UnitsOriginal, nil
+	case UnitsMetric:
+		return UnitsMetric, nil
+	case UnitsUS:
+		return UnitsUS, nil
+	}
+	return "", errors.New("units must be one of metric, us, original")
+}
+
+// ConvertContent rewrites the ingredient quantities in the markdown's
+// "## Ingredients" section and every temperature in the text to the given
+// unit system.
+func ConvertContent(markdown string, system UnitSystem) string {
+	if system == UnitsOriginal {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	start, end := ingredientSection(lines)
+	for i := start; i < end; i++ {
+		if headingPattern.MatchString(strings.TrimSpace(lines[i])) {
+			continue
+		}
+		lines[i] = convertIngredientLine(lines[i], system)
+	}
+
+	return convertTemperatures(strings.Join(lines, "\n"), system)
+}
+
+// ConvertIngredients returns a copy of the structured ingredients with
+// quantities, units and raw text in the given unit system.
+func ConvertIngredients(ingredients []models.RecipeIngredient, system UnitSystem) []models.RecipeIngredient {
+	if system == UnitsOriginal || ingredients == nil {
+		return ingredients
+	}
+
+	converted := make([]models.RecipeIngredient, len(ingredients))
+	for i, ingredient := range ingredients {
+		converted[i] = ingredient
+		if ingredient.Quantity == nil || ingredient.Unit == nil {
+			continue
+		}
+		quantity, quantityMax, unit, ok := convertQuantity(*ingredient.Quantity, ingredient.QuantityMax, *ingredient.Unit, system)
+		if !ok {
+			continue
+		}
+		converted[i].Quantity = &quantity
+		converted[i].QuantityMax = quantityMax
+		converted[i].Unit = &unit
+		converted[i].RawText = convertIngredientLine(ingredient.RawText, system)
+	}
+	return converted
+}
+
+// convertIngredientLine rewrites the quantity and unit at the start of a
+// single ingredient line, leaving lines with unknown or non-convertible
+// units untouched.
+func convertIngredientLine(line string, system UnitSystem) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(trimmed)]
+
+	marker := listMarkerPattern.FindString(trimmed)
+	text := trimmed[len(marker):]
+
+	quantity, quantityMax, rest := splitQuantity(text)
+	if quantity == nil {
+		return line
+	}
+
+	rest = strings.TrimLeft(rest, " \t")
+	unit, consumed := matchUnit(rest)
+	if unit == "" {
+		return line
+	}
+
+	newQuantity, newMax, newUnit, ok := convertQuantity(*quantity, quantityMax, unit, system)
+	if !ok {
+		return line
+	}
+
+	return indent + marker + formatMeasure(newQuantity, newMax, newUnit) + rest[consumed:]
+}
+
+// convertQuantity converts a quantity (and optional range maximum) to the
+// unit that reads best in the target system. It reports false when the unit
+// is not convertible or is already right for the system.
+func convertQuantity(quantity float64, quantityMax *float64, unit string, system UnitSystem) (float64, *float64, string, bool) {
+	entry, ok := unitTable[unit]
+	if !ok {
+		return 0, nil, "", false
+	}
+
+	base := quantity * entry.factor
+	target := targetUnit(base, unit, entry.kind, system)
+	if target == unit {
+		return 0, nil, "", false
+	}
+
+	targetFactor := unitTable[target].factor
+	converted := roundQuantity(base/targetFactor, target)
+	var convertedMax *float64
+	if quantityMax != nil {
+		v := roundQuantity(*quantityMax*entry.factor/targetFactor, target)
+		convertedMax = &v
+	}
+	return converted, convertedMax, target, true
+}
+
+// targetUnit picks the unit a cook in the given system would use for an
+// amount expressed in the base unit. Teaspoons and tablespoons are common to
+// both systems and are kept as written.
+func targetUnit(base float64, unit string, kind unitKind, system UnitSystem) string {
+	if unit == "tsp" || unit == "tbsp" {
+		return unit
+	}
+
+	switch system {
+	case UnitsMetric:
+		if kind == volumeUnit {
+			if base >= 1000 {
+				return "l"
+			}
+			return "ml"
+		}
+		if base >= 1000 {
+			return "kg"
+		}
+		return "g"
+	case UnitsUS:
+		if kind == volumeUnit {
+			if unit != "ml" && unit != "l" {
+				return unit
+			}
+			switch {
+			case base < unitTable["tbsp"].factor:
+				return "tsp"
+			case base < unitTable["cup"].factor/4:
+				return "tbsp"
+			}
+			return "cup"
+		}
+		if unit != "g" && unit != "kg" {
+			return unit
+		}
+		if base >= unitTable["lb"].factor {
+			return "lb"
+		}
+		return "oz"
+	}
+	return unit
+}
+
+// roundQuantity trims converted amounts to what can actually be measured:
+// whole or five-gram steps for metric, quarters for US cups, spoons and
+// ounces. Tiny amounts that would round to nothing are kept as they are.
+func roundQuantity(value float64, unit string) float64 {
+	switch unit {
+	case "ml", "g":
+		switch {
+		case value >= 100:
+			return math.Round(value/5) * 5
+		case value >= 10:
+			return math.Round(value)
+		}
+		return roundTo(value, 1)
+	case "l", "kg":
+		return roundTo(value, 2)
+	}
+	if rounded := math.Round(value*4) / 4; rounded > 0 {
+		return rounded
+	}
+	return value
+}
+
+func formatMeasure(quantity float64, quantityMax *float64, unit string) string {
+	text := formatConvertedQuantity(quantity, unit)
+	largest := quantity
+	if quantityMax != nil {
+		text += "-" + formatConvertedQuantity(*quantityMax, unit)
+		largest = *quantityMax
+	}
+	if pluralUnits[unit] && largest > 1 {
+		unit += "s"
+	}
+	return text + " " + unit
+}
+
+func formatConvertedQuantity(value float64, unit string) string {
+	switch unit {
+	case "ml", "g", "l", "kg":
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return FormatQuantity(value)
+}
+
+// convertTemperatures rewrites temperatures such as "350°F" or "180 degrees C"
+// into the target system, rounded the way oven dials are marked.
+func convertTemperatures(text string, system UnitSystem) string {
+	return temperaturePattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := temperaturePattern.FindStringSubmatch(match)
+		value, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return match
+		}
+		fahrenheit := strings.HasPrefix(strings.ToLower(m[2]), "f")
+
+		switch {
+		case system == UnitsMetric && fahrenheit:
+			celsius := (value - 32) * 5 / 9
+			if celsius >= 120 {
+				celsius = math.Round(celsius/10) * 10
+			}
+			return fmt.Sprintf("%.0f°C", celsius)
+		case system == UnitsUS && !fahrenheit:
+			converted := value*9/5 + 32
+			if converted >= 250 {
+				converted = math.Round(converted/25) * 25
+			}
+			return fmt.Sprintf("%.0f°F", converted)
+		}
+		return match
+	})
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/homecooking/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUnitSystem(t *testing.T) {
+	system, err := ParseUnitSystem("")
+	require.NoError(t, err)
+	assert.Equal(t, UnitsOriginal, system)
+
+	system, err = ParseUnitSystem("Metric")
+	require.NoError(t, err)
+	assert.Equal(t, UnitsMetric, system)
+
+	_, err = ParseUnitSystem("imperial")
+	assert.EqualError(t, err, "units must be one of metric, us, original")
+}
+
+func TestConvertIngredientLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		system   UnitSystem
+		expected string
+	}{
+		{"cups to ml", "- 2 cups flour", UnitsMetric, "- 475 ml flour"},
+		{"quart to litres", "- 2 quarts stock", UnitsMetric, "- 1.89 l stock"},
+		{"pounds to kilograms", "- 3 lb potatoes, peeled", UnitsMetric, "- 1.36 kg potatoes, peeled"},
+		{"ounces to grams", "- 4 oz cheddar", UnitsMetric, "- 115 g cheddar"},
+		{"range", "- 1-2 cups milk", UnitsMetric, "- 235-475 ml milk"},
+		{"spoons kept", "- 1 tbsp olive oil", UnitsMetric, "- 1 tbsp olive oil"},
+		{"already metric", "- 200 g butter", UnitsMetric, "- 200 g butter"},
+		{"grams to ounces", "- 200g butter", UnitsUS, "- 7 oz butter"},
+		{"grams to pounds", "- 1 kg flour", UnitsUS, "- 2 1/4 lb flour"},
+		{"ml to cups", "- 250 ml milk", UnitsUS, "- 1 cup milk"},
+		{"ml to cups plural", "- 375 ml milk", UnitsUS, "- 1 1/2 cups milk"},
+		{"ml to tbsp", "- 30 ml vinegar", UnitsUS, "- 2 tbsp vinegar"},
+		{"ml to tsp", "- 5 ml vanilla", UnitsUS, "- 1 tsp vanilla"},
+		{"already us", "- 2 cups flour", UnitsUS, "- 2 cups flour"},
+		{"no unit", "- 3 eggs", UnitsMetric, "- 3 eggs"},
+		{"not convertible", "- 2 cloves garlic", UnitsMetric, "- 2 cloves garlic"},
+		{"package size", "- 1 (14 oz) can tomatoes", UnitsMetric, "- 1 (14 oz) can tomatoes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, convertIngredientLine(tt.line, tt.system))
+		})
+	}
+}
+
+func TestConvertContent(t *testing.T) {
+	markdown := `## Ingredients
+
+- 1 cup sugar
+- 2 eggs
+
+## Instructions
+
+1. Preheat the oven to 350°F.
+2. Bake at 350 degrees F for 2 cups worth of minutes.`
+
+	converted := ConvertContent(markdown, UnitsMetric)
+	assert.Contains(t, converted, "- 235 ml sugar")
+	assert.Contains(t, converted, "- 2 eggs")
+	assert.Contains(t, converted, "Preheat the oven to 180°C.")
+	assert.Contains(t, converted, "Bake at 180°C for 2 cups worth of minutes.")
+
+	assert.Equal(t, markdown, ConvertContent(markdown, UnitsOriginal))
+}
+
+func TestConvertTemperatures(t *testing.T) {
+	assert.Equal(t, "Bake at 400°F.", convertTemperatures("Bake at 200°C.", UnitsUS))
+	assert.Equal(t, "Roast at 425°F.", convertTemperatures("Roast at 220 °C.", UnitsUS))
+	assert.Equal(t, "Cook to 63°C.", convertTemperatures("Cook to 145°F.", UnitsMetric))
+	assert.Equal(t, "Bake at 200°C.", convertTemperatures("Bake at 200°C.", UnitsMetric))
+}
+
+func TestConvertIngredients(t *testing.T) {
+	ingredients := []models.RecipeIngredient{
+		{RawText: "2 cups flour", Quantity: float64Ptr(2), Unit: stringPtr("cup"), Name: "flour"},
+		{RawText: "3 eggs", Quantity: float64Ptr(3), Name: "eggs"},
+	}
+
+	converted := ConvertIngredients(ingredients, UnitsMetric)
+	require.Len(t, converted, 2)
+	assert.Equal(t, 475.0, *converted[0].Quantity)
+	assert.Equal(t, "ml", *converted[0].Unit)
+	assert.Equal(t, "475 ml flour", converted[0].RawText)
+	assert.Equal(t, ingredients[1], converted[1])
+
+	// The input slice is not modified.
+	assert.Equal(t, "cup", *ingredients[0].Unit)
+}
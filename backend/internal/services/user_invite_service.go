@@ -99,8 +99,8 @@ func (s *UserInviteService) UseInvite(code string, usedBy string) (*models.UserI
 	return s.inviteRepo.Use(invite.ID.String(), usedBy)
 }
 
-func (s *UserInviteService) ListInvites() ([]*models.UserInvite, error) {
-	return s.inviteRepo.List()
+func (s *UserInviteService) ListInvites(limit int, cursor string) (*models.Page[*models.UserInvite], error) {
+	return s.inviteRepo.List(pageSize(limit), cursor)
 }
 
 func (s *UserInviteService) DeleteInvite(id string) error {
@@ -25,6 +25,10 @@ func (s *VariationService) CreateVariation(req *models.CreateVariationRequest, r
 		return nil, errors.New("markdown content is required")
 	}
 
+	if _, err := findRecipe(s.recipeRepo, recipeID); err != nil {
+		return nil, err
+	}
+
 	recipeUUID := uuid.MustParse(recipeID)
 	authorUUID := uuid.MustParse(authorID)
 
@@ -40,23 +44,54 @@ func (s *VariationService) CreateVariation(req *models.CreateVariationRequest, r
 		IsPublished:     req.IsPublished,
 	}
 
-	return s.variationRepo.Create(variationModel)
+	variation, err := s.variationRepo.Create(variationModel)
+	if err != nil {
+		return nil, err
+	}
+	classifyVariations(variation)
+	return variation, nil
 }
 
 func (s *VariationService) GetVariation(id string) (*models.RecipeVariation, error) {
-	return s.variationRepo.GetByID(id)
+	variation, err := s.variationRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findRecipe(s.recipeRepo, variation.RecipeID.String()); err != nil {
+		return nil, err
+	}
+	classifyVariations(variation)
+	return variation, nil
 }
 
 func (s *VariationService) GetVariationsByRecipe(recipeID string) ([]*models.RecipeVariation, error) {
-	return s.variationRepo.GetByRecipe(recipeID)
+	if _, err := findRecipe(s.recipeRepo, recipeID); err != nil {
+		return nil, err
+	}
+	variations, err := s.variationRepo.GetByRecipe(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	classifyVariations(variations...)
+	return variations, nil
 }
 
 func (s *VariationService) GetPublishedVariationsByRecipe(recipeID string) ([]*models.RecipeVariation, error) {
-	return s.variationRepo.GetPublishedByRecipe(recipeID)
+	variations, err := s.variationRepo.GetPublishedByRecipe(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	classifyVariations(variations...)
+	return variations, nil
 }
 
 func (s *VariationService) GetVariationByRecipeAndAuthor(recipeID string, authorID string) (*models.RecipeVariation, error) {
-	return s.variationRepo.GetByRecipeAndAuthor(recipeID, authorID)
+	variation, err := s.variationRepo.GetByRecipeAndAuthor(recipeID, authorID)
+	if err != nil {
+		return nil, err
+	}
+	classifyVariations(variation)
+	return variation, nil
 }
 
 func (s *VariationService) UpdateVariation(id string, req *models.UpdateVariationRequest, authorID string) (*models.RecipeVariation, error) {
@@ -69,7 +104,12 @@ func (s *VariationService) UpdateVariation(id string, req *models.UpdateVariatio
 		return nil, errors.New("unauthorized: you can only edit your own variations")
 	}
 
-	return s.variationRepo.Update(id, req)
+	variation, err := s.variationRepo.Update(id, req)
+	if err != nil {
+		return nil, err
+	}
+	classifyVariations(variation)
+	return variation, nil
 }
 
 func (s *VariationService) DeleteVariation(id string, authorID string) error {
@@ -85,6 +125,55 @@ func (s *VariationService) DeleteVariation(id string, authorID string) error {
 	return s.variationRepo.Delete(id)
 }
 
+// ScaleVariation rewrites the variation's ingredient quantities for the
+// requested number of servings. Variations without their own servings count
+// inherit the base recipe's. Linked sub-recipes are inlined as for
+// RecipeService.ScaleRecipe.
+// ScaleVariation scales a variation of the recipe recipeID. A variation of
+// another recipe is reported as not found.
+func (s *VariationService) ScaleVariation(recipeID, id string, servings int) (*models.ScaledRecipe, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("variation not found")
+	}
+	variation, err := s.variationRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if parsed, err := uuid.Parse(recipeID); err != nil || parsed != variation.RecipeID {
+		return nil, errors.New("variation not found")
+	}
+	recipe, err := findRecipe(s.recipeRepo, variation.RecipeID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	baseServings := variation.Servings
+	if baseServings == nil {
+		baseServings = recipe.Servings
+	}
+
+	scaled, err := scaleRecipeContent(variation.MarkdownContent, baseServings, servings)
+	if err != nil {
+		return nil, err
+	}
+
+	scaled.RecipeID = variation.RecipeID
+	scaled.VariationID = &variation.ID
+	for i := range scaled.Ingredients {
+		scaled.Ingredients[i].RecipeID = variation.RecipeID
+	}
+	scaled.Ingredients, err = inlineSubRecipes(s.recipeRepo, variation.RecipeID, variation.MarkdownContent, scaled.Ingredients, scaled.Factor)
+	if err != nil {
+		return nil, err
+	}
+	return scaled, nil
+}
+
 func (s *VariationService) ListVariationsByAuthor(authorID string, limit, offset int) ([]*models.RecipeVariation, error) {
-	return s.variationRepo.ListByAuthor(authorID, limit, offset)
+	variations, err := s.variationRepo.ListByAuthor(authorID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	classifyVariations(variations...)
+	return variations, nil
 }
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/homecooking/backend/internal/db/sqlc"
 	_ "github.com/mattn/go-sqlite3"
@@ -27,6 +28,17 @@ func SetupTestDB() (*sql.DB, *sqlc.Queries, error) {
 	migrations := []string{
 		"001_init_sqlite.up.sql",
 		"002_add_recipe_variations_sqlite.up.sql",
+		"003_add_recipe_ingredients_sqlite.up.sql",
+		"004_add_recipe_revisions_sqlite.up.sql",
+		"005_add_recipe_search_sqlite.up.sql",
+		"006_add_slug_history_sqlite.up.sql",
+		"007_add_recipe_dietary_sqlite.up.sql",
+		"008_add_recipe_links_sqlite.up.sql",
+		"009_add_recipe_forks_sqlite.up.sql",
+		"010_add_cook_logs_sqlite.up.sql",
+		"011_add_collections_sqlite.up.sql",
+		"012_add_comments_sqlite.up.sql",
+		"013_add_recipe_trash_sqlite.up.sql",
 	}
 
 	for _, migration := range migrations {
@@ -45,6 +57,18 @@ func SetupTestDB() (*sql.DB, *sqlc.Queries, error) {
 		}
 	}
 
+	// The FTS5 index needs go-sqlite3 built with the sqlite_fts5 tag; without
+	// it search falls back to LIKE matching.
+	ftsSQL, err := os.ReadFile(filepath.Join("..", "db", "migrations", "014_add_recipe_search_fts5_sqlite.up.sql"))
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	if _, err := db.Exec(string(ftsSQL)); err != nil && !strings.Contains(err.Error(), "no such module: fts5") {
+		db.Close()
+		return nil, nil, err
+	}
+
 	q := sqlc.New(db)
 	return db, q, nil
 }
@@ -1,9 +1,14 @@
 // @ts-check
 import { defineConfig } from "astro/config";
+import node from "@astrojs/node";
 import tailwindcss from "@tailwindcss/vite";
 
 // https://astro.build/config
 export default defineConfig({
+    // Pages are prerendered unless they opt out with `prerender = false`,
+    // which the recipe and share pages do to embed their JSON-LD.
+    adapter: node({ mode: "standalone" }),
+
     server: {
         port: 4321,
     },
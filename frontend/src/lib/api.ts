@@ -35,6 +35,12 @@ export interface Tag {
 	updated_at: string;
 }
 
+export interface Page<T> {
+	items: T[];
+	next_cursor: string | null;
+	total: number;
+}
+
 class ApiClient {
 	private baseUrl: string;
 
@@ -106,13 +112,13 @@ export async function getRecipe(id: number): Promise<Recipe> {
 
 export async function getRecipes(params?: {
 	limit?: number;
-	offset?: number;
-}): Promise<{ recipes: Recipe[]; total: number }> {
+	cursor?: string;
+}): Promise<Page<Recipe>> {
 	const queryParams = new URLSearchParams();
 	if (params?.limit) queryParams.append('limit', params.limit.toString());
-	if (params?.offset) queryParams.append('offset', params.offset.toString());
+	if (params?.cursor) queryParams.append('cursor', params.cursor);
 	const query = queryParams.toString() ? `?${queryParams}` : '';
-	return api.get<{ recipes: Recipe[]; total: number }>(`/recipes${query}`);
+	return api.get<Page<Recipe>>(`/recipes${query}`);
 }
 
 export async function createRecipe(data: Partial<Recipe>): Promise<Recipe> {
@@ -147,8 +153,9 @@ export async function deleteCategory(id: number): Promise<void> {
 	return api.delete<void>(`/categories/${id}`);
 }
 
-export async function getTags(): Promise<Tag[]> {
-	return api.get<Tag[]>('/tags');
+export async function getTags(cursor?: string): Promise<Page<Tag>> {
+	const query = cursor ? `?cursor=${encodeURIComponent(cursor)}` : '';
+	return api.get<Page<Tag>>(`/tags${query}`);
 }
 
 export async function getTag(id: number): Promise<Tag> {
@@ -190,3 +197,21 @@ export async function apiFetch<T>(endpoint: string, authenticated = false): Prom
 
 	return response.json();
 }
+
+// getRecipeJSONLD fetches the schema.org Recipe for a recipe endpoint, ready
+// to embed in a <script type="application/ld+json"> tag, or null if the
+// recipe can't be loaded. "<" is escaped so the data can't close the tag.
+export async function getRecipeJSONLD(endpoint: string): Promise<string | null> {
+	try {
+		const response = await fetch(`${API_BASE_URL}${endpoint}`, {
+			headers: { Accept: 'application/ld+json' },
+		});
+		if (!response.ok) {
+			return null;
+		}
+		return JSON.stringify(await response.json()).replace(/</g, '\\u003c');
+	} catch (error) {
+		console.error('Failed to load structured data:', error);
+		return null;
+	}
+}